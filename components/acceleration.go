@@ -11,7 +11,7 @@ const (
 	TypeAcceleration = "acceleration"
 )
 
-// Acceleration represents the acceleration of an entity.
+// Acceleration represents the acceleration of an entity.  The translational acceleration is expressed in the acceleration's reference frame while the rotational acceleration is always about the entity's local axes.
 type Acceleration interface {
 	Component
 	// Rotational retrieves the rotational acceleration in radians per second.
@@ -24,11 +24,21 @@ type Acceleration interface {
 	SetTranslational(mgl32.Vec3)
 	// Set sets the rotational and translational accerlation vectors.
 	Set(rotational, translational mgl32.Vec3)
+	// Frame retrieves the reference frame the translational acceleration is expressed in.
+	Frame() ReferenceFrame
+	// SetFrame sets the reference frame the translational acceleration is expressed in.
+	SetFrame(ReferenceFrame)
+	// Parent retrieves the id of the entity used by the parent frame.
+	Parent() string
+	// SetParent sets the id of the entity used by the parent frame.
+	SetParent(string)
 }
 
 type acceleration struct {
 	rotational    mgl32.Vec3
 	translational mgl32.Vec3
+	frame         ReferenceFrame
+	parent        string
 	dataLock      sync.RWMutex
 }
 
@@ -37,6 +47,7 @@ func NewAcceleration() Acceleration {
 	a := acceleration{
 		rotational:    mgl32.Vec3{0, 0, 0},
 		translational: mgl32.Vec3{0, 0, 0},
+		frame:         FrameWorld,
 	}
 	return &a
 }
@@ -81,3 +92,31 @@ func (a *acceleration) Set(rotational, translational mgl32.Vec3) {
 	a.rotational = rotational
 	a.translational = translational
 }
+
+// Frame retrieves the reference frame the translational acceleration is expressed in.
+func (a *acceleration) Frame() ReferenceFrame {
+	a.dataLock.RLock()
+	defer a.dataLock.RUnlock()
+	return a.frame
+}
+
+// SetFrame sets the reference frame the translational acceleration is expressed in.
+func (a *acceleration) SetFrame(frame ReferenceFrame) {
+	a.dataLock.Lock()
+	defer a.dataLock.Unlock()
+	a.frame = frame
+}
+
+// Parent retrieves the id of the entity used by the parent frame.
+func (a *acceleration) Parent() string {
+	a.dataLock.RLock()
+	defer a.dataLock.RUnlock()
+	return a.parent
+}
+
+// SetParent sets the id of the entity used by the parent frame.
+func (a *acceleration) SetParent(id string) {
+	a.dataLock.Lock()
+	defer a.dataLock.Unlock()
+	a.parent = id
+}
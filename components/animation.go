@@ -1,3 +1,679 @@
 package components
 
-// TODO: add handling of animations such as the rotation in model.Update
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	// TypeAnimation represents an animation component's type.
+	TypeAnimation = "animation"
+)
+
+// Interpolation describes how a track's value is calculated between two keyframes.
+type Interpolation string
+
+const (
+	// InterpolationLinear linearly interpolates between keyframes.  Rotations are spherically interpolated.
+	InterpolationLinear Interpolation = "linear"
+	// InterpolationStep holds the value of a keyframe until the next keyframe is reached.
+	InterpolationStep Interpolation = "step"
+	// InterpolationCubic uses a Catmull-Rom spline through the keyframes.  Rotations fall back to spherical interpolation.
+	InterpolationCubic Interpolation = "cubic"
+)
+
+// LoopMode describes what happens when playback reaches the end of a clip.
+type LoopMode string
+
+const (
+	// LoopOnce plays the clip a single time and holds the final pose.
+	LoopOnce LoopMode = "once"
+	// LoopRepeat restarts the clip from the beginning each time it ends.
+	LoopRepeat LoopMode = "repeat"
+	// LoopPingPong alternates between playing the clip forwards and backwards.
+	LoopPingPong LoopMode = "pingpong"
+)
+
+const (
+	// TrackTranslation is the target name of a track animating the translation of a transform.
+	TrackTranslation = "translation"
+	// TrackRotation is the target name of a track animating the rotation of a transform.  Keyframe values are either euler angles in radians or a quaternion in x, y, z, w order.
+	TrackRotation = "rotation"
+	// TrackScale is the target name of a track animating the scale of a transform.
+	TrackScale = "scale"
+	// TrackProperty is the target name of a track animating a named float property.
+	TrackProperty = "property"
+)
+
+// Keyframe is a value at a point in time within an animation track.
+type Keyframe struct {
+	Time  float32   `json:"time"`
+	Value []float32 `json:"value"`
+}
+
+// AnimationTrack is a series of keyframes animating a single target.  Tracks naming a joint animate that joint of a Skeleton instead of the Transform.
+type AnimationTrack struct {
+	Target        string        `json:"target"`
+	Property      string        `json:"property,omitempty"`
+	Joint         string        `json:"joint,omitempty"`
+	Interpolation Interpolation `json:"interpolation"`
+	Keys          []Keyframe    `json:"keys"`
+}
+
+// AnimationEvent is a named marker that is raised when playback passes its time.
+type AnimationEvent struct {
+	Time float32 `json:"time"`
+	Name string  `json:"name"`
+}
+
+// AnimationClip is a named collection of tracks and events.
+type AnimationClip struct {
+	Name     string           `json:"name"`
+	Duration float32          `json:"duration"`
+	Loop     LoopMode         `json:"loop"`
+	Tracks   []AnimationTrack `json:"tracks"`
+	Events   []AnimationEvent `json:"events"`
+}
+
+// AnimationPose is the result of sampling one or more clips at a point in time.
+type AnimationPose struct {
+	Translation    mgl32.Vec3
+	Rotation       mgl32.Quat
+	Scale          mgl32.Vec3
+	Properties     map[string]float32
+	Joints         map[string]AnimationPose
+	HasTranslation bool
+	HasRotation    bool
+	HasScale       bool
+}
+
+// Animation represents a component that plays keyframed animation clips.
+type Animation interface {
+	Component
+	// AddClip adds a clip to the component, replacing any clip with the same name.
+	AddClip(AnimationClip)
+	// Clip retrieves a clip by name.
+	Clip(name string) (AnimationClip, bool)
+	// Load loads the clips in an animation file and adds them to the component.
+	Load(fileName string) error
+	// Play immediately starts playing the named clip from the beginning.
+	Play(name string) error
+	// CrossFade blends from the currently playing clip to the named clip over the duration in seconds of clip time, so it is scaled by the playback speed.
+	CrossFade(name string, duration float32) error
+	// Stop stops playback.
+	Stop()
+	// IsPlaying returns true while a clip is being played.
+	IsPlaying() bool
+	// Current retrieves the name of the clip being played.
+	Current() string
+	// SetSpeed sets the playback speed multiplier.
+	SetSpeed(float32)
+	// Speed retrieves the playback speed multiplier.
+	Speed() float32
+	// OnEvent registers a function to be called whenever an event in a playing clip is reached.
+	OnEvent(func(clip string, event AnimationEvent))
+	// BindProperty registers the function that receives the value of a property track.
+	BindProperty(name string, setter func(float32))
+	// Update advances playback by the elapsed seconds and returns the resulting pose.  The boolean is false when nothing is playing.
+	Update(elapsed float32) (AnimationPose, bool)
+}
+
+type animationState struct {
+	clip    AnimationClip
+	time    float32
+	started bool
+}
+
+type animation struct {
+	clips        map[string]AnimationClip
+	current      *animationState
+	previous     *animationState
+	fadeTime     float32
+	fadeDuration float32
+	speed        float32
+	playing      bool
+	handlers     []func(string, AnimationEvent)
+	setters      map[string]func(float32)
+	dataLock     sync.RWMutex
+}
+
+// NewAnimation creates a new Animation component.
+func NewAnimation() Animation {
+	a := animation{
+		clips:   make(map[string]AnimationClip),
+		speed:   1,
+		setters: make(map[string]func(float32)),
+	}
+	return &a
+}
+
+// Type retrieves the type name of this component.
+func (a *animation) Type() string {
+	return TypeAnimation
+}
+
+// AddClip adds a clip to the component, replacing any clip with the same name.
+func (a *animation) AddClip(clip AnimationClip) {
+	a.dataLock.Lock()
+	defer a.dataLock.Unlock()
+	a.clips[clip.Name] = prepareClip(clip)
+}
+
+// Clip retrieves a clip by name.
+func (a *animation) Clip(name string) (AnimationClip, bool) {
+	a.dataLock.RLock()
+	defer a.dataLock.RUnlock()
+	clip, ok := a.clips[name]
+	return clip, ok
+}
+
+// Load loads the clips in an animation file and adds them to the component.
+func (a *animation) Load(fileName string) error {
+	clips, err := LoadAnimationClips(fileName)
+	if err != nil {
+		return err
+	}
+	for _, clip := range clips {
+		a.AddClip(clip)
+	}
+	return nil
+}
+
+// Play immediately starts playing the named clip from the beginning.
+func (a *animation) Play(name string) error {
+	a.dataLock.Lock()
+	defer a.dataLock.Unlock()
+
+	clip, ok := a.clips[name]
+	if !ok {
+		return fmt.Errorf("animation clip %s not found", name)
+	}
+	a.current = &animationState{clip: clip}
+	a.previous = nil
+	a.playing = true
+	return nil
+}
+
+// CrossFade blends from the currently playing clip to the named clip over the duration in seconds of clip time, so it is scaled by the playback speed.
+func (a *animation) CrossFade(name string, duration float32) error {
+	a.dataLock.Lock()
+	defer a.dataLock.Unlock()
+
+	clip, ok := a.clips[name]
+	if !ok {
+		return fmt.Errorf("animation clip %s not found", name)
+	}
+	if a.current != nil && duration > 0 {
+		a.previous = a.current
+		a.fadeTime = 0
+		a.fadeDuration = duration
+	} else {
+		a.previous = nil
+	}
+	a.current = &animationState{clip: clip}
+	a.playing = true
+	return nil
+}
+
+// Stop stops playback.
+func (a *animation) Stop() {
+	a.dataLock.Lock()
+	defer a.dataLock.Unlock()
+	a.playing = false
+	a.previous = nil
+}
+
+// IsPlaying returns true while a clip is being played.
+func (a *animation) IsPlaying() bool {
+	a.dataLock.RLock()
+	defer a.dataLock.RUnlock()
+	return a.playing
+}
+
+// Current retrieves the name of the clip being played.
+func (a *animation) Current() string {
+	a.dataLock.RLock()
+	defer a.dataLock.RUnlock()
+	if a.current == nil {
+		return ""
+	}
+	return a.current.clip.Name
+}
+
+// SetSpeed sets the playback speed multiplier.
+func (a *animation) SetSpeed(speed float32) {
+	a.dataLock.Lock()
+	defer a.dataLock.Unlock()
+	a.speed = speed
+}
+
+// Speed retrieves the playback speed multiplier.
+func (a *animation) Speed() float32 {
+	a.dataLock.RLock()
+	defer a.dataLock.RUnlock()
+	return a.speed
+}
+
+// OnEvent registers a function to be called whenever an event in a playing clip is reached.
+func (a *animation) OnEvent(handler func(clip string, event AnimationEvent)) {
+	a.dataLock.Lock()
+	defer a.dataLock.Unlock()
+	a.handlers = append(a.handlers, handler)
+}
+
+// BindProperty registers the function that receives the value of a property track.
+func (a *animation) BindProperty(name string, setter func(float32)) {
+	a.dataLock.Lock()
+	defer a.dataLock.Unlock()
+	a.setters[name] = setter
+}
+
+// Update advances playback by the elapsed seconds and returns the resulting pose.  Event handlers and property setters are called after the component is unlocked so they may safely call back into it.
+func (a *animation) Update(elapsed float32) (AnimationPose, bool) {
+	a.dataLock.Lock()
+
+	if !a.playing || a.current == nil {
+		a.dataLock.Unlock()
+		return AnimationPose{}, false
+	}
+
+	step := elapsed * a.speed
+	clipName := a.current.clip.Name
+	events := a.current.advance(step)
+	if a.previous != nil {
+		a.previous.advance(step)
+	}
+
+	pose := a.current.clip.Sample(a.current.localTime())
+	if a.previous != nil {
+		// The fade advances with the clips so it ends at the same point in them whatever the speed.
+		a.fadeTime += float32(math.Abs(float64(step)))
+		weight := a.fadeTime / a.fadeDuration
+		if weight >= 1 {
+			a.previous = nil
+		} else {
+			pose = BlendPoses(a.previous.clip.Sample(a.previous.localTime()), pose, weight)
+		}
+	}
+
+	if a.current.clip.Loop != LoopRepeat && a.current.clip.Loop != LoopPingPong && a.current.time >= a.current.clip.Duration {
+		a.playing = false
+	}
+
+	handlers := make([]func(string, AnimationEvent), len(a.handlers))
+	copy(handlers, a.handlers)
+	setters := make(map[string]func(float32), len(pose.Properties))
+	for name := range pose.Properties {
+		if setter, ok := a.setters[name]; ok {
+			setters[name] = setter
+		}
+	}
+	a.dataLock.Unlock()
+
+	for name, setter := range setters {
+		setter(pose.Properties[name])
+	}
+	for _, event := range events {
+		for _, handler := range handlers {
+			handler(clipName, event)
+		}
+	}
+
+	return pose, true
+}
+
+// advance moves the playhead forward and returns the events that were passed.
+func (s *animationState) advance(step float32) []AnimationEvent {
+	from := s.time
+	s.time += step
+	if s.clip.Loop != LoopRepeat && s.clip.Loop != LoopPingPong && s.time > s.clip.Duration {
+		s.time = s.clip.Duration
+	}
+	inclusive := !s.started
+	s.started = true
+	return s.clip.eventsBetween(from, s.time, inclusive)
+}
+
+// localTime maps the unbounded playhead onto the clip's timeline based on its loop mode.
+func (s *animationState) localTime() float32 {
+	return s.clip.localTime(s.time)
+}
+
+// localTime maps a time since playback started onto the clip's timeline based on its loop mode.
+func (c AnimationClip) localTime(t float32) float32 {
+	if c.Duration <= 0 {
+		return 0
+	}
+	switch c.Loop {
+	case LoopRepeat:
+		return float32(math.Mod(float64(t), float64(c.Duration)))
+	case LoopPingPong:
+		t = float32(math.Mod(float64(t), float64(2*c.Duration)))
+		if t > c.Duration {
+			return 2*c.Duration - t
+		}
+		return t
+	default:
+		return mgl32.Clamp(t, 0, c.Duration)
+	}
+}
+
+// eventsBetween finds the events whose time falls after from and up to and including to, taking looping into account.
+func (c AnimationClip) eventsBetween(from, to float32, inclusive bool) []AnimationEvent {
+	events := make([]AnimationEvent, 0)
+	if len(c.Events) == 0 {
+		return events
+	}
+	passed := func(t float32) bool {
+		return (t > from || (inclusive && t == from)) && t <= to
+	}
+
+	period := c.Duration
+	if c.Loop == LoopPingPong {
+		period = 2 * c.Duration
+	}
+	if period <= 0 || (c.Loop != LoopRepeat && c.Loop != LoopPingPong) {
+		for _, e := range c.Events {
+			if passed(e.Time) {
+				events = append(events, e)
+			}
+		}
+		return events
+	}
+
+	// Events are collected with the time playback reaches them so they can be raised in that order.
+	times := make([]float32, 0)
+	first := int(math.Floor(float64(from / period)))
+	last := int(math.Floor(float64(to / period)))
+	for cycle := first; cycle <= last; cycle++ {
+		base := float32(cycle) * period
+		for _, e := range c.Events {
+			if passed(base + e.Time) {
+				events = append(events, e)
+				times = append(times, base+e.Time)
+			}
+			// On the way back of a ping pong cycle the events are reached in reverse.
+			if c.Loop == LoopPingPong && e.Time < c.Duration && e.Time > 0 && passed(base+period-e.Time) {
+				events = append(events, e)
+				times = append(times, base+period-e.Time)
+			}
+		}
+	}
+	sort.Stable(eventsByTime{events, times})
+	return events
+}
+
+// eventsByTime sorts events by the time playback reached them.
+type eventsByTime struct {
+	events []AnimationEvent
+	times  []float32
+}
+
+func (e eventsByTime) Len() int           { return len(e.events) }
+func (e eventsByTime) Less(i, j int) bool { return e.times[i] < e.times[j] }
+func (e eventsByTime) Swap(i, j int) {
+	e.events[i], e.events[j] = e.events[j], e.events[i]
+	e.times[i], e.times[j] = e.times[j], e.times[i]
+}
+
+// Sample evaluates every track of the clip at a time on the clip's timeline.
+func (c AnimationClip) Sample(t float32) AnimationPose {
+	pose := newPose()
+
+	for _, track := range c.Tracks {
+		if len(track.Keys) == 0 {
+			continue
+		}
+		if track.Joint != "" {
+			joint, ok := pose.Joints[track.Joint]
+			if !ok {
+				joint = newPose()
+			}
+			track.sampleInto(&joint, t)
+			pose.Joints[track.Joint] = joint
+			continue
+		}
+		track.sampleInto(&pose, t)
+	}
+	return pose
+}
+
+// newPose creates an empty pose.
+func newPose() AnimationPose {
+	return AnimationPose{
+		Rotation:   mgl32.QuatIdent(),
+		Scale:      mgl32.Vec3{1, 1, 1},
+		Properties: make(map[string]float32),
+		Joints:     make(map[string]AnimationPose),
+	}
+}
+
+// sampleInto evaluates the track at a time and stores the result in the pose.
+func (track AnimationTrack) sampleInto(pose *AnimationPose, t float32) {
+	switch track.Target {
+	case TrackTranslation:
+		pose.Translation = toVec3(track.Sample(t))
+		pose.HasTranslation = true
+	case TrackRotation:
+		pose.Rotation = track.sampleRotation(t)
+		pose.HasRotation = true
+	case TrackScale:
+		pose.Scale = toVec3(track.Sample(t))
+		pose.HasScale = true
+	case TrackProperty:
+		if v := track.Sample(t); len(v) > 0 {
+			pose.Properties[track.Property] = v[0]
+		}
+	}
+}
+
+// Sample evaluates the track at a time using the track's interpolation.
+func (tr AnimationTrack) Sample(t float32) []float32 {
+	i, frac := tr.segment(t)
+	a := tr.Keys[i]
+	if frac == 0 || tr.Interpolation == InterpolationStep {
+		return a.Value
+	}
+	b := tr.Keys[i+1]
+
+	out := make([]float32, len(a.Value))
+	if tr.Interpolation == InterpolationCubic {
+		p0 := a
+		if i > 0 {
+			p0 = tr.Keys[i-1]
+		}
+		p3 := b
+		if i+2 < len(tr.Keys) {
+			p3 = tr.Keys[i+2]
+		}
+		for n := range out {
+			out[n] = catmullRom(component(p0.Value, n), component(a.Value, n), component(b.Value, n), component(p3.Value, n), frac)
+		}
+		return out
+	}
+
+	for n := range out {
+		out[n] = component(a.Value, n) + (component(b.Value, n)-component(a.Value, n))*frac
+	}
+	return out
+}
+
+// sampleRotation evaluates a rotation track as a quaternion.
+func (tr AnimationTrack) sampleRotation(t float32) mgl32.Quat {
+	i, frac := tr.segment(t)
+	a := keyQuat(tr.Keys[i].Value)
+	if frac == 0 || tr.Interpolation == InterpolationStep {
+		return a
+	}
+	return mgl32.QuatSlerp(a, keyQuat(tr.Keys[i+1].Value), frac)
+}
+
+// segment finds the keyframe at or before t and how far t is towards the next keyframe.
+func (tr AnimationTrack) segment(t float32) (int, float32) {
+	keys := tr.Keys
+	next := sort.Search(len(keys), func(i int) bool { return keys[i].Time > t })
+	if next == 0 {
+		return 0, 0
+	}
+	if next == len(keys) {
+		return len(keys) - 1, 0
+	}
+	i := next - 1
+	span := keys[next].Time - keys[i].Time
+	if span <= 0 {
+		return i, 0
+	}
+	return i, (t - keys[i].Time) / span
+}
+
+// BlendPoses blends two poses together where a weight of 0 returns a and a weight of 1 returns b.  Channels missing from one pose are taken from the other.
+func BlendPoses(a, b AnimationPose, weight float32) AnimationPose {
+	weight = mgl32.Clamp(weight, 0, 1)
+	out := AnimationPose{
+		Translation:    a.Translation,
+		Rotation:       a.Rotation,
+		Scale:          a.Scale,
+		Properties:     make(map[string]float32),
+		Joints:         make(map[string]AnimationPose),
+		HasTranslation: a.HasTranslation || b.HasTranslation,
+		HasRotation:    a.HasRotation || b.HasRotation,
+		HasScale:       a.HasScale || b.HasScale,
+	}
+
+	switch {
+	case a.HasTranslation && b.HasTranslation:
+		out.Translation = a.Translation.Add(b.Translation.Sub(a.Translation).Mul(weight))
+	case b.HasTranslation:
+		out.Translation = b.Translation
+	}
+	switch {
+	case a.HasRotation && b.HasRotation:
+		out.Rotation = mgl32.QuatSlerp(a.Rotation, b.Rotation, weight)
+	case b.HasRotation:
+		out.Rotation = b.Rotation
+	}
+	switch {
+	case a.HasScale && b.HasScale:
+		out.Scale = a.Scale.Add(b.Scale.Sub(a.Scale).Mul(weight))
+	case b.HasScale:
+		out.Scale = b.Scale
+	}
+
+	for name, v := range a.Properties {
+		out.Properties[name] = v
+	}
+	for name, v := range b.Properties {
+		if av, ok := a.Properties[name]; ok {
+			v = av + (v-av)*weight
+		}
+		out.Properties[name] = v
+	}
+
+	for name, joint := range a.Joints {
+		out.Joints[name] = joint
+	}
+	for name, joint := range b.Joints {
+		if aj, ok := a.Joints[name]; ok {
+			joint = BlendPoses(aj, joint, weight)
+		}
+		out.Joints[name] = joint
+	}
+	return out
+}
+
+// LoadAnimationClips loads the clips stored in an animation file.  Animation files are expected to be stored alongside the mesh files.
+func LoadAnimationClips(fileName string) ([]AnimationClip, error) {
+	fullFileName := ""
+	if strings.Contains(fileName, MeshSrcDir) {
+		fullFileName = fileName
+	} else {
+		fullFileName = fmt.Sprintf("%s%s", MeshSrcDir, fileName)
+	}
+
+	data, err := ioutil.ReadFile(fullFileName)
+	if err != nil {
+		fmt.Println(err.Error())
+		return nil, err
+	}
+
+	var af animationFile
+	err = json.Unmarshal(data, &af)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range af.Clips {
+		af.Clips[i] = prepareClip(af.Clips[i])
+	}
+	return af.Clips, nil
+}
+
+type animationFile struct {
+	Clips []AnimationClip `json:"clips"`
+}
+
+// prepareClip fills in the defaults of a clip and sorts its keyframes and events by time.
+func prepareClip(clip AnimationClip) AnimationClip {
+	if clip.Loop == "" {
+		clip.Loop = LoopOnce
+	}
+
+	tracks := make([]AnimationTrack, len(clip.Tracks))
+	for i, track := range clip.Tracks {
+		if track.Interpolation == "" {
+			track.Interpolation = InterpolationLinear
+		}
+		keys := make([]Keyframe, len(track.Keys))
+		copy(keys, track.Keys)
+		sort.SliceStable(keys, func(a, b int) bool { return keys[a].Time < keys[b].Time })
+		track.Keys = keys
+		tracks[i] = track
+
+		if n := len(keys); n > 0 && keys[n-1].Time > clip.Duration {
+			clip.Duration = keys[n-1].Time
+		}
+	}
+	clip.Tracks = tracks
+
+	events := make([]AnimationEvent, len(clip.Events))
+	copy(events, clip.Events)
+	sort.SliceStable(events, func(a, b int) bool { return events[a].Time < events[b].Time })
+	clip.Events = events
+
+	return clip
+}
+
+// keyQuat converts a rotation keyframe value into a quaternion.
+func keyQuat(v []float32) mgl32.Quat {
+	if len(v) >= 4 {
+		return mgl32.Quat{W: v[3], V: mgl32.Vec3{v[0], v[1], v[2]}}.Normalize()
+	}
+	return EulerToQuat(toVec3(v))
+}
+
+// catmullRom evaluates a Catmull-Rom spline segment between p1 and p2.
+func catmullRom(p0, p1, p2, p3, t float32) float32 {
+	t2 := t * t
+	t3 := t2 * t
+	return 0.5 * ((2 * p1) + (-p0+p2)*t + (2*p0-5*p1+4*p2-p3)*t2 + (-p0+3*p1-3*p2+p3)*t3)
+}
+
+// component safely retrieves a value from a keyframe.
+func component(v []float32, i int) float32 {
+	if i < len(v) {
+		return v[i]
+	}
+	return 0
+}
+
+// toVec3 converts a keyframe value into a Vec3.
+func toVec3(v []float32) mgl32.Vec3 {
+	return mgl32.Vec3{component(v, 0), component(v, 1), component(v, 2)}
+}
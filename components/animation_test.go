@@ -0,0 +1,247 @@
+package components
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const epsilon = 1e-4
+
+func keys(values ...float32) []Keyframe {
+	keys := make([]Keyframe, len(values))
+	for i, v := range values {
+		keys[i] = Keyframe{Time: float32(i), Value: []float32{v}}
+	}
+	return keys
+}
+
+func TestTrackSample(t *testing.T) {
+	tests := []struct {
+		name          string
+		interpolation Interpolation
+		keys          []Keyframe
+		time          float32
+		want          float32
+	}{
+		{"linear before first key", InterpolationLinear, keys(2, 4), -1, 2},
+		{"linear on key", InterpolationLinear, keys(2, 4), 1, 4},
+		{"linear between keys", InterpolationLinear, keys(2, 4), 0.25, 2.5},
+		{"linear after last key", InterpolationLinear, keys(2, 4), 3, 4},
+		{"step holds the key", InterpolationStep, keys(2, 4, 8), 0.99, 2},
+		{"step reaches the next key", InterpolationStep, keys(2, 4, 8), 1, 4},
+		{"cubic on key", InterpolationCubic, keys(0, 1, 4, 9), 2, 4},
+		{"cubic keeps a straight line", InterpolationCubic, keys(0, 1, 2, 3), 1.5, 1.5},
+		{"cubic first segment", InterpolationCubic, keys(0, 1, 4, 9), 0.5, 0.3125},
+		{"cubic middle segment", InterpolationCubic, keys(0, 1, 4, 9), 1.5, 2.25},
+	}
+	for _, test := range tests {
+		track := AnimationTrack{Target: TrackProperty, Interpolation: test.interpolation, Keys: test.keys}
+		got := track.Sample(test.time)
+		if len(got) != 1 || !mgl32.FloatEqualThreshold(got[0], test.want, epsilon) {
+			t.Errorf("%s: Sample(%v) = %v, want %v", test.name, test.time, got, test.want)
+		}
+	}
+}
+
+func TestTrackSampleRotation(t *testing.T) {
+	quarter := mgl32.QuatRotate(mgl32.DegToRad(90), mgl32.Vec3{0, 0, 1})
+	tests := []struct {
+		name          string
+		interpolation Interpolation
+		time          float32
+		want          mgl32.Quat
+	}{
+		{"linear start", InterpolationLinear, 0, mgl32.QuatIdent()},
+		{"linear halfway", InterpolationLinear, 0.5, mgl32.QuatRotate(mgl32.DegToRad(45), mgl32.Vec3{0, 0, 1})},
+		{"linear end", InterpolationLinear, 1, quarter},
+		{"step halfway", InterpolationStep, 0.5, mgl32.QuatIdent()},
+		{"cubic halfway", InterpolationCubic, 0.5, mgl32.QuatRotate(mgl32.DegToRad(45), mgl32.Vec3{0, 0, 1})},
+	}
+	for _, test := range tests {
+		track := AnimationTrack{Target: TrackRotation, Interpolation: test.interpolation, Keys: []Keyframe{
+			{Time: 0, Value: []float32{0, 0, 0}},
+			{Time: 1, Value: []float32{quarter.X(), quarter.Y(), quarter.Z(), quarter.W}},
+		}}
+		if got := track.sampleRotation(test.time); !got.ApproxEqualThreshold(test.want, epsilon) {
+			t.Errorf("%s: sampleRotation(%v) = %v, want %v", test.name, test.time, got, test.want)
+		}
+	}
+}
+
+func TestClipLocalTime(t *testing.T) {
+	tests := []struct {
+		loop LoopMode
+		time float32
+		want float32
+	}{
+		{LoopOnce, 0.5, 0.5},
+		{LoopOnce, 3, 2},
+		{LoopOnce, -1, 0},
+		{LoopRepeat, 0.5, 0.5},
+		{LoopRepeat, 2.5, 0.5},
+		{LoopRepeat, 5, 1},
+		{LoopPingPong, 1.5, 1.5},
+		{LoopPingPong, 2.5, 1.5},
+		{LoopPingPong, 3.5, 0.5},
+		{LoopPingPong, 4.5, 0.5},
+	}
+	for _, test := range tests {
+		clip := AnimationClip{Duration: 2, Loop: test.loop}
+		if got := clip.localTime(test.time); !mgl32.FloatEqualThreshold(got, test.want, epsilon) {
+			t.Errorf("%s: localTime(%v) = %v, want %v", test.loop, test.time, got, test.want)
+		}
+	}
+}
+
+func TestAnimationLoopModes(t *testing.T) {
+	tests := []struct {
+		loop    LoopMode
+		steps   []float32
+		want    float32
+		playing bool
+	}{
+		{LoopOnce, []float32{0.5, 0.25}, 1.5, true},
+		{LoopOnce, []float32{0.5, 0.75}, 2, false},
+		{LoopRepeat, []float32{0.5, 0.5, 0.5}, 1, true},
+		{LoopPingPong, []float32{0.5, 0.5, 0.5}, 1, true},
+		{LoopPingPong, []float32{0.5, 0.5, 0.5, 0.5}, 0, true},
+	}
+	for _, test := range tests {
+		a := NewAnimation()
+		a.AddClip(AnimationClip{Name: "move", Loop: test.loop, Tracks: []AnimationTrack{
+			{Target: TrackTranslation, Keys: []Keyframe{{Time: 0, Value: []float32{0, 0, 0}}, {Time: 1, Value: []float32{2, 0, 0}}}},
+		}})
+		a.Play("move")
+		var pose AnimationPose
+		for _, step := range test.steps {
+			pose, _ = a.Update(step)
+		}
+		if got := pose.Translation.X(); !mgl32.FloatEqualThreshold(got, test.want, epsilon) {
+			t.Errorf("%s after %v: x = %v, want %v", test.loop, test.steps, got, test.want)
+		}
+		if a.IsPlaying() != test.playing {
+			t.Errorf("%s after %v: playing = %v, want %v", test.loop, test.steps, a.IsPlaying(), test.playing)
+		}
+	}
+}
+
+func TestBlendPoses(t *testing.T) {
+	a := newPose()
+	a.Translation, a.HasTranslation = mgl32.Vec3{0, 0, 0}, true
+	a.Scale, a.HasScale = mgl32.Vec3{1, 1, 1}, true
+	a.Properties["alpha"] = 0
+	a.Joints["arm"] = AnimationPose{Translation: mgl32.Vec3{0, 0, 0}, HasTranslation: true}
+
+	b := newPose()
+	b.Translation, b.HasTranslation = mgl32.Vec3{4, 0, 0}, true
+	b.Rotation, b.HasRotation = mgl32.QuatRotate(mgl32.DegToRad(90), mgl32.Vec3{0, 0, 1}), true
+	b.Properties["alpha"] = 1
+	b.Properties["beta"] = 5
+	b.Joints["arm"] = AnimationPose{Translation: mgl32.Vec3{0, 2, 0}, HasTranslation: true}
+
+	tests := []struct {
+		weight      float32
+		translation mgl32.Vec3
+		alpha       float32
+		arm         mgl32.Vec3
+	}{
+		{0, mgl32.Vec3{0, 0, 0}, 0, mgl32.Vec3{0, 0, 0}},
+		{0.25, mgl32.Vec3{1, 0, 0}, 0.25, mgl32.Vec3{0, 0.5, 0}},
+		{1, mgl32.Vec3{4, 0, 0}, 1, mgl32.Vec3{0, 2, 0}},
+		{2, mgl32.Vec3{4, 0, 0}, 1, mgl32.Vec3{0, 2, 0}},
+	}
+	for _, test := range tests {
+		pose := BlendPoses(a, b, test.weight)
+		if !pose.Translation.ApproxEqualThreshold(test.translation, epsilon) {
+			t.Errorf("weight %v: translation = %v, want %v", test.weight, pose.Translation, test.translation)
+		}
+		if !mgl32.FloatEqualThreshold(pose.Properties["alpha"], test.alpha, epsilon) {
+			t.Errorf("weight %v: alpha = %v, want %v", test.weight, pose.Properties["alpha"], test.alpha)
+		}
+		if arm := pose.Joints["arm"].Translation; !arm.ApproxEqualThreshold(test.arm, epsilon) {
+			t.Errorf("weight %v: arm = %v, want %v", test.weight, arm, test.arm)
+		}
+		// Channels only one pose has are taken from it whatever the weight.
+		if !pose.HasRotation || !pose.Rotation.ApproxEqualThreshold(b.Rotation, epsilon) {
+			t.Errorf("weight %v: rotation = %v, want %v", test.weight, pose.Rotation, b.Rotation)
+		}
+		if !pose.HasScale || pose.Scale != a.Scale {
+			t.Errorf("weight %v: scale = %v, want %v", test.weight, pose.Scale, a.Scale)
+		}
+		if pose.Properties["beta"] != 5 {
+			t.Errorf("weight %v: beta = %v, want 5", test.weight, pose.Properties["beta"])
+		}
+	}
+}
+
+func TestAnimationEvents(t *testing.T) {
+	events := []AnimationEvent{{Time: 0, Name: "start"}, {Time: 0.5, Name: "mid"}, {Time: 1, Name: "end"}}
+	tests := []struct {
+		name  string
+		loop  LoopMode
+		steps []float32
+		want  []string
+	}{
+		{"once", LoopOnce, []float32{0.4, 0.4, 0.4}, []string{"start", "mid", "end"}},
+		{"once past the end", LoopOnce, []float32{0.9, 0.9, 0.9}, []string{"start", "mid", "end"}},
+		{"repeat wraps", LoopRepeat, []float32{0.75, 0.5}, []string{"start", "mid", "end", "start"}},
+		{"repeat wraps several times in a step", LoopRepeat, []float32{0.25, 2}, []string{"start", "mid", "end", "start", "mid", "end", "start"}},
+		{"pingpong reverses", LoopPingPong, []float32{0.75, 1}, []string{"start", "mid", "end", "mid"}},
+		{"pingpong turns back to the start", LoopPingPong, []float32{1.25, 1}, []string{"start", "mid", "end", "mid", "start"}},
+	}
+	for _, test := range tests {
+		a := NewAnimation()
+		a.AddClip(AnimationClip{Name: "clip", Duration: 1, Loop: test.loop, Events: events})
+		var got []string
+		a.OnEvent(func(clip string, event AnimationEvent) {
+			if clip != "clip" {
+				t.Errorf("%s: event from clip %s", test.name, clip)
+			}
+			got = append(got, event.Name)
+		})
+		a.Play("clip")
+		for _, step := range test.steps {
+			a.Update(step)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: events = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestAnimationCrossFade(t *testing.T) {
+	hold := func(name string, x float32) AnimationClip {
+		return AnimationClip{Name: name, Duration: 10, Loop: LoopRepeat, Tracks: []AnimationTrack{
+			{Target: TrackTranslation, Keys: []Keyframe{{Time: 0, Value: []float32{x, 0, 0}}}},
+		}}
+	}
+	tests := []struct {
+		speed float32
+		steps []float32
+		want  float32
+	}{
+		{1, []float32{0.5}, 5},
+		{1, []float32{1}, 10},
+		{2, []float32{0.25}, 5},
+		{2, []float32{0.5}, 10},
+		{0.5, []float32{1}, 5},
+		{0.5, []float32{1, 1}, 10},
+	}
+	for _, test := range tests {
+		a := NewAnimation()
+		a.AddClip(hold("from", 0))
+		a.AddClip(hold("to", 10))
+		a.SetSpeed(test.speed)
+		a.Play("from")
+		a.CrossFade("to", 1)
+		var pose AnimationPose
+		for _, step := range test.steps {
+			pose, _ = a.Update(step)
+		}
+		if got := pose.Translation.X(); !mgl32.FloatEqualThreshold(got, test.want, epsilon) {
+			t.Errorf("speed %v after %v: x = %v, want %v", test.speed, test.steps, got, test.want)
+		}
+	}
+}
@@ -1,7 +1,13 @@
 package components
 
 import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
 	"github.com/go-gl/mathgl/mgl32"
+
+	"github.com/Ariemeth/quantum-pulse/geometry"
 )
 
 const (
@@ -9,16 +15,41 @@ const (
 	TypeCamera = "Camera"
 )
 
-// Camera represents the behaviors of any camera.
+// Camera represents the behaviors of any camera.  A camera draws into a viewport of the window, so several cameras can share the window for split screen, picture-in-picture or minimap views.
 type Camera interface {
+	Component
 	// SetView sets the view matrix.
 	SetView(camEye, camLookAt, camUp [3]float32)
+	// SetViewMatrix sets the view matrix directly, such as from the inverse of a transform.
+	SetViewMatrix(view mgl32.Mat4)
 	// SetProjection sets the projection matrix.
 	SetProjection(fovY, nearPlane, farPlane float32, windowWidth, windowHeight int)
+	// SetPerspective switches to a perspective projection with a vertical field of view in degrees.
+	SetPerspective(fovY, nearPlane, farPlane float32)
+	// SetOrthographic switches to an orthographic projection showing a height in world units, with the width following the viewport's aspect ratio.
+	SetOrthographic(size, nearPlane, farPlane float32)
+	// SetOrthographicBounds switches to an orthographic projection showing exactly the bounds passed in.
+	SetOrthographicBounds(left, right, bottom, top, nearPlane, farPlane float32)
+	// SetPixelPerfect switches to an orthographic projection where one world unit is one pixel of the viewport.
+	SetPixelPerfect(nearPlane, farPlane float32)
+	// ProjectionKind retrieves the kind of projection the camera uses.
+	ProjectionKind() ProjectionKind
+	// SetProjectionKind switches the kind of projection, keeping the settings each kind was last given.
+	SetProjectionKind(ProjectionKind)
+	// OrthographicSize retrieves the height in world units an orthographic projection shows.
+	OrthographicSize() float32
+	// SetOrthographicSize sets the height in world units an orthographic projection shows without changing the kind of projection.
+	SetOrthographicSize(float32)
+	// OrthographicBounds retrieves the bounds an orthographic projection shows as left, right, bottom and top.
+	OrthographicBounds() (left, right, bottom, top float32)
+	// Resize recalculates the projection for a new window size.
+	Resize(windowWidth, windowHeight int)
 	// Projection retrieves the projection matrix.
 	Projection() mgl32.Mat4
 	// View retrieves the view matrix.
 	View() mgl32.Mat4
+	// Frustum retrieves the volume the camera can see in world space.
+	Frustum() geometry.Frustum
 	// PositionVec3 retrieves the camera position.
 	PositionVec3() mgl32.Vec3
 	// LookAtVec3 retrieves the point the camera is looking.
@@ -35,6 +66,121 @@ type Camera interface {
 	WindowHeight() int
 	// WindowWidth retrieves the window width used to calculate the perspective.
 	WindowWidth() int
+	// Viewport retrieves the part of the window the camera draws into.
+	Viewport() Viewport
+	// SetViewport sets the part of the window the camera draws into.
+	SetViewport(Viewport)
+	// ClearFlags retrieves which buffers are cleared before the camera draws.
+	ClearFlags() ClearFlags
+	// SetClearFlags sets which buffers are cleared before the camera draws.
+	SetClearFlags(ClearFlags)
+	// ClearColor retrieves the color the viewport is cleared to.
+	ClearColor() mgl32.Vec4
+	// SetClearColor sets the color the viewport is cleared to.
+	SetClearColor(mgl32.Vec4)
+	// Order retrieves the camera's place in the drawing order.  Cameras with a lower order draw first.
+	Order() int
+	// SetOrder sets the camera's place in the drawing order.
+	SetOrder(int)
+	// Target retrieves the name of the render target the camera draws into, or "" if it draws into the window.
+	Target() string
+	// SetTarget sets the name of the render target the camera draws into.  An empty name draws into the window.
+	SetTarget(string)
+	// Enabled returns true if the camera draws.
+	Enabled() bool
+	// SetEnabled sets whether the camera draws.
+	SetEnabled(bool)
+	// Contains returns true if a point on the screen is inside the camera's viewport.
+	Contains(x, y float64) bool
+	// ScreenToRay creates a world space ray passing through a point on the screen, such as the cursor position.
+	ScreenToRay(x, y float64) (geometry.Ray, bool)
+}
+
+// Viewport is a rectangle of the window as fractions of its size, measured from the bottom left corner as OpenGL does.  Fractions keep the viewport in place when the window is resized.
+type Viewport struct {
+	X      float32 `json:"x"`
+	Y      float32 `json:"y"`
+	Width  float32 `json:"width"`
+	Height float32 `json:"height"`
+}
+
+// FullViewport creates a viewport covering the whole window.
+func FullViewport() Viewport {
+	return Viewport{Width: 1, Height: 1}
+}
+
+// Pixels retrieves the viewport in pixels from the bottom left corner of a window or framebuffer.
+func (v Viewport) Pixels(width, height int) (x, y, w, h int32) {
+	x = int32(v.X*float32(width) + 0.5)
+	y = int32(v.Y*float32(height) + 0.5)
+	w = int32((v.X+v.Width)*float32(width)+0.5) - x
+	h = int32((v.Y+v.Height)*float32(height)+0.5) - y
+	return x, y, w, h
+}
+
+// screenRect retrieves the viewport in pixels from the top left corner of the window, which is how cursor positions are measured.
+func (v Viewport) screenRect(width, height int) (left, top, w, h float32) {
+	w = v.Width * float32(width)
+	h = v.Height * float32(height)
+	return v.X * float32(width), (1 - v.Y - v.Height) * float32(height), w, h
+}
+
+// ProjectionKind is the kind of projection a camera uses.
+type ProjectionKind string
+
+const (
+	// ProjectionPerspective makes distant objects smaller.
+	ProjectionPerspective ProjectionKind = "perspective"
+	// ProjectionOrthographic keeps objects the same size at any distance, showing a size or bounds in world units.
+	ProjectionOrthographic ProjectionKind = "orthographic"
+	// ProjectionPixel is an orthographic projection with one world unit per pixel, from 0 at the viewport's bottom left corner to its width and height at the top right.
+	ProjectionPixel ProjectionKind = "pixel"
+)
+
+// ClearFlags selects the buffers a camera clears before drawing.
+type ClearFlags int
+
+const (
+	// ClearColorBuffer clears the viewport to the camera's clear color.
+	ClearColorBuffer ClearFlags = 1 << iota
+	// ClearDepthBuffer clears the depth of the viewport so the camera draws over what is already there.
+	ClearDepthBuffer
+	// ClearNone clears nothing.
+	ClearNone ClearFlags = 0
+	// ClearAll clears both the color and depth.
+	ClearAll = ClearColorBuffer | ClearDepthBuffer
+)
+
+// MarshalJSON encodes the flags as a list such as ["color", "depth"].
+func (f ClearFlags) MarshalJSON() ([]byte, error) {
+	names := []string{}
+	if f&ClearColorBuffer != 0 {
+		names = append(names, "color")
+	}
+	if f&ClearDepthBuffer != 0 {
+		names = append(names, "depth")
+	}
+	return json.Marshal(names)
+}
+
+// UnmarshalJSON decodes the flags from a list such as ["color", "depth"].
+func (f *ClearFlags) UnmarshalJSON(data []byte) error {
+	var names []string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return err
+	}
+	*f = ClearNone
+	for _, name := range names {
+		switch name {
+		case "color":
+			*f |= ClearColorBuffer
+		case "depth":
+			*f |= ClearDepthBuffer
+		default:
+			return fmt.Errorf("unknown clear flag %q", name)
+		}
+	}
+	return nil
 }
 
 type camera struct {
@@ -48,13 +194,30 @@ type camera struct {
 	viewMat       mgl32.Mat4
 	windowHeight  int
 	windowWidth   int
+	projection    ProjectionKind
+	orthoSize     float32
+	orthoBounds   [4]float32
+	hasBounds     bool
+	viewport      Viewport
+	clearFlags    ClearFlags
+	clearColor    mgl32.Vec4
+	order         int
+	target        string
+	enabled       bool
+	dataLock      sync.RWMutex
 }
 
-// NewCamera creates a new Camera component.
+// NewCamera creates a new Camera component that draws to the whole window.
 func NewCamera() Camera {
 	c := camera{
 		projectionMat: mgl32.Ident4(),
 		viewMat:       mgl32.Ident4(),
+		projection:    ProjectionPerspective,
+		orthoSize:     10,
+		viewport:      FullViewport(),
+		clearFlags:    ClearAll,
+		clearColor:    mgl32.Vec4{0.5, 0.5, 0.5, 1.0},
+		enabled:       true,
 	}
 
 	return &c
@@ -67,68 +230,345 @@ func (c *camera) Type() string {
 
 // SetView sets the view matrix.
 func (c *camera) SetView(camEye, camLookAt, camUp [3]float32) {
+	c.dataLock.Lock()
+	defer c.dataLock.Unlock()
 	c.position = mgl32.Vec3{camEye[0], camEye[1], camEye[2]}
 	c.lookAt = mgl32.Vec3{camLookAt[0], camLookAt[1], camLookAt[2]}
 	c.up = mgl32.Vec3{camUp[0], camUp[1], camUp[2]}
 	c.viewMat = mgl32.LookAtV(c.position, c.lookAt, c.up)
 }
 
-// SetProjection sets the projection matrix.
+// SetViewMatrix sets the view matrix directly.  The position, look at point and up vector are worked out from the matrix, where the camera looks down its -Z axis.
+func (c *camera) SetViewMatrix(view mgl32.Mat4) {
+	c.dataLock.Lock()
+	defer c.dataLock.Unlock()
+	c.viewMat = view
+	world := view.Inv()
+	c.position = world.Col(3).Vec3()
+	c.lookAt = c.position.Sub(world.Col(2).Vec3().Normalize())
+	c.up = world.Col(1).Vec3().Normalize()
+}
+
+// SetProjection sets the projection matrix to a perspective projection with a vertical field of view in degrees.
 func (c *camera) SetProjection(fovY, nearPlane, farPlane float32, windowWidth, windowHeight int) {
+	c.dataLock.Lock()
+	defer c.dataLock.Unlock()
+
 	// Set the perspective
 	c.fovY = mgl32.DegToRad(fovY)
 	c.nearPlane, c.farPlane = nearPlane, farPlane
 	c.windowHeight, c.windowWidth = windowHeight, windowWidth
+	c.projection = ProjectionPerspective
+	c.updateProjection()
+}
+
+// SetPerspective switches to a perspective projection with a vertical field of view in degrees.
+func (c *camera) SetPerspective(fovY, nearPlane, farPlane float32) {
+	c.dataLock.Lock()
+	defer c.dataLock.Unlock()
+	c.fovY = mgl32.DegToRad(fovY)
+	c.nearPlane, c.farPlane = nearPlane, farPlane
+	c.projection = ProjectionPerspective
+	c.updateProjection()
+}
+
+// SetOrthographic switches to an orthographic projection showing a height in world units centered on the view, with the width following the viewport's aspect ratio.
+func (c *camera) SetOrthographic(size, nearPlane, farPlane float32) {
+	c.dataLock.Lock()
+	defer c.dataLock.Unlock()
+	c.orthoSize = size
+	c.hasBounds = false
+	c.nearPlane, c.farPlane = nearPlane, farPlane
+	c.projection = ProjectionOrthographic
+	c.updateProjection()
+}
+
+// SetOrthographicBounds switches to an orthographic projection showing exactly the bounds passed in, which are relative to the view and stretch if their aspect ratio differs from the viewport's.
+func (c *camera) SetOrthographicBounds(left, right, bottom, top, nearPlane, farPlane float32) {
+	c.dataLock.Lock()
+	defer c.dataLock.Unlock()
+	c.orthoBounds = [4]float32{left, right, bottom, top}
+	c.hasBounds = true
+	c.nearPlane, c.farPlane = nearPlane, farPlane
+	c.projection = ProjectionOrthographic
+	c.updateProjection()
+}
+
+// SetPixelPerfect switches to an orthographic projection where one world unit is one pixel of the viewport.  A camera at the origin looking down -Z with +Y up shows X from 0 to the viewport width and Y from 0 to its height, which suits 2D scenes and user interfaces.
+func (c *camera) SetPixelPerfect(nearPlane, farPlane float32) {
+	c.dataLock.Lock()
+	defer c.dataLock.Unlock()
+	c.nearPlane, c.farPlane = nearPlane, farPlane
+	c.projection = ProjectionPixel
+	c.updateProjection()
+}
+
+// ProjectionKind retrieves the kind of projection the camera uses.
+func (c *camera) ProjectionKind() ProjectionKind {
+	c.dataLock.RLock()
+	defer c.dataLock.RUnlock()
+	return c.projection
+}
 
-	c.projectionMat = mgl32.Perspective(c.fovY, float32(windowWidth)/float32(windowHeight), nearPlane, farPlane)
+// SetProjectionKind switches the kind of projection, keeping the field of view, size or bounds each kind was last given.
+func (c *camera) SetProjectionKind(kind ProjectionKind) {
+	c.dataLock.Lock()
+	defer c.dataLock.Unlock()
+	c.projection = kind
+	c.updateProjection()
+}
+
+// OrthographicSize retrieves the height in world units an orthographic projection shows.
+func (c *camera) OrthographicSize() float32 {
+	c.dataLock.RLock()
+	defer c.dataLock.RUnlock()
+	return c.orthoSize
+}
+
+// SetOrthographicSize sets the height in world units an orthographic projection shows without changing the kind of projection, replacing any explicit bounds.  Zooming an orthographic camera is done by changing its size.
+func (c *camera) SetOrthographicSize(size float32) {
+	c.dataLock.Lock()
+	defer c.dataLock.Unlock()
+	c.orthoSize = size
+	c.hasBounds = false
+	c.updateProjection()
+}
+
+// OrthographicBounds retrieves the bounds an orthographic projection shows as left, right, bottom and top.  Bounds worked out from the size depend on the viewport's aspect ratio.
+func (c *camera) OrthographicBounds() (left, right, bottom, top float32) {
+	c.dataLock.RLock()
+	defer c.dataLock.RUnlock()
+	b := c.orthographicBounds()
+	return b[0], b[1], b[2], b[3]
+}
+
+// orthographicBounds retrieves the bounds of the orthographic projection.  The caller is expected to hold a lock.
+func (c *camera) orthographicBounds() [4]float32 {
+	if c.hasBounds {
+		return c.orthoBounds
+	}
+	w := c.viewport.Width * float32(c.windowWidth)
+	h := c.viewport.Height * float32(c.windowHeight)
+	halfHeight := c.orthoSize / 2
+	halfWidth := halfHeight
+	if h > 0 {
+		halfWidth *= w / h
+	}
+	return [4]float32{-halfWidth, halfWidth, -halfHeight, halfHeight}
+}
+
+// Resize recalculates the projection for a new window size.
+func (c *camera) Resize(windowWidth, windowHeight int) {
+	c.dataLock.Lock()
+	defer c.dataLock.Unlock()
+	c.windowHeight, c.windowWidth = windowHeight, windowWidth
+	c.updateProjection()
+}
+
+// updateProjection recalculates the projection matrix using the aspect ratio of the viewport.  The projection is left alone until the window size is known, except for explicit orthographic bounds which do not depend on it.  The caller is expected to hold the write lock.
+func (c *camera) updateProjection() {
+	w := c.viewport.Width * float32(c.windowWidth)
+	h := c.viewport.Height * float32(c.windowHeight)
+	if (w <= 0 || h <= 0) && !(c.projection == ProjectionOrthographic && c.hasBounds) {
+		return
+	}
+
+	switch c.projection {
+	case ProjectionOrthographic:
+		b := c.orthographicBounds()
+		c.projectionMat = mgl32.Ortho(b[0], b[1], b[2], b[3], c.nearPlane, c.farPlane)
+	case ProjectionPixel:
+		c.projectionMat = mgl32.Ortho(0, w, 0, h, c.nearPlane, c.farPlane)
+	default:
+		c.projectionMat = mgl32.Perspective(c.fovY, w/h, c.nearPlane, c.farPlane)
+	}
 }
 
 // Projection retrieves the projection matrix.
-func (c camera) Projection() mgl32.Mat4 {
+func (c *camera) Projection() mgl32.Mat4 {
+	c.dataLock.RLock()
+	defer c.dataLock.RUnlock()
 	return c.projectionMat
 }
 
 // View retrieves the view matrix.
-func (c camera) View() mgl32.Mat4 {
+func (c *camera) View() mgl32.Mat4 {
+	c.dataLock.RLock()
+	defer c.dataLock.RUnlock()
 	return c.viewMat
 }
 
+// Frustum retrieves the volume the camera can see in world space, extracted from the projection * view matrix.
+func (c *camera) Frustum() geometry.Frustum {
+	c.dataLock.RLock()
+	defer c.dataLock.RUnlock()
+	return geometry.FrustumFromMatrix(c.projectionMat.Mul4(c.viewMat))
+}
+
 // PositionVec3 retrieves the camera position.
-func (c camera) PositionVec3() mgl32.Vec3 {
+func (c *camera) PositionVec3() mgl32.Vec3 {
+	c.dataLock.RLock()
+	defer c.dataLock.RUnlock()
 	return c.position
 }
 
 // LookAtVec3 retrieves the point the camera is looking.
-func (c camera) LookAtVec3() mgl32.Vec3 {
+func (c *camera) LookAtVec3() mgl32.Vec3 {
+	c.dataLock.RLock()
+	defer c.dataLock.RUnlock()
 	return c.lookAt
 }
 
 // UpVec3 retrieves the camera's up vector.
-func (c camera) UpVec3() mgl32.Vec3 {
+func (c *camera) UpVec3() mgl32.Vec3 {
+	c.dataLock.RLock()
+	defer c.dataLock.RUnlock()
 	return c.up
 }
 
 // FOVy retrieves the camera's FOVY.
-func (c camera) FOVy() float32 {
+func (c *camera) FOVy() float32 {
+	c.dataLock.RLock()
+	defer c.dataLock.RUnlock()
 	return c.fovY
 }
 
 // NearPlane retrieves the cameras current near plane.
-func (c camera) NearPlane() float32 {
+func (c *camera) NearPlane() float32 {
+	c.dataLock.RLock()
+	defer c.dataLock.RUnlock()
 	return c.nearPlane
 }
 
 // FarPlane retrieves the camera's current far plane.
-func (c camera) FarPlane() float32 {
+func (c *camera) FarPlane() float32 {
+	c.dataLock.RLock()
+	defer c.dataLock.RUnlock()
 	return c.farPlane
 }
 
 // WindowHeight retrieves the window height used to calculate the perspective.
-func (c camera) WindowHeight() int {
+func (c *camera) WindowHeight() int {
+	c.dataLock.RLock()
+	defer c.dataLock.RUnlock()
 	return c.windowHeight
 }
 
 // WindowWidth retrieves the window width used to calculate the perspective.
-func (c camera) WindowWidth() int {
+func (c *camera) WindowWidth() int {
+	c.dataLock.RLock()
+	defer c.dataLock.RUnlock()
 	return c.windowWidth
 }
+
+// Viewport retrieves the part of the window the camera draws into.
+func (c *camera) Viewport() Viewport {
+	c.dataLock.RLock()
+	defer c.dataLock.RUnlock()
+	return c.viewport
+}
+
+// SetViewport sets the part of the window the camera draws into and recalculates the projection for its aspect ratio.
+func (c *camera) SetViewport(viewport Viewport) {
+	c.dataLock.Lock()
+	defer c.dataLock.Unlock()
+	c.viewport = viewport
+	c.updateProjection()
+}
+
+// ClearFlags retrieves which buffers are cleared before the camera draws.
+func (c *camera) ClearFlags() ClearFlags {
+	c.dataLock.RLock()
+	defer c.dataLock.RUnlock()
+	return c.clearFlags
+}
+
+// SetClearFlags sets which buffers are cleared before the camera draws.
+func (c *camera) SetClearFlags(flags ClearFlags) {
+	c.dataLock.Lock()
+	defer c.dataLock.Unlock()
+	c.clearFlags = flags
+}
+
+// ClearColor retrieves the color the viewport is cleared to.
+func (c *camera) ClearColor() mgl32.Vec4 {
+	c.dataLock.RLock()
+	defer c.dataLock.RUnlock()
+	return c.clearColor
+}
+
+// SetClearColor sets the color the viewport is cleared to.
+func (c *camera) SetClearColor(color mgl32.Vec4) {
+	c.dataLock.Lock()
+	defer c.dataLock.Unlock()
+	c.clearColor = color
+}
+
+// Order retrieves the camera's place in the drawing order.  Cameras with a lower order draw first, so later cameras draw over them.
+func (c *camera) Order() int {
+	c.dataLock.RLock()
+	defer c.dataLock.RUnlock()
+	return c.order
+}
+
+// SetOrder sets the camera's place in the drawing order.
+func (c *camera) SetOrder(order int) {
+	c.dataLock.Lock()
+	defer c.dataLock.Unlock()
+	c.order = order
+}
+
+// Target retrieves the name of the render target the camera draws into, or "" if it draws into the window.
+func (c *camera) Target() string {
+	c.dataLock.RLock()
+	defer c.dataLock.RUnlock()
+	return c.target
+}
+
+// SetTarget sets the name of the render target the camera draws into.  An empty name draws into the window.  Cameras drawing into a render target draw before those drawing into the window, so meshes showing the render target show the current frame.
+func (c *camera) SetTarget(target string) {
+	c.dataLock.Lock()
+	defer c.dataLock.Unlock()
+	c.target = target
+}
+
+// Enabled returns true if the camera draws.
+func (c *camera) Enabled() bool {
+	c.dataLock.RLock()
+	defer c.dataLock.RUnlock()
+	return c.enabled
+}
+
+// SetEnabled sets whether the camera draws.
+func (c *camera) SetEnabled(enabled bool) {
+	c.dataLock.Lock()
+	defer c.dataLock.Unlock()
+	c.enabled = enabled
+}
+
+// Contains returns true if a point on the screen is inside the camera's viewport.  Coordinates are in pixels from the top left corner of the window.
+func (c *camera) Contains(x, y float64) bool {
+	c.dataLock.RLock()
+	defer c.dataLock.RUnlock()
+	left, top, w, h := c.viewport.screenRect(c.windowWidth, c.windowHeight)
+	px, py := float32(x), float32(y)
+	return px >= left && py >= top && px < left+w && py < top+h
+}
+
+// ScreenToRay creates a world space ray passing through a point on the screen.  Coordinates are in pixels from the top left corner of the window and are taken relative to the camera's viewport.  The boolean is false if the camera has no projection.
+func (c *camera) ScreenToRay(x, y float64) (geometry.Ray, bool) {
+	c.dataLock.RLock()
+	defer c.dataLock.RUnlock()
+	left, top, w, h := c.viewport.screenRect(c.windowWidth, c.windowHeight)
+	if w <= 0 || h <= 0 {
+		return geometry.Ray{}, false
+	}
+	// Scale the viewport up to whole pixels so the point keeps its place relative to the viewport's size.
+	sx, sy := float32(x)-left, float32(y)-top
+	width, height := int(w+0.5), int(h+0.5)
+	if width > 0 && height > 0 {
+		sx *= float32(width) / w
+		sy *= float32(height) / h
+	}
+	return geometry.ScreenToRay(sx, sy, width, height, c.viewMat, c.projectionMat)
+}
@@ -0,0 +1,107 @@
+package components
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+
+	"github.com/Ariemeth/quantum-pulse/input"
+)
+
+const (
+	// TypeCameraController represents a camera controller component's type.
+	TypeCameraController = "cameraController"
+)
+
+// CameraControllerKind is the way a camera controller moves its camera.
+type CameraControllerKind string
+
+const (
+	// ControllerOrbit circles the camera around a target.
+	ControllerOrbit CameraControllerKind = "orbit"
+	// ControllerFly moves the camera freely with the keyboard and turns it with the mouse.
+	ControllerFly CameraControllerKind = "fly"
+	// ControllerRTS pans and zooms the camera over the ground like a strategy game.
+	ControllerRTS CameraControllerKind = "rts"
+)
+
+// worldUp is the up direction of the world, which controllers keep their cameras upright against.
+var worldUp = mgl32.Vec3{0, 0, 1}
+
+// CameraController represents a component that moves a camera from user input.  Angles are in radians and yaw is measured about the world's Z axis.  Controllers start from wherever their camera is the first time they update.
+type CameraController interface {
+	Component
+	// Kind retrieves the way the controller moves its camera.
+	Kind() CameraControllerKind
+	// Enabled returns true if the controller responds to input.
+	Enabled() bool
+	// SetEnabled sets whether the controller responds to input.
+	SetEnabled(bool)
+	// Update moves the camera from the input over the elapsed seconds.
+	Update(cam Camera, in input.Snapshot, elapsed float32)
+}
+
+// orbitState is the position of a camera around a point as spherical coordinates.
+type orbitState struct {
+	center   mgl32.Vec3
+	distance float32
+	yaw      float32
+	pitch    float32
+	isSet    bool
+}
+
+// fromCamera places the state where the camera is, circling the point it looks at.
+func (o *orbitState) fromCamera(cam Camera) {
+	o.center = cam.LookAtVec3()
+	offset := cam.PositionVec3().Sub(o.center)
+	o.distance = offset.Len()
+	if o.distance > 0 {
+		o.pitch = float32(math.Asin(float64(mgl32.Clamp(offset.Z()/o.distance, -1, 1))))
+		o.yaw = float32(math.Atan2(float64(offset.Y()), float64(offset.X())))
+	}
+	o.isSet = true
+}
+
+// eye retrieves the position of the camera.
+func (o *orbitState) eye() mgl32.Vec3 {
+	return o.center.Add(direction(o.yaw, o.pitch).Mul(o.distance))
+}
+
+// apply moves the camera to the state.
+func (o *orbitState) apply(cam Camera) {
+	cam.SetView(o.eye(), o.center, worldUp)
+}
+
+// direction retrieves the unit vector with a yaw about the world's Z axis and a pitch above the XY plane.
+func direction(yaw, pitch float32) mgl32.Vec3 {
+	sy, cy := math.Sincos(float64(yaw))
+	sp, cp := math.Sincos(float64(pitch))
+	return mgl32.Vec3{float32(cp * cy), float32(cp * sy), float32(sp)}
+}
+
+// zoom scales a distance by the scroll wheel, where each step up moves closer by the zoom speed.
+func zoom(distance float32, scroll float64, speed, min, max float32) float32 {
+	if scroll != 0 {
+		distance *= float32(math.Pow(float64(1-speed), scroll))
+	}
+	return clampRange(distance, min, max)
+}
+
+// zoomOrthographic scales what an orthographic camera shows along with the distance it moved, since moving closer does not make an orthographic view any larger.
+func zoomOrthographic(cam Camera, before, after float32) {
+	if before <= 0 || after == before || cam.ProjectionKind() != ProjectionOrthographic {
+		return
+	}
+	cam.SetOrthographicSize(cam.OrthographicSize() * after / before)
+}
+
+// clampRange limits a value to a range, ignoring bounds that are not positive or are inverted.
+func clampRange(v, min, max float32) float32 {
+	if min > 0 && v < min {
+		v = min
+	}
+	if max > 0 && max >= min && v > max {
+		v = max
+	}
+	return v
+}
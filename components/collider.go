@@ -0,0 +1,202 @@
+package components
+
+import (
+	"sync"
+
+	"github.com/go-gl/mathgl/mgl32"
+
+	"github.com/Ariemeth/quantum-pulse/geometry"
+)
+
+const (
+	// TypeCollider represents a collider component's type.
+	TypeCollider = "collider"
+)
+
+// ColliderShape is the kind of volume a collider uses.
+type ColliderShape string
+
+const (
+	// ShapeAABB is a box that stays aligned to the world axes no matter how the entity is rotated.
+	ShapeAABB ColliderShape = "aabb"
+	// ShapeSphere is a sphere.
+	ShapeSphere ColliderShape = "sphere"
+	// ShapeCapsule is a sphere swept along the local Z axis.
+	ShapeCapsule ColliderShape = "capsule"
+	// ShapeBox is a box that rotates with the entity.
+	ShapeBox ColliderShape = "box"
+	// ShapeHexPrism is a hexagonal prism matching the hexagon model, extruded along the local Z axis.
+	ShapeHexPrism ColliderShape = "hexPrism"
+)
+
+const (
+	// DefaultCollisionLayer is the layer colliders are placed on when none is given.
+	DefaultCollisionLayer uint32 = 1
+	// AllCollisionLayers is a mask that collides with every layer.
+	AllCollisionLayers uint32 = 0xFFFFFFFF
+)
+
+// Collider represents a volume used to detect collisions between entities.
+type Collider interface {
+	Component
+	// Data retrieves the collider data.
+	Data() ColliderData
+	// Set replaces the collider data.
+	Set(ColliderData)
+	// Layer retrieves the layers the collider is on.
+	Layer() uint32
+	// SetLayer sets the layers the collider is on.
+	SetLayer(uint32)
+	// Mask retrieves the layers the collider collides with.
+	Mask() uint32
+	// SetMask sets the layers the collider collides with.
+	SetMask(uint32)
+	// IsTrigger returns true if the collider only reports overlaps instead of blocking.
+	IsTrigger() bool
+	// SetTrigger sets whether the collider only reports overlaps instead of blocking.
+	SetTrigger(bool)
+	// WorldShape retrieves the collider's volume placed in the world by a transform.
+	WorldShape(Transform) geometry.Shape
+}
+
+// ColliderData describes the shape of a collider in the entity's local space.  HalfExtents is used by boxes, Radius by spheres, capsules and hex prisms, and Height is the length of a capsule's segment or the full height of a hex prism.
+type ColliderData struct {
+	Shape       ColliderShape `json:"shape"`
+	Center      mgl32.Vec3    `json:"center"`
+	HalfExtents mgl32.Vec3    `json:"halfExtents"`
+	Radius      float32       `json:"radius"`
+	Height      float32       `json:"height"`
+	Layer       uint32        `json:"layer"`
+	Mask        uint32        `json:"mask"`
+	IsTrigger   bool          `json:"trigger"`
+}
+
+// NewColliderData creates collider data for a shape on the default layer that collides with every layer.  Decoding json into it keeps these defaults for any missing fields.
+func NewColliderData(shape ColliderShape) ColliderData {
+	return ColliderData{
+		Shape: shape,
+		Layer: DefaultCollisionLayer,
+		Mask:  AllCollisionLayers,
+	}
+}
+
+type collider struct {
+	data     ColliderData
+	dataLock sync.RWMutex
+}
+
+// NewCollider creates a new Collider component.
+func NewCollider(cd ColliderData) Collider {
+	c := collider{
+		data: cd,
+	}
+	return &c
+}
+
+// Type retrieves the type of this component.
+func (c *collider) Type() string {
+	return TypeCollider
+}
+
+// Data retrieves the collider data.
+func (c *collider) Data() ColliderData {
+	c.dataLock.RLock()
+	defer c.dataLock.RUnlock()
+	return c.data
+}
+
+// Set replaces the collider data.
+func (c *collider) Set(cd ColliderData) {
+	c.dataLock.Lock()
+	defer c.dataLock.Unlock()
+	c.data = cd
+}
+
+// Layer retrieves the layers the collider is on.
+func (c *collider) Layer() uint32 {
+	c.dataLock.RLock()
+	defer c.dataLock.RUnlock()
+	return c.data.Layer
+}
+
+// SetLayer sets the layers the collider is on.
+func (c *collider) SetLayer(layer uint32) {
+	c.dataLock.Lock()
+	defer c.dataLock.Unlock()
+	c.data.Layer = layer
+}
+
+// Mask retrieves the layers the collider collides with.
+func (c *collider) Mask() uint32 {
+	c.dataLock.RLock()
+	defer c.dataLock.RUnlock()
+	return c.data.Mask
+}
+
+// SetMask sets the layers the collider collides with.
+func (c *collider) SetMask(mask uint32) {
+	c.dataLock.Lock()
+	defer c.dataLock.Unlock()
+	c.data.Mask = mask
+}
+
+// IsTrigger returns true if the collider only reports overlaps instead of blocking.
+func (c *collider) IsTrigger() bool {
+	c.dataLock.RLock()
+	defer c.dataLock.RUnlock()
+	return c.data.IsTrigger
+}
+
+// SetTrigger sets whether the collider only reports overlaps instead of blocking.
+func (c *collider) SetTrigger(isTrigger bool) {
+	c.dataLock.Lock()
+	defer c.dataLock.Unlock()
+	c.data.IsTrigger = isTrigger
+}
+
+// WorldShape retrieves the collider's volume placed in the world by a transform.  Spheres and capsules use the largest scale axis so they stay round.
+func (c *collider) WorldShape(t Transform) geometry.Shape {
+	cd := c.Data()
+	m := t.Data()
+	scale := t.Scale()
+	orientation := t.Orientation()
+	center := m.Mul4x1(cd.Center.Vec4(1)).Vec3()
+
+	switch cd.Shape {
+	case ShapeSphere:
+		return geometry.Sphere{Center: center, Radius: cd.Radius * maxAbs(scale)}
+	case ShapeCapsule:
+		half := orientation.Rotate(mgl32.Vec3{0, 0, cd.Height / 2 * abs32(scale.Z())})
+		return geometry.Capsule{A: center.Sub(half), B: center.Add(half), Radius: cd.Radius * maxAbs(scale.Vec2().Vec3(0))}
+	case ShapeBox:
+		half := mgl32.Vec3{
+			cd.HalfExtents.X() * abs32(scale.X()),
+			cd.HalfExtents.Y() * abs32(scale.Y()),
+			cd.HalfExtents.Z() * abs32(scale.Z()),
+		}
+		return geometry.Box{Center: center, HalfExtents: half, Orientation: orientation}
+	case ShapeHexPrism:
+		return geometry.NewHexPrism(cd.Radius, cd.Height/2, m.Mul4(mgl32.Translate3D(cd.Center.X(), cd.Center.Y(), cd.Center.Z())))
+	default:
+		local := geometry.AABB{Min: cd.Center.Sub(cd.HalfExtents), Max: cd.Center.Add(cd.HalfExtents)}
+		return local.Transform(m)
+	}
+}
+
+func maxAbs(v mgl32.Vec3) float32 {
+	max := abs32(v.X())
+	if y := abs32(v.Y()); y > max {
+		max = y
+	}
+	if z := abs32(v.Z()); z > max {
+		max = z
+	}
+	return max
+}
+
+func abs32(v float32) float32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,157 @@
+package components
+
+import (
+	"math"
+	"sync"
+
+	"github.com/go-gl/mathgl/mgl32"
+
+	"github.com/Ariemeth/quantum-pulse/input"
+)
+
+// FlySettings configures a fly controller.  The movement keys move along the view, the up and down keys move along the world's Z axis and moving the mouse while the look button is held turns the camera.
+type FlySettings struct {
+	Forward input.Key `json:"forward"`
+	Back    input.Key `json:"back"`
+	Left    input.Key `json:"left"`
+	Right   input.Key `json:"right"`
+	Up      input.Key `json:"up"`
+	Down    input.Key `json:"down"`
+	Fast    input.Key `json:"fast"`
+	// LookButton is the mouse button held to turn the camera.  MouseButtonNone turns the camera whenever the mouse moves.
+	LookButton input.MouseButton `json:"lookButton"`
+	// MoveSpeed is the distance moved per second.
+	MoveSpeed float32 `json:"moveSpeed"`
+	// FastMultiplier scales the move speed while the fast key is held.
+	FastMultiplier float32 `json:"fastMultiplier"`
+	// LookSpeed is the angle turned per pixel the mouse moves.
+	LookSpeed float32 `json:"lookSpeed"`
+	MinPitch  float32 `json:"minPitch"`
+	MaxPitch  float32 `json:"maxPitch"`
+}
+
+// NewFlySettings creates fly settings that move with WASD, rise with E, sink with Q and look while the right button is held.
+func NewFlySettings() FlySettings {
+	return FlySettings{
+		Forward:        input.KeyW,
+		Back:           input.KeyS,
+		Left:           input.KeyA,
+		Right:          input.KeyD,
+		Up:             input.KeyE,
+		Down:           input.KeyQ,
+		Fast:           input.KeyLeftShift,
+		LookButton:     input.MouseButtonRight,
+		MoveSpeed:      5,
+		FastMultiplier: 3,
+		LookSpeed:      0.005,
+		MinPitch:       -1.5,
+		MaxPitch:       1.5,
+	}
+}
+
+// FlyController represents a camera controller that flies freely.
+type FlyController interface {
+	CameraController
+	// Settings retrieves the controller settings.
+	Settings() FlySettings
+	// SetSettings replaces the controller settings.
+	SetSettings(FlySettings)
+}
+
+type flyController struct {
+	settings FlySettings
+	position mgl32.Vec3
+	yaw      float32
+	pitch    float32
+	isSet    bool
+	enabled  bool
+	dataLock sync.RWMutex
+}
+
+// NewFlyController creates a new FlyController component.
+func NewFlyController(settings FlySettings) FlyController {
+	f := flyController{
+		settings: settings,
+		enabled:  true,
+	}
+	return &f
+}
+
+// Type retrieves the type of this component.
+func (f *flyController) Type() string {
+	return TypeCameraController
+}
+
+// Kind retrieves the way the controller moves its camera.
+func (f *flyController) Kind() CameraControllerKind {
+	return ControllerFly
+}
+
+// Enabled returns true if the controller responds to input.
+func (f *flyController) Enabled() bool {
+	f.dataLock.RLock()
+	defer f.dataLock.RUnlock()
+	return f.enabled
+}
+
+// SetEnabled sets whether the controller responds to input.
+func (f *flyController) SetEnabled(enabled bool) {
+	f.dataLock.Lock()
+	defer f.dataLock.Unlock()
+	f.enabled = enabled
+}
+
+// Settings retrieves the controller settings.
+func (f *flyController) Settings() FlySettings {
+	f.dataLock.RLock()
+	defer f.dataLock.RUnlock()
+	return f.settings
+}
+
+// SetSettings replaces the controller settings.
+func (f *flyController) SetSettings(settings FlySettings) {
+	f.dataLock.Lock()
+	defer f.dataLock.Unlock()
+	f.settings = settings
+}
+
+// Update turns and moves the camera.
+func (f *flyController) Update(cam Camera, in input.Snapshot, elapsed float32) {
+	f.dataLock.Lock()
+	defer f.dataLock.Unlock()
+
+	if !f.enabled {
+		return
+	}
+	if !f.isSet {
+		f.position = cam.PositionVec3()
+		forward := cam.LookAtVec3().Sub(f.position)
+		if l := forward.Len(); l > 0 {
+			f.pitch = float32(math.Asin(float64(mgl32.Clamp(forward.Z()/l, -1, 1))))
+			f.yaw = float32(math.Atan2(float64(forward.Y()), float64(forward.X())))
+		}
+		f.isSet = true
+	}
+
+	s := f.settings
+	if s.LookButton == input.MouseButtonNone || in.ButtonDown(s.LookButton) {
+		f.yaw -= float32(in.DeltaX) * s.LookSpeed
+		f.pitch -= float32(in.DeltaY) * s.LookSpeed
+	}
+	f.pitch = mgl32.Clamp(f.pitch, s.MinPitch, s.MaxPitch)
+
+	forward := direction(f.yaw, f.pitch)
+	right := direction(f.yaw-math.Pi/2, 0)
+	move := forward.Mul(in.Axis(s.Back, s.Forward)).
+		Add(right.Mul(in.Axis(s.Left, s.Right))).
+		Add(worldUp.Mul(in.Axis(s.Down, s.Up)))
+	if move.Len() > 0 {
+		speed := s.MoveSpeed * elapsed
+		if in.KeyDown(s.Fast) {
+			speed *= s.FastMultiplier
+		}
+		f.position = f.position.Add(move.Normalize().Mul(speed))
+	}
+
+	cam.SetView(f.position, f.position.Add(forward), worldUp)
+}
@@ -0,0 +1,13 @@
+package components
+
+// ReferenceFrame names the space a velocity or acceleration is expressed in.
+type ReferenceFrame string
+
+const (
+	// FrameWorld values are along the world axes.  It is the default frame.
+	FrameWorld ReferenceFrame = "world"
+	// FrameLocal values are along the entity's own axes, so a velocity of -Z always moves the entity forward.
+	FrameLocal ReferenceFrame = "local"
+	// FrameParent values are along the axes of a parent entity and are added to the parent's velocity, so the entity is carried along with its parent.
+	FrameParent ReferenceFrame = "parent"
+)
@@ -0,0 +1,65 @@
+package components
+
+import (
+	"sync"
+)
+
+const (
+	// TypeIntegrator represents an integrator component's type.
+	TypeIntegrator = "integrator"
+)
+
+// IntegrationMethod names a numerical method used to advance position and velocity over a time step.
+type IntegrationMethod string
+
+const (
+	// IntegrateEuler advances the position with the velocity from the start of the step.  It is the cheapest method but gains energy over time.
+	IntegrateEuler IntegrationMethod = "euler"
+	// IntegrateSemiImplicitEuler advances the velocity first and then moves the position with the new velocity.  It keeps orbits bounded and is the default.
+	IntegrateSemiImplicitEuler IntegrationMethod = "semiImplicitEuler"
+	// IntegrateVerlet is velocity Verlet, a second order method that conserves energy well under a fixed step.
+	IntegrateVerlet IntegrationMethod = "verlet"
+	// IntegrateRK4 is the classic fourth order Runge-Kutta method.  It is the most accurate per step and the most expensive.
+	IntegrateRK4 IntegrationMethod = "rk4"
+)
+
+// Integrator represents a component that overrides the integration method the movement system uses for an entity.
+type Integrator interface {
+	Component
+	// Method retrieves the integration method.
+	Method() IntegrationMethod
+	// SetMethod sets the integration method.
+	SetMethod(IntegrationMethod)
+}
+
+type integrator struct {
+	method   IntegrationMethod
+	dataLock sync.RWMutex
+}
+
+// NewIntegrator creates a new Integrator component.
+func NewIntegrator(method IntegrationMethod) Integrator {
+	i := integrator{
+		method: method,
+	}
+	return &i
+}
+
+// Type retrieves the type of this component.
+func (i *integrator) Type() string {
+	return TypeIntegrator
+}
+
+// Method retrieves the integration method.
+func (i *integrator) Method() IntegrationMethod {
+	i.dataLock.RLock()
+	defer i.dataLock.RUnlock()
+	return i.method
+}
+
+// SetMethod sets the integration method.
+func (i *integrator) SetMethod(method IntegrationMethod) {
+	i.dataLock.Lock()
+	defer i.dataLock.Unlock()
+	i.method = method
+}
@@ -0,0 +1,170 @@
+package components
+
+import (
+	"sync"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	// TypeLight represents a light component's type.
+	TypeLight = "light"
+)
+
+// LightKind names how a light shines.
+type LightKind string
+
+const (
+	// LightDirectional shines in one direction everywhere, like the sun.  It has no position and does not fade with distance.
+	LightDirectional LightKind = "directional"
+	// LightPoint shines in every direction from the position of its entity and fades with distance.
+	LightPoint LightKind = "point"
+	// LightSpot shines in a cone from the position of its entity and fades with distance and towards the edge of the cone.
+	LightSpot LightKind = "spot"
+)
+
+// Attenuation is how a light fades with distance d, dividing its intensity by Constant + Linear*d + Quadratic*d*d.
+type Attenuation struct {
+	Constant  float32 `json:"constant"`
+	Linear    float32 `json:"linear"`
+	Quadratic float32 `json:"quadratic"`
+}
+
+// LightData is the json of a light.  Direction is in the space of the light's entity, so rotating the entity's transform turns the light.  Range is the distance a point or spot light reaches, where it has faded out completely, and InnerAngle and OuterAngle are the half angles in degrees of a spot light's cone at full brightness and where it has faded out.
+type LightData struct {
+	Kind        LightKind   `json:"kind"`
+	Color       mgl32.Vec3  `json:"color"`
+	Intensity   float32     `json:"intensity"`
+	Direction   mgl32.Vec3  `json:"direction"`
+	Range       float32     `json:"range"`
+	Attenuation Attenuation `json:"attenuation"`
+	InnerAngle  float32     `json:"innerAngle"`
+	OuterAngle  float32     `json:"outerAngle"`
+	Enabled     *bool       `json:"enabled,omitempty"`
+}
+
+// NewLightData creates the data of a white light shining straight down with a range of 10.
+func NewLightData(kind LightKind) LightData {
+	return LightData{
+		Kind:        kind,
+		Color:       mgl32.Vec3{1, 1, 1},
+		Intensity:   1,
+		Direction:   mgl32.Vec3{0, 0, -1},
+		Range:       10,
+		Attenuation: Attenuation{Constant: 1, Linear: 0.09, Quadratic: 0.032},
+		InnerAngle:  20,
+		OuterAngle:  30,
+	}
+}
+
+// Light represents a component that lights the meshes around it.  Point and spot lights shine from the translation of their entity's transform.
+type Light interface {
+	Component
+	// Data retrieves the light's settings.
+	Data() LightData
+	// Set replaces the light's settings.
+	Set(LightData)
+	// Kind retrieves how the light shines.
+	Kind() LightKind
+	// Color retrieves the color of the light.
+	Color() mgl32.Vec3
+	// SetColor sets the color of the light.
+	SetColor(mgl32.Vec3)
+	// Intensity retrieves the brightness of the light.
+	Intensity() float32
+	// SetIntensity sets the brightness of the light.
+	SetIntensity(float32)
+	// Enabled returns true if the light shines.
+	Enabled() bool
+	// SetEnabled sets whether the light shines.
+	SetEnabled(bool)
+}
+
+type light struct {
+	data     LightData
+	enabled  bool
+	dataLock sync.RWMutex
+}
+
+// NewLight creates a new Light component.
+func NewLight(ld LightData) Light {
+	l := light{}
+	l.Set(ld)
+	return &l
+}
+
+// Type retrieves the type of this component.
+func (l *light) Type() string {
+	return TypeLight
+}
+
+// Data retrieves the light's settings.
+func (l *light) Data() LightData {
+	l.dataLock.RLock()
+	defer l.dataLock.RUnlock()
+	ld := l.data
+	enabled := l.enabled
+	ld.Enabled = &enabled
+	return ld
+}
+
+// Set replaces the light's settings.  Lights are enabled unless the data says otherwise.
+func (l *light) Set(ld LightData) {
+	l.dataLock.Lock()
+	defer l.dataLock.Unlock()
+	l.enabled = ld.Enabled == nil || *ld.Enabled
+	ld.Enabled = nil
+	if ld.Direction.Len() > 0 {
+		ld.Direction = ld.Direction.Normalize()
+	}
+	l.data = ld
+}
+
+// Kind retrieves how the light shines.
+func (l *light) Kind() LightKind {
+	l.dataLock.RLock()
+	defer l.dataLock.RUnlock()
+	return l.data.Kind
+}
+
+// Color retrieves the color of the light.
+func (l *light) Color() mgl32.Vec3 {
+	l.dataLock.RLock()
+	defer l.dataLock.RUnlock()
+	return l.data.Color
+}
+
+// SetColor sets the color of the light.
+func (l *light) SetColor(color mgl32.Vec3) {
+	l.dataLock.Lock()
+	defer l.dataLock.Unlock()
+	l.data.Color = color
+}
+
+// Intensity retrieves the brightness of the light.
+func (l *light) Intensity() float32 {
+	l.dataLock.RLock()
+	defer l.dataLock.RUnlock()
+	return l.data.Intensity
+}
+
+// SetIntensity sets the brightness of the light.
+func (l *light) SetIntensity(intensity float32) {
+	l.dataLock.Lock()
+	defer l.dataLock.Unlock()
+	l.data.Intensity = intensity
+}
+
+// Enabled returns true if the light shines.
+func (l *light) Enabled() bool {
+	l.dataLock.RLock()
+	defer l.dataLock.RUnlock()
+	return l.enabled
+}
+
+// SetEnabled sets whether the light shines.
+func (l *light) SetEnabled(enabled bool) {
+	l.dataLock.Lock()
+	defer l.dataLock.Unlock()
+	l.enabled = enabled
+}
@@ -0,0 +1,295 @@
+package components
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+)
+
+const (
+	// TypeMaterial represents a material component's type.
+	TypeMaterial = "material"
+	// MaterialSrcDir is the expected location of materials.
+	MaterialSrcDir = "assets/materials/"
+)
+
+// BlendMode is how a mesh is blended with what is drawn behind it.
+type BlendMode string
+
+const (
+	// BlendNone draws over what is behind the mesh.
+	BlendNone BlendMode = "none"
+	// BlendAlpha mixes the mesh with what is behind it by its alpha.
+	BlendAlpha BlendMode = "alpha"
+	// BlendAdditive adds the mesh, scaled by its alpha, to what is behind it, such as for fire and glows.
+	BlendAdditive BlendMode = "additive"
+)
+
+// CullMode is which faces of a mesh are skipped.  Front faces wind counter clockwise.
+type CullMode string
+
+const (
+	// CullNone draws both faces of every triangle.
+	CullNone CullMode = "none"
+	// CullBack skips the faces facing away from the camera.
+	CullBack CullMode = "back"
+	// CullFront skips the faces facing the camera.
+	CullFront CullMode = "front"
+)
+
+// RenderState is the blending, face culling and depth testing a mesh is drawn with.  Settings left empty keep those of the mesh's render pass, which draws without culling.
+type RenderState struct {
+	Blend      BlendMode `json:"blend,omitempty"`
+	Cull       CullMode  `json:"cull,omitempty"`
+	DepthTest  *bool     `json:"depthTest,omitempty"`
+	DepthWrite *bool     `json:"depthWrite,omitempty"`
+}
+
+// MaterialData is the json of a material.  Textures maps the name of each sampler uniform to the texture file bound to it, where the "tex" sampler replaces the mesh's texture.  Uniforms holds the values of any other uniforms, set by the type the shader declares them with: 3 values for a vec3, 16 for a mat4 and a multiple of an element for the first elements of an array.  Settings left empty use those of the mesh, or of the material's parent for a material overriding another.
+type MaterialData struct {
+	VertShaderFile string               `json:"vertShaderFile,omitempty"`
+	FragShaderFile string               `json:"fragShaderFile,omitempty"`
+	Textures       map[string]string    `json:"textures,omitempty"`
+	Uniforms       map[string][]float32 `json:"uniforms,omitempty"`
+	Pass           *RenderPass          `json:"pass,omitempty"`
+	RenderState
+}
+
+// Merge retrieves the material with every setting of another material that is not empty replacing its own.  Textures and uniforms are replaced one at a time.
+func (md MaterialData) Merge(o MaterialData) MaterialData {
+	merged := md
+	if o.VertShaderFile != "" {
+		merged.VertShaderFile = o.VertShaderFile
+	}
+	if o.FragShaderFile != "" {
+		merged.FragShaderFile = o.FragShaderFile
+	}
+	if o.Pass != nil {
+		merged.Pass = o.Pass
+	}
+	if o.Blend != "" {
+		merged.Blend = o.Blend
+	}
+	if o.Cull != "" {
+		merged.Cull = o.Cull
+	}
+	if o.DepthTest != nil {
+		merged.DepthTest = o.DepthTest
+	}
+	if o.DepthWrite != nil {
+		merged.DepthWrite = o.DepthWrite
+	}
+	if len(o.Textures) > 0 {
+		merged.Textures = make(map[string]string, len(md.Textures)+len(o.Textures))
+		for sampler, file := range md.Textures {
+			merged.Textures[sampler] = file
+		}
+		for sampler, file := range o.Textures {
+			merged.Textures[sampler] = file
+		}
+	}
+	if len(o.Uniforms) > 0 {
+		merged.Uniforms = make(map[string][]float32, len(md.Uniforms)+len(o.Uniforms))
+		for name, v := range md.Uniforms {
+			merged.Uniforms[name] = v
+		}
+		for name, v := range o.Uniforms {
+			merged.Uniforms[name] = v
+		}
+	}
+	return merged
+}
+
+// IsEmpty returns true if the material has no settings.
+func (md MaterialData) IsEmpty() bool {
+	return md.VertShaderFile == "" && md.FragShaderFile == "" && len(md.Textures) == 0 && len(md.Uniforms) == 0 &&
+		md.Pass == nil && md.Blend == "" && md.Cull == "" && md.DepthTest == nil && md.DepthWrite == nil
+}
+
+// Validate returns an error if the material's blend or cull mode is unknown.
+func (md MaterialData) Validate() error {
+	switch md.Blend {
+	case "", BlendNone, BlendAlpha, BlendAdditive:
+	default:
+		return fmt.Errorf("unknown blend mode %q", md.Blend)
+	}
+	switch md.Cull {
+	case "", CullNone, CullBack, CullFront:
+	default:
+		return fmt.Errorf("unknown cull mode %q", md.Cull)
+	}
+	return nil
+}
+
+// copy retrieves a copy of the material that shares no maps or slices with it.
+func (md MaterialData) copy() MaterialData {
+	return MaterialData{}.Merge(md)
+}
+
+// Material represents a component describing how a mesh is drawn: its shader, the textures bound to its samplers, its uniform values and its render state.  Materials can be shared by many entities, so changing one changes every entity it is on, and a material created with NewMaterialInstance overrides settings of a shared material for one entity.
+type Material interface {
+	Component
+	// Data retrieves the material's settings along with those it shares with its parent.
+	Data() MaterialData
+	// Set replaces the material's own settings.
+	Set(MaterialData)
+	// Load loads the material's settings from file.
+	Load(string) error
+	// Parent retrieves the material this material overrides, or nil if it overrides none.
+	Parent() Material
+	// SetUniform sets the values of a uniform.
+	SetUniform(name string, values ...float32)
+	// Uniform retrieves the values of a uniform.
+	Uniform(name string) ([]float32, bool)
+	// SetTexture binds a texture file to a sampler uniform.
+	SetTexture(sampler, file string)
+	// Version retrieves a number that grows every time the material or its parent changes.
+	Version() uint64
+}
+
+type material struct {
+	parent   Material
+	data     MaterialData
+	version  uint64
+	dataLock sync.RWMutex
+}
+
+// NewMaterial creates a new Material component.
+func NewMaterial() Material {
+	m := material{}
+	return &m
+}
+
+// NewMaterialInstance creates a new Material component that shares the settings of a parent material until it sets them itself.
+func NewMaterialInstance(parent Material) Material {
+	m := material{parent: parent}
+	return &m
+}
+
+// Type retrieves the type of this component.
+func (m *material) Type() string {
+	return TypeMaterial
+}
+
+// Data retrieves the material's settings along with those it shares with its parent.
+func (m *material) Data() MaterialData {
+	m.dataLock.RLock()
+	defer m.dataLock.RUnlock()
+	if m.parent == nil {
+		return m.data.copy()
+	}
+	return m.parent.Data().Merge(m.data)
+}
+
+// Set replaces the material's own settings.
+func (m *material) Set(md MaterialData) {
+	m.dataLock.Lock()
+	defer m.dataLock.Unlock()
+	m.data = md.copy()
+	m.version++
+}
+
+// Load loads the material's settings from file.
+func (m *material) Load(fileName string) error {
+	md, err := readMaterialFile(fileName)
+	if err != nil {
+		return err
+	}
+	m.Set(md)
+	return nil
+}
+
+// Parent retrieves the material this material overrides, or nil if it overrides none.
+func (m *material) Parent() Material {
+	return m.parent
+}
+
+// SetUniform sets the values of a uniform.
+func (m *material) SetUniform(name string, values ...float32) {
+	m.dataLock.Lock()
+	defer m.dataLock.Unlock()
+	if m.data.Uniforms == nil {
+		m.data.Uniforms = make(map[string][]float32)
+	}
+	m.data.Uniforms[name] = append([]float32(nil), values...)
+	m.version++
+}
+
+// Uniform retrieves the values of a uniform.
+func (m *material) Uniform(name string) ([]float32, bool) {
+	m.dataLock.RLock()
+	v, ok := m.data.Uniforms[name]
+	m.dataLock.RUnlock()
+	if ok {
+		return append([]float32(nil), v...), true
+	}
+	if m.parent != nil {
+		return m.parent.Uniform(name)
+	}
+	return nil, false
+}
+
+// SetTexture binds a texture file to a sampler uniform.
+func (m *material) SetTexture(sampler, file string) {
+	m.dataLock.Lock()
+	defer m.dataLock.Unlock()
+	if m.data.Textures == nil {
+		m.data.Textures = make(map[string]string)
+	}
+	m.data.Textures[sampler] = file
+	m.version++
+}
+
+// Version retrieves a number that grows every time the material or its parent changes.
+func (m *material) Version() uint64 {
+	m.dataLock.RLock()
+	defer m.dataLock.RUnlock()
+	if m.parent == nil {
+		return m.version
+	}
+	return m.version + m.parent.Version()
+}
+
+var (
+	sharedMaterials     = make(map[string]Material)
+	sharedMaterialsLock sync.Mutex
+)
+
+// LoadMaterial retrieves the material of a file, loading it the first time it is used.  Every caller shares the same material, so changing it changes every entity it is on.
+func LoadMaterial(fileName string) (Material, error) {
+	sharedMaterialsLock.Lock()
+	defer sharedMaterialsLock.Unlock()
+	if m, loaded := sharedMaterials[fileName]; loaded {
+		return m, nil
+	}
+	m := NewMaterial()
+	if err := m.Load(fileName); err != nil {
+		return nil, err
+	}
+	sharedMaterials[fileName] = m
+	return m, nil
+}
+
+// readMaterialFile reads a material from the material directory.
+func readMaterialFile(fileName string) (MaterialData, error) {
+	fullFileName := ""
+	if strings.Contains(fileName, MaterialSrcDir) {
+		fullFileName = fileName
+	} else {
+		fullFileName = fmt.Sprintf("%s%s", MaterialSrcDir, fileName)
+	}
+
+	data, err := ioutil.ReadFile(fullFileName)
+	if err != nil {
+		fmt.Println(err.Error())
+		return MaterialData{}, err
+	}
+
+	var md MaterialData
+	if err := json.Unmarshal(data, &md); err != nil {
+		return MaterialData{}, err
+	}
+	return md, md.Validate()
+}
@@ -1,12 +1,19 @@
 package components
 
 import (
+	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"hash/fnv"
 	"io/ioutil"
+	"math"
 	"strings"
 	"sync"
+
+	"github.com/go-gl/mathgl/mgl32"
+
+	"github.com/Ariemeth/quantum-pulse/geometry"
 )
 
 const (
@@ -25,6 +32,12 @@ type Mesh interface {
 	Set(MeshData)
 	// Load loads the mesh data from file.
 	Load(string) error
+	// Bounds retrieves the box containing the mesh in model space.
+	Bounds() geometry.AABB
+	// BoundingSphere retrieves the sphere containing the mesh in model space.
+	BoundingSphere() geometry.Sphere
+	// Key retrieves a value identifying the mesh's vertex data.  Meshes with the same data have the same key so they can be drawn together.
+	Key() uint64
 }
 
 // NewMesh creates a new Mesh component.
@@ -35,6 +48,9 @@ func NewMesh() Mesh {
 
 type mesh struct {
 	data     MeshData
+	bounds   geometry.AABB
+	sphere   geometry.Sphere
+	key      uint64
 	dataLock sync.RWMutex
 	isLoaded bool
 }
@@ -56,6 +72,35 @@ func (m *mesh) Set(md MeshData) {
 	m.dataLock.Lock()
 	defer m.dataLock.Unlock()
 	m.data = md
+	m.updateBounds()
+}
+
+// Bounds retrieves the box containing the mesh in model space.  The bounds are worked out whenever the mesh data changes.
+func (m *mesh) Bounds() geometry.AABB {
+	m.dataLock.RLock()
+	defer m.dataLock.RUnlock()
+	return m.bounds
+}
+
+// BoundingSphere retrieves the sphere containing the mesh in model space.
+func (m *mesh) BoundingSphere() geometry.Sphere {
+	m.dataLock.RLock()
+	defer m.dataLock.RUnlock()
+	return m.sphere
+}
+
+// Key retrieves a value identifying the mesh's vertex data.
+func (m *mesh) Key() uint64 {
+	m.dataLock.RLock()
+	defer m.dataLock.RUnlock()
+	return m.key
+}
+
+// updateBounds works out the bounds and key of the mesh data.  The caller is expected to hold the write lock.
+func (m *mesh) updateBounds() {
+	m.bounds = m.data.Bounds()
+	m.sphere = m.data.BoundingSphere()
+	m.key = m.data.Key()
 }
 
 // Load loads a mesh from file and returns an error if an error occurs while loading the file.  If the mesh has already been loaded an "Already Loaded" error will be returned.
@@ -83,6 +128,7 @@ func (m *mesh) Load(fileName string) error {
 	err = json.Unmarshal(data, &m.data)
 	if err == nil {
 		m.isLoaded = true
+		m.updateBounds()
 	}
 	return err
 }
@@ -96,4 +142,155 @@ type MeshData struct {
 	TextureFile    string    `json:"textureFile"`
 	FragShaderFile string    `json:"fragShaderFile"`
 	VertShaderFile string    `json:"vertShaderFile"`
+	// Pass is the render pass the mesh is drawn in.
+	Pass RenderPass `json:"pass,omitempty"`
+	// Material names a material file drawing the mesh when its entity has no material component.  The material's settings replace the mesh's texture, shaders and pass.
+	Material string `json:"material,omitempty"`
+	// Normals holds the x, y and z of the normal of each vertex, used by lit shaders.  Normals are generated for lit shaders when a mesh has none.
+	Normals []float32 `json:"normals,omitempty"`
+	// Joints holds JointsPerVertex skeleton joint indices for each vertex of a skinned mesh.
+	Joints []uint32 `json:"joints,omitempty"`
+	// Weights holds JointsPerVertex joint weights for each vertex of a skinned mesh.
+	Weights []float32 `json:"weights,omitempty"`
+}
+
+// IsSkinned returns true if the mesh has joint indices and weights for skinning.
+func (md MeshData) IsSkinned() bool {
+	return len(md.Joints) > 0 && len(md.Weights) > 0
+}
+
+// Positions retrieves the position of every vertex, which are the first three values of each vertex.
+func (md MeshData) Positions() []mgl32.Vec3 {
+	if md.VertSize < 3 {
+		return nil
+	}
+	count := len(md.Verts) / int(md.VertSize)
+	positions := make([]mgl32.Vec3, count)
+	for i := range positions {
+		offset := i * int(md.VertSize)
+		positions[i] = mgl32.Vec3{md.Verts[offset], md.Verts[offset+1], md.Verts[offset+2]}
+	}
+	return positions
+}
+
+// Bounds retrieves the box containing every vertex of the mesh in model space.
+func (md MeshData) Bounds() geometry.AABB {
+	return geometry.AABBFromPoints(md.Positions()...)
+}
+
+// BoundingSphere retrieves a sphere containing every vertex of the mesh in model space, centered on the middle of its bounds.
+func (md MeshData) BoundingSphere() geometry.Sphere {
+	positions := md.Positions()
+	if len(positions) == 0 {
+		return geometry.Sphere{}
+	}
+	center := geometry.AABBFromPoints(positions...).Center()
+	radius := float32(0)
+	for _, p := range positions {
+		if d := p.Sub(center).Len(); d > radius {
+			radius = d
+		}
+	}
+	return geometry.Sphere{Center: center, Radius: radius}
+}
+
+// Key retrieves a hash of the vertex data, indices, normals and skinning data of the mesh.  Meshes loaded from the same file have the same key.
+func (md MeshData) Key() uint64 {
+	h := fnv.New64a()
+	var buf [4]byte
+	write := func(v uint32) {
+		binary.LittleEndian.PutUint32(buf[:], v)
+		h.Write(buf[:])
+	}
+	if md.Indexed {
+		write(1)
+	} else {
+		write(0)
+	}
+	write(uint32(md.VertSize))
+	write(uint32(len(md.Verts)))
+	for _, v := range md.Verts {
+		write(math.Float32bits(v))
+	}
+	write(uint32(len(md.Indices)))
+	for _, i := range md.Indices {
+		write(i)
+	}
+	write(uint32(len(md.Joints)))
+	for _, j := range md.Joints {
+		write(j)
+	}
+	for _, w := range md.Weights {
+		write(math.Float32bits(w))
+	}
+	write(uint32(len(md.Normals)))
+	for _, n := range md.Normals {
+		write(math.Float32bits(n))
+	}
+	return h.Sum64()
+}
+
+// VertexNormals retrieves the normal of every vertex, generating smooth normals from the triangles when the mesh has none.  Generated normals average the faces around each vertex weighted by their area and point along +Z for vertices without a face.
+func (md MeshData) VertexNormals() []mgl32.Vec3 {
+	positions := md.Positions()
+	normals := make([]mgl32.Vec3, len(positions))
+	if len(md.Normals) >= len(positions)*3 {
+		for i := range normals {
+			normals[i] = mgl32.Vec3{md.Normals[i*3], md.Normals[i*3+1], md.Normals[i*3+2]}
+		}
+		return normals
+	}
+
+	for _, tri := range md.triangleIndices() {
+		a, b, c := positions[tri[0]], positions[tri[1]], positions[tri[2]]
+		// The cross product's length is twice the triangle's area, which weights larger faces more.
+		face := b.Sub(a).Cross(c.Sub(a))
+		for _, i := range tri {
+			normals[i] = normals[i].Add(face)
+		}
+	}
+	for i, n := range normals {
+		if n.Len() > 0 {
+			normals[i] = n.Normalize()
+		} else {
+			normals[i] = mgl32.Vec3{0, 0, 1}
+		}
+	}
+	return normals
+}
+
+// triangleIndices retrieves the vertex indices of every triangle, built the same way as Triangles.
+func (md MeshData) triangleIndices() [][3]uint32 {
+	count := uint32(0)
+	if md.VertSize >= 3 {
+		count = uint32(len(md.Verts) / int(md.VertSize))
+	}
+	var triangles [][3]uint32
+
+	if md.Indexed {
+		for i := 2; i < len(md.Indices); i++ {
+			a, b, c := md.Indices[0], md.Indices[i-1], md.Indices[i]
+			if a >= count || b >= count || c >= count {
+				continue
+			}
+			triangles = append(triangles, [3]uint32{a, b, c})
+		}
+		return triangles
+	}
+
+	for i := uint32(2); i < count; i += 3 {
+		triangles = append(triangles, [3]uint32{i - 2, i - 1, i})
+	}
+	return triangles
+}
+
+// Triangles retrieves the triangles of the mesh in model space.  Indexed meshes are drawn as a triangle fan and the rest as a list of triangles, so the triangles are built the same way.
+func (md MeshData) Triangles() [][3]mgl32.Vec3 {
+	positions := md.Positions()
+	indices := md.triangleIndices()
+	triangles := make([][3]mgl32.Vec3, len(indices))
+	for i, tri := range indices {
+		triangles[i] = [3]mgl32.Vec3{positions[tri[0]], positions[tri[1]], positions[tri[2]]}
+	}
+	return triangles
 }
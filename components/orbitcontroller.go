@@ -0,0 +1,164 @@
+package components
+
+import (
+	"sync"
+
+	"github.com/go-gl/mathgl/mgl32"
+
+	"github.com/Ariemeth/quantum-pulse/input"
+)
+
+// OrbitSettings configures an orbit controller.  Dragging with the rotate button circles the target, dragging with the pan button moves the target across the view and the scroll wheel zooms.
+type OrbitSettings struct {
+	RotateButton input.MouseButton `json:"rotateButton"`
+	PanButton    input.MouseButton `json:"panButton"`
+	// RotateSpeed is the angle turned per pixel dragged.
+	RotateSpeed float32 `json:"rotateSpeed"`
+	// PanSpeed is the distance panned per pixel dragged as a fraction of the distance to the target.
+	PanSpeed float32 `json:"panSpeed"`
+	// ZoomSpeed is the fraction of the distance to the target moved per scroll step.
+	ZoomSpeed   float32 `json:"zoomSpeed"`
+	MinDistance float32 `json:"minDistance"`
+	MaxDistance float32 `json:"maxDistance"`
+	MinPitch    float32 `json:"minPitch"`
+	MaxPitch    float32 `json:"maxPitch"`
+}
+
+// NewOrbitSettings creates orbit settings that rotate with the right button and pan with the middle button.
+func NewOrbitSettings() OrbitSettings {
+	return OrbitSettings{
+		RotateButton: input.MouseButtonRight,
+		PanButton:    input.MouseButtonMiddle,
+		RotateSpeed:  0.01,
+		PanSpeed:     0.002,
+		ZoomSpeed:    0.1,
+		MinDistance:  1,
+		MaxDistance:  100,
+		MinPitch:     -1.5,
+		MaxPitch:     1.5,
+	}
+}
+
+// OrbitController represents a camera controller that circles a target.
+type OrbitController interface {
+	CameraController
+	// Settings retrieves the controller settings.
+	Settings() OrbitSettings
+	// SetSettings replaces the controller settings.
+	SetSettings(OrbitSettings)
+	// Target retrieves the point the camera circles.
+	Target() mgl32.Vec3
+	// SetTarget sets the point the camera circles.
+	SetTarget(mgl32.Vec3)
+}
+
+type orbitController struct {
+	settings OrbitSettings
+	state    orbitState
+	target   *mgl32.Vec3
+	enabled  bool
+	dataLock sync.RWMutex
+}
+
+// NewOrbitController creates a new OrbitController component.
+func NewOrbitController(settings OrbitSettings) OrbitController {
+	o := orbitController{
+		settings: settings,
+		enabled:  true,
+	}
+	return &o
+}
+
+// Type retrieves the type of this component.
+func (o *orbitController) Type() string {
+	return TypeCameraController
+}
+
+// Kind retrieves the way the controller moves its camera.
+func (o *orbitController) Kind() CameraControllerKind {
+	return ControllerOrbit
+}
+
+// Enabled returns true if the controller responds to input.
+func (o *orbitController) Enabled() bool {
+	o.dataLock.RLock()
+	defer o.dataLock.RUnlock()
+	return o.enabled
+}
+
+// SetEnabled sets whether the controller responds to input.
+func (o *orbitController) SetEnabled(enabled bool) {
+	o.dataLock.Lock()
+	defer o.dataLock.Unlock()
+	o.enabled = enabled
+}
+
+// Settings retrieves the controller settings.
+func (o *orbitController) Settings() OrbitSettings {
+	o.dataLock.RLock()
+	defer o.dataLock.RUnlock()
+	return o.settings
+}
+
+// SetSettings replaces the controller settings.
+func (o *orbitController) SetSettings(settings OrbitSettings) {
+	o.dataLock.Lock()
+	defer o.dataLock.Unlock()
+	o.settings = settings
+}
+
+// Target retrieves the point the camera circles.
+func (o *orbitController) Target() mgl32.Vec3 {
+	o.dataLock.RLock()
+	defer o.dataLock.RUnlock()
+	if o.target != nil {
+		return *o.target
+	}
+	return o.state.center
+}
+
+// SetTarget sets the point the camera circles.  The camera keeps its distance and angles.
+func (o *orbitController) SetTarget(target mgl32.Vec3) {
+	o.dataLock.Lock()
+	defer o.dataLock.Unlock()
+	o.target = &target
+}
+
+// Update circles, pans and zooms the camera.
+func (o *orbitController) Update(cam Camera, in input.Snapshot, elapsed float32) {
+	o.dataLock.Lock()
+	defer o.dataLock.Unlock()
+
+	if !o.enabled {
+		return
+	}
+	if !o.state.isSet {
+		o.state.fromCamera(cam)
+	}
+	if o.target != nil {
+		o.state.center = *o.target
+		o.target = nil
+	}
+
+	s := o.settings
+	dx, dy := float32(in.DeltaX), float32(in.DeltaY)
+
+	if in.ButtonDown(s.RotateButton) {
+		o.state.yaw -= dx * s.RotateSpeed
+		o.state.pitch += dy * s.RotateSpeed
+	}
+	o.state.pitch = mgl32.Clamp(o.state.pitch, s.MinPitch, s.MaxPitch)
+
+	if in.ButtonDown(s.PanButton) {
+		forward := o.state.center.Sub(o.state.eye()).Normalize()
+		right := forward.Cross(worldUp).Normalize()
+		up := right.Cross(forward)
+		scale := o.state.distance * s.PanSpeed
+		o.state.center = o.state.center.Add(right.Mul(-dx * scale)).Add(up.Mul(dy * scale))
+	}
+
+	distance := o.state.distance
+	o.state.distance = zoom(o.state.distance, in.ScrollY, s.ZoomSpeed, s.MinDistance, s.MaxDistance)
+	zoomOrthographic(cam, distance, o.state.distance)
+	o.state.apply(cam)
+}
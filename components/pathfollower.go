@@ -0,0 +1,160 @@
+package components
+
+import (
+	"sync"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	// TypePathFollower represents a path follower component's type.
+	TypePathFollower = "pathFollower"
+)
+
+// PathFollower represents a component that steers an entity through a list of world space waypoints by setting its velocity.
+type PathFollower interface {
+	Component
+	// Waypoints retrieves the points being followed.
+	Waypoints() []mgl32.Vec3
+	// SetWaypoints replaces the points being followed and starts from the first one.
+	SetWaypoints([]mgl32.Vec3)
+	// Clear stops following the current path.
+	Clear()
+	// Current retrieves the index of the waypoint being moved towards.
+	Current() int
+	// Advance moves on to the next waypoint and returns false once the last waypoint has been passed.
+	Advance() bool
+	// IsFinished returns true once every waypoint has been reached or if there is no path.
+	IsFinished() bool
+	// Speed retrieves the speed the entity moves at.
+	Speed() float32
+	// SetSpeed sets the speed the entity moves at.
+	SetSpeed(float32)
+	// ArriveRadius retrieves how close the entity has to get to a waypoint to have reached it.
+	ArriveRadius() float32
+	// SetArriveRadius sets how close the entity has to get to a waypoint to have reached it.
+	SetArriveRadius(float32)
+	// Loop returns true if the path starts over after its last waypoint.
+	Loop() bool
+	// SetLoop sets whether the path starts over after its last waypoint.
+	SetLoop(bool)
+}
+
+type pathFollower struct {
+	waypoints    []mgl32.Vec3
+	current      int
+	speed        float32
+	arriveRadius float32
+	loop         bool
+	dataLock     sync.RWMutex
+}
+
+// NewPathFollower creates a new PathFollower component that moves at a speed and has reached a waypoint once within 0.05 units of it.
+func NewPathFollower(speed float32) PathFollower {
+	p := pathFollower{
+		speed:        speed,
+		arriveRadius: 0.05,
+	}
+	return &p
+}
+
+// Type retrieves the type of this component.
+func (p *pathFollower) Type() string {
+	return TypePathFollower
+}
+
+// Waypoints retrieves the points being followed.
+func (p *pathFollower) Waypoints() []mgl32.Vec3 {
+	p.dataLock.RLock()
+	defer p.dataLock.RUnlock()
+	waypoints := make([]mgl32.Vec3, len(p.waypoints))
+	copy(waypoints, p.waypoints)
+	return waypoints
+}
+
+// SetWaypoints replaces the points being followed and starts from the first one.
+func (p *pathFollower) SetWaypoints(waypoints []mgl32.Vec3) {
+	p.dataLock.Lock()
+	defer p.dataLock.Unlock()
+	p.waypoints = make([]mgl32.Vec3, len(waypoints))
+	copy(p.waypoints, waypoints)
+	p.current = 0
+}
+
+// Clear stops following the current path.
+func (p *pathFollower) Clear() {
+	p.dataLock.Lock()
+	defer p.dataLock.Unlock()
+	p.waypoints = nil
+	p.current = 0
+}
+
+// Current retrieves the index of the waypoint being moved towards.
+func (p *pathFollower) Current() int {
+	p.dataLock.RLock()
+	defer p.dataLock.RUnlock()
+	return p.current
+}
+
+// Advance moves on to the next waypoint and returns false once the last waypoint has been passed.
+func (p *pathFollower) Advance() bool {
+	p.dataLock.Lock()
+	defer p.dataLock.Unlock()
+	if p.current >= len(p.waypoints) {
+		return false
+	}
+	p.current++
+	if p.current == len(p.waypoints) && p.loop {
+		p.current = 0
+	}
+	return p.current < len(p.waypoints)
+}
+
+// IsFinished returns true once every waypoint has been reached or if there is no path.
+func (p *pathFollower) IsFinished() bool {
+	p.dataLock.RLock()
+	defer p.dataLock.RUnlock()
+	return p.current >= len(p.waypoints)
+}
+
+// Speed retrieves the speed the entity moves at.
+func (p *pathFollower) Speed() float32 {
+	p.dataLock.RLock()
+	defer p.dataLock.RUnlock()
+	return p.speed
+}
+
+// SetSpeed sets the speed the entity moves at.
+func (p *pathFollower) SetSpeed(speed float32) {
+	p.dataLock.Lock()
+	defer p.dataLock.Unlock()
+	p.speed = speed
+}
+
+// ArriveRadius retrieves how close the entity has to get to a waypoint to have reached it.
+func (p *pathFollower) ArriveRadius() float32 {
+	p.dataLock.RLock()
+	defer p.dataLock.RUnlock()
+	return p.arriveRadius
+}
+
+// SetArriveRadius sets how close the entity has to get to a waypoint to have reached it.
+func (p *pathFollower) SetArriveRadius(radius float32) {
+	p.dataLock.Lock()
+	defer p.dataLock.Unlock()
+	p.arriveRadius = radius
+}
+
+// Loop returns true if the path starts over after its last waypoint.
+func (p *pathFollower) Loop() bool {
+	p.dataLock.RLock()
+	defer p.dataLock.RUnlock()
+	return p.loop
+}
+
+// SetLoop sets whether the path starts over after its last waypoint.
+func (p *pathFollower) SetLoop(loop bool) {
+	p.dataLock.Lock()
+	defer p.dataLock.Unlock()
+	p.loop = loop
+}
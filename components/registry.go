@@ -0,0 +1,406 @@
+package components
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// ComponentFactory creates a component from json describing it.  Fields missing from the json keep the component's defaults.
+type ComponentFactory func(data []byte) (Component, error)
+
+var (
+	factories     = make(map[string]ComponentFactory)
+	factoriesLock sync.RWMutex
+)
+
+func init() {
+	RegisterComponent(TypeTransform, newTransformFromJSON)
+	RegisterComponent(TypeMesh, newMeshFromJSON)
+	RegisterComponent(TypeVelocity, newVelocityFromJSON)
+	RegisterComponent(TypeAcceleration, newAccelerationFromJSON)
+	RegisterComponent(TypeCollider, newColliderFromJSON)
+	RegisterComponent(TypeRigidBody, newRigidBodyFromJSON)
+	RegisterComponent(TypeIntegrator, newIntegratorFromJSON)
+	RegisterComponent(TypePathFollower, newPathFollowerFromJSON)
+	RegisterComponent(TypeAnimation, newAnimationFromJSON)
+	RegisterComponent(TypeSprite, newSpriteFromJSON)
+	RegisterComponent(TypeSkeleton, newSkeletonFromJSON)
+	RegisterComponent(TypeCamera, newCameraFromJSON)
+	RegisterComponent(TypeCameraController, newCameraControllerFromJSON)
+	RegisterComponent(TypeTint, newTintFromJSON)
+	RegisterComponent(TypeLight, newLightFromJSON)
+	RegisterComponent(TypeMaterial, newMaterialFromJSON)
+}
+
+// RegisterComponent makes a component type creatable by name, such as from the properties of an imported map.  Registering a type again replaces its factory.
+func RegisterComponent(componentType string, factory ComponentFactory) {
+	factoriesLock.Lock()
+	defer factoriesLock.Unlock()
+	factories[componentType] = factory
+}
+
+// IsRegistered returns true if a factory has been registered for the component type.
+func IsRegistered(componentType string) bool {
+	factoriesLock.RLock()
+	defer factoriesLock.RUnlock()
+	_, ok := factories[componentType]
+	return ok
+}
+
+// RegisteredComponents retrieves the names of every registered component type in alphabetical order.
+func RegisteredComponents() []string {
+	factoriesLock.RLock()
+	defer factoriesLock.RUnlock()
+	types := make([]string, 0, len(factories))
+	for t := range factories {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// CreateComponent creates a component of a registered type from json describing it.
+func CreateComponent(componentType string, data []byte) (Component, error) {
+	factoriesLock.RLock()
+	factory, ok := factories[componentType]
+	factoriesLock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown component type %s", componentType)
+	}
+	if len(data) == 0 {
+		data = []byte("{}")
+	}
+	c, err := factory(data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create %s: %v", componentType, err)
+	}
+	return c, nil
+}
+
+// fileData is the json of components loaded from a file.  Start names the clip or sequence to play.
+type fileData struct {
+	File  string `json:"file"`
+	Start string `json:"play"`
+}
+
+func newTransformFromJSON(data []byte) (Component, error) {
+	td := struct {
+		Position mgl32.Vec3 `json:"position"`
+		Rotation mgl32.Vec3 `json:"rotation"`
+		Scale    mgl32.Vec3 `json:"scale"`
+	}{Scale: mgl32.Vec3{1, 1, 1}}
+	if err := json.Unmarshal(data, &td); err != nil {
+		return nil, err
+	}
+	t := NewTransform()
+	t.SetTranslation(td.Position)
+	t.SetRotation(td.Rotation)
+	t.SetScale(td.Scale)
+	return t, nil
+}
+
+func newMeshFromJSON(data []byte) (Component, error) {
+	fd := fileData{}
+	if err := json.Unmarshal(data, &fd); err != nil {
+		return nil, err
+	}
+	m := NewMesh()
+	if err := m.Load(fd.File); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// motionData is the json of velocity and acceleration components.
+type motionData struct {
+	Rotational    mgl32.Vec3     `json:"rotational"`
+	Translational mgl32.Vec3     `json:"translational"`
+	Frame         ReferenceFrame `json:"frame"`
+	Parent        string         `json:"parent"`
+}
+
+func newVelocityFromJSON(data []byte) (Component, error) {
+	md := motionData{Frame: FrameWorld}
+	if err := json.Unmarshal(data, &md); err != nil {
+		return nil, err
+	}
+	v := NewVelocity()
+	v.Set(md.Rotational, md.Translational)
+	v.SetFrame(md.Frame)
+	v.SetParent(md.Parent)
+	return v, nil
+}
+
+func newAccelerationFromJSON(data []byte) (Component, error) {
+	md := motionData{Frame: FrameWorld}
+	if err := json.Unmarshal(data, &md); err != nil {
+		return nil, err
+	}
+	a := NewAcceleration()
+	a.Set(md.Rotational, md.Translational)
+	a.SetFrame(md.Frame)
+	a.SetParent(md.Parent)
+	return a, nil
+}
+
+func newColliderFromJSON(data []byte) (Component, error) {
+	cd := NewColliderData(ShapeAABB)
+	if err := json.Unmarshal(data, &cd); err != nil {
+		return nil, err
+	}
+	return NewCollider(cd), nil
+}
+
+func newRigidBodyFromJSON(data []byte) (Component, error) {
+	rbd := NewRigidBodyData()
+	if err := json.Unmarshal(data, &rbd); err != nil {
+		return nil, err
+	}
+	return NewRigidBody(rbd), nil
+}
+
+func newIntegratorFromJSON(data []byte) (Component, error) {
+	id := struct {
+		Method IntegrationMethod `json:"method"`
+	}{Method: IntegrateSemiImplicitEuler}
+	if err := json.Unmarshal(data, &id); err != nil {
+		return nil, err
+	}
+	return NewIntegrator(id.Method), nil
+}
+
+func newPathFollowerFromJSON(data []byte) (Component, error) {
+	pd := struct {
+		Speed        float32      `json:"speed"`
+		ArriveRadius float32      `json:"arriveRadius"`
+		Loop         bool         `json:"loop"`
+		Waypoints    []mgl32.Vec3 `json:"waypoints"`
+	}{}
+	if err := json.Unmarshal(data, &pd); err != nil {
+		return nil, err
+	}
+	p := NewPathFollower(pd.Speed)
+	if pd.ArriveRadius > 0 {
+		p.SetArriveRadius(pd.ArriveRadius)
+	}
+	p.SetLoop(pd.Loop)
+	p.SetWaypoints(pd.Waypoints)
+	return p, nil
+}
+
+func newAnimationFromJSON(data []byte) (Component, error) {
+	fd := fileData{}
+	if err := json.Unmarshal(data, &fd); err != nil {
+		return nil, err
+	}
+	a := NewAnimation()
+	if err := a.Load(fd.File); err != nil {
+		return nil, err
+	}
+	if fd.Start != "" {
+		if err := a.Play(fd.Start); err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+func newSpriteFromJSON(data []byte) (Component, error) {
+	fd := fileData{}
+	if err := json.Unmarshal(data, &fd); err != nil {
+		return nil, err
+	}
+	s := NewSprite()
+	if err := s.Load(fd.File); err != nil {
+		return nil, err
+	}
+	if fd.Start != "" {
+		if err := s.Play(fd.Start); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func newSkeletonFromJSON(data []byte) (Component, error) {
+	fd := fileData{}
+	if err := json.Unmarshal(data, &fd); err != nil {
+		return nil, err
+	}
+	s := NewSkeleton()
+	if err := s.Load(fd.File); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// CameraData is the json of a camera.  FOVY is in degrees and only used by perspective projections.  Size is the height an orthographic projection shows and Bounds, when set, replaces it with the left, right, bottom and top the projection shows.  The projection is calculated once the camera is resized to the window.  Target names the render target the camera draws into instead of the window.
+type CameraData struct {
+	Position   mgl32.Vec3     `json:"position"`
+	LookAt     mgl32.Vec3     `json:"lookat"`
+	Up         mgl32.Vec3     `json:"up"`
+	Projection ProjectionKind `json:"projection,omitempty"`
+	FOVY       float32        `json:"fovy"`
+	Size       float32        `json:"size,omitempty"`
+	Bounds     *[4]float32    `json:"bounds,omitempty"`
+	NearPlane  float32        `json:"nearPlane"`
+	FarPlane   float32        `json:"farPlane"`
+	Viewport   *Viewport      `json:"viewport,omitempty"`
+	Clear      *ClearFlags    `json:"clear,omitempty"`
+	ClearColor *mgl32.Vec4    `json:"clearColor,omitempty"`
+	Order      int            `json:"order,omitempty"`
+	Target     string         `json:"target,omitempty"`
+	Enabled    *bool          `json:"enabled,omitempty"`
+}
+
+// NewCameraData creates camera data looking down at the origin from above the -Y axis.
+func NewCameraData() CameraData {
+	return CameraData{
+		Position:  mgl32.Vec3{0, -10, 10},
+		Up:        mgl32.Vec3{0, 0, 1},
+		FOVY:      45,
+		NearPlane: 0.1,
+		FarPlane:  100,
+	}
+}
+
+// Camera creates a camera from the data for a window of the size passed in.
+func (cd CameraData) Camera(windowWidth, windowHeight int) Camera {
+	c := NewCamera()
+	if cd.Viewport != nil {
+		c.SetViewport(*cd.Viewport)
+	}
+	if cd.Clear != nil {
+		c.SetClearFlags(*cd.Clear)
+	}
+	if cd.ClearColor != nil {
+		c.SetClearColor(*cd.ClearColor)
+	}
+	if cd.Enabled != nil {
+		c.SetEnabled(*cd.Enabled)
+	}
+	c.SetOrder(cd.Order)
+	c.SetTarget(cd.Target)
+	c.SetView(cd.Position, cd.LookAt, cd.Up)
+	c.SetProjection(cd.FOVY, cd.NearPlane, cd.FarPlane, windowWidth, windowHeight)
+	switch cd.Projection {
+	case ProjectionOrthographic:
+		if cd.Bounds != nil {
+			b := cd.Bounds
+			c.SetOrthographicBounds(b[0], b[1], b[2], b[3], cd.NearPlane, cd.FarPlane)
+		} else if cd.Size > 0 {
+			c.SetOrthographic(cd.Size, cd.NearPlane, cd.FarPlane)
+		} else {
+			c.SetProjectionKind(ProjectionOrthographic)
+		}
+	case ProjectionPixel:
+		c.SetPixelPerfect(cd.NearPlane, cd.FarPlane)
+	}
+	return c
+}
+
+func newCameraFromJSON(data []byte) (Component, error) {
+	cd := NewCameraData()
+	if err := json.Unmarshal(data, &cd); err != nil {
+		return nil, err
+	}
+	return cd.Camera(0, 0), nil
+}
+
+func newCameraControllerFromJSON(data []byte) (Component, error) {
+	kind := struct {
+		Kind CameraControllerKind `json:"kind"`
+	}{}
+	if err := json.Unmarshal(data, &kind); err != nil {
+		return nil, err
+	}
+
+	switch kind.Kind {
+	case ControllerOrbit:
+		settings := NewOrbitSettings()
+		if err := json.Unmarshal(data, &settings); err != nil {
+			return nil, err
+		}
+		return NewOrbitController(settings), nil
+	case ControllerFly:
+		settings := NewFlySettings()
+		if err := json.Unmarshal(data, &settings); err != nil {
+			return nil, err
+		}
+		return NewFlyController(settings), nil
+	case ControllerRTS:
+		settings := NewRTSSettings()
+		if err := json.Unmarshal(data, &settings); err != nil {
+			return nil, err
+		}
+		return NewRTSController(settings), nil
+	default:
+		return nil, fmt.Errorf("unknown camera controller %q", kind.Kind)
+	}
+}
+
+func newTintFromJSON(data []byte) (Component, error) {
+	td := struct {
+		Color mgl32.Vec4 `json:"color"`
+	}{Color: mgl32.Vec4{1, 1, 1, 1}}
+	if err := json.Unmarshal(data, &td); err != nil {
+		return nil, err
+	}
+	return NewTint(td.Color), nil
+}
+
+func newLightFromJSON(data []byte) (Component, error) {
+	kind := struct {
+		Kind LightKind `json:"kind"`
+	}{Kind: LightPoint}
+	if err := json.Unmarshal(data, &kind); err != nil {
+		return nil, err
+	}
+	switch kind.Kind {
+	case LightDirectional, LightPoint, LightSpot:
+	default:
+		return nil, fmt.Errorf("unknown light %q", kind.Kind)
+	}
+
+	ld := NewLightData(kind.Kind)
+	if err := json.Unmarshal(data, &ld); err != nil {
+		return nil, err
+	}
+	return NewLight(ld), nil
+}
+
+// materialJSON is the json of a material component.  File names a material that is shared with every entity using it, and any other setting overrides that material for this entity alone.
+type materialJSON struct {
+	File string `json:"file"`
+	MaterialData
+}
+
+func newMaterialFromJSON(data []byte) (Component, error) {
+	mj := materialJSON{}
+	if err := json.Unmarshal(data, &mj); err != nil {
+		return nil, err
+	}
+	if err := mj.Validate(); err != nil {
+		return nil, err
+	}
+	if mj.File == "" {
+		m := NewMaterial()
+		m.Set(mj.MaterialData)
+		return m, nil
+	}
+
+	shared, err := LoadMaterial(mj.File)
+	if err != nil {
+		return nil, err
+	}
+	if mj.IsEmpty() {
+		return shared, nil
+	}
+	m := NewMaterialInstance(shared)
+	m.Set(mj.MaterialData)
+	return m, nil
+}
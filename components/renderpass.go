@@ -0,0 +1,48 @@
+package components
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// RenderPass groups what is drawn together.  Passes draw in order, so everything in a later pass draws over an earlier one.
+type RenderPass int
+
+const (
+	// PassOpaque draws solid meshes with depth testing.  It is the default pass.
+	PassOpaque RenderPass = iota
+	// PassTransparent draws blended meshes from the furthest to the nearest after everything solid.
+	PassTransparent
+	// PassOverlay draws blended meshes without depth testing on top of everything else, such as for user interfaces.
+	PassOverlay
+)
+
+var renderPassNames = []string{"opaque", "transparent", "overlay"}
+
+// String retrieves the name of the pass.
+func (p RenderPass) String() string {
+	if p < 0 || int(p) >= len(renderPassNames) {
+		return fmt.Sprintf("RenderPass(%d)", int(p))
+	}
+	return renderPassNames[p]
+}
+
+// MarshalJSON encodes the pass by its name.
+func (p RenderPass) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.String())
+}
+
+// UnmarshalJSON decodes the pass from its name.
+func (p *RenderPass) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+	for i, n := range renderPassNames {
+		if n == name {
+			*p = RenderPass(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown render pass %q", name)
+}
@@ -0,0 +1,212 @@
+package components
+
+import (
+	"sync"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	// TypeRigidBody represents a rigid body component's type.
+	TypeRigidBody = "rigidBody"
+)
+
+// BodyKind describes how a rigid body takes part in the simulation.
+type BodyKind string
+
+const (
+	// BodyDynamic bodies are moved by forces, gravity and collisions.
+	BodyDynamic BodyKind = "dynamic"
+	// BodyKinematic bodies are moved only by their velocity and acceleration.  They push dynamic bodies but are never pushed back.
+	BodyKinematic BodyKind = "kinematic"
+	// BodyStatic bodies never move.
+	BodyStatic BodyKind = "static"
+)
+
+// RigidBody represents the mass properties of an entity and the forces acting on it.  The body's motion is stored in the entity's Velocity and Acceleration components.
+type RigidBody interface {
+	Component
+	// Data retrieves the rigid body data.
+	Data() RigidBodyData
+	// Set replaces the rigid body data.
+	Set(RigidBodyData)
+	// Kind retrieves how the body takes part in the simulation.
+	Kind() BodyKind
+	// SetKind sets how the body takes part in the simulation.
+	SetKind(BodyKind)
+	// Mass retrieves the mass of the body.
+	Mass() float32
+	// SetMass sets the mass of the body.
+	SetMass(float32)
+	// InverseMass retrieves one over the mass, which is 0 for bodies that cannot be pushed.
+	InverseMass() float32
+	// InverseInertia retrieves one over the moment of inertia about each local axis, which is 0 for bodies that cannot be turned.
+	InverseInertia() mgl32.Vec3
+	// AddForce applies a world space force over the next update.
+	AddForce(mgl32.Vec3)
+	// AddTorque applies a world space torque over the next update.
+	AddTorque(mgl32.Vec3)
+	// AddImpulse instantly changes the momentum of the body.
+	AddImpulse(mgl32.Vec3)
+	// AddAngularImpulse instantly changes the angular momentum of the body.
+	AddAngularImpulse(mgl32.Vec3)
+	// TakeForces retrieves the forces and impulses added since the last call and clears them.
+	TakeForces() RigidBodyForces
+}
+
+// RigidBodyData describes the physical properties of a body.  Inertia is the moment of inertia about each local axis.  A MaxSpeed or MaxAngularSpeed of 0 leaves the speed unlimited.
+type RigidBodyData struct {
+	Kind            BodyKind   `json:"kind"`
+	Mass            float32    `json:"mass"`
+	Inertia         mgl32.Vec3 `json:"inertia"`
+	LinearDamping   float32    `json:"linearDamping"`
+	AngularDamping  float32    `json:"angularDamping"`
+	MaxSpeed        float32    `json:"maxSpeed"`
+	MaxAngularSpeed float32    `json:"maxAngularSpeed"`
+	GravityScale    float32    `json:"gravityScale"`
+	Restitution     float32    `json:"restitution"`
+	Friction        float32    `json:"friction"`
+}
+
+// RigidBodyForces holds the forces and impulses accumulated on a body between updates.
+type RigidBodyForces struct {
+	Force          mgl32.Vec3
+	Torque         mgl32.Vec3
+	Impulse        mgl32.Vec3
+	AngularImpulse mgl32.Vec3
+}
+
+// NewRigidBodyData creates the data of a dynamic body with a mass of 1 that is fully affected by gravity.  Decoding json into it keeps these defaults for any missing fields.
+func NewRigidBodyData() RigidBodyData {
+	return RigidBodyData{
+		Kind:         BodyDynamic,
+		Mass:         1,
+		Inertia:      mgl32.Vec3{1, 1, 1},
+		GravityScale: 1,
+		Friction:     0.5,
+	}
+}
+
+type rigidBody struct {
+	data     RigidBodyData
+	forces   RigidBodyForces
+	dataLock sync.RWMutex
+}
+
+// NewRigidBody creates a new RigidBody component.
+func NewRigidBody(rbd RigidBodyData) RigidBody {
+	rb := rigidBody{
+		data: rbd,
+	}
+	return &rb
+}
+
+// Type retrieves the type of this component.
+func (rb *rigidBody) Type() string {
+	return TypeRigidBody
+}
+
+// Data retrieves the rigid body data.
+func (rb *rigidBody) Data() RigidBodyData {
+	rb.dataLock.RLock()
+	defer rb.dataLock.RUnlock()
+	return rb.data
+}
+
+// Set replaces the rigid body data.
+func (rb *rigidBody) Set(rbd RigidBodyData) {
+	rb.dataLock.Lock()
+	defer rb.dataLock.Unlock()
+	rb.data = rbd
+}
+
+// Kind retrieves how the body takes part in the simulation.
+func (rb *rigidBody) Kind() BodyKind {
+	rb.dataLock.RLock()
+	defer rb.dataLock.RUnlock()
+	return rb.data.Kind
+}
+
+// SetKind sets how the body takes part in the simulation.
+func (rb *rigidBody) SetKind(kind BodyKind) {
+	rb.dataLock.Lock()
+	defer rb.dataLock.Unlock()
+	rb.data.Kind = kind
+}
+
+// Mass retrieves the mass of the body.
+func (rb *rigidBody) Mass() float32 {
+	rb.dataLock.RLock()
+	defer rb.dataLock.RUnlock()
+	return rb.data.Mass
+}
+
+// SetMass sets the mass of the body.
+func (rb *rigidBody) SetMass(mass float32) {
+	rb.dataLock.Lock()
+	defer rb.dataLock.Unlock()
+	rb.data.Mass = mass
+}
+
+// InverseMass retrieves one over the mass.  Only dynamic bodies with a positive mass can be pushed.
+func (rb *rigidBody) InverseMass() float32 {
+	rb.dataLock.RLock()
+	defer rb.dataLock.RUnlock()
+	if rb.data.Kind != BodyDynamic || rb.data.Mass <= 0 {
+		return 0
+	}
+	return 1 / rb.data.Mass
+}
+
+// InverseInertia retrieves one over the moment of inertia about each local axis.  Only dynamic bodies can be turned, and an axis with no inertia cannot be turned about.
+func (rb *rigidBody) InverseInertia() mgl32.Vec3 {
+	rb.dataLock.RLock()
+	defer rb.dataLock.RUnlock()
+	var inv mgl32.Vec3
+	if rb.data.Kind != BodyDynamic {
+		return inv
+	}
+	for i := 0; i < 3; i++ {
+		if rb.data.Inertia[i] > 0 {
+			inv[i] = 1 / rb.data.Inertia[i]
+		}
+	}
+	return inv
+}
+
+// AddForce applies a world space force over the next update.
+func (rb *rigidBody) AddForce(force mgl32.Vec3) {
+	rb.dataLock.Lock()
+	defer rb.dataLock.Unlock()
+	rb.forces.Force = rb.forces.Force.Add(force)
+}
+
+// AddTorque applies a world space torque over the next update.
+func (rb *rigidBody) AddTorque(torque mgl32.Vec3) {
+	rb.dataLock.Lock()
+	defer rb.dataLock.Unlock()
+	rb.forces.Torque = rb.forces.Torque.Add(torque)
+}
+
+// AddImpulse instantly changes the momentum of the body.
+func (rb *rigidBody) AddImpulse(impulse mgl32.Vec3) {
+	rb.dataLock.Lock()
+	defer rb.dataLock.Unlock()
+	rb.forces.Impulse = rb.forces.Impulse.Add(impulse)
+}
+
+// AddAngularImpulse instantly changes the angular momentum of the body.
+func (rb *rigidBody) AddAngularImpulse(impulse mgl32.Vec3) {
+	rb.dataLock.Lock()
+	defer rb.dataLock.Unlock()
+	rb.forces.AngularImpulse = rb.forces.AngularImpulse.Add(impulse)
+}
+
+// TakeForces retrieves the forces and impulses added since the last call and clears them.
+func (rb *rigidBody) TakeForces() RigidBodyForces {
+	rb.dataLock.Lock()
+	defer rb.dataLock.Unlock()
+	forces := rb.forces
+	rb.forces = RigidBodyForces{}
+	return forces
+}
@@ -0,0 +1,240 @@
+package components
+
+import (
+	"sync"
+
+	"github.com/go-gl/mathgl/mgl32"
+
+	"github.com/Ariemeth/quantum-pulse/geometry"
+	"github.com/Ariemeth/quantum-pulse/input"
+)
+
+// RTSSettings configures an RTS controller.  The camera looks down at a point on the ground which the movement keys and screen edges pan.  Dragging with the grab button keeps the ground under the cursor, dragging with the rotate button or holding the rotate keys turns around the point and the scroll wheel zooms.
+type RTSSettings struct {
+	Forward     input.Key `json:"forward"`
+	Back        input.Key `json:"back"`
+	Left        input.Key `json:"left"`
+	Right       input.Key `json:"right"`
+	RotateLeft  input.Key `json:"rotateLeft"`
+	RotateRight input.Key `json:"rotateRight"`
+	// UseArrows also pans with the arrow keys.
+	UseArrows    bool              `json:"useArrows"`
+	GrabButton   input.MouseButton `json:"grabButton"`
+	RotateButton input.MouseButton `json:"rotateButton"`
+	// PanSpeed is the distance panned per second as a multiple of the distance to the ground.
+	PanSpeed float32 `json:"panSpeed"`
+	// EdgeSize is how close in pixels the cursor must be to the edge of the window to pan.  0 turns edge panning off.
+	EdgeSize float32 `json:"edgeSize"`
+	// RotateSpeed is the angle turned per second by the keys.
+	RotateSpeed float32 `json:"rotateSpeed"`
+	// DragRotateSpeed is the angle turned per pixel dragged.
+	DragRotateSpeed float32 `json:"dragRotateSpeed"`
+	// ZoomSpeed is the fraction of the distance to the ground moved per scroll step.
+	ZoomSpeed   float32 `json:"zoomSpeed"`
+	MinDistance float32 `json:"minDistance"`
+	MaxDistance float32 `json:"maxDistance"`
+	// GroundHeight is the height along Z of the plane the camera pans over.
+	GroundHeight float32 `json:"groundHeight"`
+	// MinBounds and MaxBounds limit the point looked at along X and Y.  Axes where the maximum is not above the minimum are not limited.
+	MinBounds mgl32.Vec2 `json:"minBounds"`
+	MaxBounds mgl32.Vec2 `json:"maxBounds"`
+}
+
+// NewRTSSettings creates RTS settings that pan with WASD, the arrows and the screen edges, grab with the middle button, rotate with the right button or Q and E and zoom between 2 and 50 units.
+func NewRTSSettings() RTSSettings {
+	return RTSSettings{
+		Forward:         input.KeyW,
+		Back:            input.KeyS,
+		Left:            input.KeyA,
+		Right:           input.KeyD,
+		RotateLeft:      input.KeyQ,
+		RotateRight:     input.KeyE,
+		UseArrows:       true,
+		GrabButton:      input.MouseButtonMiddle,
+		RotateButton:    input.MouseButtonRight,
+		PanSpeed:        1,
+		EdgeSize:        10,
+		RotateSpeed:     1.5,
+		DragRotateSpeed: 0.01,
+		ZoomSpeed:       0.1,
+		MinDistance:     2,
+		MaxDistance:     50,
+	}
+}
+
+// RTSController represents a camera controller that pans over the ground.
+type RTSController interface {
+	CameraController
+	// Settings retrieves the controller settings.
+	Settings() RTSSettings
+	// SetSettings replaces the controller settings.
+	SetSettings(RTSSettings)
+	// Focus retrieves the point on the ground the camera looks at.
+	Focus() mgl32.Vec3
+	// SetFocus moves the camera to look at a point on the ground.
+	SetFocus(mgl32.Vec3)
+}
+
+type rtsController struct {
+	settings RTSSettings
+	state    orbitState
+	focus    *mgl32.Vec3
+	enabled  bool
+	dataLock sync.RWMutex
+}
+
+// NewRTSController creates a new RTSController component.
+func NewRTSController(settings RTSSettings) RTSController {
+	r := rtsController{
+		settings: settings,
+		enabled:  true,
+	}
+	return &r
+}
+
+// Type retrieves the type of this component.
+func (r *rtsController) Type() string {
+	return TypeCameraController
+}
+
+// Kind retrieves the way the controller moves its camera.
+func (r *rtsController) Kind() CameraControllerKind {
+	return ControllerRTS
+}
+
+// Enabled returns true if the controller responds to input.
+func (r *rtsController) Enabled() bool {
+	r.dataLock.RLock()
+	defer r.dataLock.RUnlock()
+	return r.enabled
+}
+
+// SetEnabled sets whether the controller responds to input.
+func (r *rtsController) SetEnabled(enabled bool) {
+	r.dataLock.Lock()
+	defer r.dataLock.Unlock()
+	r.enabled = enabled
+}
+
+// Settings retrieves the controller settings.
+func (r *rtsController) Settings() RTSSettings {
+	r.dataLock.RLock()
+	defer r.dataLock.RUnlock()
+	return r.settings
+}
+
+// SetSettings replaces the controller settings.
+func (r *rtsController) SetSettings(settings RTSSettings) {
+	r.dataLock.Lock()
+	defer r.dataLock.Unlock()
+	r.settings = settings
+}
+
+// Focus retrieves the point on the ground the camera looks at.
+func (r *rtsController) Focus() mgl32.Vec3 {
+	r.dataLock.RLock()
+	defer r.dataLock.RUnlock()
+	if r.focus != nil {
+		return *r.focus
+	}
+	return r.state.center
+}
+
+// SetFocus moves the camera to look at a point on the ground.
+func (r *rtsController) SetFocus(focus mgl32.Vec3) {
+	r.dataLock.Lock()
+	defer r.dataLock.Unlock()
+	r.focus = &focus
+}
+
+// Update pans, grabs, rotates and zooms the camera.
+func (r *rtsController) Update(cam Camera, in input.Snapshot, elapsed float32) {
+	r.dataLock.Lock()
+	defer r.dataLock.Unlock()
+
+	if !r.enabled {
+		return
+	}
+	s := r.settings
+	ground := geometry.PlaneFromPoint(mgl32.Vec3{0, 0, s.GroundHeight}, worldUp)
+
+	if !r.state.isSet {
+		r.state.fromCamera(cam)
+		// Look at the ground rather than wherever the camera was pointed.
+		eye := cam.PositionVec3()
+		ray := geometry.Ray{Origin: eye, Direction: r.state.center.Sub(eye).Normalize()}
+		if t, ok := ray.IntersectPlane(ground); ok {
+			r.state.center = ray.At(t)
+			r.state.distance = t
+		}
+		// A camera looking straight down has no yaw to rotate around.
+		r.state.pitch = mgl32.Clamp(r.state.pitch, 0.1, 1.5)
+	}
+	if r.focus != nil {
+		r.state.center = *r.focus
+		r.focus = nil
+	}
+	r.state.center[2] = s.GroundHeight
+
+	// The view direction flattened onto the ground, pointing away from the camera.
+	forward := direction(r.state.yaw, 0).Mul(-1)
+	right := forward.Cross(worldUp)
+
+	moveX := in.Axis(s.Left, s.Right)
+	moveY := in.Axis(s.Back, s.Forward)
+	if s.UseArrows {
+		moveX += in.Axis(input.KeyLeft, input.KeyRight)
+		moveY += in.Axis(input.KeyDown, input.KeyUp)
+	}
+	if s.EdgeSize > 0 && in.HasCursor && in.Width > 0 && in.Height > 0 {
+		x, y := float32(in.CursorX), float32(in.CursorY)
+		w, h := float32(in.Width), float32(in.Height)
+		if x >= 0 && y >= 0 && x <= w && y <= h {
+			switch {
+			case x < s.EdgeSize:
+				moveX--
+			case x > w-s.EdgeSize:
+				moveX++
+			}
+			switch {
+			case y < s.EdgeSize:
+				moveY++
+			case y > h-s.EdgeSize:
+				moveY--
+			}
+		}
+	}
+	moveX = mgl32.Clamp(moveX, -1, 1)
+	moveY = mgl32.Clamp(moveY, -1, 1)
+	speed := s.PanSpeed * r.state.distance * elapsed
+	r.state.center = r.state.center.Add(right.Mul(moveX * speed)).Add(forward.Mul(moveY * speed))
+
+	if in.ButtonDown(s.GrabButton) && (in.DeltaX != 0 || in.DeltaY != 0) {
+		// Move by how far the ground under the cursor slid, so the point grabbed stays under the cursor.
+		before, okBefore := cam.ScreenToRay(in.CursorX-in.DeltaX, in.CursorY-in.DeltaY)
+		after, okAfter := cam.ScreenToRay(in.CursorX, in.CursorY)
+		if okBefore && okAfter {
+			tBefore, hitBefore := before.IntersectPlane(ground)
+			tAfter, hitAfter := after.IntersectPlane(ground)
+			if hitBefore && hitAfter {
+				r.state.center = r.state.center.Add(before.At(tBefore).Sub(after.At(tAfter)))
+			}
+		}
+	}
+
+	r.state.yaw += in.Axis(s.RotateLeft, s.RotateRight) * s.RotateSpeed * elapsed
+	if in.ButtonDown(s.RotateButton) {
+		r.state.yaw -= float32(in.DeltaX) * s.DragRotateSpeed
+	}
+
+	distance := r.state.distance
+	r.state.distance = zoom(r.state.distance, in.ScrollY, s.ZoomSpeed, s.MinDistance, s.MaxDistance)
+	zoomOrthographic(cam, distance, r.state.distance)
+	for i := 0; i < 2; i++ {
+		if s.MaxBounds[i] > s.MinBounds[i] {
+			r.state.center[i] = mgl32.Clamp(r.state.center[i], s.MinBounds[i], s.MaxBounds[i])
+		}
+	}
+	r.state.center[2] = s.GroundHeight
+	r.state.apply(cam)
+}
@@ -0,0 +1,279 @@
+package components
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	// TypeSkeleton represents a skeleton component's type.
+	TypeSkeleton = "skeleton"
+	// JointsPerVertex is the number of joint indices and weights each vertex of a skinned mesh has.
+	JointsPerVertex = 4
+)
+
+// Skeleton represents a component holding a joint hierarchy used to skin a mesh.
+type Skeleton interface {
+	Component
+	// Data retrieves the skeleton data.
+	Data() SkeletonData
+	// Set replaces the skeleton data and resets every joint to its rest pose.
+	Set(SkeletonData)
+	// Load loads the skeleton data from file.
+	Load(string) error
+	// JointIndex retrieves the index of a joint by name.
+	JointIndex(name string) (int, bool)
+	// ApplyPose sets the local transform of each named joint from an animation pose.  Channels missing from a pose keep the joint's rest value.
+	ApplyPose(map[string]AnimationPose)
+	// ResetPose returns every joint to its rest pose.
+	ResetPose()
+	// JointMatrices retrieves the skinning matrix of every joint, which is the joint's model space transform multiplied by its inverse bind matrix.
+	JointMatrices() []mgl32.Mat4
+}
+
+// SkeletonData represents the joints making up a skeleton.  Joints are expected to be listed with parents before their children.
+type SkeletonData struct {
+	Joints []Joint `json:"joints"`
+}
+
+// Joint is a single joint of a skeleton along with its rest pose.  Parent is the index of the parent joint or -1 for a root joint.
+type Joint struct {
+	Name        string     `json:"name"`
+	Parent      int        `json:"parent"`
+	InverseBind mgl32.Mat4 `json:"inverseBind"`
+	Translation mgl32.Vec3 `json:"translation"`
+	Rotation    []float32  `json:"rotation"`
+	Scale       []float32  `json:"scale"`
+}
+
+type jointPose struct {
+	translation mgl32.Vec3
+	rotation    mgl32.Quat
+	scale       mgl32.Vec3
+}
+
+type skeleton struct {
+	data     SkeletonData
+	indices  map[string]int
+	poses    []jointPose
+	matrices []mgl32.Mat4
+	dirty    bool
+	isLoaded bool
+	dataLock sync.RWMutex
+}
+
+// NewSkeleton creates a new Skeleton component.
+func NewSkeleton() Skeleton {
+	s := skeleton{
+		indices: make(map[string]int),
+	}
+	return &s
+}
+
+// Type retrieves the type of this component.
+func (s *skeleton) Type() string {
+	return TypeSkeleton
+}
+
+// Data retrieves the skeleton data.
+func (s *skeleton) Data() SkeletonData {
+	s.dataLock.RLock()
+	defer s.dataLock.RUnlock()
+	return s.data
+}
+
+// Set replaces the skeleton data and resets every joint to its rest pose.
+func (s *skeleton) Set(sd SkeletonData) {
+	s.dataLock.Lock()
+	defer s.dataLock.Unlock()
+	s.set(sd)
+}
+
+// Load loads a skeleton from file and returns an error if an error occurs while loading the file.  Skeleton files are expected to be stored alongside the mesh files.  If the skeleton has already been loaded an "Already Loaded" error will be returned.
+func (s *skeleton) Load(fileName string) error {
+	s.dataLock.Lock()
+	defer s.dataLock.Unlock()
+
+	if s.isLoaded {
+		return errors.New("Already Loaded")
+	}
+
+	fullFileName := ""
+	if strings.Contains(fileName, MeshSrcDir) {
+		fullFileName = fileName
+	} else {
+		fullFileName = fmt.Sprintf("%s%s", MeshSrcDir, fileName)
+	}
+
+	data, err := ioutil.ReadFile(fullFileName)
+	if err != nil {
+		fmt.Println(err.Error())
+		return err
+	}
+
+	var sd SkeletonData
+	err = json.Unmarshal(data, &sd)
+	if err != nil {
+		return err
+	}
+
+	s.set(sd)
+	s.isLoaded = true
+	return nil
+}
+
+// JointIndex retrieves the index of a joint by name.
+func (s *skeleton) JointIndex(name string) (int, bool) {
+	s.dataLock.RLock()
+	defer s.dataLock.RUnlock()
+	i, ok := s.indices[name]
+	return i, ok
+}
+
+// ApplyPose sets the local transform of each named joint from an animation pose.
+func (s *skeleton) ApplyPose(poses map[string]AnimationPose) {
+	s.dataLock.Lock()
+	defer s.dataLock.Unlock()
+
+	for name, pose := range poses {
+		i, ok := s.indices[name]
+		if !ok {
+			continue
+		}
+		rest := restPose(s.data.Joints[i])
+		if pose.HasTranslation {
+			rest.translation = pose.Translation
+		}
+		if pose.HasRotation {
+			rest.rotation = pose.Rotation
+		}
+		if pose.HasScale {
+			rest.scale = pose.Scale
+		}
+		s.poses[i] = rest
+	}
+	s.dirty = true
+}
+
+// ResetPose returns every joint to its rest pose.
+func (s *skeleton) ResetPose() {
+	s.dataLock.Lock()
+	defer s.dataLock.Unlock()
+	for i, j := range s.data.Joints {
+		s.poses[i] = restPose(j)
+	}
+	s.dirty = true
+}
+
+// JointMatrices retrieves the skinning matrix of every joint.  The matrices are only recalculated when the pose has changed.
+func (s *skeleton) JointMatrices() []mgl32.Mat4 {
+	s.dataLock.Lock()
+	defer s.dataLock.Unlock()
+
+	if s.dirty {
+		s.calculate()
+	}
+	matrices := make([]mgl32.Mat4, len(s.matrices))
+	copy(matrices, s.matrices)
+	return matrices
+}
+
+// set replaces the skeleton data.  The caller is expected to hold the write lock.
+func (s *skeleton) set(sd SkeletonData) {
+	s.data = sd
+	s.indices = make(map[string]int, len(sd.Joints))
+	s.poses = make([]jointPose, len(sd.Joints))
+	s.matrices = make([]mgl32.Mat4, len(sd.Joints))
+	for i, j := range sd.Joints {
+		s.indices[j.Name] = i
+		s.poses[i] = restPose(j)
+	}
+	s.dirty = true
+}
+
+// calculate rebuilds the skinning matrices from the joint poses.  The caller is expected to hold the write lock.
+func (s *skeleton) calculate() {
+	world := make([]mgl32.Mat4, len(s.poses))
+	for i, p := range s.poses {
+		local := mgl32.Translate3D(p.translation.X(), p.translation.Y(), p.translation.Z()).
+			Mul4(p.rotation.Mat4()).
+			Mul4(mgl32.Scale3D(p.scale.X(), p.scale.Y(), p.scale.Z()))
+
+		parent := s.data.Joints[i].Parent
+		if parent >= 0 && parent < i {
+			world[i] = world[parent].Mul4(local)
+		} else {
+			world[i] = local
+		}
+		s.matrices[i] = world[i].Mul4(inverseBind(s.data.Joints[i]))
+	}
+	s.dirty = false
+}
+
+// restPose retrieves the pose a joint has when it is not animated.
+func restPose(j Joint) jointPose {
+	p := jointPose{
+		translation: j.Translation,
+		rotation:    mgl32.QuatIdent(),
+		scale:       mgl32.Vec3{1, 1, 1},
+	}
+	if len(j.Rotation) > 0 {
+		p.rotation = keyQuat(j.Rotation)
+	}
+	if len(j.Scale) > 0 {
+		p.scale = toVec3(j.Scale)
+	}
+	return p
+}
+
+// inverseBind retrieves a joint's inverse bind matrix, treating a missing matrix as the identity.
+func inverseBind(j Joint) mgl32.Mat4 {
+	if j.InverseBind == (mgl32.Mat4{}) {
+		return mgl32.Ident4()
+	}
+	return j.InverseBind
+}
+
+// SkinVertices applies joint matrices to the positions of a skinned mesh on the CPU and returns a copy of the interleaved vertex data.  It is used when the shader does not support skinning.
+func SkinVertices(md MeshData, joints []mgl32.Mat4) []float32 {
+	verts := make([]float32, len(md.Verts))
+	copy(verts, md.Verts)
+	if md.VertSize <= 0 || len(joints) == 0 {
+		return verts
+	}
+
+	count := len(md.Verts) / int(md.VertSize)
+	for v := 0; v < count; v++ {
+		if (v+1)*JointsPerVertex > len(md.Joints) || (v+1)*JointsPerVertex > len(md.Weights) {
+			break
+		}
+		offset := v * int(md.VertSize)
+		pos := mgl32.Vec4{md.Verts[offset], md.Verts[offset+1], md.Verts[offset+2], 1}
+
+		skinned := mgl32.Vec4{}
+		total := float32(0)
+		for n := 0; n < JointsPerVertex; n++ {
+			weight := md.Weights[v*JointsPerVertex+n]
+			joint := int(md.Joints[v*JointsPerVertex+n])
+			if weight == 0 || joint >= len(joints) {
+				continue
+			}
+			skinned = skinned.Add(joints[joint].Mul4x1(pos).Mul(weight))
+			total += weight
+		}
+		if total == 0 {
+			continue
+		}
+		if total != 1 {
+			skinned = skinned.Mul(1 / total)
+		}
+		verts[offset], verts[offset+1], verts[offset+2] = skinned.X(), skinned.Y(), skinned.Z()
+	}
+	return verts
+}
@@ -0,0 +1,150 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// near returns true if every component of two vectors is within epsilon of each other.  mgl32's thresholds are relative, which is too strict near zero.
+func near(a, b mgl32.Vec3) bool {
+	for i := range a {
+		if d := a[i] - b[i]; d > epsilon || d < -epsilon {
+			return false
+		}
+	}
+	return true
+}
+
+// armData is a root joint at x 1 turned a quarter turn about z with a child 2 units along the root's y.
+func armData(bind bool) SkeletonData {
+	quarter := mgl32.QuatRotate(mgl32.DegToRad(90), mgl32.Vec3{0, 0, 1})
+	sd := SkeletonData{Joints: []Joint{
+		{Name: "root", Parent: -1, Translation: mgl32.Vec3{1, 0, 0}, Rotation: []float32{quarter.X(), quarter.Y(), quarter.Z(), quarter.W}},
+		{Name: "hand", Parent: 0, Translation: mgl32.Vec3{0, 2, 0}},
+	}}
+	if bind {
+		rootWorld := mgl32.Translate3D(1, 0, 0).Mul4(quarter.Mat4())
+		handWorld := rootWorld.Mul4(mgl32.Translate3D(0, 2, 0))
+		sd.Joints[0].InverseBind = rootWorld.Inv()
+		sd.Joints[1].InverseBind = handWorld.Inv()
+	}
+	return sd
+}
+
+func TestJointMatrices(t *testing.T) {
+	tests := []struct {
+		name string
+		bind bool
+		pose map[string]AnimationPose
+		// want is where each joint's matrix moves the origin.
+		want []mgl32.Vec3
+	}{
+		{"rest without inverse binds", false, nil, []mgl32.Vec3{{1, 0, 0}, {-1, 0, 0}}},
+		{"rest with inverse binds", true, nil, []mgl32.Vec3{{0, 0, 0}, {0, 0, 0}}},
+		{"child follows the posed parent", false, map[string]AnimationPose{
+			"root": {Rotation: mgl32.QuatIdent(), HasRotation: true},
+		}, []mgl32.Vec3{{1, 0, 0}, {1, 2, 0}}},
+		{"inverse bind is applied after the posed parent", true, map[string]AnimationPose{
+			"root": {Translation: mgl32.Vec3{1, 0, 3}, HasTranslation: true},
+		}, []mgl32.Vec3{{0, 0, 3}, {0, 0, 3}}},
+	}
+	for _, test := range tests {
+		s := NewSkeleton()
+		s.Set(armData(test.bind))
+		if test.pose != nil {
+			s.ApplyPose(test.pose)
+		}
+		matrices := s.JointMatrices()
+		if len(matrices) != len(test.want) {
+			t.Fatalf("%s: got %d matrices, want %d", test.name, len(matrices), len(test.want))
+		}
+		for i, m := range matrices {
+			if got := mgl32.TransformCoordinate(mgl32.Vec3{}, m); !near(got, test.want[i]) {
+				t.Errorf("%s: joint %d moves the origin to %v, want %v", test.name, i, got, test.want[i])
+			}
+		}
+	}
+}
+
+func TestApplyPoseKeepsRestChannels(t *testing.T) {
+	s := NewSkeleton()
+	s.Set(SkeletonData{Joints: []Joint{
+		{Name: "root", Parent: -1, Translation: mgl32.Vec3{0, 2, 0}, Scale: []float32{2, 2, 2}},
+		{Name: "tail", Parent: 0, Translation: mgl32.Vec3{0, 1, 0}},
+	}})
+	half := mgl32.QuatRotate(mgl32.DegToRad(180), mgl32.Vec3{0, 0, 1})
+	s.ApplyPose(map[string]AnimationPose{
+		"root":    {Rotation: half, HasRotation: true, Translation: mgl32.Vec3{9, 9, 9}},
+		"missing": {Translation: mgl32.Vec3{5, 5, 5}, HasTranslation: true},
+	})
+
+	matrices := s.JointMatrices()
+	// The root keeps its rest translation and scale under the posed rotation, and the tail keeps its rest pose.
+	want := []mgl32.Mat4{
+		mgl32.Translate3D(0, 2, 0).Mul4(half.Mat4()).Mul4(mgl32.Scale3D(2, 2, 2)),
+		mgl32.Translate3D(0, 2, 0).Mul4(half.Mat4()).Mul4(mgl32.Scale3D(2, 2, 2)).Mul4(mgl32.Translate3D(0, 1, 0)),
+	}
+	for i := range want {
+		if !matrices[i].ApproxEqualThreshold(want[i], epsilon) {
+			t.Errorf("joint %d = %v, want %v", i, matrices[i], want[i])
+		}
+	}
+
+	s.ResetPose()
+	if got := mgl32.TransformCoordinate(mgl32.Vec3{}, s.JointMatrices()[1]); !near(got, mgl32.Vec3{0, 4, 0}) {
+		t.Errorf("after ResetPose the tail is at %v, want %v", got, mgl32.Vec3{0, 4, 0})
+	}
+}
+
+func TestSkinVertices(t *testing.T) {
+	// Joint 0 moves vertices 1 along x and joint 1 leaves them in place.
+	joints := []mgl32.Mat4{mgl32.Translate3D(1, 0, 0), mgl32.Ident4()}
+	tests := []struct {
+		name    string
+		verts   []float32
+		joints  []uint32
+		weights []float32
+		want    []float32
+	}{
+		{"single joint",
+			[]float32{1, 0, 0, 0.5, 0.5}, []uint32{0, 0, 0, 0}, []float32{1, 0, 0, 0},
+			[]float32{2, 0, 0, 0.5, 0.5}},
+		{"weights are normalized",
+			[]float32{1, 0, 0, 0.5, 0.5}, []uint32{0, 1, 0, 0}, []float32{1, 1, 0, 0},
+			[]float32{1.5, 0, 0, 0.5, 0.5}},
+		{"zero weights leave the vertex",
+			[]float32{1, 2, 3, 0.5, 0.5}, []uint32{0, 1, 0, 0}, []float32{0, 0, 0, 0},
+			[]float32{1, 2, 3, 0.5, 0.5}},
+		{"joints out of range are skipped",
+			[]float32{1, 0, 0, 0.5, 0.5}, []uint32{7, 0, 0, 0}, []float32{0.5, 0.5, 0, 0},
+			[]float32{2, 0, 0, 0.5, 0.5}},
+		{"vertices without joint data are left",
+			[]float32{1, 0, 0, 0, 0, 1, 0, 0, 0, 0}, []uint32{0, 0, 0, 0, 0}, []float32{1, 0, 0, 0, 1, 0},
+			[]float32{2, 0, 0, 0, 0, 1, 0, 0, 0, 0}},
+		{"no joint data",
+			[]float32{1, 0, 0, 0, 0}, nil, nil,
+			[]float32{1, 0, 0, 0, 0}},
+	}
+	for _, test := range tests {
+		md := MeshData{Verts: test.verts, VertSize: 5, Joints: test.joints, Weights: test.weights}
+		got := SkinVertices(md, joints)
+		if len(got) != len(test.want) {
+			t.Fatalf("%s: got %d values, want %d", test.name, len(got), len(test.want))
+		}
+		for i := range got {
+			if !mgl32.FloatEqualThreshold(got[i], test.want[i], epsilon) {
+				t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+				break
+			}
+		}
+		if &got[0] == &md.Verts[0] {
+			t.Errorf("%s: the mesh's vertices were skinned in place", test.name)
+		}
+	}
+
+	md := MeshData{Verts: []float32{1, 0, 0, 0, 0}, VertSize: 5, Joints: []uint32{0, 0, 0, 0}, Weights: []float32{1, 0, 0, 0}}
+	if got := SkinVertices(md, nil); got[0] != 1 {
+		t.Errorf("without joint matrices the vertex moved to %v", got[:3])
+	}
+}
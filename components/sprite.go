@@ -0,0 +1,284 @@
+package components
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	// TypeSprite represents a sprite component's type.
+	TypeSprite = "sprite"
+	// SpriteSrcDir is the expected location of sprite sheets.
+	SpriteSrcDir = "assets/sprites/"
+)
+
+// Sprite represents a component that animates a mesh's texture coordinates through the frames of a sprite sheet.
+type Sprite interface {
+	Component
+	// Sheet retrieves the sprite sheet.
+	Sheet() SpriteSheet
+	// Set replaces the sprite sheet and shows its first frame.
+	Set(SpriteSheet)
+	// Load loads the sprite sheet from file.
+	Load(string) error
+	// Play starts playing the named sequence from its first frame.
+	Play(name string) error
+	// Stop stops playback leaving the current frame visible.
+	Stop()
+	// IsPlaying returns true while a sequence is being played.
+	IsPlaying() bool
+	// Current retrieves the name of the sequence being played.
+	Current() string
+	// SetFrame stops playback and shows a specific frame of the sheet.
+	SetFrame(int)
+	// Frame retrieves the frame of the sheet currently shown.
+	Frame() int
+	// Update advances playback by the elapsed seconds.
+	Update(elapsed float32)
+	// UV retrieves the texture coordinate offset and scale of the current frame.
+	UV() (offset, scale mgl32.Vec2)
+}
+
+// SpriteSheet describes how the frames of a texture are laid out.  Frames are either cells of a grid of Columns by Rows or explicit rectangles in pixels.
+type SpriteSheet struct {
+	TextureFile string           `json:"textureFile"`
+	Width       int              `json:"width"`
+	Height      int              `json:"height"`
+	Columns     int              `json:"columns"`
+	Rows        int              `json:"rows"`
+	Frames      []SpriteRect     `json:"frames"`
+	FrameRate   float32          `json:"fps"`
+	Sequences   []SpriteSequence `json:"sequences"`
+}
+
+// SpriteRect is the area of a sprite sheet frame in pixels.
+type SpriteRect struct {
+	X      float32 `json:"x"`
+	Y      float32 `json:"y"`
+	Width  float32 `json:"width"`
+	Height float32 `json:"height"`
+}
+
+// SpriteSequence is a named list of frames played at a frame rate.  A frame rate of 0 uses the sheet's frame rate.
+type SpriteSequence struct {
+	Name      string   `json:"name"`
+	Frames    []int    `json:"frames"`
+	FrameRate float32  `json:"fps"`
+	Loop      LoopMode `json:"loop"`
+}
+
+// FrameCount retrieves the number of frames in the sheet.
+func (ss SpriteSheet) FrameCount() int {
+	if len(ss.Frames) > 0 {
+		return len(ss.Frames)
+	}
+	return ss.Columns * ss.Rows
+}
+
+// FrameUV retrieves the texture coordinate offset and scale of a frame.  Frames outside of the sheet return the whole texture.
+func (ss SpriteSheet) FrameUV(frame int) (offset, scale mgl32.Vec2) {
+	if frame < 0 || frame >= ss.FrameCount() {
+		return mgl32.Vec2{0, 0}, mgl32.Vec2{1, 1}
+	}
+
+	if len(ss.Frames) > 0 {
+		if ss.Width <= 0 || ss.Height <= 0 {
+			return mgl32.Vec2{0, 0}, mgl32.Vec2{1, 1}
+		}
+		r := ss.Frames[frame]
+		w, h := float32(ss.Width), float32(ss.Height)
+		return mgl32.Vec2{r.X / w, r.Y / h}, mgl32.Vec2{r.Width / w, r.Height / h}
+	}
+
+	col, row := frame%ss.Columns, frame/ss.Columns
+	scale = mgl32.Vec2{1 / float32(ss.Columns), 1 / float32(ss.Rows)}
+	return mgl32.Vec2{float32(col) * scale.X(), float32(row) * scale.Y()}, scale
+}
+
+type sprite struct {
+	sheet    SpriteSheet
+	sequence SpriteSequence
+	frame    int
+	time     float32
+	playing  bool
+	isLoaded bool
+	dataLock sync.RWMutex
+}
+
+// NewSprite creates a new Sprite component.
+func NewSprite() Sprite {
+	s := sprite{}
+	return &s
+}
+
+// Type retrieves the type of this component.
+func (s *sprite) Type() string {
+	return TypeSprite
+}
+
+// Sheet retrieves the sprite sheet.
+func (s *sprite) Sheet() SpriteSheet {
+	s.dataLock.RLock()
+	defer s.dataLock.RUnlock()
+	return s.sheet
+}
+
+// Set replaces the sprite sheet and shows its first frame.
+func (s *sprite) Set(ss SpriteSheet) {
+	s.dataLock.Lock()
+	defer s.dataLock.Unlock()
+	s.set(ss)
+}
+
+// Load loads a sprite sheet from file and returns an error if an error occurs while loading the file.  If the sheet has already been loaded an "Already Loaded" error will be returned.
+func (s *sprite) Load(fileName string) error {
+	s.dataLock.Lock()
+	defer s.dataLock.Unlock()
+
+	if s.isLoaded {
+		return errors.New("Already Loaded")
+	}
+
+	fullFileName := ""
+	if strings.Contains(fileName, SpriteSrcDir) {
+		fullFileName = fileName
+	} else {
+		fullFileName = fmt.Sprintf("%s%s", SpriteSrcDir, fileName)
+	}
+
+	data, err := ioutil.ReadFile(fullFileName)
+	if err != nil {
+		fmt.Println(err.Error())
+		return err
+	}
+
+	var ss SpriteSheet
+	err = json.Unmarshal(data, &ss)
+	if err != nil {
+		return err
+	}
+
+	s.set(ss)
+	s.isLoaded = true
+	return nil
+}
+
+// Play starts playing the named sequence from its first frame.
+func (s *sprite) Play(name string) error {
+	s.dataLock.Lock()
+	defer s.dataLock.Unlock()
+
+	for _, seq := range s.sheet.Sequences {
+		if seq.Name != name {
+			continue
+		}
+		if seq.FrameRate <= 0 {
+			seq.FrameRate = s.sheet.FrameRate
+		}
+		if seq.Loop == "" {
+			seq.Loop = LoopRepeat
+		}
+		s.sequence = seq
+		s.time = 0
+		s.playing = len(seq.Frames) > 0
+		if s.playing {
+			s.frame = seq.Frames[0]
+		}
+		return nil
+	}
+	return fmt.Errorf("sprite sequence %s not found", name)
+}
+
+// Stop stops playback leaving the current frame visible.
+func (s *sprite) Stop() {
+	s.dataLock.Lock()
+	defer s.dataLock.Unlock()
+	s.playing = false
+}
+
+// IsPlaying returns true while a sequence is being played.
+func (s *sprite) IsPlaying() bool {
+	s.dataLock.RLock()
+	defer s.dataLock.RUnlock()
+	return s.playing
+}
+
+// Current retrieves the name of the sequence being played.
+func (s *sprite) Current() string {
+	s.dataLock.RLock()
+	defer s.dataLock.RUnlock()
+	return s.sequence.Name
+}
+
+// SetFrame stops playback and shows a specific frame of the sheet.
+func (s *sprite) SetFrame(frame int) {
+	s.dataLock.Lock()
+	defer s.dataLock.Unlock()
+	s.playing = false
+	s.frame = frame
+}
+
+// Frame retrieves the frame of the sheet currently shown.
+func (s *sprite) Frame() int {
+	s.dataLock.RLock()
+	defer s.dataLock.RUnlock()
+	return s.frame
+}
+
+// Update advances playback by the elapsed seconds.
+func (s *sprite) Update(elapsed float32) {
+	s.dataLock.Lock()
+	defer s.dataLock.Unlock()
+
+	seq := s.sequence
+	if !s.playing || seq.FrameRate <= 0 {
+		return
+	}
+
+	s.time += elapsed
+	count := len(seq.Frames)
+	index := int(s.time * seq.FrameRate)
+
+	switch seq.Loop {
+	case LoopRepeat:
+		index %= count
+	case LoopPingPong:
+		if count > 1 {
+			period := 2 * (count - 1)
+			index %= period
+			if index >= count {
+				index = period - index
+			}
+		} else {
+			index = 0
+		}
+	default:
+		if index >= count {
+			index = count - 1
+			s.playing = false
+		}
+	}
+	s.frame = seq.Frames[index]
+}
+
+// UV retrieves the texture coordinate offset and scale of the current frame.
+func (s *sprite) UV() (offset, scale mgl32.Vec2) {
+	s.dataLock.RLock()
+	defer s.dataLock.RUnlock()
+	return s.sheet.FrameUV(s.frame)
+}
+
+// set replaces the sprite sheet.  The caller is expected to hold the write lock.
+func (s *sprite) set(ss SpriteSheet) {
+	s.sheet = ss
+	s.sequence = SpriteSequence{}
+	s.frame = 0
+	s.time = 0
+	s.playing = false
+}
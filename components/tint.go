@@ -0,0 +1,53 @@
+package components
+
+import (
+	"sync"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	// TypeTint represents a tint component's type.
+	TypeTint = "tint"
+)
+
+// Tint represents a component that colors an entity's mesh.  The texture color is multiplied by the tint, so white leaves it unchanged.
+type Tint interface {
+	Component
+	// Color retrieves the red, green, blue and alpha of the tint.
+	Color() mgl32.Vec4
+	// SetColor sets the red, green, blue and alpha of the tint.
+	SetColor(mgl32.Vec4)
+}
+
+type tint struct {
+	color    mgl32.Vec4
+	dataLock sync.RWMutex
+}
+
+// NewTint creates a new Tint component.
+func NewTint(color mgl32.Vec4) Tint {
+	t := tint{
+		color: color,
+	}
+	return &t
+}
+
+// Type retrieves the type of this component.
+func (t *tint) Type() string {
+	return TypeTint
+}
+
+// Color retrieves the red, green, blue and alpha of the tint.
+func (t *tint) Color() mgl32.Vec4 {
+	t.dataLock.RLock()
+	defer t.dataLock.RUnlock()
+	return t.color
+}
+
+// SetColor sets the red, green, blue and alpha of the tint.
+func (t *tint) SetColor(color mgl32.Vec4) {
+	t.dataLock.Lock()
+	defer t.dataLock.Unlock()
+	t.color = color
+}
@@ -1,6 +1,7 @@
 package components
 
 import (
+	"math"
 	"sync"
 
 	"github.com/go-gl/mathgl/mgl32"
@@ -11,27 +12,52 @@ const (
 	TypeTransform = "transform"
 )
 
-// Transform represents the world position of an entity.
+// Transform represents the world position, orientation and scale of an entity.  The matrix is composed as Translate * Rotate * Scale.  Local axes follow the OpenGL convention where forward is -Z, right is +X and up is +Y.
 type Transform interface {
 	Component
-	// Set sets the transform to a specific matrix.
+	// Set sets the transform to a specific matrix.  The matrix is decomposed into translation, orientation and scale and is expected not to contain any shear.
 	Set(mgl32.Mat4)
 	// Data retrieves the transforms matrix.
 	Data() mgl32.Mat4
-	// Rotate rotates the transform by the angles passed into the method.
+	// Rotate rotates the transform about its local axes by the euler angles passed into the method.
 	Rotate(mgl32.Vec3)
 	// Translate translates the transform by the value passed into the method.
 	Translate(mgl32.Vec3)
 	// Update translates the transform based on the first argument then rotates it using the second argument.
 	Update(mgl32.Vec3, mgl32.Vec3)
+	// Translation retrieves the current translation.
+	Translation() mgl32.Vec3
+	// SetTranslation replaces the current translation.
+	SetTranslation(mgl32.Vec3)
+	// Rotation retrieves the current orientation as euler angles in radians applied in Z, Y, X order.
+	Rotation() mgl32.Vec3
+	// SetRotation replaces the current orientation with the euler angles passed in, applied in Z, Y, X order.
+	SetRotation(mgl32.Vec3)
+	// Orientation retrieves the current orientation.
+	Orientation() mgl32.Quat
+	// SetOrientation replaces the current orientation.
+	SetOrientation(mgl32.Quat)
+	// Scale retrieves the current scale.
+	Scale() mgl32.Vec3
+	// SetScale replaces the current scale.
+	SetScale(mgl32.Vec3)
+	// Forward retrieves the direction the transform is facing in world space.
+	Forward() mgl32.Vec3
+	// Right retrieves the direction to the right of the transform in world space.
+	Right() mgl32.Vec3
+	// Up retrieves the direction above the transform in world space.
+	Up() mgl32.Vec3
+	// LookAt orients the transform so it faces the target with its up axis as close to the up vector as possible.
+	LookAt(target, up mgl32.Vec3)
 }
 
 // NewTransform creates a new transform component.
 func NewTransform() Transform {
 	t := transform{
 		modelView:   mgl32.Ident4(),
-		rotation:    mgl32.Vec3{0, 0, 0},
+		orientation: mgl32.QuatIdent(),
 		translation: mgl32.Vec3{0, 0, 0},
+		scale:       mgl32.Vec3{1, 1, 1},
 	}
 	return &t
 }
@@ -39,8 +65,9 @@ func NewTransform() Transform {
 // transform represents the data of the Transform component.
 type transform struct {
 	modelView   mgl32.Mat4
-	rotation    mgl32.Vec3
+	orientation mgl32.Quat
 	translation mgl32.Vec3
+	scale       mgl32.Vec3
 	dataLock    sync.RWMutex
 }
 
@@ -53,6 +80,22 @@ func (t *transform) Type() string {
 func (t *transform) Set(modelView mgl32.Mat4) {
 	t.dataLock.Lock()
 	defer t.dataLock.Unlock()
+
+	t.translation = modelView.Col(3).Vec3()
+
+	x, y, z := modelView.Col(0).Vec3(), modelView.Col(1).Vec3(), modelView.Col(2).Vec3()
+	scale := mgl32.Vec3{x.Len(), y.Len(), z.Len()}
+	// A negative determinant means the matrix mirrors, which is represented by a negative x scale.
+	if modelView.Mat3().Det() < 0 {
+		scale[0] = -scale[0]
+	}
+	t.scale = scale
+
+	if scale[0] != 0 && scale[1] != 0 && scale[2] != 0 {
+		rot := mgl32.Mat3FromCols(x.Mul(1/scale[0]), y.Mul(1/scale[1]), z.Mul(1/scale[2]))
+		t.orientation = mgl32.Mat4ToQuat(rot.Mat4()).Normalize()
+	}
+
 	t.modelView = modelView
 }
 
@@ -63,19 +106,12 @@ func (t *transform) Data() mgl32.Mat4 {
 	return t.modelView
 }
 
-// Rotate rotates the transform by the angles passed into the method.
+// Rotate rotates the transform about its local axes by the angles passed into the method.
 func (t *transform) Rotate(rotate mgl32.Vec3) {
 	t.dataLock.Lock()
 	defer t.dataLock.Unlock()
-
-	t.rotation = t.rotation.Add(rotate)
-	total := t.rotation
-	rotX := mgl32.HomogRotate3DX(total.X())
-	rotY := mgl32.HomogRotate3DY(total.Y())
-	rotZ := mgl32.HomogRotate3DZ(total.Z())
-	rotMatrix := rotZ.Mul4(rotY).Mul4(rotX)
-	trans := t.translation
-	t.modelView = mgl32.Ident4().Mul4(mgl32.Translate3D(trans.X(), trans.Y(), trans.Z())).Mul4(rotMatrix)
+	t.rotate(rotate)
+	t.rebuild()
 }
 
 // Translate translates the transform by the value passed into the method.
@@ -83,25 +119,170 @@ func (t *transform) Translate(translate mgl32.Vec3) {
 	t.dataLock.Lock()
 	defer t.dataLock.Unlock()
 	t.translation = t.translation.Add(translate)
-	trans := t.translation
-	t.modelView = t.modelView.Mul4(mgl32.Translate3D(trans.X(), trans.Y(), trans.Z()))
+	t.rebuild()
 }
 
 // Update translates and rotates the transform.
 func (t *transform) Update(translate, rotate mgl32.Vec3) {
-
 	t.dataLock.Lock()
 	defer t.dataLock.Unlock()
 
 	t.translation = t.translation.Add(translate)
+	t.rotate(rotate)
+	t.rebuild()
+}
+
+// Translation retrieves the current translation.
+func (t *transform) Translation() mgl32.Vec3 {
+	t.dataLock.RLock()
+	defer t.dataLock.RUnlock()
+	return t.translation
+}
+
+// SetTranslation replaces the current translation.
+func (t *transform) SetTranslation(translation mgl32.Vec3) {
+	t.dataLock.Lock()
+	defer t.dataLock.Unlock()
+	t.translation = translation
+	t.rebuild()
+}
+
+// Rotation retrieves the current orientation as euler angles in radians.
+func (t *transform) Rotation() mgl32.Vec3 {
+	t.dataLock.RLock()
+	defer t.dataLock.RUnlock()
+	return QuatToEuler(t.orientation)
+}
+
+// SetRotation replaces the current orientation with the euler angles passed in.
+func (t *transform) SetRotation(rotation mgl32.Vec3) {
+	t.dataLock.Lock()
+	defer t.dataLock.Unlock()
+	t.orientation = EulerToQuat(rotation)
+	t.rebuild()
+}
+
+// Orientation retrieves the current orientation.
+func (t *transform) Orientation() mgl32.Quat {
+	t.dataLock.RLock()
+	defer t.dataLock.RUnlock()
+	return t.orientation
+}
+
+// SetOrientation replaces the current orientation.
+func (t *transform) SetOrientation(orientation mgl32.Quat) {
+	t.dataLock.Lock()
+	defer t.dataLock.Unlock()
+	t.orientation = orientation.Normalize()
+	t.rebuild()
+}
+
+// Scale retrieves the current scale.
+func (t *transform) Scale() mgl32.Vec3 {
+	t.dataLock.RLock()
+	defer t.dataLock.RUnlock()
+	return t.scale
+}
+
+// SetScale replaces the current scale.
+func (t *transform) SetScale(scale mgl32.Vec3) {
+	t.dataLock.Lock()
+	defer t.dataLock.Unlock()
+	t.scale = scale
+	t.rebuild()
+}
+
+// Forward retrieves the direction the transform is facing in world space.
+func (t *transform) Forward() mgl32.Vec3 {
+	t.dataLock.RLock()
+	defer t.dataLock.RUnlock()
+	return t.orientation.Rotate(mgl32.Vec3{0, 0, -1})
+}
+
+// Right retrieves the direction to the right of the transform in world space.
+func (t *transform) Right() mgl32.Vec3 {
+	t.dataLock.RLock()
+	defer t.dataLock.RUnlock()
+	return t.orientation.Rotate(mgl32.Vec3{1, 0, 0})
+}
+
+// Up retrieves the direction above the transform in world space.
+func (t *transform) Up() mgl32.Vec3 {
+	t.dataLock.RLock()
+	defer t.dataLock.RUnlock()
+	return t.orientation.Rotate(mgl32.Vec3{0, 1, 0})
+}
+
+// LookAt orients the transform so it faces the target with its up axis as close to the up vector as possible.  Nothing changes if the target is at the transform's position.
+func (t *transform) LookAt(target, up mgl32.Vec3) {
+	t.dataLock.Lock()
+	defer t.dataLock.Unlock()
+
+	orientation, ok := LookAtQuat(target.Sub(t.translation), up)
+	if !ok {
+		return
+	}
+	t.orientation = orientation
+	t.rebuild()
+}
+
+// rotate applies euler angle deltas about the local axes.  The caller is expected to hold the write lock.
+func (t *transform) rotate(rotate mgl32.Vec3) {
+	if rotate.X() == 0 && rotate.Y() == 0 && rotate.Z() == 0 {
+		return
+	}
+	t.orientation = t.orientation.Mul(EulerToQuat(rotate)).Normalize()
+}
+
+// rebuild recalculates the matrix from the translation, orientation and scale.  The caller is expected to hold the write lock.
+func (t *transform) rebuild() {
 	trans := t.translation
+	scale := t.scale
+	t.modelView = mgl32.Translate3D(trans.X(), trans.Y(), trans.Z()).
+		Mul4(t.orientation.Mat4()).
+		Mul4(mgl32.Scale3D(scale.X(), scale.Y(), scale.Z()))
+}
+
+// LookAtQuat creates an orientation whose forward (-Z) axis points along the direction with its up axis as close to the up vector as possible.  The boolean is false if the direction has no length.
+func LookAtQuat(direction, up mgl32.Vec3) (mgl32.Quat, bool) {
+	if direction.Len() < 1e-6 {
+		return mgl32.QuatIdent(), false
+	}
+	f := direction.Normalize()
 
-	t.rotation = t.rotation.Add(rotate)
-	total := t.rotation
-	rotX := mgl32.HomogRotate3DX(total.X())
-	rotY := mgl32.HomogRotate3DY(total.Y())
-	rotZ := mgl32.HomogRotate3DZ(total.Z())
-	rotMatrix := rotZ.Mul4(rotY).Mul4(rotX)
-	trans = t.translation
-	t.modelView = mgl32.Ident4().Mul4(mgl32.Translate3D(trans.X(), trans.Y(), trans.Z())).Mul4(rotMatrix)
+	r := f.Cross(up)
+	if r.Len() < 1e-6 {
+		// The up vector is parallel to the direction so pick any perpendicular axis.
+		r = f.Cross(mgl32.Vec3{1, 0, 0})
+		if r.Len() < 1e-6 {
+			r = f.Cross(mgl32.Vec3{0, 0, 1})
+		}
+	}
+	r = r.Normalize()
+	u := r.Cross(f)
+
+	rot := mgl32.Mat3FromCols(r, u, f.Mul(-1))
+	return mgl32.Mat4ToQuat(rot.Mat4()).Normalize(), true
+}
+
+// EulerToQuat converts euler angles in radians, applied in Z, Y, X order, into a quaternion.
+func EulerToQuat(e mgl32.Vec3) mgl32.Quat {
+	return mgl32.AnglesToQuat(e.Z(), e.Y(), e.X(), mgl32.ZYX)
+}
+
+// QuatToEuler converts a quaternion into euler angles in radians matching the Z, Y, X rotation order used by Transform.
+func QuatToEuler(q mgl32.Quat) mgl32.Vec3 {
+	m := q.Normalize().Mat4()
+	sinY := -m.At(2, 0)
+	if sinY >= 1 {
+		return mgl32.Vec3{float32(math.Atan2(float64(-m.At(1, 2)), float64(m.At(1, 1)))), math.Pi / 2, 0}
+	}
+	if sinY <= -1 {
+		return mgl32.Vec3{float32(math.Atan2(float64(-m.At(1, 2)), float64(m.At(1, 1)))), -math.Pi / 2, 0}
+	}
+	return mgl32.Vec3{
+		float32(math.Atan2(float64(m.At(2, 1)), float64(m.At(2, 2)))),
+		float32(math.Asin(float64(sinY))),
+		float32(math.Atan2(float64(m.At(1, 0)), float64(m.At(0, 0)))),
+	}
 }
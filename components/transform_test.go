@@ -0,0 +1,254 @@
+package components
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const halfPi = float32(math.Pi / 2)
+
+// sameRotation returns true if two orientations turn every axis the same way, which is true of q and -q.
+func sameRotation(a, b mgl32.Quat) bool {
+	for _, axis := range []mgl32.Vec3{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}} {
+		if !near(a.Rotate(axis), b.Rotate(axis)) {
+			return false
+		}
+	}
+	return true
+}
+
+// nearMat returns true if every value of two matrices is within epsilon of each other.
+func nearMat(a, b mgl32.Mat4) bool {
+	for i := range a {
+		if d := a[i] - b[i]; d > epsilon || d < -epsilon {
+			return false
+		}
+	}
+	return true
+}
+
+func TestTransformSet(t *testing.T) {
+	turn := EulerToQuat(mgl32.Vec3{0.3, -0.4, 1.2})
+	compose := func(translation, scale mgl32.Vec3, q mgl32.Quat) mgl32.Mat4 {
+		return mgl32.Translate3D(translation.X(), translation.Y(), translation.Z()).
+			Mul4(q.Mat4()).
+			Mul4(mgl32.Scale3D(scale.X(), scale.Y(), scale.Z()))
+	}
+	tests := []struct {
+		name        string
+		matrix      mgl32.Mat4
+		translation mgl32.Vec3
+		scale       mgl32.Vec3
+		orientation mgl32.Quat
+	}{
+		{"identity", mgl32.Ident4(), mgl32.Vec3{}, mgl32.Vec3{1, 1, 1}, mgl32.QuatIdent()},
+		{"translated", mgl32.Translate3D(1, 2, 3), mgl32.Vec3{1, 2, 3}, mgl32.Vec3{1, 1, 1}, mgl32.QuatIdent()},
+		{"rotated and scaled", compose(mgl32.Vec3{-4, 5, 6}, mgl32.Vec3{2, 3, 4}, turn), mgl32.Vec3{-4, 5, 6}, mgl32.Vec3{2, 3, 4}, turn},
+		{"mirrored in x", compose(mgl32.Vec3{1, 0, 0}, mgl32.Vec3{-2, 3, 4}, turn), mgl32.Vec3{1, 0, 0}, mgl32.Vec3{-2, 3, 4}, turn},
+		// Mirroring in y is the same as mirroring in x and turning half way about z.
+		{"mirrored in y", mgl32.Scale3D(2, -3, 4), mgl32.Vec3{}, mgl32.Vec3{-2, 3, 4}, mgl32.QuatRotate(math.Pi, mgl32.Vec3{0, 0, 1})},
+	}
+	for _, test := range tests {
+		tr := NewTransform()
+		tr.Set(test.matrix)
+		if got := tr.Translation(); !near(got, test.translation) {
+			t.Errorf("%s: translation = %v, want %v", test.name, got, test.translation)
+		}
+		if got := tr.Scale(); !near(got, test.scale) {
+			t.Errorf("%s: scale = %v, want %v", test.name, got, test.scale)
+		}
+		if got := tr.Orientation(); !sameRotation(got, test.orientation) {
+			t.Errorf("%s: orientation = %v, want %v", test.name, got, test.orientation)
+		}
+		if got := tr.Data(); got != test.matrix {
+			t.Errorf("%s: Data = %v, want the matrix that was set", test.name, got)
+		}
+		// Rebuilding the matrix from the decomposed parts gives back the matrix that was set.
+		tr.SetTranslation(tr.Translation())
+		if got := tr.Data(); !nearMat(got, test.matrix) {
+			t.Errorf("%s: rebuilt matrix = %v, want %v", test.name, got, test.matrix)
+		}
+	}
+}
+
+func TestTransformTranslate(t *testing.T) {
+	tests := []struct {
+		name   string
+		start  mgl32.Vec3
+		deltas []mgl32.Vec3
+		want   mgl32.Vec3
+	}{
+		{"no movement", mgl32.Vec3{1, 2, 3}, nil, mgl32.Vec3{1, 2, 3}},
+		{"one step", mgl32.Vec3{}, []mgl32.Vec3{{1, 0, 0}}, mgl32.Vec3{1, 0, 0}},
+		{"steps add up", mgl32.Vec3{1, 1, 1}, []mgl32.Vec3{{1, 0, 0}, {0, 2, 0}, {0, 0, -3}, {1, 0, 0}}, mgl32.Vec3{3, 3, -2}},
+		{"steps cancel out", mgl32.Vec3{}, []mgl32.Vec3{{5, -5, 2}, {-5, 5, -2}}, mgl32.Vec3{}},
+	}
+	for _, test := range tests {
+		tr := NewTransform()
+		tr.SetTranslation(test.start)
+		tr.SetScale(mgl32.Vec3{2, 2, 2})
+		for _, delta := range test.deltas {
+			tr.Translate(delta)
+		}
+		if got := tr.Translation(); !near(got, test.want) {
+			t.Errorf("%s: translation = %v, want %v", test.name, got, test.want)
+		}
+		// Translating moves the matrix without changing its scale.
+		if got := tr.Data().Col(3).Vec3(); !near(got, test.want) {
+			t.Errorf("%s: matrix translation = %v, want %v", test.name, got, test.want)
+		}
+		if got := tr.Data().At(0, 0); got != 2 {
+			t.Errorf("%s: matrix scale = %v, want 2", test.name, got)
+		}
+	}
+}
+
+func TestTransformRotate(t *testing.T) {
+	tests := []struct {
+		name    string
+		start   mgl32.Vec3
+		deltas  []mgl32.Vec3
+		forward mgl32.Vec3
+		up      mgl32.Vec3
+	}{
+		{"no rotation", mgl32.Vec3{}, nil, mgl32.Vec3{0, 0, -1}, mgl32.Vec3{0, 1, 0}},
+		{"pitch", mgl32.Vec3{}, []mgl32.Vec3{{halfPi, 0, 0}}, mgl32.Vec3{0, 1, 0}, mgl32.Vec3{0, 0, 1}},
+		// Pitching after turning about z pitches about the turned x axis, not the world's.
+		{"pitch about the local x axis", mgl32.Vec3{0, 0, halfPi}, []mgl32.Vec3{{halfPi, 0, 0}}, mgl32.Vec3{-1, 0, 0}, mgl32.Vec3{0, 0, 1}},
+		{"rotations add up", mgl32.Vec3{}, []mgl32.Vec3{{0, halfPi / 2, 0}, {0, halfPi / 2, 0}}, mgl32.Vec3{-1, 0, 0}, mgl32.Vec3{0, 1, 0}},
+		{"rotations cancel out", mgl32.Vec3{}, []mgl32.Vec3{{0.5, 0, 0}, {-0.5, 0, 0}}, mgl32.Vec3{0, 0, -1}, mgl32.Vec3{0, 1, 0}},
+	}
+	for _, test := range tests {
+		tr := NewTransform()
+		tr.SetRotation(test.start)
+		for _, delta := range test.deltas {
+			tr.Rotate(delta)
+		}
+		if got := tr.Forward(); !near(got, test.forward) {
+			t.Errorf("%s: forward = %v, want %v", test.name, got, test.forward)
+		}
+		if got := tr.Up(); !near(got, test.up) {
+			t.Errorf("%s: up = %v, want %v", test.name, got, test.up)
+		}
+		want := EulerToQuat(test.start)
+		for _, delta := range test.deltas {
+			want = want.Mul(EulerToQuat(delta))
+		}
+		if got := tr.Orientation(); !sameRotation(got, want) {
+			t.Errorf("%s: orientation = %v, want %v", test.name, got, want)
+		}
+	}
+}
+
+func TestTransformRotation(t *testing.T) {
+	tests := []struct {
+		name  string
+		euler mgl32.Vec3
+		// want is the euler angles read back, which differ from those set when pitched straight up or down.
+		want mgl32.Vec3
+	}{
+		{"none", mgl32.Vec3{}, mgl32.Vec3{}},
+		{"x", mgl32.Vec3{0.5, 0, 0}, mgl32.Vec3{0.5, 0, 0}},
+		{"y", mgl32.Vec3{0, -0.7, 0}, mgl32.Vec3{0, -0.7, 0}},
+		{"z", mgl32.Vec3{0, 0, 2.5}, mgl32.Vec3{0, 0, 2.5}},
+		{"all", mgl32.Vec3{0.3, -0.2, 1.1}, mgl32.Vec3{0.3, -0.2, 1.1}},
+		{"all negative", mgl32.Vec3{-2, 1.2, -3}, mgl32.Vec3{-2, 1.2, -3}},
+		{"straight up", mgl32.Vec3{0, halfPi, 0}, mgl32.Vec3{0, halfPi, 0}},
+		{"straight down", mgl32.Vec3{0, -halfPi, 0}, mgl32.Vec3{0, -halfPi, 0}},
+		// Straight up or down z turns the same way as x, so it is folded into x.
+		{"straight up and turned", mgl32.Vec3{0.5, halfPi, 0}, mgl32.Vec3{0.5, halfPi, 0}},
+		{"straight up with z", mgl32.Vec3{0, halfPi, 0.5}, mgl32.Vec3{-0.5, halfPi, 0}},
+		{"straight down with x and z", mgl32.Vec3{0.25, -halfPi, 0.75}, mgl32.Vec3{1, -halfPi, 0}},
+	}
+	for _, test := range tests {
+		tr := NewTransform()
+		tr.SetRotation(test.euler)
+		got := tr.Rotation()
+		if !near(got, test.want) {
+			t.Errorf("%s: Rotation = %v, want %v", test.name, got, test.want)
+		}
+		if !sameRotation(EulerToQuat(got), EulerToQuat(test.euler)) {
+			t.Errorf("%s: %v read back as %v, which turns differently", test.name, test.euler, got)
+		}
+	}
+}
+
+func TestTransformAxes(t *testing.T) {
+	tests := []struct {
+		name               string
+		euler              mgl32.Vec3
+		forward, right, up mgl32.Vec3
+	}{
+		{"identity", mgl32.Vec3{}, mgl32.Vec3{0, 0, -1}, mgl32.Vec3{1, 0, 0}, mgl32.Vec3{0, 1, 0}},
+		{"turned about z", mgl32.Vec3{0, 0, halfPi}, mgl32.Vec3{0, 0, -1}, mgl32.Vec3{0, 1, 0}, mgl32.Vec3{-1, 0, 0}},
+		{"pitched about x", mgl32.Vec3{halfPi, 0, 0}, mgl32.Vec3{0, 1, 0}, mgl32.Vec3{1, 0, 0}, mgl32.Vec3{0, 0, 1}},
+		{"turned about y", mgl32.Vec3{0, halfPi, 0}, mgl32.Vec3{-1, 0, 0}, mgl32.Vec3{0, 0, -1}, mgl32.Vec3{0, 1, 0}},
+	}
+	for _, test := range tests {
+		tr := NewTransform()
+		tr.SetRotation(test.euler)
+		if got := tr.Forward(); !near(got, test.forward) {
+			t.Errorf("%s: forward = %v, want %v", test.name, got, test.forward)
+		}
+		if got := tr.Right(); !near(got, test.right) {
+			t.Errorf("%s: right = %v, want %v", test.name, got, test.right)
+		}
+		if got := tr.Up(); !near(got, test.up) {
+			t.Errorf("%s: up = %v, want %v", test.name, got, test.up)
+		}
+	}
+}
+
+func TestTransformLookAt(t *testing.T) {
+	tests := []struct {
+		name     string
+		position mgl32.Vec3
+		target   mgl32.Vec3
+		up       mgl32.Vec3
+		forward  mgl32.Vec3
+		// wantUp is the up axis expected, or the zero vector to only check it is perpendicular to forward.
+		wantUp mgl32.Vec3
+	}{
+		{"along y with z up", mgl32.Vec3{}, mgl32.Vec3{0, 5, 0}, mgl32.Vec3{0, 0, 1}, mgl32.Vec3{0, 1, 0}, mgl32.Vec3{0, 0, 1}},
+		{"from a position", mgl32.Vec3{1, 1, 1}, mgl32.Vec3{4, 1, 1}, mgl32.Vec3{0, 0, 1}, mgl32.Vec3{1, 0, 0}, mgl32.Vec3{0, 0, 1}},
+		{"up is straightened", mgl32.Vec3{}, mgl32.Vec3{0, 5, 0}, mgl32.Vec3{0, 1, 1}, mgl32.Vec3{0, 1, 0}, mgl32.Vec3{0, 0, 1}},
+		{"up parallel to forward", mgl32.Vec3{}, mgl32.Vec3{0, 0, 10}, mgl32.Vec3{0, 0, 1}, mgl32.Vec3{0, 0, 1}, mgl32.Vec3{}},
+		{"up parallel to forward along x", mgl32.Vec3{}, mgl32.Vec3{-3, 0, 0}, mgl32.Vec3{1, 0, 0}, mgl32.Vec3{-1, 0, 0}, mgl32.Vec3{}},
+	}
+	for _, test := range tests {
+		tr := NewTransform()
+		tr.SetTranslation(test.position)
+		tr.LookAt(test.target, test.up)
+		if got := tr.Forward(); !near(got, test.forward) {
+			t.Errorf("%s: forward = %v, want %v", test.name, got, test.forward)
+		}
+		up, right := tr.Up(), tr.Right()
+		if test.wantUp != (mgl32.Vec3{}) && !near(up, test.wantUp) {
+			t.Errorf("%s: up = %v, want %v", test.name, up, test.wantUp)
+		}
+		if d := up.Dot(test.forward); d > epsilon || d < -epsilon {
+			t.Errorf("%s: up %v is not perpendicular to forward", test.name, up)
+		}
+		if !near(right, test.forward.Cross(up)) {
+			t.Errorf("%s: right = %v, want %v", test.name, right, test.forward.Cross(up))
+		}
+		if got := tr.Translation(); got != test.position {
+			t.Errorf("%s: LookAt moved the transform to %v", test.name, got)
+		}
+	}
+
+	// Looking at its own position leaves the transform as it was.
+	tr := NewTransform()
+	tr.SetTranslation(mgl32.Vec3{1, 2, 3})
+	tr.SetRotation(mgl32.Vec3{0.1, 0.2, 0.3})
+	before := tr.Data()
+	tr.LookAt(mgl32.Vec3{1, 2, 3}, mgl32.Vec3{0, 0, 1})
+	if got := tr.Data(); got != before {
+		t.Errorf("looking at its own position changed the transform to %v", got)
+	}
+	if _, ok := LookAtQuat(mgl32.Vec3{}, mgl32.Vec3{0, 0, 1}); ok {
+		t.Error("LookAtQuat of no direction succeeded")
+	}
+}
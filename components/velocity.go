@@ -11,7 +11,7 @@ const (
 	TypeVelocity = "velocity"
 )
 
-// Velocity represents the velocity of an entity.
+// Velocity represents the velocity of an entity.  The translational velocity is expressed in the velocity's reference frame while the rotational velocity is always about the entity's local axes.
 type Velocity interface {
 	Component
 	// Rotational retrieves the rotational velocity in radians per second.
@@ -24,11 +24,21 @@ type Velocity interface {
 	SetTranslational(mgl32.Vec3)
 	// Set sets the rotational and translational velocities.
 	Set(rotational mgl32.Vec3, translational mgl32.Vec3)
+	// Frame retrieves the reference frame the translational velocity is expressed in.
+	Frame() ReferenceFrame
+	// SetFrame sets the reference frame the translational velocity is expressed in.
+	SetFrame(ReferenceFrame)
+	// Parent retrieves the id of the entity used by the parent frame.
+	Parent() string
+	// SetParent sets the id of the entity used by the parent frame.
+	SetParent(string)
 }
 
 type velocity struct {
 	rotational    mgl32.Vec3
 	translational mgl32.Vec3
+	frame         ReferenceFrame
+	parent        string
 	dataLock      sync.RWMutex
 }
 
@@ -37,6 +47,7 @@ func NewVelocity() Velocity {
 	v := velocity{
 		rotational:    mgl32.Vec3{0, 0, 0},
 		translational: mgl32.Vec3{0, 0, 0},
+		frame:         FrameWorld,
 	}
 
 	return &v
@@ -82,3 +93,31 @@ func (v *velocity) Set(rotate, translate mgl32.Vec3) {
 	v.rotational = rotate
 	v.translational = translate
 }
+
+// Frame retrieves the reference frame the translational velocity is expressed in.
+func (v *velocity) Frame() ReferenceFrame {
+	v.dataLock.RLock()
+	defer v.dataLock.RUnlock()
+	return v.frame
+}
+
+// SetFrame sets the reference frame the translational velocity is expressed in.
+func (v *velocity) SetFrame(frame ReferenceFrame) {
+	v.dataLock.Lock()
+	defer v.dataLock.Unlock()
+	v.frame = frame
+}
+
+// Parent retrieves the id of the entity used by the parent frame.
+func (v *velocity) Parent() string {
+	v.dataLock.RLock()
+	defer v.dataLock.RUnlock()
+	return v.parent
+}
+
+// SetParent sets the id of the entity used by the parent frame.
+func (v *velocity) SetParent(id string) {
+	v.dataLock.Lock()
+	defer v.dataLock.Unlock()
+	v.parent = id
+}
@@ -0,0 +1,155 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/Ariemeth/quantum-pulse/systems"
+)
+
+// screenshotTimeFormat names screenshots taken with the screenshot key by when they were taken.
+const screenshotTimeFormat = "20060102-150405.000"
+
+var (
+	errNoScene  = errors.New("no scene has been loaded")
+	errNoFrames = errors.New("no frames to save")
+)
+
+// Screenshot saves the next frame drawn to the window as a PNG file.  It waits for the frame to be drawn so it must not be called from the main thread, such as from an input callback.
+func (e *Engine) Screenshot(fileName string) error {
+	frames, err := e.CaptureFrames(1)
+	if err != nil {
+		return err
+	}
+	return SavePNG(fileName, frames[0].Image)
+}
+
+// CaptureFrames reads back the next count consecutive frames drawn to the window.  It waits for the frames to be drawn so it must not be called from the main thread.
+func (e *Engine) CaptureFrames(count int) ([]systems.CapturedFrame, error) {
+	if e.currentScene == nil {
+		return nil, errNoScene
+	}
+	if count <= 0 {
+		return nil, fmt.Errorf("invalid frame count %d", count)
+	}
+	frames := make([]systems.CapturedFrame, 0, count)
+	for frame := range e.currentScene.Rendering().CaptureFrames(count) {
+		frames = append(frames, frame)
+	}
+	return frames, nil
+}
+
+// CapturePNGs saves the next count consecutive frames drawn to the window as numbered PNG files.  The pattern formats the frame number into each file name, such as "frame%03d.png".
+func (e *Engine) CapturePNGs(count int, pattern string) error {
+	frames, err := e.CaptureFrames(count)
+	if err != nil {
+		return err
+	}
+	for i, frame := range frames {
+		if err := SavePNG(fmt.Sprintf(pattern, i), frame.Image); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CaptureGIF saves the next count consecutive frames drawn to the window as an animated GIF.  Each frame is shown for as long as it was on screen.
+func (e *Engine) CaptureGIF(count int, fileName string) error {
+	frames, err := e.CaptureFrames(count)
+	if err != nil {
+		return err
+	}
+	return SaveGIF(fileName, frames)
+}
+
+// RenderOffscreen draws one frame of the current scene at any resolution without showing it in the window.  It must not be called from the main thread.
+func (e *Engine) RenderOffscreen(width, height int) (*image.RGBA, error) {
+	if e.currentScene == nil {
+		return nil, errNoScene
+	}
+	return e.currentScene.Rendering().RenderOffscreen(width, height)
+}
+
+// saveScreenshot saves the next frame to a file named by the current time.  It runs off the main thread since the frame is drawn on it.
+func (e *Engine) saveScreenshot() {
+	fileName := fmt.Sprintf("screenshot-%s.png", time.Now().Format(screenshotTimeFormat))
+	go func() {
+		if err := e.Screenshot(fileName); err != nil {
+			log.Printf("Unable to save screenshot %s: %v", fileName, err)
+			return
+		}
+		log.Printf("Saved screenshot %s", fileName)
+	}()
+}
+
+// SavePNG saves an image as a PNG file.
+func SavePNG(fileName string, img image.Image) error {
+	f, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// SaveGIF saves frames as an animated GIF that loops forever.  Frames are reduced to the Plan 9 palette and each is shown until the next frame's time, with the last shown as long as the one before it.
+func SaveGIF(fileName string, frames []systems.CapturedFrame) error {
+	if len(frames) == 0 {
+		return errNoFrames
+	}
+
+	f, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	if err := encodeGIF(f, frames); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// encodeGIF writes frames as an animated GIF that loops forever.
+func encodeGIF(w io.Writer, frames []systems.CapturedFrame) error {
+	if len(frames) == 0 {
+		return errNoFrames
+	}
+
+	anim := gif.GIF{}
+	for i, frame := range frames {
+		bounds := frame.Image.Bounds()
+		paletted := image.NewPaletted(bounds, palette.Plan9)
+		draw.FloydSteinberg.Draw(paletted, bounds, frame.Image, bounds.Min)
+		anim.Image = append(anim.Image, paletted)
+		anim.Delay = append(anim.Delay, gifDelay(frames, i))
+	}
+	return gif.EncodeAll(w, &anim)
+}
+
+// gifDelay retrieves how long a frame is shown in hundredths of a second.  Most viewers show shorter delays slowly, so frames are shown for at least two hundredths.
+func gifDelay(frames []systems.CapturedFrame, i int) int {
+	var shown time.Duration
+	switch {
+	case i+1 < len(frames):
+		shown = frames[i+1].Time.Sub(frames[i].Time)
+	case i > 0:
+		shown = frames[i].Time.Sub(frames[i-1].Time)
+	}
+	delay := int(shown / (10 * time.Millisecond))
+	if delay < 2 {
+		delay = 2
+	}
+	return delay
+}
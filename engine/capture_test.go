@@ -0,0 +1,90 @@
+package engine
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"testing"
+	"time"
+
+	"github.com/Ariemeth/quantum-pulse/systems"
+)
+
+// solidFrame creates a frame of a single color drawn at an offset from a start time.
+func solidFrame(c color.RGBA, start time.Time, offset time.Duration) systems.CapturedFrame {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 3))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = c.R, c.G, c.B, c.A
+	}
+	return systems.CapturedFrame{Image: img, Time: start.Add(offset)}
+}
+
+func TestEncodeGIF(t *testing.T) {
+	start := time.Now()
+	// Black, white and pure red are all in the Plan 9 palette so they survive dithering unchanged.
+	colors := []color.RGBA{{0, 0, 0, 0xff}, {0xff, 0xff, 0xff, 0xff}, {0xff, 0, 0, 0xff}}
+	frames := []systems.CapturedFrame{
+		solidFrame(colors[0], start, 0),
+		solidFrame(colors[1], start, 50*time.Millisecond),
+		solidFrame(colors[2], start, 120*time.Millisecond),
+	}
+
+	var buf bytes.Buffer
+	if err := encodeGIF(&buf, frames); err != nil {
+		t.Fatalf("encodeGIF: %v", err)
+	}
+	anim, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatalf("Unable to decode the GIF: %v", err)
+	}
+
+	if len(anim.Image) != len(frames) {
+		t.Fatalf("the GIF has %d frames, want %d", len(anim.Image), len(frames))
+	}
+	if anim.LoopCount != 0 {
+		t.Errorf("LoopCount = %d, want 0 to loop forever", anim.LoopCount)
+	}
+	wantDelays := []int{5, 7, 7}
+	for i, img := range anim.Image {
+		if img.Bounds() != frames[i].Image.Bounds() {
+			t.Errorf("frame %d bounds = %v, want %v", i, img.Bounds(), frames[i].Image.Bounds())
+		}
+		if anim.Delay[i] != wantDelays[i] {
+			t.Errorf("frame %d delay = %d, want %d", i, anim.Delay[i], wantDelays[i])
+		}
+		r, g, b, _ := img.At(2, 1).RGBA()
+		got := color.RGBA{byte(r >> 8), byte(g >> 8), byte(b >> 8), 0xff}
+		if got != colors[i] {
+			t.Errorf("frame %d color = %v, want %v", i, got, colors[i])
+		}
+	}
+
+	if err := encodeGIF(&buf, nil); err == nil {
+		t.Errorf("encoding no frames did not fail")
+	}
+}
+
+func TestGIFDelay(t *testing.T) {
+	start := time.Now()
+	frame := func(offset time.Duration) systems.CapturedFrame {
+		return systems.CapturedFrame{Time: start.Add(offset)}
+	}
+	tests := []struct {
+		name   string
+		frames []systems.CapturedFrame
+		want   []int
+	}{
+		{"single frame", []systems.CapturedFrame{frame(0)}, []int{2}},
+		{"60 frames a second is held for the shortest delay", []systems.CapturedFrame{frame(0), frame(16 * time.Millisecond), frame(33 * time.Millisecond)}, []int{2, 2, 2}},
+		{"uneven frames", []systems.CapturedFrame{frame(0), frame(100 * time.Millisecond), frame(130 * time.Millisecond)}, []int{10, 3, 3}},
+		{"partial hundredths are dropped", []systems.CapturedFrame{frame(0), frame(59 * time.Millisecond)}, []int{5, 5}},
+	}
+	for _, test := range tests {
+		for i, want := range test.want {
+			if got := gifDelay(test.frames, i); got != want {
+				t.Errorf("%s: frame %d delay = %d, want %d", test.name, i, got, want)
+			}
+		}
+	}
+}
@@ -4,11 +4,15 @@ import (
 	"fmt"
 	"log"
 	"runtime"
+	"sync"
 
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/glfw/v3.2/glfw"
 
+	"github.com/Ariemeth/quantum-pulse/input"
 	"github.com/Ariemeth/quantum-pulse/resources"
+	"github.com/Ariemeth/quantum-pulse/scenefile"
+	"github.com/Ariemeth/quantum-pulse/systems"
 )
 
 var (
@@ -24,6 +28,11 @@ type Engine struct {
 	window       *glfw.Window
 	windowWidth  int
 	windowHeight int
+	cursorLock   sync.Mutex
+	cursorX      float64
+	cursorY      float64
+	pickHandlers []func(systems.PickResult)
+	input        *input.State
 }
 
 func init() {
@@ -65,7 +74,9 @@ func (e *Engine) Init(width, height int, title string) error {
 		}
 		e.windowWidth = width
 		e.windowHeight = height
-		window, err := createWindow(width, height, title)
+		e.input = input.NewState()
+		e.input.SetWindowSize(width, height)
+		window, err := e.createWindow(width, height, title)
 		if err != nil {
 			initError <- err
 		}
@@ -110,12 +121,67 @@ func (e *Engine) LoadScene(name string) {
 	}
 }
 
+// Tweens retrieves the tween system of the current scene so gameplay code can start tweens.  Returns nil if no scene has been loaded.
+func (e *Engine) Tweens() systems.Tweener {
+	if e.currentScene == nil {
+		return nil
+	}
+	return e.currentScene.Tweens()
+}
+
+// Collisions retrieves the collision system of the current scene so gameplay code can listen for collision events.  Returns nil if no scene has been loaded.
+func (e *Engine) Collisions() systems.Collision {
+	if e.currentScene == nil {
+		return nil
+	}
+	return e.currentScene.Collisions()
+}
+
+// RenderStats retrieves how many entities the current scene drew and culled in the last frame.  Returns empty stats if no scene has been loaded.
+func (e *Engine) RenderStats() systems.RenderStats {
+	if e.currentScene == nil {
+		return systems.RenderStats{}
+	}
+	return e.currentScene.Rendering().Stats()
+}
+
+// Input retrieves the state of the keyboard and mouse, which the engine updates as the window receives input.
+func (e *Engine) Input() *input.State {
+	return e.input
+}
+
+// Pick retrieves the nearest entity of the current scene under a point on the screen, such as the cursor position.  Coordinates are in pixels from the top left corner of the window.  The ray is cast from the camera drawn last into the window whose viewport holds the point.
+func (e *Engine) Pick(x, y float64) (systems.PickResult, bool) {
+	if e.currentScene == nil {
+		return systems.PickResult{}, false
+	}
+	cameras := e.currentScene.Cameras()
+	for i := len(cameras) - 1; i >= 0; i-- {
+		if cameras[i].Target() != "" || !cameras[i].Contains(x, y) {
+			continue
+		}
+		ray, ok := cameras[i].ScreenToRay(x, y)
+		if !ok {
+			return systems.PickResult{}, false
+		}
+		return e.currentScene.Picking().Pick(ray)
+	}
+	return systems.PickResult{}, false
+}
+
+// OnPick registers a function called with the entity under the cursor whenever the left mouse button is clicked on one.
+func (e *Engine) OnPick(handler func(systems.PickResult)) {
+	e.cursorLock.Lock()
+	defer e.cursorLock.Unlock()
+	e.pickHandlers = append(e.pickHandlers, handler)
+}
+
 // LoadSceneFile loads a scene from a file but does not make it the current scene. You
 // must still call LoadScene with the scene id to load it as the current scene.  LoadSceneFile
 // should not be called before Init is called.
 func (e *Engine) LoadSceneFile(fileName string) (string, error) {
 
-	scene, err := newScene(fileName, e.assets, e.window, e.windowWidth, e.windowHeight)
+	scene, err := newScene(fileName, e.assets, e.window, e.input, e.windowWidth, e.windowHeight)
 	if err != nil {
 		return "", err
 	}
@@ -128,7 +194,16 @@ func (e *Engine) LoadSceneFile(fileName string) (string, error) {
 	return scene.ID(), nil
 }
 
-func createWindow(width, height int, title string) (*glfw.Window, error) {
+// LoadSceneData creates a scene from scene data, such as a generated map, but does not make it the current scene.
+// The scene is added with the id passed in, which must then be passed to LoadScene to make it the current scene.
+// LoadSceneData should not be called before Init is called.
+func (e *Engine) LoadSceneData(id string, sd scenefile.Scene) string {
+	scene := newSceneFromData(id, sd, e.assets, e.window, e.input, e.windowWidth, e.windowHeight)
+	e.AddScene(scene, scene.ID())
+	return scene.ID()
+}
+
+func (e *Engine) createWindow(width, height int, title string) (*glfw.Window, error) {
 	glfw.WindowHint(glfw.Resizable, glfw.False)
 	glfw.WindowHint(glfw.ContextVersionMajor, 4)
 	glfw.WindowHint(glfw.ContextVersionMinor, 1)
@@ -141,11 +216,11 @@ func createWindow(width, height int, title string) (*glfw.Window, error) {
 	}
 
 	window.MakeContextCurrent()
-	window.SetKeyCallback(onKey)
-	window.SetMouseButtonCallback(onMouseButton)
+	window.SetKeyCallback(e.onKey)
+	window.SetMouseButtonCallback(e.onMouseButton)
 	window.SetCloseCallback(onClose)
-	window.SetScrollCallback(onScroll)
-	window.SetCursorPosCallback(onCursorPos)
+	window.SetScrollCallback(e.onScroll)
+	window.SetCursorPosCallback(e.onCursorPos)
 
 	return window, nil
 }
@@ -169,26 +244,55 @@ func initGL() (string, error) {
 	return version, nil
 }
 
-func onKey(window *glfw.Window, k glfw.Key, s int, action glfw.Action, mods glfw.ModifierKey) {
-	if action != glfw.Press {
+func (e *Engine) onKey(window *glfw.Window, k glfw.Key, s int, action glfw.Action, mods glfw.ModifierKey) {
+	switch action {
+	case glfw.Press:
+		e.input.SetKey(input.Key(k), true)
+	case glfw.Release:
+		e.input.SetKey(input.Key(k), false)
+		return
+	default:
 		return
 	}
 
 	switch glfw.Key(k) {
 	case glfw.KeyEscape:
 		window.SetShouldClose(true)
+	case glfw.KeyF12:
+		e.saveScreenshot()
 	default:
 		return
 	}
 }
 
-func onMouseButton(window *glfw.Window, b glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) {
-	if action != glfw.Press {
+func (e *Engine) onMouseButton(window *glfw.Window, b glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) {
+	switch action {
+	case glfw.Press:
+		e.input.SetButton(input.MouseButton(b), true)
+	case glfw.Release:
+		e.input.SetButton(input.MouseButton(b), false)
+		return
+	default:
 		return
 	}
 
 	switch glfw.MouseButton(b) {
 	case glfw.MouseButtonLeft:
+		e.cursorLock.Lock()
+		x, y := e.cursorX, e.cursorY
+		handlers := e.pickHandlers
+		e.cursorLock.Unlock()
+		if len(handlers) == 0 {
+			return
+		}
+		if result, hit := e.Pick(x, y); hit {
+			// Handlers run off the main thread so they are free to use the engine's systems.
+			go func() {
+				for _, handler := range handlers {
+					handler(result)
+				}
+			}()
+		}
 		return
 	case glfw.MouseButtonRight:
 		return
@@ -201,10 +305,13 @@ func onClose(window *glfw.Window) {
 	window.SetShouldClose(true)
 }
 
-func onScroll(window *glfw.Window, xoff float64, yoff float64) {
-
+func (e *Engine) onScroll(window *glfw.Window, xoff float64, yoff float64) {
+	e.input.Scroll(xoff, yoff)
 }
 
-func onCursorPos(window *glfw.Window, xpos float64, ypos float64) {
-
+func (e *Engine) onCursorPos(window *glfw.Window, xpos float64, ypos float64) {
+	e.cursorLock.Lock()
+	defer e.cursorLock.Unlock()
+	e.cursorX, e.cursorY = xpos, ypos
+	e.input.SetCursor(xpos, ypos)
 }
@@ -4,14 +4,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"log"
+	"sort"
 
 	"github.com/go-gl/glfw/v3.2/glfw"
 	"github.com/go-gl/mathgl/mgl32"
 
 	"github.com/Ariemeth/quantum-pulse/components"
 	"github.com/Ariemeth/quantum-pulse/entity"
+	"github.com/Ariemeth/quantum-pulse/input"
 	"github.com/Ariemeth/quantum-pulse/resources"
+	"github.com/Ariemeth/quantum-pulse/scenefile"
 	"github.com/Ariemeth/quantum-pulse/systems"
+	"github.com/Ariemeth/quantum-pulse/tiled"
 )
 
 const (
@@ -20,11 +25,17 @@ const (
 )
 
 type scene struct {
-	Renderer systems.Renderer
-	//	Animator systems.Animator
-	Movement systems.Movement
-	fileName string
-	assets   *resources.Manager
+	Renderer      systems.Renderer
+	Animator      systems.Animator
+	Movement      systems.Movement
+	Tweener       systems.Tweener
+	Collision     systems.Collision
+	Picker        systems.Picker
+	Navigator     systems.Navigator
+	CameraControl systems.CameraControl
+	camera        components.Camera
+	fileName      string
+	assets        *resources.Manager
 }
 
 // Scene represents a logical grouping of entities
@@ -37,24 +48,55 @@ type Scene interface {
 	Start()
 	// Terminate stops the scene and frees any resources.
 	Terminate()
+	// Tweens retrieves the system used to play tweens in the scene.
+	Tweens() systems.Tweener
+	// Collisions retrieves the system detecting collisions in the scene.
+	Collisions() systems.Collision
+	// Picking retrieves the system finding the entities under a ray.
+	Picking() systems.Picker
+	// Rendering retrieves the system drawing the scene.
+	Rendering() systems.Renderer
+	// Camera retrieves the default camera the scene is viewed through.
+	Camera() components.Camera
+	// Cameras retrieves the enabled cameras of the scene in the order they draw.
+	Cameras() []components.Camera
 }
 
 // newScene creates a new Scene
-func newScene(fileName string, assets *resources.Manager, window *glfw.Window, width, height int) (*scene, error) {
-	scene := scene{
-		fileName: fileName,
-		Renderer: systems.NewRenderer(assets, runOnMain, window),
-		//		Animator: systems.NewAnimator(),
-		Movement: systems.NewMovement(),
-		assets:   assets,
-	}
+func newScene(fileName string, assets *resources.Manager, window *glfw.Window, in *input.State, width, height int) (*scene, error) {
+	scene := createScene(fileName, assets, window, in)
 
 	err := scene.loadSceneFile(fileName, width, height)
 	if err != nil {
 		return nil, err
 	}
 
-	return &scene, nil
+	return scene, nil
+}
+
+// newSceneFromData creates a new Scene from scene data that has already been read or generated.
+func newSceneFromData(id string, sd scenefile.Scene, assets *resources.Manager, window *glfw.Window, in *input.State, width, height int) *scene {
+	scene := createScene(id, assets, window, in)
+	scene.loadSceneData(sd, width, height)
+	return scene
+}
+
+// createScene creates a Scene with its systems but no entities.  Camera controllers read the input state passed in.
+func createScene(fileName string, assets *resources.Manager, window *glfw.Window, in *input.State) *scene {
+	scene := scene{
+		fileName:      fileName,
+		Renderer:      systems.NewRenderer(assets, runOnMain, window),
+		Animator:      systems.NewAnimator(),
+		Movement:      systems.NewMovement(),
+		Tweener:       systems.NewTweener(),
+		Collision:     systems.NewCollision(),
+		Picker:        systems.NewPicker(),
+		Navigator:     systems.NewNavigator(),
+		CameraControl: systems.NewCameraControl(in),
+		assets:        assets,
+	}
+
+	return &scene
 }
 
 // ID returns the id of the scene which currently is the scene filename used to load the scene.
@@ -64,17 +106,65 @@ func (s *scene) ID() string {
 
 func (s *scene) Stop() {
 	s.Renderer.Stop()
+	s.Animator.Stop()
 	s.Movement.Stop()
+	s.Tweener.Stop()
+	s.Collision.Stop()
+	s.Picker.Stop()
+	s.Navigator.Stop()
+	s.CameraControl.Stop()
 }
 
 func (s *scene) Start() {
 	s.Renderer.Start()
+	s.Animator.Start()
 	s.Movement.Start()
+	s.Tweener.Start()
+	s.Collision.Start()
+	s.Picker.Start()
+	s.Navigator.Start()
+	s.CameraControl.Start()
 }
 
 func (s *scene) Terminate() {
 	s.Renderer.Terminate()
+	s.Animator.Terminate()
 	s.Movement.Terminate()
+	s.Tweener.Terminate()
+	s.Collision.Terminate()
+	s.Picker.Terminate()
+	s.Navigator.Terminate()
+	s.CameraControl.Terminate()
+}
+
+// Tweens retrieves the system used to play tweens in the scene.
+func (s *scene) Tweens() systems.Tweener {
+	return s.Tweener
+}
+
+// Collisions retrieves the system detecting collisions in the scene.
+func (s *scene) Collisions() systems.Collision {
+	return s.Collision
+}
+
+// Picking retrieves the system finding the entities under a ray.
+func (s *scene) Picking() systems.Picker {
+	return s.Picker
+}
+
+// Rendering retrieves the system drawing the scene.
+func (s *scene) Rendering() systems.Renderer {
+	return s.Renderer
+}
+
+// Camera retrieves the camera the scene is viewed through.
+func (s *scene) Camera() components.Camera {
+	return s.camera
+}
+
+// Cameras retrieves the enabled cameras of the scene in the order they draw.
+func (s *scene) Cameras() []components.Camera {
+	return s.Renderer.Cameras()
 }
 
 func (s *scene) loadSceneFile(fileName string, width, height int) error {
@@ -84,17 +174,51 @@ func (s *scene) loadSceneFile(fileName string, width, height int) error {
 		return err
 	}
 
-	var sd sceneData
-	err = json.Unmarshal(data, &sd)
+	sd, err := scenefile.Parse(data)
 	if err != nil {
 		return err
 	}
 
-	// configure the camera
-	cam := components.NewCamera()
-	cam.SetView(sd.Camera.Position, sd.Camera.LookAt, sd.Camera.Up)
-	cam.SetProjection(sd.Camera.FOVY, sd.Camera.NearPlane, sd.Camera.FarPlane, width, height)
+	s.loadSceneData(sd, width, height)
+	return nil
+}
+
+func (s *scene) loadSceneData(sd scenefile.Scene, width, height int) {
+	// Render targets are created first so cameras can draw into them and meshes can show them.
+	s.createRenderTargets(sd.RenderTargets, width, height)
+	s.Renderer.SetPostProcessing(sd.PostProcessing)
+
+	// configure the cameras.  Every camera lives on an entity so it can be moved, animated and found like any other component.
+	defaultCamera := sd.Camera
+	defaultCamera.Name = "defaultCamera"
+	defaultCamera.Controller = sd.CameraController
+	cam := s.addCamera(defaultCamera, width, height)
 	s.Renderer.LoadCamera(cam)
+	s.CameraControl.LoadCamera(cam)
+	s.camera = cam
+
+	for i, c := range sd.Cameras {
+		if c.Name == "" {
+			c.Name = fmt.Sprintf("camera%d", i)
+		}
+		s.addCamera(c, width, height)
+	}
+
+	if sd.AmbientLight != nil {
+		s.Renderer.SetAmbientLight(mgl32.Vec3(*sd.AmbientLight))
+	}
+	for i, l := range sd.Lights {
+		if l.Name == "" {
+			l.Name = fmt.Sprintf("light%d", i)
+		}
+		s.addLight(l)
+	}
+
+	s.Movement.SetGravity(mgl32.Vec3{sd.Gravity[0], sd.Gravity[1], sd.Gravity[2]})
+	if sd.Integrator != "" {
+		s.Movement.SetIntegrator(sd.Integrator)
+	}
+	s.Movement.SetFixedStep(sd.FixedStep)
 
 	// Load models
 	for _, modelFile := range sd.Models {
@@ -121,43 +245,229 @@ func (s *scene) loadSceneFile(fileName string, width, height int) error {
 		rotVel := mgl32.Vec3{modelFile.RotVelocity[0], modelFile.RotVelocity[1], modelFile.RotVelocity[2]}
 		tranVel := mgl32.Vec3{modelFile.TransVelocity[0], modelFile.TransVelocity[1], modelFile.TransVelocity[2]}
 		vel.Set(rotVel, tranVel)
+		if modelFile.VelocityFrame != "" {
+			vel.SetFrame(modelFile.VelocityFrame)
+		}
+		vel.SetParent(modelFile.Parent)
 
 		// Load the acceleration component
 		accel := components.NewAcceleration()
 		accRotVel := mgl32.Vec3{modelFile.RotAccel[0], modelFile.RotAccel[1], modelFile.RotAccel[2]}
 		accTranVel := mgl32.Vec3{modelFile.TransAccel[0], modelFile.TransAccel[1], modelFile.TransAccel[2]}
 		accel.Set(accRotVel, accTranVel)
+		if modelFile.AccelFrame != "" {
+			accel.SetFrame(modelFile.AccelFrame)
+		}
+		accel.SetParent(modelFile.Parent)
 
 		ent.AddComponent(vel)
 		ent.AddComponent(accel)
 
-		// Add the model to appropriate systems
-		s.Renderer.AddEntity(ent)
-		s.Movement.AddEntity(ent)
+		// Load the animation component if the model has animations
+		if modelFile.Animation != "" {
+			anim := components.NewAnimation()
+			err := anim.Load(modelFile.Animation)
+			if err != nil {
+				log.Printf("Unable to load animation %s: %v\n", modelFile.Animation, err)
+			} else {
+				if modelFile.Clip != "" {
+					if err := anim.Play(modelFile.Clip); err != nil {
+						log.Printf("Unable to play clip %s: %v\n", modelFile.Clip, err)
+					}
+				}
+				ent.AddComponent(anim)
+			}
+		}
+
+		// Load the skeleton component if the model is skinned
+		if modelFile.Skeleton != "" {
+			skeleton := components.NewSkeleton()
+			err := skeleton.Load(modelFile.Skeleton)
+			if err != nil {
+				log.Printf("Unable to load skeleton %s: %v\n", modelFile.Skeleton, err)
+			} else {
+				ent.AddComponent(skeleton)
+			}
+		}
+
+		// Load the sprite component if the model animates its texture
+		if modelFile.Sprite != "" {
+			sprite := components.NewSprite()
+			err := sprite.Load(modelFile.Sprite)
+			if err != nil {
+				log.Printf("Unable to load sprite %s: %v\n", modelFile.Sprite, err)
+			} else {
+				if modelFile.SpriteSequence != "" {
+					if err := sprite.Play(modelFile.SpriteSequence); err != nil {
+						log.Printf("Unable to play sprite sequence %s: %v\n", modelFile.SpriteSequence, err)
+					}
+				}
+				ent.AddComponent(sprite)
+			}
+		}
+
+		// Load the collider component if the model can collide
+		if modelFile.Collider != nil {
+			s.addComponent(ent, components.TypeCollider, *modelFile.Collider)
+		}
+
+		// Load the rigid body component if the model is simulated
+		if modelFile.RigidBody != nil {
+			s.addComponent(ent, components.TypeRigidBody, *modelFile.RigidBody)
+		}
+
+		// Load the integrator component if the model overrides the scene's integrator
+		if modelFile.Integrator != "" {
+			ent.AddComponent(components.NewIntegrator(modelFile.Integrator))
+		}
+
+		// Load the path follower component if the model walks a route
+		if modelFile.Path != nil {
+			follower := components.NewPathFollower(modelFile.Path.Speed)
+			if modelFile.Path.ArriveRadius > 0 {
+				follower.SetArriveRadius(modelFile.Path.ArriveRadius)
+			}
+			follower.SetLoop(modelFile.Path.Loop)
+			waypoints := make([]mgl32.Vec3, len(modelFile.Path.Waypoints))
+			for i, w := range modelFile.Path.Waypoints {
+				waypoints[i] = mgl32.Vec3{w[0], w[1], w[2]}
+			}
+			follower.SetWaypoints(waypoints)
+			ent.AddComponent(follower)
+		}
+
+		// Load any other components of the model by their registered type
+		for _, componentType := range sortedComponentTypes(modelFile.Components) {
+			if data := modelFile.Components[componentType]; data != nil {
+				s.addComponent(ent, componentType, *data)
+			}
+		}
+
+		s.addEntity(ent)
 	}
-	return nil
+
+	// Load the Tiled maps
+	for _, mapFile := range sd.Maps {
+		m, err := tiled.Load(mapFile.FileName)
+		if err != nil {
+			fmt.Printf("Unable to load map:%s %v\n", mapFile.FileName, err)
+			continue
+		}
+
+		opts := tiled.DefaultOptions()
+		if mapFile.TileSize != [2]float32{} {
+			opts.TileSize = mgl32.Vec2{mapFile.TileSize[0], mapFile.TileSize[1]}
+		}
+		opts.Origin = mgl32.Vec3{mapFile.Origin[0], mapFile.Origin[1], mapFile.Origin[2]}
+		opts.LayerHeight = mapFile.LayerHeight
+		if mapFile.Mesh != "" {
+			opts.Mesh = mapFile.Mesh
+		}
+
+		entities, err := m.Entities(opts)
+		if err != nil {
+			fmt.Printf("Unable to load map:%s %v\n", mapFile.FileName, err)
+			continue
+		}
+		for _, ent := range entities {
+			s.addEntity(ent)
+		}
+	}
+}
+
+// createRenderTargets creates the render targets of a scene.  Render targets without a size are the size of the window.
+func (s *scene) createRenderTargets(targets []scenefile.RenderTarget, width, height int) {
+	for _, t := range targets {
+		settings := resources.RenderTargetSettings{
+			Width:        t.Width,
+			Height:       t.Height,
+			Depth:        resources.DepthFormat(t.Depth),
+			DepthTexture: t.DepthTexture,
+			Nearest:      t.Nearest,
+		}
+		if settings.Width == 0 {
+			settings.Width = width
+		}
+		if settings.Height == 0 {
+			settings.Height = height
+		}
+		for _, format := range t.Color {
+			settings.Color = append(settings.Color, resources.ColorFormat(format))
+		}
+
+		var err error
+		runOnMain(func() {
+			_, err = s.assets.RenderTargets().CreateRenderTarget(t.Name, settings)
+		})
+		if err != nil {
+			fmt.Printf("Unable to create render target:%s %v\n", t.Name, err)
+		}
+	}
+}
+
+// addCamera creates an entity with a camera and adds it to the scene.
+func (s *scene) addCamera(c scenefile.Camera, width, height int) components.Camera {
+	cam := c.Data().Camera(width, height)
+	ent := entity.NewEntity(c.Name)
+	ent.AddComponent(cam)
+	if c.Controller != nil {
+		s.addComponent(ent, components.TypeCameraController, *c.Controller)
+	}
+	for _, componentType := range sortedComponentTypes(c.Components) {
+		if data := c.Components[componentType]; data != nil {
+			s.addComponent(ent, componentType, *data)
+		}
+	}
+	s.addEntity(ent)
+	return cam
 }
 
-type sceneData struct {
-	Camera sceneCamera   `json:"defaultCamera"`
-	Models []sceneModels `json:"models"`
+// addLight creates an entity with a light placed by a transform and adds it to the scene.
+func (s *scene) addLight(l scenefile.Light) {
+	t := components.NewTransform()
+	t.Translate(mgl32.Vec3(l.Position))
+	ent := entity.NewEntity(l.Name)
+	ent.AddComponent(components.NewLight(l.LightData))
+	ent.AddComponent(t)
+	for _, componentType := range sortedComponentTypes(l.Components) {
+		if data := l.Components[componentType]; data != nil {
+			s.addComponent(ent, componentType, *data)
+		}
+	}
+	s.addEntity(ent)
 }
 
-type sceneModels struct {
-	Name          string     `json:"name"`
-	FileName      string     `json:"fileName"`
-	Position      [3]float32 `json:"position"`
-	RotAccel      [3]float32 `json:"rotationalAcceleration"`
-	TransAccel    [3]float32 `json:"translationalAcceleration"`
-	RotVelocity   [3]float32 `json:"rotationalVelocity"`
-	TransVelocity [3]float32 `json:"translationalVelocity"`
+// addEntity adds an entity to every system of the scene.  Each system only keeps the entities that have the components it requires.
+func (s *scene) addEntity(ent entity.Entity) {
+	s.Renderer.AddEntity(ent)
+	s.Animator.AddEntity(ent)
+	s.Movement.AddEntity(ent)
+	s.Collision.AddEntity(ent)
+	s.Picker.AddEntity(ent)
+	s.Navigator.AddEntity(ent)
+	s.CameraControl.AddEntity(ent)
 }
 
-type sceneCamera struct {
-	Position  [3]float32 `json:"position"`
-	LookAt    [3]float32 `json:"lookat"`
-	Up        [3]float32 `json:"up"`
-	FOVY      float32    `json:"fovy"`
-	NearPlane float32    `json:"nearPlane"`
-	FarPlane  float32    `json:"farPlane"`
+// addComponent creates a component of a registered type from json and adds it to an entity, replacing any component of the same type.
+func (s *scene) addComponent(ent entity.Entity, componentType string, data json.RawMessage) {
+	c, err := components.CreateComponent(componentType, data)
+	if err != nil {
+		fmt.Printf("Unable to load %s:%s %v\n", componentType, ent.ID(), err)
+		return
+	}
+	if ent.Component(componentType) != nil {
+		ent.ReplaceComponent(c)
+		return
+	}
+	ent.AddComponent(c)
+}
+
+// sortedComponentTypes retrieves the component types of a model in alphabetical order so components are always added in the same order.
+func sortedComponentTypes(comps map[string]*json.RawMessage) []string {
+	types := make([]string, 0, len(comps))
+	for t := range comps {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
 }
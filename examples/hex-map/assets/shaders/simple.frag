@@ -3,6 +3,7 @@
 // Input attributes
 layout(location = 0) in vec2 fragTexCoord;
 layout(location = 1) in vec4 position;
+layout(location = 2) in vec4 fragColor;
 
 // Uniforms
 uniform sampler2D tex;
@@ -12,7 +13,7 @@ layout(location = 0) out vec4 outputColor;
 
 void main() {
 	if(gl_FrontFacing)
-    	outputColor = texture(tex, fragTexCoord);
+    	outputColor = texture(tex, fragTexCoord) * fragColor;
 	else
 		outputColor = vec4(1.0, 0.5,0.0,0.0);
 	//outputColor = vec4(position.x*0.25, position.y*0.75, position.z, 0);
@@ -3,18 +3,33 @@
 // Input attributes
 layout(location = 0) in vec3 vert;
 layout(location = 1) in vec2 vertTexCoord;
+layout(location = 4) in mat4 instanceModel;
+layout(location = 8) in vec4 instanceColor;
+layout(location = 9) in vec4 instanceUV;
 
 // Uniforms
 uniform mat4 projection;
 uniform mat4 camera;
 uniform mat4 model;
+uniform vec2 uvOffset;
+uniform vec2 uvScale;
+uniform vec4 tint;
+uniform bool instanced;
 
 // Output attributes
 layout(location = 0) out vec2 fragTexCoord;
 layout(location = 1) out vec4 position;
+layout(location = 2) out vec4 fragColor;
 
 void main() {
-    fragTexCoord = vertTexCoord;
-    gl_Position = projection * camera * model * vec4(vert, 1);
+	if(instanced) {
+		fragTexCoord = instanceUV.xy + vertTexCoord * instanceUV.zw;
+		fragColor = instanceColor;
+		gl_Position = projection * camera * instanceModel * vec4(vert, 1);
+	} else {
+		fragTexCoord = uvOffset + vertTexCoord * uvScale;
+		fragColor = tint;
+		gl_Position = projection * camera * model * vec4(vert, 1);
+	}
 	position = gl_Position;
 }
@@ -0,0 +1,33 @@
+#version 410
+
+// Input attributes
+layout(location = 0) in vec3 vert;
+layout(location = 1) in vec2 vertTexCoord;
+layout(location = 2) in uvec4 vertJoints;
+layout(location = 3) in vec4 vertWeights;
+
+// Uniforms
+uniform mat4 projection;
+uniform mat4 camera;
+uniform mat4 model;
+uniform vec2 uvOffset;
+uniform vec2 uvScale;
+uniform vec4 tint;
+uniform mat4 joints[64];
+
+// Output attributes
+layout(location = 0) out vec2 fragTexCoord;
+layout(location = 1) out vec4 position;
+layout(location = 2) out vec4 fragColor;
+
+void main() {
+	mat4 skin = vertWeights.x * joints[vertJoints.x] +
+		vertWeights.y * joints[vertJoints.y] +
+		vertWeights.z * joints[vertJoints.z] +
+		vertWeights.w * joints[vertJoints.w];
+
+    fragTexCoord = uvOffset + vertTexCoord * uvScale;
+	fragColor = tint;
+    gl_Position = projection * camera * model * skin * vec4(vert, 1);
+	position = gl_Position;
+}
@@ -1,7 +1,11 @@
 package main
 
 import (
+	"github.com/go-gl/mathgl/mgl32"
+
+	"github.com/Ariemeth/quantum-pulse/components"
 	"github.com/Ariemeth/quantum-pulse/engine"
+	"github.com/Ariemeth/quantum-pulse/systems"
 )
 
 const (
@@ -10,6 +14,11 @@ const (
 	windowTitle  = "hex map test"
 )
 
+var (
+	normalColor   = mgl32.Vec4{1, 1, 1, 1}
+	selectedColor = mgl32.Vec4{1, 0.6, 0.2, 1}
+)
+
 func main() {
 
 	// Create the engine
@@ -30,6 +39,19 @@ func main() {
 	// Make the scene file that was just loaded the active scene.
 	e.LoadScene(sceneID)
 
+	// Highlight the hex tile that was clicked on, returning the previous one to its normal color.
+	var selected components.Tint
+	e.OnPick(func(pick systems.PickResult) {
+		if selected != nil {
+			selected.SetColor(normalColor)
+			selected = nil
+		}
+		if tint, isTint := pick.Entity.Component(components.TypeTint).(components.Tint); isTint {
+			tint.SetColor(selectedColor)
+			selected = tint
+		}
+	})
+
 	// Start the main game loop.
 	e.Run()
 }
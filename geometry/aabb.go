@@ -0,0 +1,115 @@
+package geometry
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// AABB is an axis aligned bounding box.
+type AABB struct {
+	Min mgl32.Vec3
+	Max mgl32.Vec3
+}
+
+// EmptyAABB creates a box that contains nothing and grows to fit whatever it is extended by.
+func EmptyAABB() AABB {
+	inf := float32(math.Inf(1))
+	return AABB{
+		Min: mgl32.Vec3{inf, inf, inf},
+		Max: mgl32.Vec3{-inf, -inf, -inf},
+	}
+}
+
+// AABBFromPoints creates the smallest box containing all of the points.
+func AABBFromPoints(points ...mgl32.Vec3) AABB {
+	b := EmptyAABB()
+	for _, p := range points {
+		b = b.ExtendPoint(p)
+	}
+	return b
+}
+
+// IsEmpty returns true if the box does not contain any points.
+func (b AABB) IsEmpty() bool {
+	return b.Min.X() > b.Max.X() || b.Min.Y() > b.Max.Y() || b.Min.Z() > b.Max.Z()
+}
+
+// Center retrieves the center of the box.
+func (b AABB) Center() mgl32.Vec3 {
+	return b.Min.Add(b.Max).Mul(0.5)
+}
+
+// Extents retrieves the half size of the box along each axis.
+func (b AABB) Extents() mgl32.Vec3 {
+	return b.Max.Sub(b.Min).Mul(0.5)
+}
+
+// ExtendPoint grows the box to contain the point.
+func (b AABB) ExtendPoint(p mgl32.Vec3) AABB {
+	for i := 0; i < 3; i++ {
+		if p[i] < b.Min[i] {
+			b.Min[i] = p[i]
+		}
+		if p[i] > b.Max[i] {
+			b.Max[i] = p[i]
+		}
+	}
+	return b
+}
+
+// Union grows the box to contain another box.
+func (b AABB) Union(o AABB) AABB {
+	if o.IsEmpty() {
+		return b
+	}
+	return b.ExtendPoint(o.Min).ExtendPoint(o.Max)
+}
+
+// Intersects returns true if the boxes overlap.
+func (b AABB) Intersects(o AABB) bool {
+	return b.Min.X() <= o.Max.X() && b.Max.X() >= o.Min.X() &&
+		b.Min.Y() <= o.Max.Y() && b.Max.Y() >= o.Min.Y() &&
+		b.Min.Z() <= o.Max.Z() && b.Max.Z() >= o.Min.Z()
+}
+
+// Contains returns true if the point is inside the box.
+func (b AABB) Contains(p mgl32.Vec3) bool {
+	return p.X() >= b.Min.X() && p.X() <= b.Max.X() &&
+		p.Y() >= b.Min.Y() && p.Y() <= b.Max.Y() &&
+		p.Z() >= b.Min.Z() && p.Z() <= b.Max.Z()
+}
+
+// Transform retrieves the box containing this box after it has been transformed by a matrix.
+func (b AABB) Transform(m mgl32.Mat4) AABB {
+	if b.IsEmpty() {
+		return b
+	}
+	center := m.Mul4x1(b.Center().Vec4(1)).Vec3()
+	ext := b.Extents()
+	var world mgl32.Vec3
+	for row := 0; row < 3; row++ {
+		for col := 0; col < 3; col++ {
+			world[row] += abs(m.At(row, col)) * ext[col]
+		}
+	}
+	return AABB{Min: center.Sub(world), Max: center.Add(world)}
+}
+
+// Support retrieves the corner of the box furthest in a direction.
+func (b AABB) Support(d mgl32.Vec3) mgl32.Vec3 {
+	p := b.Min
+	for i := 0; i < 3; i++ {
+		if d[i] > 0 {
+			p[i] = b.Max[i]
+		}
+	}
+	return p
+}
+
+func abs(v float32) float32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
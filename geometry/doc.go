@@ -0,0 +1,2 @@
+// Package geometry provides bounding volumes, convex shapes and the intersection tests used for collision detection.
+package geometry
@@ -0,0 +1,70 @@
+package geometry
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Frustum is the volume a camera can see, bounded by six planes whose normals point inwards.
+type Frustum struct {
+	// Planes are the left, right, bottom, top, near and far planes.
+	Planes [6]Plane
+}
+
+// FrustumFromMatrix extracts the frustum of a projection * view matrix.  Points inside the frustum are those the matrix maps into clip space, so the frustum is in world space.  A projection matrix alone gives a frustum in view space.
+func FrustumFromMatrix(m mgl32.Mat4) Frustum {
+	r0, r1, r2, r3 := m.Row(0), m.Row(1), m.Row(2), m.Row(3)
+	rows := [6]mgl32.Vec4{
+		r3.Add(r0),
+		r3.Sub(r0),
+		r3.Add(r1),
+		r3.Sub(r1),
+		r3.Add(r2),
+		r3.Sub(r2),
+	}
+
+	f := Frustum{}
+	for i, row := range rows {
+		// The row holds a, b, c and d of the plane ax + by + cz + d >= 0.
+		n := row.Vec3()
+		l := n.Len()
+		if l == 0 {
+			continue
+		}
+		f.Planes[i] = Plane{Normal: n.Mul(1 / l), Distance: -row.W() / l}
+	}
+	return f
+}
+
+// ContainsPoint returns true if the point is inside the frustum.
+func (f Frustum) ContainsPoint(p mgl32.Vec3) bool {
+	for _, plane := range f.Planes {
+		if plane.SignedDistance(p) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// IntersectsAABB returns true if any part of the box may be inside the frustum.  Boxes near the frustum's corners can be reported as inside when they are not, which is safe for culling.
+func (f Frustum) IntersectsAABB(b AABB) bool {
+	if b.IsEmpty() {
+		return false
+	}
+	for _, plane := range f.Planes {
+		// The corner furthest along the normal is the last to leave the plane.
+		if plane.SignedDistance(b.Support(plane.Normal)) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// IntersectsSphere returns true if any part of the sphere may be inside the frustum.
+func (f Frustum) IntersectsSphere(s Sphere) bool {
+	for _, plane := range f.Planes {
+		if plane.SignedDistance(s.Center) < -s.Radius {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,135 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// testFrustums retrieves the frustums of a camera at (0, 0, 5) looking down -Z.  The perspective frustum has a 90 degree field of view so it is as wide as it is deep, and the orthographic frustum is 8 wide and 6 high.  Both run from 1 to 100 units in front of the camera, which is z 4 to -95.
+func testFrustums() (perspective, ortho Frustum) {
+	view := mgl32.LookAtV(mgl32.Vec3{0, 0, 5}, mgl32.Vec3{}, mgl32.Vec3{0, 1, 0})
+	perspective = FrustumFromMatrix(mgl32.Perspective(mgl32.DegToRad(90), 1, 1, 100).Mul4(view))
+	ortho = FrustumFromMatrix(mgl32.Ortho(-4, 4, -3, 3, 1, 100).Mul4(view))
+	return perspective, ortho
+}
+
+func TestFrustumFromMatrix(t *testing.T) {
+	perspective, ortho := testFrustums()
+	diagonal := float32(1 / math.Sqrt2)
+	tests := []struct {
+		name    string
+		frustum Frustum
+		want    [6]Plane
+	}{
+		{"orthographic", ortho, [6]Plane{
+			{mgl32.Vec3{1, 0, 0}, -4},
+			{mgl32.Vec3{-1, 0, 0}, -4},
+			{mgl32.Vec3{0, 1, 0}, -3},
+			{mgl32.Vec3{0, -1, 0}, -3},
+			{mgl32.Vec3{0, 0, -1}, -4},
+			{mgl32.Vec3{0, 0, 1}, -95},
+		}},
+		// The side planes meet at the camera so they are 5 units behind the origin along their normals.
+		{"perspective", perspective, [6]Plane{
+			{mgl32.Vec3{diagonal, 0, -diagonal}, -5 * diagonal},
+			{mgl32.Vec3{-diagonal, 0, -diagonal}, -5 * diagonal},
+			{mgl32.Vec3{0, diagonal, -diagonal}, -5 * diagonal},
+			{mgl32.Vec3{0, -diagonal, -diagonal}, -5 * diagonal},
+			{mgl32.Vec3{0, 0, -1}, -4},
+			{mgl32.Vec3{0, 0, 1}, -95},
+		}},
+	}
+	for _, test := range tests {
+		for i, want := range test.want {
+			got := test.frustum.Planes[i]
+			// Distances are compared to within 0.01 since the far plane of a perspective projection loses precision.
+			if !near(got.Normal, want.Normal) || !nearFloat(got.Distance/100, want.Distance/100) {
+				t.Errorf("%s: plane %d = %v, want %v", test.name, i, got, want)
+			}
+		}
+	}
+}
+
+func TestFrustumContainsPoint(t *testing.T) {
+	perspective, ortho := testFrustums()
+	tests := []struct {
+		name    string
+		frustum Frustum
+		point   mgl32.Vec3
+		inside  bool
+	}{
+		{"perspective center", perspective, mgl32.Vec3{}, true},
+		{"perspective inside the right edge", perspective, mgl32.Vec3{4.9, 0, 0}, true},
+		{"perspective outside the right edge", perspective, mgl32.Vec3{5.1, 0, 0}, false},
+		{"perspective widens with distance", perspective, mgl32.Vec3{0, 50, -50}, true},
+		{"perspective in front of the near plane", perspective, mgl32.Vec3{0, 0, 4.5}, false},
+		{"perspective behind the camera", perspective, mgl32.Vec3{0, 0, 10}, false},
+		{"perspective past the far plane", perspective, mgl32.Vec3{0, 0, -96}, false},
+		{"orthographic corner", ortho, mgl32.Vec3{-3.9, 2.9, -90}, true},
+		{"orthographic above", ortho, mgl32.Vec3{0, 3.1, 0}, false},
+		{"orthographic does not widen", ortho, mgl32.Vec3{4.1, 0, -90}, false},
+	}
+	for _, test := range tests {
+		if got := test.frustum.ContainsPoint(test.point); got != test.inside {
+			t.Errorf("%s: ContainsPoint(%v) = %v, want %v", test.name, test.point, got, test.inside)
+		}
+	}
+}
+
+func TestFrustumIntersectsSphere(t *testing.T) {
+	perspective, ortho := testFrustums()
+	tests := []struct {
+		name    string
+		frustum Frustum
+		sphere  Sphere
+		inside  bool
+	}{
+		{"perspective inside", perspective, Sphere{mgl32.Vec3{}, 1}, true},
+		{"perspective outside the right", perspective, Sphere{mgl32.Vec3{20, 0, 0}, 1}, false},
+		{"perspective straddling the right", perspective, Sphere{mgl32.Vec3{5.5, 0, 0}, 1}, true},
+		{"perspective behind the camera", perspective, Sphere{mgl32.Vec3{0, 0, 7}, 0.5}, false},
+		{"perspective straddling the near plane", perspective, Sphere{mgl32.Vec3{0, 0, 4.5}, 1}, true},
+		{"perspective past the far plane", perspective, Sphere{mgl32.Vec3{0, 0, -200}, 10}, false},
+		{"perspective straddling the far plane", perspective, Sphere{mgl32.Vec3{0, 0, -96}, 2}, true},
+		{"perspective around the camera", perspective, Sphere{mgl32.Vec3{0, 0, 5}, 200}, true},
+		{"orthographic inside", ortho, Sphere{mgl32.Vec3{3, 2, 0}, 0.5}, true},
+		{"orthographic above", ortho, Sphere{mgl32.Vec3{0, 5, 0}, 1}, false},
+		{"orthographic straddling the top", ortho, Sphere{mgl32.Vec3{0, 3.5, 0}, 1}, true},
+		{"orthographic left", ortho, Sphere{mgl32.Vec3{-6, 0, -50}, 1}, false},
+	}
+	for _, test := range tests {
+		if got := test.frustum.IntersectsSphere(test.sphere); got != test.inside {
+			t.Errorf("%s: IntersectsSphere(%v) = %v, want %v", test.name, test.sphere, got, test.inside)
+		}
+	}
+}
+
+func TestFrustumIntersectsAABB(t *testing.T) {
+	perspective, ortho := testFrustums()
+	tests := []struct {
+		name    string
+		frustum Frustum
+		box     AABB
+		inside  bool
+	}{
+		{"perspective inside", perspective, AABB{mgl32.Vec3{-1, -1, -1}, mgl32.Vec3{1, 1, 1}}, true},
+		{"perspective outside the right", perspective, AABB{mgl32.Vec3{10, -1, -1}, mgl32.Vec3{12, 1, 1}}, false},
+		{"perspective straddling the right", perspective, AABB{mgl32.Vec3{4, -1, -1}, mgl32.Vec3{7, 1, 1}}, true},
+		{"perspective behind the camera", perspective, AABB{mgl32.Vec3{-1, -1, 6}, mgl32.Vec3{1, 1, 8}}, false},
+		{"perspective straddling the near plane", perspective, AABB{mgl32.Vec3{-1, -1, 3}, mgl32.Vec3{1, 1, 6}}, true},
+		{"perspective past the far plane", perspective, AABB{mgl32.Vec3{-1, -1, -120}, mgl32.Vec3{1, 1, -100}}, false},
+		{"perspective around the whole frustum", perspective, AABB{mgl32.Vec3{-500, -500, -500}, mgl32.Vec3{500, 500, 500}}, true},
+		{"empty", perspective, AABB{mgl32.Vec3{1, 1, 1}, mgl32.Vec3{-1, -1, -1}}, false},
+		{"orthographic inside", ortho, AABB{mgl32.Vec3{-1, -1, -50}, mgl32.Vec3{1, 1, -40}}, true},
+		{"orthographic below", ortho, AABB{mgl32.Vec3{-1, -5, -1}, mgl32.Vec3{1, -4, 1}}, false},
+		{"orthographic straddling the bottom", ortho, AABB{mgl32.Vec3{-1, -4, -1}, mgl32.Vec3{1, -2, 1}}, true},
+		{"orthographic straddling the far plane", ortho, AABB{mgl32.Vec3{-1, -1, -100}, mgl32.Vec3{1, 1, -90}}, true},
+	}
+	for _, test := range tests {
+		if got := test.frustum.IntersectsAABB(test.box); got != test.inside {
+			t.Errorf("%s: IntersectsAABB(%v) = %v, want %v", test.name, test.box, got, test.inside)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package geometry
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	gjkMaxIterations = 64
+	epaMaxIterations = 64
+	epaMaxFaces      = 128
+	epaTolerance     = 0.0001
+)
+
+// Contact describes how two overlapping shapes penetrate each other.  Normal points from the second shape towards the first, so moving the first shape by Normal * Depth separates them.
+type Contact struct {
+	Normal mgl32.Vec3
+	Depth  float32
+}
+
+// Intersects returns true if two convex shapes overlap.
+func Intersects(a, b Shape) bool {
+	_, hit := gjk(a, b)
+	return hit
+}
+
+// Penetration tests two convex shapes for overlap and, when they do overlap, retrieves the contact needed to separate them.
+func Penetration(a, b Shape) (Contact, bool) {
+	simplex, hit := gjk(a, b)
+	if !hit {
+		return Contact{}, false
+	}
+	if simplex == nil {
+		// The shapes are only touching so there is nothing to separate.
+		return Contact{Normal: mgl32.Vec3{0, 0, 1}}, true
+	}
+	return epa(a, b, simplex), true
+}
+
+// minkowski retrieves the support point of the Minkowski difference a - b.
+func minkowski(a, b Shape, d mgl32.Vec3) mgl32.Vec3 {
+	return a.Support(d).Sub(b.Support(d.Mul(-1)))
+}
+
+// gjk runs the Gilbert-Johnson-Keerthi algorithm.  On a hit the tetrahedron enclosing the origin is returned, or nil if the shapes only touch.
+func gjk(a, b Shape) (*[4]mgl32.Vec3, bool) {
+	var pa, pb, pc, pd mgl32.Vec3
+
+	pc = minkowski(a, b, mgl32.Vec3{1, 0, 0})
+	if isZero(pc) {
+		return nil, true
+	}
+	dir := pc.Mul(-1)
+	pb = minkowski(a, b, dir)
+	if pb.Dot(dir) < 0 {
+		return nil, false
+	}
+
+	cb := pc.Sub(pb)
+	dir = cb.Cross(pb.Mul(-1)).Cross(cb)
+	if isZero(dir) {
+		// The origin lies on the line so any perpendicular direction will do.
+		dir = cb.Cross(mgl32.Vec3{1, 0, 0})
+		if isZero(dir) {
+			dir = cb.Cross(mgl32.Vec3{0, 0, -1})
+		}
+	}
+
+	dimension := 2
+	for i := 0; i < gjkMaxIterations; i++ {
+		pa = minkowski(a, b, dir)
+		if pa.Dot(dir) < 0 {
+			return nil, false
+		}
+		dimension++
+		if dimension == 3 {
+			dimension, dir = gjkTriangle(&pa, &pb, &pc, &pd)
+		} else {
+			var enclosed bool
+			dimension, dir, enclosed = gjkTetrahedron(&pa, &pb, &pc, &pd)
+			if enclosed {
+				return &[4]mgl32.Vec3{pa, pb, pc, pd}, true
+			}
+		}
+		if isZero(dir) {
+			// The origin lies on the simplex so the shapes are touching.
+			return nil, true
+		}
+	}
+	return nil, false
+}
+
+// gjkTriangle reduces the triangle a, b, c to the feature closest to the origin and retrieves the new simplex size and search direction.
+func gjkTriangle(a, b, c, d *mgl32.Vec3) (int, mgl32.Vec3) {
+	ab, ac, ao := b.Sub(*a), c.Sub(*a), a.Mul(-1)
+	n := ab.Cross(ac)
+
+	if ab.Cross(n).Dot(ao) > 0 {
+		*c = *a
+		return 2, ab.Cross(ao).Cross(ab)
+	}
+	if n.Cross(ac).Dot(ao) > 0 {
+		*b = *a
+		return 2, ac.Cross(ao).Cross(ac)
+	}
+	if n.Dot(ao) > 0 {
+		*d, *c, *b = *c, *b, *a
+		return 3, n
+	}
+	*d, *b = *b, *a
+	return 3, n.Mul(-1)
+}
+
+// gjkTetrahedron checks whether the tetrahedron a, b, c, d encloses the origin, otherwise it is reduced to the face closest to the origin.
+func gjkTetrahedron(a, b, c, d *mgl32.Vec3) (int, mgl32.Vec3, bool) {
+	ab, ac, ad, ao := b.Sub(*a), c.Sub(*a), d.Sub(*a), a.Mul(-1)
+	abc := ab.Cross(ac)
+	acd := ac.Cross(ad)
+	adb := ad.Cross(ab)
+
+	if abc.Dot(ao) > 0 {
+		*d, *c, *b = *c, *b, *a
+		return 3, abc, false
+	}
+	if acd.Dot(ao) > 0 {
+		*b = *a
+		return 3, acd, false
+	}
+	if adb.Dot(ao) > 0 {
+		*c, *d, *b = *d, *b, *a
+		return 3, adb, false
+	}
+	return 4, mgl32.Vec3{}, true
+}
+
+type epaFace struct {
+	points [3]mgl32.Vec3
+	normal mgl32.Vec3
+}
+
+// epa runs the Expanding Polytope Algorithm on the tetrahedron found by gjk to find the direction and depth of least penetration.
+func epa(a, b Shape, s *[4]mgl32.Vec3) Contact {
+	pa, pb, pc, pd := s[0], s[1], s[2], s[3]
+	faces := make([]epaFace, 0, epaMaxFaces)
+	faces = append(faces,
+		newEPAFace(pa, pb, pc),
+		newEPAFace(pa, pc, pd),
+		newEPAFace(pa, pd, pb),
+		newEPAFace(pb, pd, pc),
+	)
+
+	closest := 0
+	for i := 0; i < epaMaxIterations; i++ {
+		closest = closestFace(faces)
+		dir := faces[closest].normal
+		distance := faces[closest].points[0].Dot(dir)
+
+		p := minkowski(a, b, dir)
+		if p.Dot(dir)-distance < epaTolerance {
+			return contact(dir, p.Dot(dir))
+		}
+
+		// Remove every face that can see the new point, keeping the edges on the border of the hole.
+		var edges [][2]mgl32.Vec3
+		kept := faces[:0]
+		for _, f := range faces {
+			if f.normal.Dot(p.Sub(f.points[0])) <= 0 {
+				kept = append(kept, f)
+				continue
+			}
+			for j := 0; j < 3; j++ {
+				edge := [2]mgl32.Vec3{f.points[j], f.points[(j+1)%3]}
+				shared := false
+				for k, e := range edges {
+					if e[0] == edge[1] && e[1] == edge[0] {
+						edges[k] = edges[len(edges)-1]
+						edges = edges[:len(edges)-1]
+						shared = true
+						break
+					}
+				}
+				if !shared {
+					edges = append(edges, edge)
+				}
+			}
+		}
+		faces = kept
+
+		// Patch the hole with faces joining the border edges to the new point.
+		for _, e := range edges {
+			if len(faces) >= epaMaxFaces {
+				break
+			}
+			f := newEPAFace(e[0], e[1], p)
+			if f.points[0].Dot(f.normal)+0.000001 < 0 {
+				f.points[0], f.points[1] = f.points[1], f.points[0]
+				f.normal = f.normal.Mul(-1)
+			}
+			faces = append(faces, f)
+		}
+		if len(faces) == 0 {
+			break
+		}
+	}
+
+	if len(faces) == 0 {
+		return Contact{Normal: mgl32.Vec3{0, 0, 1}}
+	}
+	closest = closestFace(faces)
+	return contact(faces[closest].normal, faces[closest].points[0].Dot(faces[closest].normal))
+}
+
+func newEPAFace(a, b, c mgl32.Vec3) epaFace {
+	n := b.Sub(a).Cross(c.Sub(a))
+	if !isZero(n) {
+		n = n.Normalize()
+	}
+	return epaFace{points: [3]mgl32.Vec3{a, b, c}, normal: n}
+}
+
+func closestFace(faces []epaFace) int {
+	closest := 0
+	min := faces[0].points[0].Dot(faces[0].normal)
+	for i := 1; i < len(faces); i++ {
+		if dist := faces[i].points[0].Dot(faces[i].normal); dist < min {
+			min, closest = dist, i
+		}
+	}
+	return closest
+}
+
+// contact converts the outward normal of the Minkowski difference into a contact pushing the first shape out of the second.
+func contact(normal mgl32.Vec3, depth float32) Contact {
+	if depth < 0 {
+		depth = 0
+	}
+	return Contact{Normal: normal.Mul(-1), Depth: depth}
+}
+
+func isZero(v mgl32.Vec3) bool {
+	return v.Dot(v) < 1e-12
+}
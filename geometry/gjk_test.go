@@ -0,0 +1,127 @@
+package geometry
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// epaEpsilon allows for EPA approximating curved shapes by a polytope of their support points.
+const epaEpsilon = 0.01
+
+func TestPenetration(t *testing.T) {
+	unitBox := Box{HalfExtents: mgl32.Vec3{1, 1, 1}, Orientation: mgl32.QuatIdent()}
+	tests := []struct {
+		name   string
+		a, b   Shape
+		normal mgl32.Vec3
+		depth  float32
+	}{
+		{"sphere/sphere",
+			Sphere{Center: mgl32.Vec3{1.5, 0, 0}, Radius: 1},
+			Sphere{Radius: 1},
+			mgl32.Vec3{1, 0, 0}, 0.5},
+		{"sphere/sphere reversed",
+			Sphere{Radius: 1},
+			Sphere{Center: mgl32.Vec3{0, 1.5, 0}, Radius: 1},
+			mgl32.Vec3{0, -1, 0}, 0.5},
+		{"box/box",
+			Box{Center: mgl32.Vec3{1.75, 0.5, 0}, HalfExtents: mgl32.Vec3{1, 1, 1}, Orientation: mgl32.QuatIdent()},
+			unitBox,
+			mgl32.Vec3{1, 0, 0}, 0.25},
+		{"box/box on the least overlapping axis",
+			Box{Center: mgl32.Vec3{0.5, 0.2, -1.9}, HalfExtents: mgl32.Vec3{1, 1, 1}, Orientation: mgl32.QuatIdent()},
+			unitBox,
+			mgl32.Vec3{0, 0, -1}, 0.1},
+		{"box/rotated box",
+			Box{Center: mgl32.Vec3{0, 2.2, 0}, HalfExtents: mgl32.Vec3{1, 1, 1}, Orientation: mgl32.QuatIdent()},
+			Box{HalfExtents: mgl32.Vec3{1, 1, 1}, Orientation: mgl32.QuatRotate(mgl32.DegToRad(45), mgl32.Vec3{0, 0, 1})},
+			mgl32.Vec3{0, 1, 0}, 1.414214 - 1.2},
+		{"capsule/sphere",
+			Capsule{A: mgl32.Vec3{0, 1.2, -1}, B: mgl32.Vec3{0, 1.2, 1}, Radius: 0.5},
+			Sphere{Radius: 1},
+			mgl32.Vec3{0, 1, 0}, 0.3},
+		{"capsule/box",
+			unitBox,
+			Capsule{A: mgl32.Vec3{-2, 0, 1.25}, B: mgl32.Vec3{2, 0, 1.25}, Radius: 0.5},
+			mgl32.Vec3{0, 0, -1}, 0.25},
+		{"hex prism stacked on hex prism",
+			NewHexPrism(1, 0.5, mgl32.Translate3D(0, 0, 0.8)),
+			NewHexPrism(1, 0.5, mgl32.Ident4()),
+			mgl32.Vec3{0, 0, 1}, 0.2},
+		{"hex prism beside hex prism",
+			NewHexPrism(1, 0.5, mgl32.Translate3D(-1.9, 0, 0)),
+			NewHexPrism(1, 0.5, mgl32.Ident4()),
+			mgl32.Vec3{-1, 0, 0}, 0.1},
+		{"sphere on hex prism",
+			Sphere{Center: mgl32.Vec3{0, 0, 1.25}, Radius: 1},
+			NewHexPrism(1, 0.5, mgl32.Ident4()),
+			mgl32.Vec3{0, 0, 1}, 0.25},
+	}
+	for _, test := range tests {
+		contact, hit := Penetration(test.a, test.b)
+		if !hit {
+			t.Errorf("%s: no contact, want one", test.name)
+			continue
+		}
+		if !Intersects(test.a, test.b) {
+			t.Errorf("%s: Intersects = false while Penetration found a contact", test.name)
+		}
+		if d := contact.Normal.Dot(test.normal); d < 1-epaEpsilon {
+			t.Errorf("%s: normal = %v, want %v", test.name, contact.Normal, test.normal)
+		}
+		if d := contact.Depth - test.depth; d > epaEpsilon || d < -epaEpsilon {
+			t.Errorf("%s: depth = %v, want %v", test.name, contact.Depth, test.depth)
+		}
+
+		// Moving the first shape out along the normal separates the shapes.
+		moved := translated{test.a, contact.Normal.Mul(contact.Depth + epaEpsilon)}
+		if Intersects(moved, test.b) {
+			t.Errorf("%s: shapes still overlap after being pushed apart by the contact", test.name)
+		}
+	}
+}
+
+func TestPenetrationSeparated(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Shape
+	}{
+		{"sphere/sphere",
+			Sphere{Center: mgl32.Vec3{2.1, 0, 0}, Radius: 1},
+			Sphere{Radius: 1}},
+		{"box/box",
+			Box{Center: mgl32.Vec3{0, 0, 2.1}, HalfExtents: mgl32.Vec3{1, 1, 1}, Orientation: mgl32.QuatIdent()},
+			Box{HalfExtents: mgl32.Vec3{1, 1, 1}, Orientation: mgl32.QuatIdent()}},
+		{"box/box across the corner of a rotated box",
+			Box{Center: mgl32.Vec3{1.5, 1.5, 0}, HalfExtents: mgl32.Vec3{0.5, 0.5, 0.5}, Orientation: mgl32.QuatIdent()},
+			Box{HalfExtents: mgl32.Vec3{1, 1, 1}, Orientation: mgl32.QuatRotate(mgl32.DegToRad(45), mgl32.Vec3{0, 0, 1})}},
+		{"capsule/sphere",
+			Capsule{A: mgl32.Vec3{0, 2, -1}, B: mgl32.Vec3{0, 2, 1}, Radius: 0.5},
+			Sphere{Radius: 1}},
+		{"hex prism beside hex prism",
+			NewHexPrism(1, 0.5, mgl32.Translate3D(2.1, 0, 0)),
+			NewHexPrism(1, 0.5, mgl32.Ident4())},
+		{"sphere off the corner of a hex prism",
+			Sphere{Center: mgl32.Vec3{0, 1.6, 0}, Radius: 0.5},
+			NewHexPrism(1, 0.5, mgl32.Ident4())},
+	}
+	for _, test := range tests {
+		if contact, hit := Penetration(test.a, test.b); hit {
+			t.Errorf("%s: got contact %+v, want none", test.name, contact)
+		}
+		if Intersects(test.a, test.b) {
+			t.Errorf("%s: Intersects = true, want false", test.name)
+		}
+	}
+}
+
+// translated is a shape moved by an offset.
+type translated struct {
+	Shape
+	offset mgl32.Vec3
+}
+
+func (t translated) Support(d mgl32.Vec3) mgl32.Vec3 {
+	return t.Shape.Support(d).Add(t.offset)
+}
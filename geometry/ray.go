@@ -0,0 +1,156 @@
+package geometry
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const rayEpsilon = 1e-7
+
+// Ray is a half line starting at Origin and extending along Direction.  Distances along the ray are measured in multiples of Direction, so they are world units when the direction has a length of 1.
+type Ray struct {
+	Origin    mgl32.Vec3
+	Direction mgl32.Vec3
+}
+
+// At retrieves the point a distance along the ray.
+func (r Ray) At(t float32) mgl32.Vec3 {
+	return r.Origin.Add(r.Direction.Mul(t))
+}
+
+// Transform retrieves the ray moved by a matrix.  The direction is not normalized so distances along the transformed ray match distances along the original.
+func (r Ray) Transform(m mgl32.Mat4) Ray {
+	return Ray{
+		Origin:    m.Mul4x1(r.Origin.Vec4(1)).Vec3(),
+		Direction: m.Mul4x1(r.Direction.Vec4(0)).Vec3(),
+	}
+}
+
+// Plane is the set of points p where Normal.Dot(p) equals Distance.
+type Plane struct {
+	Normal   mgl32.Vec3
+	Distance float32
+}
+
+// PlaneFromPoint creates the plane passing through a point with a normal.
+func PlaneFromPoint(point, normal mgl32.Vec3) Plane {
+	n := normal.Normalize()
+	return Plane{Normal: n, Distance: n.Dot(point)}
+}
+
+// SignedDistance retrieves how far a point is in front of the plane.  Points behind the plane have a negative distance.
+func (p Plane) SignedDistance(point mgl32.Vec3) float32 {
+	return p.Normal.Dot(point) - p.Distance
+}
+
+// IntersectPlane retrieves the distance along the ray where it crosses a plane.
+func (r Ray) IntersectPlane(p Plane) (float32, bool) {
+	denom := p.Normal.Dot(r.Direction)
+	if abs(denom) < rayEpsilon {
+		return 0, false
+	}
+	t := (p.Distance - p.Normal.Dot(r.Origin)) / denom
+	return t, t >= 0
+}
+
+// IntersectAABB retrieves the distance along the ray where it enters a box.  A ray starting inside the box hits at a distance of 0.
+func (r Ray) IntersectAABB(b AABB) (float32, bool) {
+	tMin := float32(0)
+	tMax := float32(math.MaxFloat32)
+	for i := 0; i < 3; i++ {
+		if abs(r.Direction[i]) < rayEpsilon {
+			if r.Origin[i] < b.Min[i] || r.Origin[i] > b.Max[i] {
+				return 0, false
+			}
+			continue
+		}
+		inv := 1 / r.Direction[i]
+		t1 := (b.Min[i] - r.Origin[i]) * inv
+		t2 := (b.Max[i] - r.Origin[i]) * inv
+		if t1 > t2 {
+			t1, t2 = t2, t1
+		}
+		if t1 > tMin {
+			tMin = t1
+		}
+		if t2 < tMax {
+			tMax = t2
+		}
+		if tMin > tMax {
+			return 0, false
+		}
+	}
+	return tMin, true
+}
+
+// IntersectSphere retrieves the distance along the ray where it enters a sphere.  A ray starting inside the sphere hits at a distance of 0.
+func (r Ray) IntersectSphere(s Sphere) (float32, bool) {
+	m := r.Origin.Sub(s.Center)
+	a := r.Direction.Dot(r.Direction)
+	if a < rayEpsilon {
+		return 0, false
+	}
+	b := m.Dot(r.Direction)
+	c := m.Dot(m) - s.Radius*s.Radius
+	if c <= 0 {
+		return 0, true
+	}
+	if b > 0 {
+		return 0, false
+	}
+	disc := b*b - a*c
+	if disc < 0 {
+		return 0, false
+	}
+	return (-b - float32(math.Sqrt(float64(disc)))) / a, true
+}
+
+// IntersectTriangle retrieves the distance along the ray where it hits a triangle.  Both sides of the triangle are hit.
+func (r Ray) IntersectTriangle(a, b, c mgl32.Vec3) (float32, bool) {
+	ab, ac := b.Sub(a), c.Sub(a)
+	p := r.Direction.Cross(ac)
+	det := ab.Dot(p)
+	if abs(det) < rayEpsilon {
+		return 0, false
+	}
+	inv := 1 / det
+	s := r.Origin.Sub(a)
+	u := s.Dot(p) * inv
+	if u < 0 || u > 1 {
+		return 0, false
+	}
+	q := s.Cross(ab)
+	v := r.Direction.Dot(q) * inv
+	if v < 0 || u+v > 1 {
+		return 0, false
+	}
+	t := ac.Dot(q) * inv
+	return t, t >= 0
+}
+
+// ScreenToRay creates the ray passing through a point on the screen.  Screen coordinates start at the top left corner of the viewport, matching cursor positions reported by the window.
+func ScreenToRay(x, y float32, width, height int, view, projection mgl32.Mat4) (Ray, bool) {
+	if width <= 0 || height <= 0 {
+		return Ray{}, false
+	}
+	inverse := projection.Mul4(view).Inv()
+	if inverse == (mgl32.Mat4{}) {
+		return Ray{}, false
+	}
+
+	ndcX := 2*x/float32(width) - 1
+	ndcY := 1 - 2*y/float32(height)
+	near := inverse.Mul4x1(mgl32.Vec4{ndcX, ndcY, -1, 1})
+	far := inverse.Mul4x1(mgl32.Vec4{ndcX, ndcY, 1, 1})
+	if near.W() == 0 || far.W() == 0 {
+		return Ray{}, false
+	}
+	origin := near.Vec3().Mul(1 / near.W())
+	target := far.Vec3().Mul(1 / far.W())
+	dir := target.Sub(origin)
+	if dir.Len() < rayEpsilon {
+		return Ray{}, false
+	}
+	return Ray{Origin: origin, Direction: dir.Normalize()}, true
+}
@@ -0,0 +1,140 @@
+package geometry
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// testEpsilon is the tolerance used when comparing results, which is much larger than rayEpsilon.
+const testEpsilon = 1e-4
+
+// near returns true if every component of two vectors is within testEpsilon of each other.
+func near(a, b mgl32.Vec3) bool {
+	for i := range a {
+		if d := a[i] - b[i]; d > testEpsilon || d < -testEpsilon {
+			return false
+		}
+	}
+	return true
+}
+
+func nearFloat(a, b float32) bool {
+	return a-b <= testEpsilon && b-a <= testEpsilon
+}
+
+func TestRayIntersectAABB(t *testing.T) {
+	box := AABB{Min: mgl32.Vec3{-1, -1, -1}, Max: mgl32.Vec3{1, 1, 1}}
+	tests := []struct {
+		name string
+		ray  Ray
+		hit  bool
+		t    float32
+	}{
+		{"straight on", Ray{mgl32.Vec3{-5, 0, 0}, mgl32.Vec3{1, 0, 0}}, true, 4},
+		{"from below", Ray{mgl32.Vec3{0.5, 0.5, -3}, mgl32.Vec3{0, 0, 1}}, true, 2},
+		{"diagonally through a corner", Ray{mgl32.Vec3{-2, -2, -2}, mgl32.Vec3{1, 1, 1}}, true, 1},
+		{"unnormalized direction", Ray{mgl32.Vec3{-5, 0, 0}, mgl32.Vec3{2, 0, 0}}, true, 2},
+		{"starting inside", Ray{mgl32.Vec3{0, 0, 0}, mgl32.Vec3{0, 1, 0}}, true, 0},
+		{"pointing away", Ray{mgl32.Vec3{-5, 0, 0}, mgl32.Vec3{-1, 0, 0}}, false, 0},
+		{"parallel outside a slab", Ray{mgl32.Vec3{-5, 2, 0}, mgl32.Vec3{1, 0, 0}}, false, 0},
+		{"passing beside", Ray{mgl32.Vec3{-5, 0, 0}, mgl32.Vec3{1, 1, 0}.Normalize()}, false, 0},
+	}
+	for _, test := range tests {
+		got, hit := test.ray.IntersectAABB(box)
+		if hit != test.hit || (hit && !nearFloat(got, test.t)) {
+			t.Errorf("%s: got %v, %v, want %v, %v", test.name, got, hit, test.t, test.hit)
+		}
+	}
+}
+
+func TestRayIntersectSphere(t *testing.T) {
+	sphere := Sphere{Center: mgl32.Vec3{0, 0, 3}, Radius: 1}
+	tests := []struct {
+		name string
+		ray  Ray
+		hit  bool
+		t    float32
+	}{
+		{"through the center", Ray{mgl32.Vec3{}, mgl32.Vec3{0, 0, 1}}, true, 2},
+		{"unnormalized direction", Ray{mgl32.Vec3{}, mgl32.Vec3{0, 0, 4}}, true, 0.5},
+		{"grazing the edge", Ray{mgl32.Vec3{1, 0, 0}, mgl32.Vec3{0, 0, 1}}, true, 3},
+		{"starting inside", Ray{mgl32.Vec3{0, 0, 3.5}, mgl32.Vec3{1, 0, 0}}, true, 0},
+		{"behind the origin", Ray{mgl32.Vec3{}, mgl32.Vec3{0, 0, -1}}, false, 0},
+		{"missing", Ray{mgl32.Vec3{1.1, 0, 0}, mgl32.Vec3{0, 0, 1}}, false, 0},
+		{"no direction", Ray{mgl32.Vec3{}, mgl32.Vec3{}}, false, 0},
+	}
+	for _, test := range tests {
+		got, hit := test.ray.IntersectSphere(sphere)
+		if hit != test.hit || (hit && !nearFloat(got, test.t)) {
+			t.Errorf("%s: got %v, %v, want %v, %v", test.name, got, hit, test.t, test.hit)
+		}
+	}
+}
+
+func TestRayIntersectTriangle(t *testing.T) {
+	a, b, c := mgl32.Vec3{0, 0, 0}, mgl32.Vec3{2, 0, 0}, mgl32.Vec3{0, 2, 0}
+	tests := []struct {
+		name string
+		ray  Ray
+		hit  bool
+		t    float32
+	}{
+		{"front", Ray{mgl32.Vec3{0.5, 0.5, 2}, mgl32.Vec3{0, 0, -1}}, true, 2},
+		{"back", Ray{mgl32.Vec3{0.5, 0.5, -3}, mgl32.Vec3{0, 0, 1}}, true, 3},
+		{"at an angle", Ray{mgl32.Vec3{-1, 0.5, 1}, mgl32.Vec3{1, 0, -1}}, true, 1},
+		{"on an edge", Ray{mgl32.Vec3{1, 1, 1}, mgl32.Vec3{0, 0, -1}}, true, 1},
+		{"outside the hypotenuse", Ray{mgl32.Vec3{1.5, 1.5, 1}, mgl32.Vec3{0, 0, -1}}, false, 0},
+		{"outside a leg", Ray{mgl32.Vec3{-0.5, 0.5, 1}, mgl32.Vec3{0, 0, -1}}, false, 0},
+		{"behind the origin", Ray{mgl32.Vec3{0.5, 0.5, 1}, mgl32.Vec3{0, 0, 1}}, false, 0},
+		{"parallel to the plane", Ray{mgl32.Vec3{0.5, 0.5, 1}, mgl32.Vec3{1, 0, 0}}, false, 0},
+	}
+	for _, test := range tests {
+		got, hit := test.ray.IntersectTriangle(a, b, c)
+		if hit != test.hit || (hit && !nearFloat(got, test.t)) {
+			t.Errorf("%s: got %v, %v, want %v, %v", test.name, got, hit, test.t, test.hit)
+		}
+	}
+}
+
+func TestScreenToRay(t *testing.T) {
+	const width, height = 800, 600
+	aspect := float32(width) / height
+	view := mgl32.LookAtV(mgl32.Vec3{0, 0, 5}, mgl32.Vec3{}, mgl32.Vec3{0, 1, 0})
+	// A 90 degree field of view puts the edges of the near plane as far from the center as the plane is from the eye.
+	perspective := mgl32.Perspective(mgl32.DegToRad(90), aspect, 1, 100)
+	ortho := mgl32.Ortho(-4, 4, -3, 3, 1, 100)
+
+	tests := []struct {
+		name       string
+		projection mgl32.Mat4
+		x, y       float32
+		origin     mgl32.Vec3
+		direction  mgl32.Vec3
+	}{
+		{"perspective center", perspective, 400, 300, mgl32.Vec3{0, 0, 4}, mgl32.Vec3{0, 0, -1}},
+		{"perspective top left", perspective, 0, 0, mgl32.Vec3{-aspect, 1, 4}, mgl32.Vec3{-aspect, 1, -1}.Normalize()},
+		{"perspective top right", perspective, width, 0, mgl32.Vec3{aspect, 1, 4}, mgl32.Vec3{aspect, 1, -1}.Normalize()},
+		{"perspective bottom left", perspective, 0, height, mgl32.Vec3{-aspect, -1, 4}, mgl32.Vec3{-aspect, -1, -1}.Normalize()},
+		{"perspective bottom right", perspective, width, height, mgl32.Vec3{aspect, -1, 4}, mgl32.Vec3{aspect, -1, -1}.Normalize()},
+		{"orthographic top left", ortho, 0, 0, mgl32.Vec3{-4, 3, 4}, mgl32.Vec3{0, 0, -1}},
+		{"orthographic bottom right", ortho, width, height, mgl32.Vec3{4, -3, 4}, mgl32.Vec3{0, 0, -1}},
+	}
+	for _, test := range tests {
+		ray, ok := ScreenToRay(test.x, test.y, width, height, view, test.projection)
+		if !ok {
+			t.Errorf("%s: no ray", test.name)
+			continue
+		}
+		if !near(ray.Origin, test.origin) || !near(ray.Direction, test.direction) {
+			t.Errorf("%s: ray = %v, want origin %v and direction %v", test.name, ray, test.origin, test.direction)
+		}
+	}
+
+	if _, ok := ScreenToRay(0, 0, 0, height, view, perspective); ok {
+		t.Errorf("a viewport without a width gave a ray")
+	}
+	if _, ok := ScreenToRay(0, 0, width, height, view, mgl32.Mat4{}); ok {
+		t.Errorf("a projection without an inverse gave a ray")
+	}
+}
@@ -0,0 +1,136 @@
+package geometry
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Shape represents a convex shape in world space.  Any shape able to find its furthest point in a direction can be tested for intersection with any other.
+type Shape interface {
+	// Support retrieves the point of the shape furthest in the direction.
+	Support(direction mgl32.Vec3) mgl32.Vec3
+}
+
+// Sphere is a sphere shape.
+type Sphere struct {
+	Center mgl32.Vec3
+	Radius float32
+}
+
+// Support retrieves the point of the sphere furthest in the direction.
+func (s Sphere) Support(d mgl32.Vec3) mgl32.Vec3 {
+	if d.Len() == 0 {
+		return s.Center
+	}
+	return s.Center.Add(d.Normalize().Mul(s.Radius))
+}
+
+// Transform retrieves the sphere containing this sphere after it has been transformed by a matrix.  The radius grows by the largest scale of the matrix.
+func (s Sphere) Transform(m mgl32.Mat4) Sphere {
+	scale := m.Col(0).Vec3().Len()
+	if l := m.Col(1).Vec3().Len(); l > scale {
+		scale = l
+	}
+	if l := m.Col(2).Vec3().Len(); l > scale {
+		scale = l
+	}
+	return Sphere{Center: m.Mul4x1(s.Center.Vec4(1)).Vec3(), Radius: s.Radius * scale}
+}
+
+// Box is an oriented box shape.
+type Box struct {
+	Center      mgl32.Vec3
+	HalfExtents mgl32.Vec3
+	Orientation mgl32.Quat
+}
+
+// Support retrieves the corner of the box furthest in the direction.
+func (b Box) Support(d mgl32.Vec3) mgl32.Vec3 {
+	local := b.Orientation.Conjugate().Rotate(d)
+	corner := b.HalfExtents
+	for i := 0; i < 3; i++ {
+		if local[i] < 0 {
+			corner[i] = -corner[i]
+		}
+	}
+	return b.Center.Add(b.Orientation.Rotate(corner))
+}
+
+// Capsule is a line segment from A to B swept by a radius.
+type Capsule struct {
+	A      mgl32.Vec3
+	B      mgl32.Vec3
+	Radius float32
+}
+
+// Support retrieves the point of the capsule furthest in the direction.
+func (c Capsule) Support(d mgl32.Vec3) mgl32.Vec3 {
+	end := c.A
+	if c.B.Dot(d) > c.A.Dot(d) {
+		end = c.B
+	}
+	if d.Len() == 0 {
+		return end
+	}
+	return end.Add(d.Normalize().Mul(c.Radius))
+}
+
+// ConvexHull is the convex shape enclosing a set of points.
+type ConvexHull struct {
+	Points []mgl32.Vec3
+}
+
+// Support retrieves the point of the hull furthest in the direction.
+func (h ConvexHull) Support(d mgl32.Vec3) mgl32.Vec3 {
+	if len(h.Points) == 0 {
+		return mgl32.Vec3{}
+	}
+	best := h.Points[0]
+	bestDot := best.Dot(d)
+	for _, p := range h.Points[1:] {
+		if dot := p.Dot(d); dot > bestDot {
+			best, bestDot = p, dot
+		}
+	}
+	return best
+}
+
+// HexPrismCorners retrieves the corners of a pointy topped hexagon in the XY plane matching the profile of the hexagon model, where a radius of 1 spans from -1 to 1 on the X axis and from -1 to 1 on the Y axis.
+func HexPrismCorners(radius float32) []mgl32.Vec3 {
+	return []mgl32.Vec3{
+		{0, radius, 0},
+		{-radius, radius / 2, 0},
+		{-radius, -radius / 2, 0},
+		{0, -radius, 0},
+		{radius, -radius / 2, 0},
+		{radius, radius / 2, 0},
+	}
+}
+
+// NewHexPrism creates the convex hull of a hexagonal prism whose hexagon lies in the local XY plane and extends halfHeight along the local Z axis in both directions.  The transform is applied to every corner.
+func NewHexPrism(radius, halfHeight float32, transform mgl32.Mat4) ConvexHull {
+	corners := HexPrismCorners(radius)
+	points := make([]mgl32.Vec3, 0, len(corners)*2)
+	for _, c := range corners {
+		for _, z := range []float32{-halfHeight, halfHeight} {
+			p := mgl32.Vec4{c.X(), c.Y(), z, 1}
+			points = append(points, transform.Mul4x1(p).Vec3())
+		}
+	}
+	return ConvexHull{Points: points}
+}
+
+// Bounds retrieves the axis aligned box containing a shape.
+func Bounds(s Shape) AABB {
+	if b, ok := s.(AABB); ok {
+		return b
+	}
+	b := EmptyAABB()
+	for i := 0; i < 3; i++ {
+		var d mgl32.Vec3
+		d[i] = 1
+		b = b.ExtendPoint(s.Support(d))
+		d[i] = -1
+		b = b.ExtendPoint(s.Support(d))
+	}
+	return b
+}
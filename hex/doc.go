@@ -0,0 +1,4 @@
+// Package hex provides coordinates, layouts and grid algorithms for hexagonal maps.
+//
+// Hexes are addressed with axial coordinates (Q, R) where the third cube coordinate S is -Q-R.  Layouts convert between hexes and world positions in the XY plane, and HexagonMeshLayout matches the hexagon model used by the hex-map example.
+package hex
@@ -0,0 +1,37 @@
+package hex
+
+import (
+	"math"
+)
+
+// FractionalHex is a position between hex centers in axial coordinates, such as the result of converting a world position.
+type FractionalHex struct {
+	Q float64
+	R float64
+}
+
+// S retrieves the third cube coordinate.
+func (f FractionalHex) S() float64 {
+	return -f.Q - f.R
+}
+
+// Round retrieves the hex containing the position.
+func (f FractionalHex) Round() Hex {
+	q, r, s := math.Floor(f.Q+0.5), math.Floor(f.R+0.5), math.Floor(f.S()+0.5)
+	dq, dr, ds := math.Abs(q-f.Q), math.Abs(r-f.R), math.Abs(s-f.S())
+	// The coordinate that was rounded the most is recalculated from the others so the three still sum to 0.
+	if dq > dr && dq > ds {
+		q = -r - s
+	} else if dr > ds {
+		r = -q - s
+	}
+	return Hex{int(q), int(r)}
+}
+
+// Lerp retrieves the position a fraction of the way to another position.
+func (f FractionalHex) Lerp(o FractionalHex, t float64) FractionalHex {
+	return FractionalHex{
+		Q: f.Q + (o.Q-f.Q)*t,
+		R: f.R + (o.R-f.R)*t,
+	}
+}
@@ -0,0 +1,185 @@
+package hex
+
+// Hex is a hexagon addressed by axial coordinates.
+type Hex struct {
+	Q int
+	R int
+}
+
+// New creates a hex from axial coordinates.
+func New(q, r int) Hex {
+	return Hex{Q: q, R: r}
+}
+
+// NewCube creates a hex from cube coordinates.  The coordinates are expected to sum to 0 and s is otherwise ignored.
+func NewCube(q, r, s int) Hex {
+	return Hex{Q: q, R: r}
+}
+
+// S retrieves the third cube coordinate.
+func (h Hex) S() int {
+	return -h.Q - h.R
+}
+
+// Add retrieves the sum of two hexes.
+func (h Hex) Add(o Hex) Hex {
+	return Hex{h.Q + o.Q, h.R + o.R}
+}
+
+// Sub retrieves the difference of two hexes.
+func (h Hex) Sub(o Hex) Hex {
+	return Hex{h.Q - o.Q, h.R - o.R}
+}
+
+// Scale retrieves the hex multiplied by a factor.
+func (h Hex) Scale(k int) Hex {
+	return Hex{h.Q * k, h.R * k}
+}
+
+// Length retrieves the number of steps from the origin to the hex.
+func (h Hex) Length() int {
+	return (abs(h.Q) + abs(h.R) + abs(h.S())) / 2
+}
+
+// Distance retrieves the number of steps between two hexes.
+func (h Hex) Distance(o Hex) int {
+	return h.Sub(o).Length()
+}
+
+// directions are the six neighbor offsets in counter clockwise order, as seen looking down on the XY plane, starting with +Q.
+var directions = [6]Hex{
+	{1, 0}, {0, 1}, {-1, 1}, {-1, 0}, {0, -1}, {1, -1},
+}
+
+// diagonals are the six diagonal offsets in counter clockwise order, starting between directions 0 and 1.
+var diagonals = [6]Hex{
+	{1, 1}, {-1, 2}, {-2, 1}, {-1, -1}, {1, -2}, {2, -1},
+}
+
+// Direction retrieves the offset to the neighbor in a direction from 0 to 5.  Directions wrap so any integer is accepted.
+func Direction(direction int) Hex {
+	return directions[mod6(direction)]
+}
+
+// Neighbor retrieves the adjacent hex in a direction from 0 to 5.
+func (h Hex) Neighbor(direction int) Hex {
+	return h.Add(Direction(direction))
+}
+
+// Neighbors retrieves the six adjacent hexes.
+func (h Hex) Neighbors() []Hex {
+	neighbors := make([]Hex, 6)
+	for i, d := range directions {
+		neighbors[i] = h.Add(d)
+	}
+	return neighbors
+}
+
+// DiagonalNeighbor retrieves the hex across a corner in a direction from 0 to 5.
+func (h Hex) DiagonalNeighbor(direction int) Hex {
+	return h.Add(diagonals[mod6(direction)])
+}
+
+// RotateLeft rotates the hex 60 degrees counter clockwise about the origin.
+func (h Hex) RotateLeft() Hex {
+	return Hex{-h.R, -h.S()}
+}
+
+// RotateRight rotates the hex 60 degrees clockwise about the origin.
+func (h Hex) RotateRight() Hex {
+	return Hex{-h.S(), -h.Q}
+}
+
+// Rotate rotates the hex about a center by steps of 60 degrees.  Positive steps rotate counter clockwise.
+func (h Hex) Rotate(center Hex, steps int) Hex {
+	v := h.Sub(center)
+	for i := 0; i < mod6(steps); i++ {
+		v = v.RotateLeft()
+	}
+	return center.Add(v)
+}
+
+// Reflect mirrors the hex across the axis through the origin along Q.
+func (h Hex) Reflect() Hex {
+	return Hex{h.Q, h.S()}
+}
+
+// Ring retrieves the hexes exactly radius steps from the center in counter clockwise order.  A radius of 0 retrieves only the center.
+func Ring(center Hex, radius int) []Hex {
+	if radius <= 0 {
+		return []Hex{center}
+	}
+	ring := make([]Hex, 0, 6*radius)
+	h := center.Add(Direction(4).Scale(radius))
+	for side := 0; side < 6; side++ {
+		for step := 0; step < radius; step++ {
+			ring = append(ring, h)
+			h = h.Neighbor(side)
+		}
+	}
+	return ring
+}
+
+// Spiral retrieves the hexes within radius steps of the center ordered by ring, starting with the center.
+func Spiral(center Hex, radius int) []Hex {
+	spiral := []Hex{center}
+	for r := 1; r <= radius; r++ {
+		spiral = append(spiral, Ring(center, r)...)
+	}
+	return spiral
+}
+
+// Range retrieves the hexes within n steps of the center ordered by Q then R.
+func Range(center Hex, n int) []Hex {
+	if n < 0 {
+		return nil
+	}
+	hexes := make([]Hex, 0, 3*n*(n+1)+1)
+	for q := -n; q <= n; q++ {
+		for r := max(-n, -q-n); r <= min(n, -q+n); r++ {
+			hexes = append(hexes, center.Add(Hex{q, r}))
+		}
+	}
+	return hexes
+}
+
+// Line retrieves the hexes on a straight line between two hexes including both ends.
+func Line(a, b Hex) []Hex {
+	n := a.Distance(b)
+	if n == 0 {
+		return []Hex{a}
+	}
+	// Nudging the ends keeps points that fall exactly on an edge rounding the same way every time.
+	fa := FractionalHex{float64(a.Q) + 1e-6, float64(a.R) + 1e-6}
+	fb := FractionalHex{float64(b.Q) + 1e-6, float64(b.R) + 1e-6}
+	line := make([]Hex, n+1)
+	for i := 0; i <= n; i++ {
+		line[i] = fa.Lerp(fb, float64(i)/float64(n)).Round()
+	}
+	return line
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func mod6(v int) int {
+	return ((v % 6) + 6) % 6
+}
@@ -0,0 +1,208 @@
+package hex
+
+import (
+	"testing"
+)
+
+// unique returns true if no hex appears twice.
+func unique(hexes []Hex) bool {
+	seen := make(map[Hex]bool, len(hexes))
+	for _, h := range hexes {
+		if seen[h] {
+			return false
+		}
+		seen[h] = true
+	}
+	return true
+}
+
+// connected returns true if every hex is next to the one before it.
+func connected(hexes []Hex) bool {
+	for i := 1; i < len(hexes); i++ {
+		if hexes[i].Distance(hexes[i-1]) != 1 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestCube(t *testing.T) {
+	for _, h := range Range(New(1, -2), 3) {
+		if sum := h.Q + h.R + h.S(); sum != 0 {
+			t.Errorf("%v: cube coordinates sum to %d, want 0", h, sum)
+		}
+		if got := NewCube(h.Q, h.R, h.S()); got != h {
+			t.Errorf("NewCube(%d, %d, %d) = %v, want %v", h.Q, h.R, h.S(), got, h)
+		}
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b Hex
+		want int
+	}{
+		{New(0, 0), New(0, 0), 0},
+		{New(0, 0), New(1, 0), 1},
+		{New(0, 0), New(1, -1), 1},
+		{New(0, 0), New(2, 1), 3},
+		{New(-2, 3), New(1, -1), 4},
+		{New(3, -3), New(-3, 3), 6},
+		{New(-1, -1), New(1, 1), 4},
+	}
+	for _, test := range tests {
+		if got := test.a.Distance(test.b); got != test.want {
+			t.Errorf("%v.Distance(%v) = %d, want %d", test.a, test.b, got, test.want)
+		}
+		if got := test.b.Distance(test.a); got != test.want {
+			t.Errorf("%v.Distance(%v) = %d, want %d", test.b, test.a, got, test.want)
+		}
+	}
+}
+
+func TestNeighbors(t *testing.T) {
+	center := New(2, -1)
+	neighbors := center.Neighbors()
+	if len(neighbors) != 6 || !unique(neighbors) {
+		t.Fatalf("Neighbors() = %v, want 6 different hexes", neighbors)
+	}
+	for i, n := range neighbors {
+		if d := center.Distance(n); d != 1 {
+			t.Errorf("neighbor %d %v is %d away, want 1", i, n, d)
+		}
+		if got := center.Neighbor(i); got != n {
+			t.Errorf("Neighbor(%d) = %v, want %v", i, got, n)
+		}
+		// Each neighbor is next to the following one, going around the center.
+		if next := neighbors[(i+1)%6]; n.Distance(next) != 1 {
+			t.Errorf("neighbors %v and %v are not adjacent", n, next)
+		}
+		if d := center.Distance(center.DiagonalNeighbor(i)); d != 2 {
+			t.Errorf("diagonal neighbor %d is %d away, want 2", i, d)
+		}
+	}
+	if Direction(-1) != Direction(5) || Direction(6) != Direction(0) {
+		t.Errorf("directions do not wrap")
+	}
+}
+
+func TestRing(t *testing.T) {
+	center := New(-1, 2)
+	for radius := 0; radius <= 4; radius++ {
+		ring := Ring(center, radius)
+		want := 6 * radius
+		if radius == 0 {
+			want = 1
+		}
+		if len(ring) != want || !unique(ring) {
+			t.Errorf("Ring radius %d has %d hexes, want %d different hexes", radius, len(ring), want)
+			continue
+		}
+		for _, h := range ring {
+			if d := center.Distance(h); d != radius {
+				t.Errorf("Ring radius %d contains %v, %d away", radius, h, d)
+			}
+		}
+		if radius > 0 && (!connected(ring) || ring[0].Distance(ring[len(ring)-1]) != 1) {
+			t.Errorf("Ring radius %d does not go around the center in order", radius)
+		}
+	}
+}
+
+func TestSpiralAndRange(t *testing.T) {
+	center := New(3, -1)
+	for n := 0; n <= 4; n++ {
+		want := 3*n*(n+1) + 1
+		spiral, area := Spiral(center, n), Range(center, n)
+		if len(spiral) != want || !unique(spiral) {
+			t.Errorf("Spiral radius %d has %d hexes, want %d different hexes", n, len(spiral), want)
+		}
+		if len(area) != want || !unique(area) {
+			t.Errorf("Range %d has %d hexes, want %d different hexes", n, len(area), want)
+		}
+		if spiral[0] != center {
+			t.Errorf("Spiral radius %d starts at %v, want the center", n, spiral[0])
+		}
+		// Both hold every hex within n steps so each hex in the range is in the spiral.
+		inSpiral := make(map[Hex]bool, len(spiral))
+		for _, h := range spiral {
+			inSpiral[h] = true
+		}
+		for _, h := range area {
+			if center.Distance(h) > n || !inSpiral[h] {
+				t.Errorf("Range %d contains %v which is not in the spiral", n, h)
+			}
+		}
+	}
+	if got := Range(center, -1); len(got) != 0 {
+		t.Errorf("Range -1 = %v, want no hexes", got)
+	}
+}
+
+func TestLine(t *testing.T) {
+	tests := []struct {
+		a, b Hex
+	}{
+		{New(0, 0), New(0, 0)},
+		{New(0, 0), New(3, 0)},
+		{New(0, 0), New(2, 1)},
+		{New(-2, 3), New(3, -3)},
+		{New(1, 1), New(-4, 1)},
+		// Every other point of this line falls on an edge between two hexes.
+		{New(0, 0), New(2, -4)},
+	}
+	for _, test := range tests {
+		line := Line(test.a, test.b)
+		if want := test.a.Distance(test.b) + 1; len(line) != want {
+			t.Errorf("Line(%v, %v) has %d hexes, want %d", test.a, test.b, len(line), want)
+			continue
+		}
+		if line[0] != test.a || line[len(line)-1] != test.b {
+			t.Errorf("Line(%v, %v) = %v, want it to start and end at the hexes", test.a, test.b, line)
+		}
+		if !connected(line) {
+			t.Errorf("Line(%v, %v) = %v, want each hex next to the last", test.a, test.b, line)
+		}
+	}
+}
+
+func TestRotate(t *testing.T) {
+	h := New(2, -1)
+	if got := New(1, 0).RotateLeft(); got != Direction(1) {
+		t.Errorf("RotateLeft of direction 0 = %v, want direction 1 %v", got, Direction(1))
+	}
+	if got := New(1, 0).RotateRight(); got != Direction(5) {
+		t.Errorf("RotateRight of direction 0 = %v, want direction 5 %v", got, Direction(5))
+	}
+	if got := h.RotateLeft().RotateRight(); got != h {
+		t.Errorf("RotateLeft then RotateRight = %v, want %v", got, h)
+	}
+
+	left, right := h, h
+	for i := 0; i < 6; i++ {
+		left, right = left.RotateLeft(), right.RotateRight()
+		if left.Length() != h.Length() || right.Length() != h.Length() {
+			t.Errorf("rotating %v changed its distance from the origin", h)
+		}
+	}
+	if left != h || right != h {
+		t.Errorf("six rotations of %v gave %v and %v, want %v", h, left, right, h)
+	}
+
+	center := New(-1, 1)
+	tests := []struct {
+		steps int
+		want  Hex
+	}{
+		{0, h},
+		{1, center.Add(h.Sub(center).RotateLeft())},
+		{-1, center.Add(h.Sub(center).RotateRight())},
+		{5, center.Add(h.Sub(center).RotateRight())},
+		{3, center.Sub(h.Sub(center))},
+	}
+	for _, test := range tests {
+		if got := h.Rotate(center, test.steps); got != test.want {
+			t.Errorf("Rotate(%v, %d) = %v, want %v", center, test.steps, got, test.want)
+		}
+	}
+}
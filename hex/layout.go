@@ -0,0 +1,95 @@
+package hex
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+var sqrt3 = math.Sqrt(3)
+
+// Orientation holds the matrices converting between axial coordinates and positions, along with the angle of the first corner in sixths of a turn.
+type Orientation struct {
+	f0, f1, f2, f3 float64
+	b0, b1, b2, b3 float64
+	startAngle     float64
+}
+
+var (
+	// Pointy orients hexes with a corner at the top.  Rows of hexes run along the X axis.
+	Pointy = Orientation{
+		f0: sqrt3, f1: sqrt3 / 2, f2: 0, f3: 3.0 / 2,
+		b0: sqrt3 / 3, b1: -1.0 / 3, b2: 0, b3: 2.0 / 3,
+		startAngle: 0.5,
+	}
+	// Flat orients hexes with an edge at the top.  Columns of hexes run along the Y axis.
+	Flat = Orientation{
+		f0: 3.0 / 2, f1: 0, f2: sqrt3 / 2, f3: sqrt3,
+		b0: 2.0 / 3, b1: 0, b2: -1.0 / 3, b3: sqrt3 / 3,
+		startAngle: 0,
+	}
+)
+
+// Layout places hexes in the world's XY plane.  Size is the distance from a hex's center to its corners along each axis, which allows stretched hexes, and Origin is the position of hex (0, 0).  Q increases along +X and R increases along +Y.
+type Layout struct {
+	Orientation Orientation
+	Size        mgl32.Vec2
+	Origin      mgl32.Vec2
+}
+
+// HexagonMeshLayout matches the hexagon model of the hex-map example, whose corners are at (0, ±1) and (±1, ±0.5) so neighboring hexes are 2 apart along X and rows are 1.5 apart along Y.
+var HexagonMeshLayout = Layout{
+	Orientation: Pointy,
+	Size:        mgl32.Vec2{float32(2 / sqrt3), 1},
+}
+
+// NewLayout creates a layout of regular hexes with a distance of size from the center of each hex to its corners.
+func NewLayout(orientation Orientation, size float32, origin mgl32.Vec2) Layout {
+	return Layout{Orientation: orientation, Size: mgl32.Vec2{size, size}, Origin: origin}
+}
+
+// ToWorld retrieves the world position of a hex's center.  Z is always 0.
+func (l Layout) ToWorld(h Hex) mgl32.Vec3 {
+	o := l.Orientation
+	q, r := float64(h.Q), float64(h.R)
+	// The explicit conversions stop the products being fused into FMA instructions on some architectures so positions are identical on every machine.
+	x := (float64(o.f0*q) + float64(o.f1*r)) * float64(l.Size.X())
+	y := (float64(o.f2*q) + float64(o.f3*r)) * float64(l.Size.Y())
+	return mgl32.Vec3{float32(x) + l.Origin.X(), float32(y) + l.Origin.Y(), 0}
+}
+
+// FromWorld retrieves the fractional hex at a world position.  Z is ignored.  Call Round on the result to find the hex containing the position.
+func (l Layout) FromWorld(p mgl32.Vec3) FractionalHex {
+	o := l.Orientation
+	x := float64(p.X()-l.Origin.X()) / float64(l.Size.X())
+	y := float64(p.Y()-l.Origin.Y()) / float64(l.Size.Y())
+	return FractionalHex{
+		Q: o.b0*x + o.b1*y,
+		R: o.b2*x + o.b3*y,
+	}
+}
+
+// HexAt retrieves the hex containing a world position.
+func (l Layout) HexAt(p mgl32.Vec3) Hex {
+	return l.FromWorld(p).Round()
+}
+
+// CornerOffset retrieves the offset from a hex's center to one of its six corners, counted counter clockwise.
+func (l Layout) CornerOffset(corner int) mgl32.Vec3 {
+	angle := 2 * math.Pi * (l.Orientation.startAngle + float64(corner)) / 6
+	return mgl32.Vec3{
+		float32(float64(l.Size.X()) * math.Cos(angle)),
+		float32(float64(l.Size.Y()) * math.Sin(angle)),
+		0,
+	}
+}
+
+// Corners retrieves the world positions of a hex's six corners counted counter clockwise.
+func (l Layout) Corners(h Hex) []mgl32.Vec3 {
+	center := l.ToWorld(h)
+	corners := make([]mgl32.Vec3, 6)
+	for i := range corners {
+		corners[i] = center.Add(l.CornerOffset(i))
+	}
+	return corners
+}
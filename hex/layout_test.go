@@ -0,0 +1,142 @@
+package hex
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const epsilon = 1e-4
+
+// near returns true if every component of two vectors is within epsilon of each other.
+func near(a, b mgl32.Vec3) bool {
+	for i := range a {
+		if d := a[i] - b[i]; d > epsilon || d < -epsilon {
+			return false
+		}
+	}
+	return true
+}
+
+// hexagonCorners loads the corners of the hex-map example's hexagon model, which are every vertex after the center.
+func hexagonCorners(t *testing.T) []mgl32.Vec3 {
+	data, err := ioutil.ReadFile("../examples/hex-map/assets/models/hexagon.json")
+	if err != nil {
+		t.Fatalf("Unable to read the hexagon model: %v", err)
+	}
+	var model struct {
+		Verts    []float32 `json:"verts"`
+		VertSize int       `json:"vertSize"`
+	}
+	if err := json.Unmarshal(data, &model); err != nil {
+		t.Fatalf("Unable to decode the hexagon model: %v", err)
+	}
+	var corners []mgl32.Vec3
+	for i := model.VertSize; i+2 < len(model.Verts); i += model.VertSize {
+		corners = append(corners, mgl32.Vec3{model.Verts[i], model.Verts[i+1], model.Verts[i+2]})
+	}
+	return corners
+}
+
+func TestHexagonMeshLayoutCorners(t *testing.T) {
+	model := hexagonCorners(t)
+	corners := HexagonMeshLayout.Corners(New(0, 0))
+	if len(model) != len(corners) {
+		t.Fatalf("the model has %d corners, want %d", len(model), len(corners))
+	}
+	for _, c := range corners {
+		found := false
+		for _, m := range model {
+			found = found || near(c, m)
+		}
+		if !found {
+			t.Errorf("layout corner %v is not a corner of the model %v", c, model)
+		}
+	}
+
+	// Neighbors share the corners on their common edge so the tiles fit together.
+	center, right, up := HexagonMeshLayout.Corners(New(0, 0)), HexagonMeshLayout.Corners(New(1, 0)), HexagonMeshLayout.Corners(New(0, 1))
+	shared := []struct {
+		name string
+		a, b mgl32.Vec3
+	}{
+		{"right neighbor's upper left corner", center[0], right[2]},
+		{"right neighbor's lower left corner", center[5], right[3]},
+		{"upper neighbor's lower left corner", center[1], up[3]},
+		{"upper neighbor's bottom corner", center[0], up[4]},
+	}
+	for _, s := range shared {
+		if !near(s.a, s.b) {
+			t.Errorf("%s is at %v, want %v", s.name, s.b, s.a)
+		}
+	}
+}
+
+func TestLayoutRoundTrip(t *testing.T) {
+	layouts := []struct {
+		name   string
+		layout Layout
+	}{
+		{"hexagon mesh", HexagonMeshLayout},
+		{"pointy", NewLayout(Pointy, 1, mgl32.Vec2{})},
+		{"flat", NewLayout(Flat, 1, mgl32.Vec2{})},
+		{"flat moved and scaled", NewLayout(Flat, 2.5, mgl32.Vec2{-3, 7})},
+		{"stretched", Layout{Orientation: Pointy, Size: mgl32.Vec2{1, 3}, Origin: mgl32.Vec2{1, 1}}},
+	}
+	for _, l := range layouts {
+		for _, h := range Range(New(-1, 2), 6) {
+			center := l.layout.ToWorld(h)
+			if got := l.layout.HexAt(center); got != h {
+				t.Errorf("%s: %v is at %v, which is in %v", l.name, h, center, got)
+			}
+			f := l.layout.FromWorld(center)
+			if math.Abs(f.Q-float64(h.Q)) > epsilon || math.Abs(f.R-float64(h.R)) > epsilon {
+				t.Errorf("%s: FromWorld(%v) = %v, want %v", l.name, center, f, h)
+			}
+			// Points just inside each corner still belong to the hex.
+			for i := 0; i < 6; i++ {
+				p := center.Add(l.layout.CornerOffset(i).Mul(0.95))
+				if got := l.layout.HexAt(p); got != h {
+					t.Errorf("%s: the point %v inside corner %d of %v is in %v", l.name, p, i, h, got)
+				}
+			}
+		}
+	}
+}
+
+func TestLayoutSpacing(t *testing.T) {
+	tests := []struct {
+		name   string
+		layout Layout
+		h      Hex
+		want   mgl32.Vec3
+	}{
+		{"hexagon mesh columns are 2 apart", HexagonMeshLayout, New(1, 0), mgl32.Vec3{2, 0, 0}},
+		{"hexagon mesh rows are 1.5 apart", HexagonMeshLayout, New(0, 1), mgl32.Vec3{1, 1.5, 0}},
+		{"hexagon mesh negative rows", HexagonMeshLayout, New(1, -2), mgl32.Vec3{0, -3, 0}},
+		{"flat columns", NewLayout(Flat, 1, mgl32.Vec2{}), New(1, 0), mgl32.Vec3{1.5, float32(sqrt3 / 2), 0}},
+		{"origin", NewLayout(Flat, 1, mgl32.Vec2{4, -2}), New(0, 0), mgl32.Vec3{4, -2, 0}},
+	}
+	for _, test := range tests {
+		if got := test.layout.ToWorld(test.h); !near(got, test.want) {
+			t.Errorf("%s: ToWorld(%v) = %v, want %v", test.name, test.h, got, test.want)
+		}
+	}
+}
+
+func TestRotateInWorld(t *testing.T) {
+	// Rotating a hex left turns its world position 60 degrees counter clockwise about the origin.
+	for _, orientation := range []Orientation{Pointy, Flat} {
+		l := NewLayout(orientation, 1, mgl32.Vec2{})
+		turn := mgl32.Rotate3DZ(mgl32.DegToRad(60))
+		for _, h := range Ring(New(0, 0), 2) {
+			want := turn.Mul3x1(l.ToWorld(h))
+			if got := l.ToWorld(h.RotateLeft()); !near(got, want) {
+				t.Errorf("%v rotated left is at %v, want %v", h, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package hex
+
+// OffsetType describes which rows or columns of an offset grid are shifted.
+type OffsetType int
+
+const (
+	// OddR shifts odd rows right and is used with pointy layouts.
+	OddR OffsetType = iota
+	// EvenR shifts even rows right and is used with pointy layouts.
+	EvenR
+	// OddQ shifts odd columns down and is used with flat layouts.
+	OddQ
+	// EvenQ shifts even columns down and is used with flat layouts.
+	EvenQ
+)
+
+// Offset is a hex addressed by column and row, which suits rectangular maps stored in arrays.
+type Offset struct {
+	Col int
+	Row int
+}
+
+// ToOffset converts a hex to offset coordinates.
+func ToOffset(h Hex, t OffsetType) Offset {
+	switch t {
+	case EvenR:
+		return Offset{Col: h.Q + (h.R+(h.R&1))/2, Row: h.R}
+	case OddQ:
+		return Offset{Col: h.Q, Row: h.R + (h.Q-(h.Q&1))/2}
+	case EvenQ:
+		return Offset{Col: h.Q, Row: h.R + (h.Q+(h.Q&1))/2}
+	default:
+		return Offset{Col: h.Q + (h.R-(h.R&1))/2, Row: h.R}
+	}
+}
+
+// FromOffset converts offset coordinates to a hex.
+func FromOffset(o Offset, t OffsetType) Hex {
+	switch t {
+	case EvenR:
+		return Hex{Q: o.Col - (o.Row+(o.Row&1))/2, R: o.Row}
+	case OddQ:
+		return Hex{Q: o.Col, R: o.Row - (o.Col-(o.Col&1))/2}
+	case EvenQ:
+		return Hex{Q: o.Col, R: o.Row - (o.Col+(o.Col&1))/2}
+	default:
+		return Hex{Q: o.Col - (o.Row-(o.Row&1))/2, R: o.Row}
+	}
+}
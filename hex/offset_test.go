@@ -0,0 +1,72 @@
+package hex
+
+import (
+	"testing"
+)
+
+var offsetTypes = []struct {
+	name string
+	t    OffsetType
+}{
+	{"odd-r", OddR},
+	{"even-r", EvenR},
+	{"odd-q", OddQ},
+	{"even-q", EvenQ},
+}
+
+func TestOffsetRoundTrip(t *testing.T) {
+	for _, ot := range offsetTypes {
+		for _, h := range Range(New(0, 0), 5) {
+			o := ToOffset(h, ot.t)
+			if got := FromOffset(o, ot.t); got != h {
+				t.Errorf("%s: %v to %v and back = %v", ot.name, h, o, got)
+			}
+		}
+		for col := -4; col <= 4; col++ {
+			for row := -4; row <= 4; row++ {
+				o := Offset{col, row}
+				h := FromOffset(o, ot.t)
+				if got := ToOffset(h, ot.t); got != o {
+					t.Errorf("%s: %v to %v and back = %v", ot.name, o, h, got)
+				}
+			}
+		}
+	}
+}
+
+func TestToOffset(t *testing.T) {
+	tests := []struct {
+		h    Hex
+		want [4]Offset
+	}{
+		{New(0, 0), [4]Offset{{0, 0}, {0, 0}, {0, 0}, {0, 0}}},
+		{New(1, 1), [4]Offset{{1, 1}, {2, 1}, {1, 1}, {1, 2}}},
+		{New(-1, -1), [4]Offset{{-2, -1}, {-1, -1}, {-1, -2}, {-1, -1}}},
+		{New(3, -2), [4]Offset{{2, -2}, {2, -2}, {3, -1}, {3, 0}}},
+		{New(-3, 1), [4]Offset{{-3, 1}, {-2, 1}, {-3, -1}, {-3, 0}}},
+	}
+	for _, test := range tests {
+		for i, ot := range offsetTypes {
+			if got := ToOffset(test.h, ot.t); got != test.want[i] {
+				t.Errorf("%s: ToOffset(%v) = %v, want %v", ot.name, test.h, got, test.want[i])
+			}
+		}
+	}
+}
+
+func TestOffsetNeighbors(t *testing.T) {
+	// Moving along a row or column of the grid steps to a neighbor, whichever way the rows or columns are shifted.
+	for _, ot := range offsetTypes {
+		for col := -3; col <= 3; col++ {
+			for row := -3; row <= 3; row++ {
+				h := FromOffset(Offset{col, row}, ot.t)
+				if d := h.Distance(FromOffset(Offset{col + 1, row}, ot.t)); ot.t <= EvenR && d != 1 {
+					t.Errorf("%s: columns %d and %d of row %d are %d apart", ot.name, col, col+1, row, d)
+				}
+				if d := h.Distance(FromOffset(Offset{col, row + 1}, ot.t)); ot.t >= OddQ && d != 1 {
+					t.Errorf("%s: rows %d and %d of column %d are %d apart", ot.name, row, row+1, col, d)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,4 @@
+// Package input tracks the state of the keyboard and mouse.
+//
+// The engine feeds window events into a State and systems read it by taking snapshots.  Each snapshot holds the keys and buttons that are down and how far the cursor and scroll wheel moved since the previous snapshot taken by the same reader, so any number of systems can read input without consuming it from each other.
+package input
@@ -0,0 +1,37 @@
+package input
+
+// Key is a keyboard key.  Values match the key codes of glfw so the engine can pass them through unchanged.
+type Key int
+
+// Keys used by the engine's controllers.  Letters and digits use their ASCII codes, so Key('W') is the W key.
+const (
+	KeySpace       Key = 32
+	KeyA           Key = 'A'
+	KeyD           Key = 'D'
+	KeyE           Key = 'E'
+	KeyF           Key = 'F'
+	KeyQ           Key = 'Q'
+	KeyR           Key = 'R'
+	KeyS           Key = 'S'
+	KeyW           Key = 'W'
+	KeyEscape      Key = 256
+	KeyRight       Key = 262
+	KeyLeft        Key = 263
+	KeyDown        Key = 264
+	KeyUp          Key = 265
+	KeyF12         Key = 301
+	KeyLeftShift   Key = 340
+	KeyLeftControl Key = 341
+)
+
+// MouseButton is a mouse button.  Values match the button numbers of glfw.
+type MouseButton int
+
+// Mouse buttons.
+const (
+	MouseButtonLeft   MouseButton = 0
+	MouseButtonRight  MouseButton = 1
+	MouseButtonMiddle MouseButton = 2
+	// MouseButtonNone is never down, which turns off features bound to a button.
+	MouseButtonNone MouseButton = -1
+)
@@ -0,0 +1,164 @@
+package input
+
+import "sync"
+
+// State is the current state of the keyboard and mouse.  It is safe to use from multiple goroutines.
+type State struct {
+	keys      map[Key]bool
+	buttons   map[MouseButton]bool
+	cursorX   float64
+	cursorY   float64
+	hasCursor bool
+	scrollX   float64
+	scrollY   float64
+	width     int
+	height    int
+	dataLock  sync.RWMutex
+}
+
+// NewState creates a State with nothing pressed.
+func NewState() *State {
+	s := State{
+		keys:    make(map[Key]bool),
+		buttons: make(map[MouseButton]bool),
+	}
+	return &s
+}
+
+// SetKey records a key being pressed or released.
+func (s *State) SetKey(k Key, down bool) {
+	s.dataLock.Lock()
+	defer s.dataLock.Unlock()
+	s.keys[k] = down
+}
+
+// SetButton records a mouse button being pressed or released.
+func (s *State) SetButton(b MouseButton, down bool) {
+	s.dataLock.Lock()
+	defer s.dataLock.Unlock()
+	s.buttons[b] = down
+}
+
+// SetCursor records the cursor position in pixels from the top left corner of the window.
+func (s *State) SetCursor(x, y float64) {
+	s.dataLock.Lock()
+	defer s.dataLock.Unlock()
+	s.cursorX, s.cursorY = x, y
+	s.hasCursor = true
+}
+
+// Scroll records the scroll wheel moving.  Positive y scrolls up, away from the user.
+func (s *State) Scroll(x, y float64) {
+	s.dataLock.Lock()
+	defer s.dataLock.Unlock()
+	s.scrollX += x
+	s.scrollY += y
+}
+
+// SetWindowSize records the size of the window in pixels.
+func (s *State) SetWindowSize(width, height int) {
+	s.dataLock.Lock()
+	defer s.dataLock.Unlock()
+	s.width, s.height = width, height
+}
+
+// KeyDown returns true while a key is held down.
+func (s *State) KeyDown(k Key) bool {
+	s.dataLock.RLock()
+	defer s.dataLock.RUnlock()
+	return s.keys[k]
+}
+
+// ButtonDown returns true while a mouse button is held down.
+func (s *State) ButtonDown(b MouseButton) bool {
+	s.dataLock.RLock()
+	defer s.dataLock.RUnlock()
+	return s.buttons[b]
+}
+
+// Cursor retrieves the cursor position in pixels from the top left corner of the window.
+func (s *State) Cursor() (x, y float64) {
+	s.dataLock.RLock()
+	defer s.dataLock.RUnlock()
+	return s.cursorX, s.cursorY
+}
+
+// Snapshot captures the state along with how far the cursor and scroll wheel moved since the previous snapshot.  Pass the zero Snapshot the first time.
+func (s *State) Snapshot(previous Snapshot) Snapshot {
+	s.dataLock.RLock()
+	defer s.dataLock.RUnlock()
+
+	snap := Snapshot{
+		keys:      make(map[Key]bool, len(s.keys)),
+		buttons:   make(map[MouseButton]bool, len(s.buttons)),
+		CursorX:   s.cursorX,
+		CursorY:   s.cursorY,
+		HasCursor: s.hasCursor,
+		Width:     s.width,
+		Height:    s.height,
+		scrollX:   s.scrollX,
+		scrollY:   s.scrollY,
+	}
+	for k, down := range s.keys {
+		if down {
+			snap.keys[k] = true
+		}
+	}
+	for b, down := range s.buttons {
+		if down {
+			snap.buttons[b] = true
+		}
+	}
+
+	if previous.HasCursor {
+		snap.DeltaX = snap.CursorX - previous.CursorX
+		snap.DeltaY = snap.CursorY - previous.CursorY
+	}
+	snap.ScrollX = snap.scrollX - previous.scrollX
+	snap.ScrollY = snap.scrollY - previous.scrollY
+	return snap
+}
+
+// Snapshot is the state of the keyboard and mouse at one moment.
+type Snapshot struct {
+	keys    map[Key]bool
+	buttons map[MouseButton]bool
+	// CursorX and CursorY are the cursor position in pixels from the top left corner of the window.
+	CursorX float64
+	CursorY float64
+	// HasCursor is false until the cursor position is first known.
+	HasCursor bool
+	// DeltaX and DeltaY are how far the cursor moved since the previous snapshot.
+	DeltaX float64
+	DeltaY float64
+	// ScrollX and ScrollY are how far the scroll wheel moved since the previous snapshot.
+	ScrollX float64
+	ScrollY float64
+	// Width and Height are the size of the window in pixels.
+	Width   int
+	Height  int
+	scrollX float64
+	scrollY float64
+}
+
+// KeyDown returns true if the key was held down.
+func (s Snapshot) KeyDown(k Key) bool {
+	return s.keys[k]
+}
+
+// ButtonDown returns true if the mouse button was held down.
+func (s Snapshot) ButtonDown(b MouseButton) bool {
+	return s.buttons[b]
+}
+
+// Axis retrieves 1 if only the positive key is down, -1 if only the negative key is down and 0 otherwise.
+func (s Snapshot) Axis(negative, positive Key) float32 {
+	v := float32(0)
+	if s.keys[positive] {
+		v++
+	}
+	if s.keys[negative] {
+		v--
+	}
+	return v
+}
@@ -0,0 +1,6 @@
+// Package mapgen procedurally generates hex maps from a seed.
+//
+// Elevation, moisture and temperature come from layered Perlin noise, rivers run downhill from the highlands to the sea and biome rules turn the three fields into terrain.  A generated map can be turned into scene data the engine loads directly or written out as a scene file.
+//
+// Generation only uses integer arithmetic, correctly rounded floating point operations and ordered iteration, so the same seed and parameters produce byte-identical maps and scene files on every machine.
+package mapgen
@@ -0,0 +1,284 @@
+package mapgen
+
+import (
+	"math"
+	"sort"
+
+	"github.com/Ariemeth/quantum-pulse/hex"
+	"github.com/Ariemeth/quantum-pulse/pathfinding"
+)
+
+// sqrt3 is correctly rounded by math.Sqrt, so it is the same on every machine.
+var sqrt3 = math.Sqrt(3)
+
+// Tile is a single hex of a generated map.
+type Tile struct {
+	Hex         hex.Hex
+	Offset      hex.Offset
+	Elevation   float64
+	Moisture    float64
+	Temperature float64
+	Terrain     Terrain
+	River       bool
+}
+
+// Map is a generated hex map.  Tiles are stored row by row using odd-r offset coordinates, matching a pointy layout.
+type Map struct {
+	Params Params
+	Tiles  []Tile
+	// Rivers holds the tiles of each river from its source to where it meets water, ends in a basin or joins another river.
+	Rivers [][]hex.Hex
+}
+
+// Generate creates a map from the parameters.  The same parameters always produce the same map.
+func Generate(p Params) *Map {
+	if p.Width < 0 {
+		p.Width = 0
+	}
+	if p.Height < 0 {
+		p.Height = 0
+	}
+	if p.Scale <= 0 {
+		p.Scale = 1
+	}
+
+	m := Map{
+		Params: p,
+		Tiles:  make([]Tile, p.Width*p.Height),
+	}
+
+	// Each field gets its own noise and offset so they do not line up with each other.
+	r := NewRand(p.Seed)
+	elevation := NewPerlin(r)
+	moisture := NewPerlin(r)
+	temperature := NewPerlin(r)
+	offsets := [3][2]float64{}
+	for i := range offsets {
+		offsets[i] = [2]float64{float64(r.Intn(1024)), float64(r.Intn(1024))}
+	}
+
+	for row := 0; row < p.Height; row++ {
+		for col := 0; col < p.Width; col++ {
+			o := hex.Offset{Col: col, Row: row}
+			x, y := tileCenter(o)
+			nx, ny := x/p.Scale, y/p.Scale
+
+			e := (elevation.Fractal(nx+offsets[0][0], ny+offsets[0][1], p.Octaves, p.Persistence, p.Lacunarity) + 1) / 2
+			if p.Island {
+				e = (e + 1 - m.edgeDistance(x, y)) / 2
+			}
+			e = clamp01(e)
+
+			mo := clamp01((moisture.Fractal(nx+offsets[1][0], ny+offsets[1][1], p.Octaves, p.Persistence, p.Lacunarity) + 1) / 2)
+
+			t := 1 - m.latitude(row)
+			t += float64(0.2 * temperature.Fractal(nx+offsets[2][0], ny+offsets[2][1], p.Octaves, p.Persistence, p.Lacunarity))
+			if e > p.SeaLevel && p.SeaLevel < 1 {
+				t -= float64(p.Lapse * ((e - p.SeaLevel) / (1 - p.SeaLevel)))
+			}
+
+			m.Tiles[m.index(o)] = Tile{
+				Hex:         hex.FromOffset(o, hex.OddR),
+				Offset:      o,
+				Elevation:   e,
+				Moisture:    mo,
+				Temperature: clamp01(t),
+			}
+		}
+	}
+
+	m.placeRivers(r)
+	m.assignTerrain()
+
+	return &m
+}
+
+// Width retrieves the number of columns of the map.
+func (m *Map) Width() int {
+	return m.Params.Width
+}
+
+// Height retrieves the number of rows of the map.
+func (m *Map) Height() int {
+	return m.Params.Height
+}
+
+// Tile retrieves the tile at offset coordinates.  The boolean is false if the coordinates are outside the map.
+func (m *Map) Tile(col, row int) (*Tile, bool) {
+	if col < 0 || row < 0 || col >= m.Params.Width || row >= m.Params.Height {
+		return nil, false
+	}
+	return &m.Tiles[m.index(hex.Offset{Col: col, Row: row})], true
+}
+
+// At retrieves the tile of a hex.  The boolean is false if the hex is outside the map.
+func (m *Map) At(h hex.Hex) (*Tile, bool) {
+	o := hex.ToOffset(h, hex.OddR)
+	return m.Tile(o.Col, o.Row)
+}
+
+// HexGrid creates a pathfinding graph of the map.  Each tile costs the value of its terrain, and tiles whose terrain has no cost cannot be entered.
+func (m *Map) HexGrid(costs map[Terrain]float64) *pathfinding.HexGrid {
+	g := pathfinding.NewHexGrid()
+	for _, t := range m.Tiles {
+		if cost, ok := costs[t.Terrain]; ok {
+			g.SetCost(t.Hex, cost)
+		}
+	}
+	return g
+}
+
+func (m *Map) index(o hex.Offset) int {
+	return o.Row*m.Params.Width + o.Col
+}
+
+// neighbors retrieves the indices of the tiles adjacent to a tile in direction order.
+func (m *Map) neighbors(i int) []int {
+	h := m.Tiles[i].Hex
+	n := make([]int, 0, 6)
+	for d := 0; d < 6; d++ {
+		o := hex.ToOffset(h.Neighbor(d), hex.OddR)
+		if o.Col < 0 || o.Row < 0 || o.Col >= m.Params.Width || o.Row >= m.Params.Height {
+			continue
+		}
+		n = append(n, m.index(o))
+	}
+	return n
+}
+
+// edgeDistance retrieves the squared distance of a point from the center of the map, where 1 is the middle of an edge.
+func (m *Map) edgeDistance(x, y float64) float64 {
+	maxX, maxY := tileCenter(hex.Offset{Col: m.Params.Width - 1, Row: m.Params.Height - 1})
+	maxX += 0.5
+	dx, dy := 0.0, 0.0
+	if maxX > 0 {
+		dx = 2*x/maxX - 1
+	}
+	if maxY > 0 {
+		dy = 2*y/maxY - 1
+	}
+	return float64(dx*dx) + float64(dy*dy)
+}
+
+// latitude retrieves how far a row is from the middle row, where 0 is the equator and 1 is a pole.
+func (m *Map) latitude(row int) float64 {
+	if m.Params.Height < 2 {
+		return 0
+	}
+	return math.Abs(float64(2*row)/float64(m.Params.Height-1) - 1)
+}
+
+// placeRivers starts rivers at random highland tiles and runs each one downhill.
+func (m *Map) placeRivers(r *Rand) {
+	p := m.Params
+	var sources []int
+	for i, t := range m.Tiles {
+		if t.Elevation >= p.HillLevel && t.Elevation >= p.SeaLevel {
+			sources = append(sources, i)
+		}
+	}
+
+	var placed []hex.Hex
+	for attempts := 0; len(m.Rivers) < p.Rivers && attempts < len(sources); attempts++ {
+		source := sources[r.Intn(len(sources))]
+		h := m.Tiles[source].Hex
+		spaced := true
+		for _, other := range placed {
+			if h.Distance(other) < p.RiverSpacing {
+				spaced = false
+				break
+			}
+		}
+		if !spaced || m.Tiles[source].River {
+			continue
+		}
+		placed = append(placed, h)
+		m.Rivers = append(m.Rivers, m.traceRiver(source))
+	}
+
+	// Rivers water the land around them.
+	wet := make([]float64, len(m.Tiles))
+	for i, t := range m.Tiles {
+		if !t.River {
+			continue
+		}
+		wet[i] += p.RiverMoisture
+		for _, n := range m.neighbors(i) {
+			wet[n] += p.RiverMoisture / 2
+		}
+	}
+	for i := range m.Tiles {
+		m.Tiles[i].Moisture = clamp01(m.Tiles[i].Moisture + wet[i])
+	}
+}
+
+// traceRiver follows the steepest descent from a tile until it reaches water, a basin or another river.  Ties go to the tile stored first so the route never depends on anything but the map.
+func (m *Map) traceRiver(i int) []hex.Hex {
+	var river []hex.Hex
+	for {
+		if m.Tiles[i].Elevation < m.Params.SeaLevel {
+			return river
+		}
+		joined := m.Tiles[i].River
+		m.Tiles[i].River = true
+		river = append(river, m.Tiles[i].Hex)
+		if joined {
+			return river
+		}
+
+		neighbors := m.neighbors(i)
+		sort.Ints(neighbors)
+		next := -1
+		for _, n := range neighbors {
+			if next < 0 || m.Tiles[n].Elevation < m.Tiles[next].Elevation {
+				next = n
+			}
+		}
+		if next < 0 || m.Tiles[next].Elevation >= m.Tiles[i].Elevation {
+			return river
+		}
+		i = next
+	}
+}
+
+// assignTerrain applies the biome rules to every tile.
+func (m *Map) assignTerrain() {
+	p := m.Params
+	for i := range m.Tiles {
+		t := &m.Tiles[i]
+		switch {
+		case t.Elevation < p.CoastLevel:
+			t.Terrain = Ocean
+		case t.Elevation < p.SeaLevel:
+			t.Terrain = Coast
+		case t.Elevation < p.BeachLevel && m.nearWater(i):
+			t.Terrain = Beach
+		default:
+			t.Terrain = biome(p, t.Elevation, t.Moisture, t.Temperature)
+		}
+	}
+}
+
+func (m *Map) nearWater(i int) bool {
+	for _, n := range m.neighbors(i) {
+		if m.Tiles[n].Elevation < m.Params.SeaLevel {
+			return true
+		}
+	}
+	return false
+}
+
+// tileCenter retrieves the center of a tile in tile widths, which is where the noise is sampled.
+func tileCenter(o hex.Offset) (float64, float64) {
+	return float64(o.Col) + 0.5*float64(o.Row&1), float64(float64(o.Row)*sqrt3) / 2
+}
+
+func clamp01(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
@@ -0,0 +1,80 @@
+package mapgen
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"testing"
+)
+
+// goldenSceneHash is the SHA-256 of the scene written for seed 1234 on a 24 by 16 map with the default parameters.  Any change to the generator that moves a tile or changes its terrain changes it.
+const goldenSceneHash = "35d5c19e386b67e813f5c3743b2b227aa2cb6a75e34a386710ae5e5f6ecd25e3"
+
+// goldenOptions are the default scene options with a mesh for each terrain and for rivers, so the scene records the terrain of every tile as well as its height.
+func goldenOptions() SceneOptions {
+	opts := DefaultSceneOptions()
+	for _, terrain := range Terrains {
+		opts.Meshes[terrain] = string(terrain) + ".json"
+	}
+	opts.RiverMesh = "river.json"
+	return opts
+}
+
+func TestGenerateGolden(t *testing.T) {
+	m := Generate(DefaultParams(1234, 24, 16))
+	var buf bytes.Buffer
+	if err := m.WriteScene(&buf, goldenOptions()); err != nil {
+		t.Fatalf("Unable to write the scene: %v", err)
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+	if got := fmt.Sprintf("%x", sum); got != goldenSceneHash {
+		t.Errorf("the generated scene hashes to %s, want %s, update goldenSceneHash if the change is intended", got, goldenSceneHash)
+	}
+}
+
+func TestGenerateDeterministic(t *testing.T) {
+	p := DefaultParams(99, 20, 12)
+	var first, second bytes.Buffer
+	Generate(p).WriteScene(&first, goldenOptions())
+	Generate(p).WriteScene(&second, goldenOptions())
+	if !bytes.Equal(first.Bytes(), second.Bytes()) {
+		t.Errorf("two maps from the same parameters wrote different scenes")
+	}
+
+	p.Seed = 100
+	var other bytes.Buffer
+	Generate(p).WriteScene(&other, goldenOptions())
+	if bytes.Equal(first.Bytes(), other.Bytes()) {
+		t.Errorf("maps from different seeds wrote the same scene")
+	}
+}
+
+func TestGenerateTiles(t *testing.T) {
+	m := Generate(DefaultParams(1234, 24, 16))
+	if len(m.Tiles) != 24*16 {
+		t.Fatalf("generated %d tiles, want %d", len(m.Tiles), 24*16)
+	}
+	counts := make(map[Terrain]int)
+	for _, tile := range m.Tiles {
+		counts[tile.Terrain]++
+		for name, v := range map[string]float64{"elevation": tile.Elevation, "moisture": tile.Moisture, "temperature": tile.Temperature} {
+			if v < 0 || v > 1 {
+				t.Errorf("tile %v has a %s of %v, want it within [0, 1]", tile.Offset, name, v)
+			}
+		}
+		if got, ok := m.At(tile.Hex); !ok || got.Offset != tile.Offset {
+			t.Errorf("At(%v) does not find tile %v", tile.Hex, tile.Offset)
+		}
+	}
+	// The island setting surrounds the land with water.
+	for _, tile := range m.Tiles {
+		edge := tile.Offset.Col == 0 || tile.Offset.Row == 0 || tile.Offset.Col == 23 || tile.Offset.Row == 15
+		if edge && !tile.Terrain.IsWater() {
+			t.Errorf("edge tile %v is %s, want water", tile.Offset, tile.Terrain)
+		}
+	}
+	if counts[Ocean] == 0 || counts[Ocean] == len(m.Tiles) {
+		t.Errorf("terrain counts %v, want both land and ocean", counts)
+	}
+}
@@ -0,0 +1,78 @@
+package mapgen
+
+import "math"
+
+// Perlin generates two dimensional gradient noise.  Products are converted explicitly throughout so they are never fused into FMA instructions, which would change the results on some architectures.
+type Perlin struct {
+	perm [512]uint8
+}
+
+// gradients are the directions used at the corners of each noise cell.
+var gradients = [8][2]float64{
+	{1, 1}, {-1, 1}, {1, -1}, {-1, -1},
+	{1, 0}, {-1, 0}, {0, 1}, {0, -1},
+}
+
+// NewPerlin creates a noise generator whose lattice is shuffled by the random number generator.
+func NewPerlin(r *Rand) *Perlin {
+	p := Perlin{}
+	for i := 0; i < 256; i++ {
+		p.perm[i] = uint8(i)
+	}
+	for i := 255; i > 0; i-- {
+		j := r.Intn(i + 1)
+		p.perm[i], p.perm[j] = p.perm[j], p.perm[i]
+	}
+	for i := 0; i < 256; i++ {
+		p.perm[i+256] = p.perm[i]
+	}
+	return &p
+}
+
+// Noise retrieves the noise at a point, which is roughly within [-1, 1] and 0 at every integer point.
+func (p *Perlin) Noise(x, y float64) float64 {
+	fx, fy := math.Floor(x), math.Floor(y)
+	xi, yi := int(fx)&255, int(fy)&255
+	x, y = x-fx, y-fy
+
+	u, v := fade(x), fade(y)
+
+	aa := p.perm[int(p.perm[xi])+yi]
+	ab := p.perm[int(p.perm[xi])+yi+1]
+	ba := p.perm[int(p.perm[xi+1])+yi]
+	bb := p.perm[int(p.perm[xi+1])+yi+1]
+
+	n0 := lerp(u, grad(aa, x, y), grad(ba, x-1, y))
+	n1 := lerp(u, grad(ab, x, y-1), grad(bb, x-1, y-1))
+	return lerp(v, n0, n1)
+}
+
+// Fractal sums octaves of noise, each at lacunarity times the frequency and persistence times the amplitude of the one before.  The result is normalized to roughly [-1, 1].
+func (p *Perlin) Fractal(x, y float64, octaves int, persistence, lacunarity float64) float64 {
+	sum, total := 0.0, 0.0
+	amplitude, frequency := 1.0, 1.0
+	for i := 0; i < octaves; i++ {
+		sum += float64(amplitude * p.Noise(float64(x*frequency), float64(y*frequency)))
+		total += amplitude
+		amplitude = float64(amplitude * persistence)
+		frequency = float64(frequency * lacunarity)
+	}
+	if total == 0 {
+		return 0
+	}
+	return sum / total
+}
+
+func fade(t float64) float64 {
+	t3 := float64(float64(t*t) * t)
+	return float64(t3 * (float64(t*(float64(t*6)-15)) + 10))
+}
+
+func lerp(t, a, b float64) float64 {
+	return a + float64(t*(b-a))
+}
+
+func grad(hash uint8, x, y float64) float64 {
+	g := gradients[hash&7]
+	return float64(g[0]*x) + float64(g[1]*y)
+}
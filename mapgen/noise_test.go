@@ -0,0 +1,40 @@
+package mapgen
+
+import (
+	"testing"
+)
+
+func TestPerlinValues(t *testing.T) {
+	p := NewPerlin(NewRand(42))
+	tests := []struct {
+		x, y float64
+		want float64
+	}{
+		{1.25, 3.75, -0.23928451538085938},
+		{-2.3, 7.9, -0.037496807040000316},
+		{100.1, -50.6, 0.49799010047999653},
+	}
+	for _, test := range tests {
+		if got := p.Noise(test.x, test.y); got != test.want {
+			t.Errorf("Noise(%v, %v) = %v, want %v", test.x, test.y, got, test.want)
+		}
+	}
+	if got := p.Fractal(3.3, 4.4, 5, 0.5, 2); got != 0.1224178093419354 {
+		t.Errorf("Fractal(3.3, 4.4) = %v, want 0.1224178093419354", got)
+	}
+}
+
+func TestPerlinLattice(t *testing.T) {
+	p := NewPerlin(NewRand(3))
+	for x := -3; x <= 3; x++ {
+		for y := -3; y <= 3; y++ {
+			if got := p.Noise(float64(x), float64(y)); got != 0 {
+				t.Errorf("Noise(%d, %d) = %v, want 0 at a lattice point", x, y, got)
+			}
+		}
+	}
+	// The lattice repeats every 256 cells.
+	if a, b := p.Noise(1.3, 2.6), p.Noise(257.3, 2.6); a-b > 1e-9 || b-a > 1e-9 {
+		t.Errorf("Noise(1.3, 2.6) = %v but Noise(257.3, 2.6) = %v", a, b)
+	}
+}
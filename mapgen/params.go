@@ -0,0 +1,81 @@
+package mapgen
+
+// Params controls how a map is generated.  Elevation, moisture and temperature are all within [0, 1], and the levels and thresholds are compared against them.
+type Params struct {
+	// Seed selects the map.  The same seed and parameters always produce the same map.
+	Seed uint64 `json:"seed"`
+	// Width is the number of columns of the map.
+	Width int `json:"width"`
+	// Height is the number of rows of the map.
+	Height int `json:"height"`
+
+	// Scale is the number of tiles spanned by one cell of the coarsest noise octave.  Larger scales produce larger continents.
+	Scale float64 `json:"scale"`
+	// Octaves is the number of layers of noise summed together.
+	Octaves int `json:"octaves"`
+	// Persistence scales the amplitude of each octave relative to the one before.
+	Persistence float64 `json:"persistence"`
+	// Lacunarity scales the frequency of each octave relative to the one before.
+	Lacunarity float64 `json:"lacunarity"`
+	// Island lowers the elevation toward the edges of the map so land is surrounded by ocean.
+	Island bool `json:"island"`
+
+	// SeaLevel is the elevation below which tiles are water.
+	SeaLevel float64 `json:"seaLevel"`
+	// CoastLevel is the elevation below the sea level above which water is shallow.
+	CoastLevel float64 `json:"coastLevel"`
+	// BeachLevel is the elevation above the sea level below which land next to water is beach.
+	BeachLevel float64 `json:"beachLevel"`
+	// HillLevel is the elevation above which land is hills.
+	HillLevel float64 `json:"hillLevel"`
+	// MountainLevel is the elevation above which land is mountains.
+	MountainLevel float64 `json:"mountainLevel"`
+
+	// DryMoisture is the moisture below which land is desert.
+	DryMoisture float64 `json:"dryMoisture"`
+	// WetMoisture is the moisture above which land is forest or jungle.
+	WetMoisture float64 `json:"wetMoisture"`
+	// FrozenTemperature is the temperature below which land is snow.
+	FrozenTemperature float64 `json:"frozenTemperature"`
+	// ColdTemperature is the temperature below which land is tundra.
+	ColdTemperature float64 `json:"coldTemperature"`
+	// HotTemperature is the temperature above which land is savanna, jungle or desert.
+	HotTemperature float64 `json:"hotTemperature"`
+	// Lapse is how much colder the highest land is than land at the sea level.
+	Lapse float64 `json:"lapse"`
+
+	// Rivers is the number of rivers to try to place.
+	Rivers int `json:"rivers"`
+	// RiverSpacing is the minimum distance in tiles between river sources.
+	RiverSpacing int `json:"riverSpacing"`
+	// RiverMoisture is the moisture added to tiles a river runs through and half of it to their neighbors.
+	RiverMoisture float64 `json:"riverMoisture"`
+}
+
+// DefaultParams creates parameters for a temperate island map of the given size.
+func DefaultParams(seed uint64, width, height int) Params {
+	return Params{
+		Seed:              seed,
+		Width:             width,
+		Height:            height,
+		Scale:             16,
+		Octaves:           5,
+		Persistence:       0.5,
+		Lacunarity:        2,
+		Island:            true,
+		SeaLevel:          0.45,
+		CoastLevel:        0.4,
+		BeachLevel:        0.48,
+		HillLevel:         0.7,
+		MountainLevel:     0.8,
+		DryMoisture:       0.3,
+		WetMoisture:       0.6,
+		FrozenTemperature: 0.15,
+		ColdTemperature:   0.3,
+		HotTemperature:    0.7,
+		Lapse:             0.4,
+		Rivers:            4,
+		RiverSpacing:      4,
+		RiverMoisture:     0.2,
+	}
+}
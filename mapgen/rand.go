@@ -0,0 +1,40 @@
+package mapgen
+
+// Rand is a small splitmix64 random number generator.  Unlike math/rand its sequence is part of this package, so a seed always produces the same numbers.
+type Rand struct {
+	state uint64
+}
+
+// NewRand creates a random number generator from a seed.
+func NewRand(seed uint64) *Rand {
+	return &Rand{state: seed}
+}
+
+// Uint64 retrieves the next random number.
+func (r *Rand) Uint64() uint64 {
+	r.state += 0x9e3779b97f4a7c15
+	z := r.state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// Intn retrieves a random number in [0, n).  Intn panics if n is not positive.
+func (r *Rand) Intn(n int) int {
+	if n <= 0 {
+		panic("mapgen: invalid argument to Intn")
+	}
+	// Reject the top of the range that would bias the result toward small numbers.
+	max := ^uint64(0) - ^uint64(0)%uint64(n)
+	for {
+		v := r.Uint64()
+		if v < max {
+			return int(v % uint64(n))
+		}
+	}
+}
+
+// Float64 retrieves a random number in [0, 1).
+func (r *Rand) Float64() float64 {
+	return float64(r.Uint64()>>11) / (1 << 53)
+}
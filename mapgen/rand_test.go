@@ -0,0 +1,50 @@
+package mapgen
+
+import (
+	"testing"
+)
+
+func TestRandSequence(t *testing.T) {
+	tests := []struct {
+		seed uint64
+		want []uint64
+	}{
+		// The reference splitmix64 outputs for a state of 0.
+		{0, []uint64{0xe220a8397b1dcdaf, 0x6e789e6aa1b965f4, 0x06c45d188009454f, 0xf88bb8a8724c81ec}},
+		{20161231, []uint64{0x3b725b96e2da4b79, 0xc36f7f47f61ace6b, 0x08c0f230ae18ac5b}},
+	}
+	for _, test := range tests {
+		r := NewRand(test.seed)
+		for i, want := range test.want {
+			if got := r.Uint64(); got != want {
+				t.Errorf("seed %d: number %d = %#x, want %#x", test.seed, i, got, want)
+			}
+		}
+	}
+}
+
+func TestRandDerived(t *testing.T) {
+	r := NewRand(7)
+	if got := r.Intn(10); got != 7 {
+		t.Errorf("Intn(10) = %d, want 7", got)
+	}
+	if got := r.Intn(1000); got != 804 {
+		t.Errorf("Intn(1000) = %d, want 804", got)
+	}
+	if got := r.Intn(3); got != 0 {
+		t.Errorf("Intn(3) = %d, want 0", got)
+	}
+	if got := r.Float64(); got != 0.5829302930280781 {
+		t.Errorf("Float64() = %v, want 0.5829302930280781", got)
+	}
+
+	r = NewRand(1)
+	for i := 0; i < 1000; i++ {
+		if v := r.Intn(6); v < 0 || v >= 6 {
+			t.Fatalf("Intn(6) = %d", v)
+		}
+		if v := r.Float64(); v < 0 || v >= 1 {
+			t.Fatalf("Float64() = %v", v)
+		}
+	}
+}
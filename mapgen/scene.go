@@ -0,0 +1,132 @@
+package mapgen
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/Ariemeth/quantum-pulse/components"
+	"github.com/Ariemeth/quantum-pulse/hex"
+	"github.com/Ariemeth/quantum-pulse/scenefile"
+)
+
+// SceneOptions controls how a map is turned into a scene.
+type SceneOptions struct {
+	// Layout places the tiles in the world.
+	Layout hex.Layout
+	// HeightScale is the height of land at the highest elevation.  Water is flat at the sea level, which is placed at a height of 0.
+	HeightScale float32
+	// Meshes maps terrain to the mesh file of its tiles.
+	Meshes map[Terrain]string
+	// DefaultMesh is the mesh file of tiles whose terrain has no mesh.
+	DefaultMesh string
+	// RiverMesh is the mesh file of tiles a river runs through.  Rivers use the terrain's mesh if it is empty.
+	RiverMesh string
+	// Camera is the camera of the scene.  If it is nil a camera looking down over the whole map is created.
+	Camera *scenefile.Camera
+	// Lights are the lights of the scene, which light tiles drawn with a lit shader.
+	Lights []scenefile.Light
+	// AmbientLight is the color of the light reaching every surface of a lit tile.  The engine's default is used if it is nil.
+	AmbientLight *[3]float32
+}
+
+// DefaultSceneOptions creates options placing every tile as the hexagon model of the hex-map example, lit by a sun shining down from the south east.
+func DefaultSceneOptions() SceneOptions {
+	sun := scenefile.Light{
+		LightData: components.NewLightData(components.LightDirectional),
+		Name:      "sun",
+	}
+	sun.Direction = [3]float32{-0.4, 0.3, -1}
+	return SceneOptions{
+		Layout:      hex.HexagonMeshLayout,
+		HeightScale: 2,
+		Meshes:      make(map[Terrain]string),
+		DefaultMesh: "hexagon.json",
+		Lights:      []scenefile.Light{sun},
+	}
+}
+
+// Scene creates scene data with a model for every tile of the map, which can be passed to the engine's LoadSceneData.  Tiles are named hex_<col>_<row>.
+func (m *Map) Scene(opts SceneOptions) scenefile.Scene {
+	sd := scenefile.Scene{
+		Models:       make([]scenefile.Model, 0, len(m.Tiles)),
+		Lights:       append([]scenefile.Light(nil), opts.Lights...),
+		AmbientLight: opts.AmbientLight,
+	}
+
+	for _, t := range m.Tiles {
+		file := opts.DefaultMesh
+		if mesh, ok := opts.Meshes[t.Terrain]; ok && mesh != "" {
+			file = mesh
+		}
+		if t.River && opts.RiverMesh != "" {
+			file = opts.RiverMesh
+		}
+
+		pos := opts.Layout.ToWorld(t.Hex)
+		pos[2] = m.height(t, opts.HeightScale)
+
+		sd.Models = append(sd.Models, scenefile.Model{
+			Name:     fmt.Sprintf("hex_%d_%d", t.Offset.Col, t.Offset.Row),
+			FileName: file,
+			Position: [3]float32{pos.X(), pos.Y(), pos.Z()},
+		})
+	}
+
+	if opts.Camera != nil {
+		sd.Camera = *opts.Camera
+	} else {
+		sd.Camera = m.overviewCamera(opts)
+	}
+
+	return sd
+}
+
+// WriteScene writes the map as a scene file.  The same map and options always write the same bytes.
+func (m *Map) WriteScene(w io.Writer, opts SceneOptions) error {
+	data, err := scenefile.Marshal(m.Scene(opts))
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// height retrieves the height of a tile's model.
+func (m *Map) height(t Tile, scale float32) float32 {
+	sea := m.Params.SeaLevel
+	if t.Elevation <= sea || sea >= 1 {
+		return 0
+	}
+	return float32((t.Elevation-sea)/(1-sea)) * scale
+}
+
+// overviewCamera creates a camera south of the map looking down at its center.  It avoids the mgl32 helpers, whose products may be fused differently on other machines.
+func (m *Map) overviewCamera(opts SceneOptions) scenefile.Camera {
+	var min, max [2]float64
+	for i, t := range m.Tiles {
+		p := opts.Layout.ToWorld(t.Hex)
+		for a := 0; a < 2; a++ {
+			v := float64(p[a])
+			if i == 0 || v < min[a] {
+				min[a] = v
+			}
+			if i == 0 || v > max[a] {
+				max[a] = v
+			}
+		}
+	}
+
+	cx, cy := (min[0]+max[0])/2, (min[1]+max[1])/2
+	dx, dy := max[0]-min[0], max[1]-min[1]
+	span := math.Sqrt(float64(dx*dx)+float64(dy*dy)) + 2
+
+	return scenefile.Camera{
+		Position:  [3]float32{float32(cx), float32(cy - float64(span*0.6)), float32(span * 0.8)},
+		LookAt:    [3]float32{float32(cx), float32(cy), 0},
+		Up:        [3]float32{0, 0, 1},
+		FOVY:      60,
+		NearPlane: 0.1,
+		FarPlane:  float32(span * 3),
+	}
+}
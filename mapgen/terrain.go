@@ -0,0 +1,74 @@
+package mapgen
+
+// Terrain is the kind of land or water covering a tile.
+type Terrain string
+
+const (
+	// Ocean is deep water below the sea level.
+	Ocean Terrain = "ocean"
+	// Coast is shallow water next to land.
+	Coast Terrain = "coast"
+	// Beach is low land next to water.
+	Beach Terrain = "beach"
+	// Grassland is temperate land with moderate rainfall.
+	Grassland Terrain = "grassland"
+	// Forest is temperate land with high rainfall.
+	Forest Terrain = "forest"
+	// Jungle is hot land with high rainfall.
+	Jungle Terrain = "jungle"
+	// Savanna is hot land with moderate rainfall.
+	Savanna Terrain = "savanna"
+	// Desert is hot land with little rainfall.
+	Desert Terrain = "desert"
+	// Tundra is cold land.
+	Tundra Terrain = "tundra"
+	// Snow is frozen land.
+	Snow Terrain = "snow"
+	// Hills is land above the hill level.
+	Hills Terrain = "hills"
+	// Mountain is land above the mountain level.
+	Mountain Terrain = "mountain"
+)
+
+// Terrains lists every terrain type in a fixed order.
+var Terrains = []Terrain{Ocean, Coast, Beach, Grassland, Forest, Jungle, Savanna, Desert, Tundra, Snow, Hills, Mountain}
+
+// IsWater returns true if the terrain is ocean or coast.
+func (t Terrain) IsWater() bool {
+	return t == Ocean || t == Coast
+}
+
+// biome chooses the terrain of a land tile from its elevation, moisture and temperature, which are all within [0, 1].
+func biome(p Params, elevation, moisture, temperature float64) Terrain {
+	switch {
+	case elevation >= p.MountainLevel:
+		if temperature < p.FrozenTemperature {
+			return Snow
+		}
+		return Mountain
+	case elevation >= p.HillLevel:
+		return Hills
+	case temperature < p.FrozenTemperature:
+		return Snow
+	case temperature < p.ColdTemperature:
+		return Tundra
+	case temperature >= p.HotTemperature:
+		switch {
+		case moisture < p.DryMoisture:
+			return Desert
+		case moisture < p.WetMoisture:
+			return Savanna
+		default:
+			return Jungle
+		}
+	default:
+		switch {
+		case moisture < p.DryMoisture:
+			return Desert
+		case moisture < p.WetMoisture:
+			return Grassland
+		default:
+			return Forest
+		}
+	}
+}
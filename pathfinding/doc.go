@@ -0,0 +1,2 @@
+// Package pathfinding finds routes through graphs with A* and Dijkstra, builds flow fields for many units heading to the same goal and smooths the resulting paths.  Hex and square grid graphs are provided for tile based maps.
+package pathfinding
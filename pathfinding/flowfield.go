@@ -0,0 +1,100 @@
+package pathfinding
+
+import (
+	"container/heap"
+)
+
+// FlowField holds the cost of reaching the nearest goal from every node of a graph, letting any number of units find their next step without searching.
+type FlowField struct {
+	graph Graph
+	costs map[Node]float64
+	goals map[Node]bool
+}
+
+// NewFlowField builds a flow field towards one or more goals.  The graph's edges are expected to go both ways, though the costs in each direction may differ.  A maxCost greater than 0 leaves nodes further than that from every goal out of the field.
+func NewFlowField(g Graph, maxCost float64, goals ...Node) *FlowField {
+	costs := make(map[Node]float64, len(goals))
+	isGoal := make(map[Node]bool, len(goals))
+	open := &nodeQueue{}
+	for _, goal := range goals {
+		costs[goal] = 0
+		isGoal[goal] = true
+		heap.Push(open, &queueItem{node: goal})
+	}
+
+	for open.Len() > 0 {
+		current := heap.Pop(open).(*queueItem)
+		if current.cost > costs[current.node] {
+			continue
+		}
+		// Walking the edges backwards needs the cost of the edge pointing at the current node.
+		for _, back := range g.Neighbors(current.node) {
+			edgeCost, ok := edgeCost(g, back.To, current.node)
+			if !ok {
+				continue
+			}
+			cost := current.cost + edgeCost
+			if maxCost > 0 && cost > maxCost {
+				continue
+			}
+			if known, ok := costs[back.To]; ok && known <= cost {
+				continue
+			}
+			costs[back.To] = cost
+			heap.Push(open, &queueItem{node: back.To, cost: cost, priority: cost})
+		}
+	}
+
+	return &FlowField{graph: g, costs: costs, goals: isGoal}
+}
+
+// Cost retrieves the cost of reaching the nearest goal from a node.  The boolean is false if no goal can be reached.
+func (f *FlowField) Cost(n Node) (float64, bool) {
+	cost, ok := f.costs[n]
+	return cost, ok
+}
+
+// Next retrieves the neighbor to move to from a node to get closer to a goal.  The boolean is false at a goal or where no goal can be reached.
+func (f *FlowField) Next(n Node) (Node, bool) {
+	// Zero cost edges can leave nodes that are not goals with no cost, so goals are looked up rather than found by their cost.
+	current, ok := f.costs[n]
+	if !ok || f.goals[n] {
+		return nil, false
+	}
+
+	var best Node
+	bestCost := current
+	for _, e := range f.graph.Neighbors(n) {
+		cost, ok := f.costs[e.To]
+		if !ok {
+			continue
+		}
+		if total := cost + e.Cost; best == nil || total < bestCost {
+			best, bestCost = e.To, total
+		}
+	}
+	return best, best != nil
+}
+
+// Path retrieves the nodes followed from a node to the nearest goal.  The boolean is false if no goal can be reached.
+func (f *FlowField) Path(n Node) (Path, bool) {
+	cost, ok := f.costs[n]
+	if !ok {
+		return Path{}, false
+	}
+	nodes := []Node{n}
+	for next, ok := f.Next(n); ok && len(nodes) <= len(f.costs); next, ok = f.Next(next) {
+		nodes = append(nodes, next)
+	}
+	return Path{Nodes: nodes, Cost: cost}, true
+}
+
+// edgeCost retrieves the cost of the edge between two nodes.
+func edgeCost(g Graph, from, to Node) (float64, bool) {
+	for _, e := range g.Neighbors(from) {
+		if e.To == to {
+			return e.Cost, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,149 @@
+package pathfinding
+
+import (
+	"math"
+	"testing"
+
+	"github.com/Ariemeth/quantum-pulse/hex"
+)
+
+func TestFlowField(t *testing.T) {
+	for _, sg := range searchGraphs() {
+		goals := []Node{sg.nodes[0], sg.nodes[len(sg.nodes)-1]}
+		field := NewFlowField(sg.graph, 0, goals...)
+
+		for _, n := range sg.nodes {
+			// The field's cost is the cheapest path to whichever goal is nearest.
+			want := math.Inf(1)
+			for _, goal := range goals {
+				if p, ok := Dijkstra(sg.graph, n, goal); ok && p.Cost < want {
+					want = p.Cost
+				}
+			}
+			cost, ok := field.Cost(n)
+			if !ok || math.Abs(cost-want) > epsilon {
+				t.Errorf("%s: Cost(%v) = %v, %v, want %v", sg.name, n, cost, ok, want)
+				continue
+			}
+
+			next, ok := field.Next(n)
+			if cost == 0 {
+				if ok {
+					t.Errorf("%s: Next(%v) = %v at a goal, want none", sg.name, n, next)
+				}
+				continue
+			}
+			if !ok {
+				t.Errorf("%s: Next(%v) found no step", sg.name, n)
+				continue
+			}
+			// The next step is an edge along a cheapest path to a goal.
+			nextCost, _ := field.Cost(next)
+			step, isEdge := edgeCost(sg.graph, n, next)
+			if !isEdge || math.Abs(step+nextCost-cost) > epsilon {
+				t.Errorf("%s: Next(%v) = %v which is not on a cheapest path", sg.name, n, next)
+			}
+
+			p, ok := field.Path(n)
+			if !ok {
+				t.Errorf("%s: Path(%v) found no path", sg.name, n)
+				continue
+			}
+			last := p.Nodes[len(p.Nodes)-1]
+			if last != goals[0] && last != goals[1] {
+				t.Errorf("%s: Path(%v) = %v, want it to end at a goal", sg.name, n, p.Nodes)
+			}
+			checkPath(t, sg.name+" flow field", sg.graph, p, n, last)
+		}
+	}
+}
+
+func TestFlowFieldUnreachable(t *testing.T) {
+	g := NewHexGridRange(hex.New(0, 0), 3, 1)
+	for _, h := range hex.Ring(hex.New(2, 0), 1) {
+		g.SetBlocked(h)
+	}
+	field := NewFlowField(g, 0, hex.New(2, 0))
+
+	outside := hex.New(-2, 1)
+	if cost, ok := field.Cost(outside); ok {
+		t.Errorf("Cost outside the ring = %v, want none", cost)
+	}
+	if next, ok := field.Next(outside); ok {
+		t.Errorf("Next outside the ring = %v, want none", next)
+	}
+	if p, ok := field.Path(outside); ok {
+		t.Errorf("Path outside the ring = %v, want none", p.Nodes)
+	}
+	if p, ok := field.Path(hex.New(2, 0)); !ok || len(p.Nodes) != 1 || p.Cost != 0 {
+		t.Errorf("Path from the goal = %v, %v, want only the goal", p, ok)
+	}
+}
+
+func TestFlowFieldMaxCost(t *testing.T) {
+	g := NewSquareGrid(10, 1, 1, false)
+	field := NewFlowField(g, 3, Point{0, 0})
+	for x := 0; x < 10; x++ {
+		if _, ok := field.Cost(Point{x, 0}); ok != (x <= 3) {
+			t.Errorf("tile %d in the field = %v with a max cost of 3", x, ok)
+		}
+	}
+}
+
+func TestFlowFieldDirectedCosts(t *testing.T) {
+	// Moving onto the expensive tile costs more than leaving it so the field has to use the cost of the edge towards the goal.
+	g := NewSquareGrid(3, 1, 1, false)
+	g.SetCost(Point{1, 0}, 5)
+	field := NewFlowField(g, 0, Point{2, 0})
+	tests := []struct {
+		p    Point
+		cost float64
+	}{
+		{Point{2, 0}, 0},
+		{Point{1, 0}, 1},
+		{Point{0, 0}, 6},
+	}
+	for _, test := range tests {
+		if cost, _ := field.Cost(test.p); cost != test.cost {
+			t.Errorf("Cost(%v) = %v, want %v", test.p, cost, test.cost)
+		}
+	}
+}
+
+// edgeList is a directed graph given by the edges leaving each node.
+type edgeList map[Node][]Edge
+
+func (g edgeList) Neighbors(n Node) []Edge {
+	return g[n]
+}
+
+func TestFlowFieldZeroCostEdges(t *testing.T) {
+	// A free edge joins the goal to a node that is not a goal, so that node costs nothing to leave from.
+	g := edgeList{
+		"goal": {{"free", 0}},
+		"free": {{"goal", 0}, {"far", 1}},
+		"far":  {{"free", 1}},
+	}
+	field := NewFlowField(g, 0, "goal")
+
+	tests := []struct {
+		n    Node
+		next Node
+		ok   bool
+	}{
+		{"goal", nil, false},
+		{"free", "goal", true},
+		{"far", "free", true},
+	}
+	for _, test := range tests {
+		if next, ok := field.Next(test.n); next != test.next || ok != test.ok {
+			t.Errorf("Next(%v) = %v, %v, want %v, %v", test.n, next, ok, test.next, test.ok)
+		}
+	}
+
+	p, ok := field.Path("far")
+	if !ok || p.Cost != 1 {
+		t.Fatalf("Path from far = %v, %v, want a path costing 1", p, ok)
+	}
+	checkPath(t, "zero cost edges", g, p, "far", "goal")
+}
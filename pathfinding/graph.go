@@ -0,0 +1,35 @@
+package pathfinding
+
+// Node is a location in a graph.  Nodes are used as map keys so they must be comparable, such as hex.Hex or Point values.
+type Node interface{}
+
+// Edge is a connection to a neighboring node along with the cost of moving along it.
+type Edge struct {
+	To   Node
+	Cost float64
+}
+
+// Graph represents anything that can be searched for a path.
+type Graph interface {
+	// Neighbors retrieves the edges leaving a node.
+	Neighbors(n Node) []Edge
+}
+
+// HeuristicGraph is a graph able to estimate the cost between two nodes.  Estimates must never be more than the true cost for A* to find the cheapest path.
+type HeuristicGraph interface {
+	Graph
+	// Estimate retrieves the estimated cost of moving between two nodes.
+	Estimate(from, to Node) float64
+}
+
+// LineOfSight is a graph able to tell whether a unit can move straight between two nodes.  It is used to smooth paths.
+type LineOfSight interface {
+	// Visible returns true if the straight line between two nodes is passable.
+	Visible(from, to Node) bool
+}
+
+// Path is a route through a graph from its first node to its last.
+type Path struct {
+	Nodes []Node
+	Cost  float64
+}
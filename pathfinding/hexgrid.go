@@ -0,0 +1,106 @@
+package pathfinding
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+
+	"github.com/Ariemeth/quantum-pulse/hex"
+)
+
+// HexGrid is a graph of hex tiles where each tile has the cost of moving onto it.  Tiles that have not been added or that are blocked cannot be entered.  Nodes are hex.Hex values.
+type HexGrid struct {
+	costs   map[hex.Hex]float64
+	minCost float64
+}
+
+// NewHexGrid creates an empty hex grid.
+func NewHexGrid() *HexGrid {
+	return &HexGrid{
+		costs:   make(map[hex.Hex]float64),
+		minCost: math.Inf(1),
+	}
+}
+
+// NewHexGridRange creates a hex grid of every tile within a radius of a center, each with the same cost.
+func NewHexGridRange(center hex.Hex, radius int, cost float64) *HexGrid {
+	g := NewHexGrid()
+	for _, h := range hex.Range(center, radius) {
+		g.SetCost(h, cost)
+	}
+	return g
+}
+
+// SetCost adds a tile or changes the cost of moving onto it.  A cost that is infinite or not positive blocks the tile.
+func (g *HexGrid) SetCost(h hex.Hex, cost float64) {
+	if cost <= 0 || math.IsInf(cost, 1) || math.IsNaN(cost) {
+		g.SetBlocked(h)
+		return
+	}
+	g.costs[h] = cost
+	if cost < g.minCost {
+		g.minCost = cost
+	}
+}
+
+// SetBlocked removes a tile so it can no longer be entered.
+func (g *HexGrid) SetBlocked(h hex.Hex) {
+	delete(g.costs, h)
+}
+
+// Cost retrieves the cost of moving onto a tile.  The boolean is false if the tile cannot be entered.
+func (g *HexGrid) Cost(h hex.Hex) (float64, bool) {
+	cost, ok := g.costs[h]
+	return cost, ok
+}
+
+// Neighbors retrieves the edges to the passable tiles adjacent to a tile.
+func (g *HexGrid) Neighbors(n Node) []Edge {
+	h, ok := n.(hex.Hex)
+	if !ok {
+		return nil
+	}
+	edges := make([]Edge, 0, 6)
+	for _, neighbor := range h.Neighbors() {
+		if cost, ok := g.costs[neighbor]; ok {
+			edges = append(edges, Edge{To: neighbor, Cost: cost})
+		}
+	}
+	return edges
+}
+
+// Estimate retrieves the number of steps between two tiles multiplied by the cheapest tile cost.
+func (g *HexGrid) Estimate(from, to Node) float64 {
+	a, okA := from.(hex.Hex)
+	b, okB := to.(hex.Hex)
+	if !okA || !okB || math.IsInf(g.minCost, 1) {
+		return 0
+	}
+	return float64(a.Distance(b)) * g.minCost
+}
+
+// Visible returns true if every tile on the straight line between two tiles can be entered.
+func (g *HexGrid) Visible(from, to Node) bool {
+	a, okA := from.(hex.Hex)
+	b, okB := to.(hex.Hex)
+	if !okA || !okB {
+		return false
+	}
+	for _, h := range hex.Line(a, b) {
+		if _, ok := g.costs[h]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// HexWaypoints converts a path of hexes into the world positions of their centers.
+func HexWaypoints(p Path, layout hex.Layout) []mgl32.Vec3 {
+	points := make([]mgl32.Vec3, 0, len(p.Nodes))
+	for _, n := range p.Nodes {
+		if h, ok := n.(hex.Hex); ok {
+			points = append(points, layout.ToWorld(h))
+		}
+	}
+	return points
+}
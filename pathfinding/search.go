@@ -0,0 +1,130 @@
+package pathfinding
+
+import (
+	"container/heap"
+)
+
+// AStar finds the cheapest path between two nodes guided by the graph's estimate.  The boolean is false if the goal cannot be reached.
+func AStar(g HeuristicGraph, start, goal Node) (Path, bool) {
+	return search(g, start, goal, g.Estimate)
+}
+
+// Dijkstra finds the cheapest path between two nodes by searching outwards evenly in every direction.  The boolean is false if the goal cannot be reached.
+func Dijkstra(g Graph, start, goal Node) (Path, bool) {
+	return search(g, start, goal, nil)
+}
+
+// Distances retrieves the cost of the cheapest path from the start to every reachable node.  A maxCost greater than 0 stops the search at that cost, which is useful for finding the tiles a unit can reach in a turn.
+func Distances(g Graph, start Node, maxCost float64) map[Node]float64 {
+	costs := map[Node]float64{start: 0}
+	open := &nodeQueue{}
+	heap.Push(open, &queueItem{node: start})
+
+	for open.Len() > 0 {
+		current := heap.Pop(open).(*queueItem)
+		if current.cost > costs[current.node] {
+			continue
+		}
+		for _, e := range g.Neighbors(current.node) {
+			cost := current.cost + e.Cost
+			if maxCost > 0 && cost > maxCost {
+				continue
+			}
+			if known, ok := costs[e.To]; ok && known <= cost {
+				continue
+			}
+			costs[e.To] = cost
+			heap.Push(open, &queueItem{node: e.To, cost: cost, priority: cost})
+		}
+	}
+	return costs
+}
+
+// search is A* when an estimate is given and Dijkstra otherwise.
+func search(g Graph, start, goal Node, estimate func(from, to Node) float64) (Path, bool) {
+	costs := map[Node]float64{start: 0}
+	from := map[Node]Node{}
+	open := &nodeQueue{}
+	heap.Push(open, &queueItem{node: start})
+
+	for open.Len() > 0 {
+		current := heap.Pop(open).(*queueItem)
+		if current.node == goal {
+			return Path{Nodes: reconstruct(from, start, goal), Cost: current.cost}, true
+		}
+		if current.cost > costs[current.node] {
+			// A cheaper route to this node was found after it was queued.
+			continue
+		}
+
+		for _, e := range g.Neighbors(current.node) {
+			cost := current.cost + e.Cost
+			if known, ok := costs[e.To]; ok && known <= cost {
+				continue
+			}
+			costs[e.To] = cost
+			from[e.To] = current.node
+			priority := cost
+			if estimate != nil {
+				priority += estimate(e.To, goal)
+			}
+			heap.Push(open, &queueItem{node: e.To, cost: cost, priority: priority})
+		}
+	}
+	return Path{}, false
+}
+
+// reconstruct walks back from the goal to the start.
+func reconstruct(from map[Node]Node, start, goal Node) []Node {
+	nodes := []Node{goal}
+	for n := goal; n != start; {
+		n = from[n]
+		nodes = append(nodes, n)
+	}
+	for i, j := 0, len(nodes)-1; i < j; i, j = i+1, j-1 {
+		nodes[i], nodes[j] = nodes[j], nodes[i]
+	}
+	return nodes
+}
+
+type queueItem struct {
+	node     Node
+	cost     float64
+	priority float64
+	order    int
+}
+
+// nodeQueue is a priority queue of nodes.  Ties are broken by insertion order so searches are deterministic.
+type nodeQueue struct {
+	items []*queueItem
+	count int
+}
+
+func (q nodeQueue) Len() int {
+	return len(q.items)
+}
+
+func (q nodeQueue) Less(i, j int) bool {
+	if q.items[i].priority != q.items[j].priority {
+		return q.items[i].priority < q.items[j].priority
+	}
+	return q.items[i].order < q.items[j].order
+}
+
+func (q nodeQueue) Swap(i, j int) {
+	q.items[i], q.items[j] = q.items[j], q.items[i]
+}
+
+func (q *nodeQueue) Push(x interface{}) {
+	item := x.(*queueItem)
+	item.order = q.count
+	q.count++
+	q.items = append(q.items, item)
+}
+
+func (q *nodeQueue) Pop() interface{} {
+	last := len(q.items) - 1
+	item := q.items[last]
+	q.items = q.items[:last]
+	return item
+}
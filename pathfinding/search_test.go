@@ -0,0 +1,237 @@
+package pathfinding
+
+import (
+	"math"
+	"testing"
+
+	"github.com/Ariemeth/quantum-pulse/hex"
+)
+
+const epsilon = 1e-9
+
+// searchGraph is a graph along with every node that can be entered, which is needed to check results against an exhaustive search.
+type searchGraph struct {
+	name  string
+	graph HeuristicGraph
+	nodes []Node
+}
+
+// wallGrid is a 5 by 5 grid with a wall along x 2 leaving a gap at the top, and an expensive tile in the gap's way.
+func wallGrid(diagonal bool) *SquareGrid {
+	g := NewSquareGrid(5, 5, 1, diagonal)
+	for y := 0; y < 4; y++ {
+		g.SetBlocked(Point{2, y})
+	}
+	g.SetCost(Point{3, 4}, 4)
+	return g
+}
+
+// wallHexGrid is a hex grid of radius 3 with a wall across the middle leaving a gap at one end, and swampy tiles beside the gap.
+func wallHexGrid() *HexGrid {
+	g := NewHexGridRange(hex.New(0, 0), 3, 1)
+	for q := -3; q <= 2; q++ {
+		g.SetBlocked(hex.New(q, 0))
+	}
+	g.SetCost(hex.New(2, 1), 3)
+	g.SetCost(hex.New(3, -1), 3)
+	return g
+}
+
+func squareNodes(g *SquareGrid) []Node {
+	var nodes []Node
+	for y := 0; y < g.Height; y++ {
+		for x := 0; x < g.Width; x++ {
+			if _, ok := g.Cost(Point{x, y}); ok {
+				nodes = append(nodes, Point{x, y})
+			}
+		}
+	}
+	return nodes
+}
+
+func hexNodes(g *HexGrid, radius int) []Node {
+	var nodes []Node
+	for _, h := range hex.Range(hex.New(0, 0), radius) {
+		if _, ok := g.Cost(h); ok {
+			nodes = append(nodes, h)
+		}
+	}
+	return nodes
+}
+
+func searchGraphs() []searchGraph {
+	straight, diagonal, hexes := wallGrid(false), wallGrid(true), wallHexGrid()
+	return []searchGraph{
+		{"square grid", straight, squareNodes(straight)},
+		{"square grid with diagonals", diagonal, squareNodes(diagonal)},
+		{"hex grid", hexes, hexNodes(hexes, 3)},
+	}
+}
+
+// bellmanFord retrieves the cheapest cost from a start to every node by relaxing every edge until nothing changes.  It shares no code with the searches it checks.
+func bellmanFord(g Graph, nodes []Node, start Node) map[Node]float64 {
+	costs := map[Node]float64{start: 0}
+	for changed := true; changed; {
+		changed = false
+		for _, n := range nodes {
+			cost, ok := costs[n]
+			if !ok {
+				continue
+			}
+			for _, e := range g.Neighbors(n) {
+				if known, ok := costs[e.To]; !ok || cost+e.Cost < known-epsilon {
+					costs[e.To] = cost + e.Cost
+					changed = true
+				}
+			}
+		}
+	}
+	return costs
+}
+
+// checkPath reports a path that does not run along the graph's edges from the start to the goal at its cost.
+func checkPath(t *testing.T, name string, g Graph, p Path, start, goal Node) {
+	if len(p.Nodes) == 0 || p.Nodes[0] != start || p.Nodes[len(p.Nodes)-1] != goal {
+		t.Errorf("%s: path %v does not run from %v to %v", name, p.Nodes, start, goal)
+		return
+	}
+	total := 0.0
+	for i := 1; i < len(p.Nodes); i++ {
+		cost, ok := 0.0, false
+		for _, e := range g.Neighbors(p.Nodes[i-1]) {
+			if e.To == p.Nodes[i] {
+				cost, ok = e.Cost, true
+			}
+		}
+		if !ok {
+			t.Errorf("%s: path %v steps from %v to %v without an edge", name, p.Nodes, p.Nodes[i-1], p.Nodes[i])
+			return
+		}
+		total += cost
+	}
+	if math.Abs(total-p.Cost) > epsilon {
+		t.Errorf("%s: path %v costs %v but reports %v", name, p.Nodes, total, p.Cost)
+	}
+}
+
+func TestSearchOptimal(t *testing.T) {
+	for _, sg := range searchGraphs() {
+		for _, start := range sg.nodes {
+			best := bellmanFord(sg.graph, sg.nodes, start)
+			for _, goal := range sg.nodes {
+				astar, okA := AStar(sg.graph, start, goal)
+				dijkstra, okD := Dijkstra(sg.graph, start, goal)
+				if !okA || !okD {
+					t.Errorf("%s: no path from %v to %v, A* %v and Dijkstra %v", sg.name, start, goal, okA, okD)
+					continue
+				}
+				if math.Abs(astar.Cost-best[goal]) > epsilon || math.Abs(dijkstra.Cost-best[goal]) > epsilon {
+					t.Errorf("%s: %v to %v costs %v with A* and %v with Dijkstra, want %v", sg.name, start, goal, astar.Cost, dijkstra.Cost, best[goal])
+				}
+				checkPath(t, sg.name+" A*", sg.graph, astar, start, goal)
+				checkPath(t, sg.name+" Dijkstra", sg.graph, dijkstra, start, goal)
+			}
+		}
+	}
+}
+
+func TestSearchAroundWall(t *testing.T) {
+	tests := []struct {
+		name        string
+		graph       HeuristicGraph
+		start, goal Node
+		cost        float64
+	}{
+		// Up to the gap, over the expensive tile and back down.
+		{"square grid", wallGrid(false), Point{0, 0}, Point{4, 0}, 6 + 4 + 5},
+		// Diagonal steps shorten the climb but the corners of the wall cannot be cut so the gap is crossed straight.
+		{"square grid with diagonals", wallGrid(true), Point{0, 0}, Point{4, 0}, 3 + math.Sqrt2 + 1 + 4 + 3 + math.Sqrt2},
+		// The only way through the gap crosses both swampy tiles.
+		{"hex grid", wallHexGrid(), hex.New(-1, -1), hex.New(-1, 1), 1 + 1 + 1 + 3 + 1 + 3 + 1 + 1 + 1},
+	}
+	for _, test := range tests {
+		for name, p := range map[string]Path{
+			"A*":       mustFind(AStar(test.graph, test.start, test.goal)),
+			"Dijkstra": mustFind(Dijkstra(test.graph, test.start, test.goal)),
+		} {
+			if math.Abs(p.Cost-test.cost) > epsilon {
+				t.Errorf("%s %s: cost = %v, want %v along %v", test.name, name, p.Cost, test.cost, p.Nodes)
+			}
+		}
+	}
+}
+
+// mustFind turns a missing path into one with an infinite cost so it fails the cost check.
+func mustFind(p Path, ok bool) Path {
+	if !ok {
+		return Path{Cost: math.Inf(1)}
+	}
+	return p
+}
+
+func TestSearchUnreachable(t *testing.T) {
+	square := NewSquareGrid(5, 5, 1, true)
+	for _, p := range []Point{{3, 3}, {3, 4}, {4, 3}} {
+		square.SetBlocked(p)
+	}
+	hexes := NewHexGridRange(hex.New(0, 0), 3, 1)
+	for _, h := range hex.Ring(hex.New(2, 0), 1) {
+		hexes.SetBlocked(h)
+	}
+	hexes.SetBlocked(hex.New(-3, 0))
+
+	tests := []struct {
+		name        string
+		graph       HeuristicGraph
+		start, goal Node
+	}{
+		{"walled in square corner", square, Point{0, 0}, Point{4, 4}},
+		{"off the square grid", square, Point{0, 0}, Point{5, 0}},
+		{"ringed hex", hexes, hex.New(-2, 0), hex.New(2, 0)},
+		{"blocked hex", hexes, hex.New(0, 0), hex.New(-3, 0)},
+		{"hex off the grid", hexes, hex.New(0, 0), hex.New(4, 0)},
+	}
+	for _, test := range tests {
+		if p, ok := AStar(test.graph, test.start, test.goal); ok {
+			t.Errorf("%s: A* found %v", test.name, p.Nodes)
+		}
+		if p, ok := Dijkstra(test.graph, test.start, test.goal); ok {
+			t.Errorf("%s: Dijkstra found %v", test.name, p.Nodes)
+		}
+		if _, ok := Distances(test.graph, test.start, 0)[test.goal]; ok {
+			t.Errorf("%s: Distances reached the goal", test.name)
+		}
+	}
+}
+
+func TestSearchStartIsGoal(t *testing.T) {
+	g := NewSquareGrid(3, 3, 1, false)
+	p, ok := AStar(g, Point{1, 1}, Point{1, 1})
+	if !ok || len(p.Nodes) != 1 || p.Cost != 0 {
+		t.Errorf("AStar to the start = %v, %v, want only the start at no cost", p, ok)
+	}
+}
+
+func TestDistances(t *testing.T) {
+	for _, sg := range searchGraphs() {
+		start := sg.nodes[0]
+		best := bellmanFord(sg.graph, sg.nodes, start)
+		all := Distances(sg.graph, start, 0)
+		if len(all) != len(best) {
+			t.Errorf("%s: Distances reached %d nodes, want %d", sg.name, len(all), len(best))
+		}
+		for n, cost := range best {
+			if got, ok := all[n]; !ok || math.Abs(got-cost) > epsilon {
+				t.Errorf("%s: Distances to %v = %v, want %v", sg.name, n, got, cost)
+			}
+		}
+
+		limited := Distances(sg.graph, start, 3)
+		for n, cost := range best {
+			_, ok := limited[n]
+			if ok != (cost <= 3) {
+				t.Errorf("%s: %v at cost %v is reachable = %v with a max cost of 3", sg.name, n, cost, ok)
+			}
+		}
+	}
+}
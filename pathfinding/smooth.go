@@ -0,0 +1,19 @@
+package pathfinding
+
+// Smooth removes the nodes of a path that can be skipped by moving in a straight line, leaving the corners a unit has to turn at.  The cost of the path is left unchanged.
+func Smooth(p Path, los LineOfSight) Path {
+	if len(p.Nodes) <= 2 {
+		return p
+	}
+
+	nodes := []Node{p.Nodes[0]}
+	anchor := 0
+	for i := 2; i < len(p.Nodes); i++ {
+		if !los.Visible(p.Nodes[anchor], p.Nodes[i]) {
+			anchor = i - 1
+			nodes = append(nodes, p.Nodes[anchor])
+		}
+	}
+	nodes = append(nodes, p.Nodes[len(p.Nodes)-1])
+	return Path{Nodes: nodes, Cost: p.Cost}
+}
@@ -0,0 +1,84 @@
+package pathfinding
+
+import (
+	"testing"
+)
+
+// losFunc adapts a function to the LineOfSight interface.
+type losFunc func(from, to Node) bool
+
+func (f losFunc) Visible(from, to Node) bool {
+	return f(from, to)
+}
+
+func nodes(values ...int) []Node {
+	n := make([]Node, len(values))
+	for i, v := range values {
+		n[i] = v
+	}
+	return n
+}
+
+func TestSmooth(t *testing.T) {
+	// Nodes are numbers along a route and can see up to reach numbers ahead.
+	within := func(reach int) LineOfSight {
+		return losFunc(func(from, to Node) bool {
+			d := to.(int) - from.(int)
+			return d >= -reach && d <= reach
+		})
+	}
+	everything := losFunc(func(from, to Node) bool { return true })
+	nothing := losFunc(func(from, to Node) bool { return false })
+
+	tests := []struct {
+		name string
+		path []Node
+		los  LineOfSight
+		want []Node
+	}{
+		{"empty", nil, everything, nil},
+		{"single node", nodes(0), nothing, nodes(0)},
+		{"two nodes", nodes(0, 1), nothing, nodes(0, 1)},
+		{"clear line", nodes(0, 1, 2, 3, 4, 5), everything, nodes(0, 5)},
+		{"blocked line", nodes(0, 1, 2, 3), nothing, nodes(0, 1, 2, 3)},
+		{"limited sight", nodes(0, 1, 2, 3, 4, 5), within(2), nodes(0, 2, 4, 5)},
+		{"sight ending at the goal", nodes(0, 1, 2, 3, 4), within(2), nodes(0, 2, 4)},
+	}
+	for _, test := range tests {
+		got := Smooth(Path{Nodes: test.path, Cost: 7}, test.los)
+		if got.Cost != 7 {
+			t.Errorf("%s: cost = %v, want it left at 7", test.name, got.Cost)
+		}
+		if len(got.Nodes) != len(test.want) {
+			t.Errorf("%s: Smooth = %v, want %v", test.name, got.Nodes, test.want)
+			continue
+		}
+		for i := range test.want {
+			if got.Nodes[i] != test.want[i] {
+				t.Errorf("%s: Smooth = %v, want %v", test.name, got.Nodes, test.want)
+				break
+			}
+		}
+	}
+}
+
+func TestSmoothSquareGrid(t *testing.T) {
+	// A path around the end of a wall keeps only the tiles it turns at.
+	g := NewSquareGrid(5, 5, 1, false)
+	for y := 0; y < 4; y++ {
+		g.SetBlocked(Point{2, y})
+	}
+	p, ok := AStar(g, Point{0, 0}, Point{4, 0})
+	if !ok {
+		t.Fatal("no path around the wall")
+	}
+	smooth := Smooth(p, g)
+	for i := 1; i < len(smooth.Nodes); i++ {
+		if !g.Visible(smooth.Nodes[i-1], smooth.Nodes[i]) {
+			t.Errorf("smoothed path %v crosses the wall between %v and %v", smooth.Nodes, smooth.Nodes[i-1], smooth.Nodes[i])
+		}
+	}
+	if len(smooth.Nodes) >= len(p.Nodes) || len(smooth.Nodes) < 3 {
+		t.Errorf("smoothed path %v from %v, want fewer nodes that still turn around the wall", smooth.Nodes, p.Nodes)
+	}
+}
@@ -0,0 +1,205 @@
+package pathfinding
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Point is a tile of a square grid.
+type Point struct {
+	X int
+	Y int
+}
+
+// SquareGrid is a graph of square tiles where each tile has the cost of moving onto it.  Nodes are Point values.  When diagonal movement is allowed it costs the square root of 2 times as much and cannot cut past a blocked corner.
+type SquareGrid struct {
+	Width    int
+	Height   int
+	Diagonal bool
+	costs    []float64
+	min      float64
+}
+
+// NewSquareGrid creates a grid where every tile has the same cost.
+func NewSquareGrid(width, height int, cost float64, diagonal bool) *SquareGrid {
+	g := SquareGrid{
+		Width:    width,
+		Height:   height,
+		Diagonal: diagonal,
+		costs:    make([]float64, width*height),
+		min:      math.Inf(1),
+	}
+	for i := range g.costs {
+		g.costs[i] = cost
+	}
+	if cost > 0 {
+		g.min = cost
+	}
+	return &g
+}
+
+// InBounds returns true if a point is on the grid.
+func (g *SquareGrid) InBounds(p Point) bool {
+	return p.X >= 0 && p.Y >= 0 && p.X < g.Width && p.Y < g.Height
+}
+
+// SetCost changes the cost of moving onto a tile.  A cost that is infinite or not positive blocks the tile.
+func (g *SquareGrid) SetCost(p Point, cost float64) {
+	if !g.InBounds(p) {
+		return
+	}
+	if cost <= 0 || math.IsNaN(cost) {
+		cost = math.Inf(1)
+	}
+	g.costs[p.Y*g.Width+p.X] = cost
+	// The cheapest cost is never raised so estimates stay low enough for A* when tiles become more expensive.
+	if cost < g.min {
+		g.min = cost
+	}
+}
+
+// SetBlocked blocks a tile so it cannot be entered.
+func (g *SquareGrid) SetBlocked(p Point) {
+	g.SetCost(p, math.Inf(1))
+}
+
+// Cost retrieves the cost of moving onto a tile.  The boolean is false if the tile cannot be entered.
+func (g *SquareGrid) Cost(p Point) (float64, bool) {
+	if !g.InBounds(p) {
+		return 0, false
+	}
+	cost := g.costs[p.Y*g.Width+p.X]
+	return cost, !math.IsInf(cost, 1)
+}
+
+var (
+	straightSteps = []Point{{1, 0}, {0, 1}, {-1, 0}, {0, -1}}
+	diagonalSteps = []Point{{1, 1}, {-1, 1}, {-1, -1}, {1, -1}}
+)
+
+// Neighbors retrieves the edges to the passable tiles adjacent to a tile.
+func (g *SquareGrid) Neighbors(n Node) []Edge {
+	p, ok := n.(Point)
+	if !ok {
+		return nil
+	}
+	edges := make([]Edge, 0, 8)
+	for _, s := range straightSteps {
+		to := Point{p.X + s.X, p.Y + s.Y}
+		if cost, ok := g.Cost(to); ok {
+			edges = append(edges, Edge{To: to, Cost: cost})
+		}
+	}
+	if !g.Diagonal {
+		return edges
+	}
+	for _, s := range diagonalSteps {
+		to := Point{p.X + s.X, p.Y + s.Y}
+		cost, ok := g.Cost(to)
+		if !ok {
+			continue
+		}
+		_, okX := g.Cost(Point{p.X + s.X, p.Y})
+		_, okY := g.Cost(Point{p.X, p.Y + s.Y})
+		if okX && okY {
+			edges = append(edges, Edge{To: to, Cost: cost * math.Sqrt2})
+		}
+	}
+	return edges
+}
+
+// Estimate retrieves the Manhattan distance between two tiles, or the octile distance when diagonal movement is allowed, multiplied by the cheapest tile cost.
+func (g *SquareGrid) Estimate(from, to Node) float64 {
+	a, okA := from.(Point)
+	b, okB := to.(Point)
+	if !okA || !okB {
+		return 0
+	}
+	dx := math.Abs(float64(a.X - b.X))
+	dy := math.Abs(float64(a.Y - b.Y))
+	distance := dx + dy
+	if g.Diagonal {
+		distance = math.Max(dx, dy) + (math.Sqrt2-1)*math.Min(dx, dy)
+	}
+	return distance * g.minCost()
+}
+
+// Visible returns true if every tile the straight line between the centers of two tiles passes through can be entered.
+func (g *SquareGrid) Visible(from, to Node) bool {
+	a, okA := from.(Point)
+	b, okB := to.(Point)
+	if !okA || !okB {
+		return false
+	}
+
+	// Walk every tile the line touches, including both tiles at a corner crossing.
+	dx, dy := abs(b.X-a.X), abs(b.Y-a.Y)
+	sx, sy := sign(b.X-a.X), sign(b.Y-a.Y)
+	p := a
+	if _, ok := g.Cost(p); !ok {
+		return false
+	}
+	for ix, iy := 0, 0; ix < dx || iy < dy; {
+		decision := (1+2*ix)*dy - (1+2*iy)*dx
+		switch {
+		case decision == 0:
+			if _, ok := g.Cost(Point{p.X + sx, p.Y}); !ok {
+				return false
+			}
+			if _, ok := g.Cost(Point{p.X, p.Y + sy}); !ok {
+				return false
+			}
+			p.X += sx
+			p.Y += sy
+			ix++
+			iy++
+		case decision < 0:
+			p.X += sx
+			ix++
+		default:
+			p.Y += sy
+			iy++
+		}
+		if _, ok := g.Cost(p); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// SquareWaypoints converts a path of tiles into world positions in the XY plane, where each tile is size units wide and tile (0, 0) is centered on the origin.
+func SquareWaypoints(p Path, size float32, origin mgl32.Vec3) []mgl32.Vec3 {
+	points := make([]mgl32.Vec3, 0, len(p.Nodes))
+	for _, n := range p.Nodes {
+		if t, ok := n.(Point); ok {
+			points = append(points, origin.Add(mgl32.Vec3{float32(t.X) * size, float32(t.Y) * size, 0}))
+		}
+	}
+	return points
+}
+
+// minCost retrieves the cheapest tile cost seen.
+func (g *SquareGrid) minCost() float64 {
+	if math.IsInf(g.min, 1) {
+		return 0
+	}
+	return g.min
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func sign(v int) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
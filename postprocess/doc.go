@@ -0,0 +1,4 @@
+// Package postprocess describes effects applied to a finished frame, such as tone mapping, bloom and anti-aliasing.
+//
+// Effects run in order as a chain of fullscreen passes, each reading what the one before it drew.  Every effect has parameters passed to its shader as uniforms of the same name, so effects can be configured from json and custom effects can be written as a fragment shader following the same conventions as the built in ones.
+package postprocess
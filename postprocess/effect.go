@@ -0,0 +1,200 @@
+package postprocess
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Kind names an effect.
+type Kind string
+
+const (
+	// ToneMap maps bright colors into the range the screen can show using the ACES filmic curve.  Its parameters are exposure and gamma.
+	ToneMap Kind = "tonemap"
+	// Bloom makes bright parts of the frame glow.  Its parameters are threshold, the brightness that starts to glow, intensity and radius, which spreads the glow.
+	Bloom Kind = "bloom"
+	// FXAA smooths jagged edges.  Its parameters are spanMax, reduceMul and reduceMin, which tune how far and how strongly edges are blended.
+	FXAA Kind = "fxaa"
+	// ColorGrading adjusts the colors of the frame.  Its parameters are brightness, contrast, saturation and tint.
+	ColorGrading Kind = "colorGrading"
+	// Vignette darkens the edges of the frame.  Its parameters are radius, where the darkening starts, softness and strength.
+	Vignette Kind = "vignette"
+	// Custom runs a fragment shader loaded from a file.  Its parameters are whatever uniforms the shader declares.
+	Custom Kind = "custom"
+)
+
+// defaults holds the parameters of each built in effect and their default values.
+var defaults = map[Kind]map[string][]float32{
+	ToneMap: {
+		"exposure": {1},
+		"gamma":    {1},
+	},
+	Bloom: {
+		"threshold": {0.8},
+		"intensity": {0.6},
+		"radius":    {1},
+	},
+	FXAA: {
+		"spanMax":   {8},
+		"reduceMul": {1.0 / 8},
+		"reduceMin": {1.0 / 128},
+	},
+	ColorGrading: {
+		"brightness": {0},
+		"contrast":   {1},
+		"saturation": {1},
+		"tint":       {1, 1, 1},
+	},
+	Vignette: {
+		"radius":   {0.75},
+		"softness": {0.45},
+		"strength": {0.8},
+	},
+	Custom: {},
+}
+
+// Effect is one step of a post processing chain.
+type Effect struct {
+	Kind Kind
+	// FragShader is the fragment shader file of a custom effect in the shader directory.
+	FragShader string
+	// Params holds the values of the effect's uniforms by name.  Each parameter is one to four floats.
+	Params map[string][]float32
+}
+
+// NewEffect creates an effect with the default parameters of its kind.
+func NewEffect(kind Kind) Effect {
+	e := Effect{
+		Kind:   kind,
+		Params: make(map[string][]float32),
+	}
+	for name, values := range defaults[kind] {
+		e.Params[name] = append([]float32(nil), values...)
+	}
+	return e
+}
+
+// NewCustomEffect creates an effect running a fragment shader file from the shader directory.
+func NewCustomEffect(fragShader string) Effect {
+	e := NewEffect(Custom)
+	e.FragShader = fragShader
+	return e
+}
+
+// Param retrieves the value of a parameter, or nil if the effect does not have it.
+func (e Effect) Param(name string) []float32 {
+	return e.Params[name]
+}
+
+// SetParam sets the value of a parameter.  Built in effects only accept their own parameters with the same number of values as their defaults.
+func (e *Effect) SetParam(name string, values ...float32) error {
+	if err := e.checkParam(name, values); err != nil {
+		return err
+	}
+	if e.Params == nil {
+		e.Params = make(map[string][]float32)
+	}
+	e.Params[name] = append([]float32(nil), values...)
+	return nil
+}
+
+// ParamNames retrieves the names of the effect's parameters in alphabetical order.
+func (e Effect) ParamNames() []string {
+	names := make([]string, 0, len(e.Params))
+	for name := range e.Params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Validate returns an error if the effect is unknown or a parameter does not belong to it.
+func (e Effect) Validate() error {
+	if _, known := defaults[e.Kind]; !known {
+		return fmt.Errorf("unknown effect %q", e.Kind)
+	}
+	if e.Kind == Custom && e.FragShader == "" {
+		return fmt.Errorf("custom effect is missing its fragShader")
+	}
+	for name, values := range e.Params {
+		if err := e.checkParam(name, values); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e Effect) checkParam(name string, values []float32) error {
+	if len(values) < 1 || len(values) > 4 {
+		return fmt.Errorf("%s %s must have 1 to 4 values", e.Kind, name)
+	}
+	known, isKnown := defaults[e.Kind]
+	if !isKnown || e.Kind == Custom {
+		return nil
+	}
+	def, ok := known[name]
+	if !ok {
+		return fmt.Errorf("%s has no parameter %s", e.Kind, name)
+	}
+	if len(def) != len(values) {
+		return fmt.Errorf("%s %s must have %d values", e.Kind, name, len(def))
+	}
+	return nil
+}
+
+// MarshalJSON encodes the effect as an object holding its kind under "effect" and each parameter as a field.
+func (e Effect) MarshalJSON() ([]byte, error) {
+	fields := make(map[string]interface{}, len(e.Params)+2)
+	for name, values := range e.Params {
+		if len(values) == 1 {
+			fields[name] = values[0]
+		} else {
+			fields[name] = values
+		}
+	}
+	fields["effect"] = e.Kind
+	if e.FragShader != "" {
+		fields["fragShader"] = e.FragShader
+	}
+	return json.Marshal(fields)
+}
+
+// UnmarshalJSON decodes an effect such as {"effect": "bloom", "threshold": 0.9}.  Parameters missing from the json keep their defaults and parameters are single numbers or arrays of numbers.
+func (e *Effect) UnmarshalJSON(data []byte) error {
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	var kind Kind
+	if err := json.Unmarshal(fields["effect"], &kind); err != nil {
+		return fmt.Errorf("effect is missing its kind: %v", err)
+	}
+	decoded := NewEffect(kind)
+	if raw, ok := fields["fragShader"]; ok {
+		if err := json.Unmarshal(raw, &decoded.FragShader); err != nil {
+			return err
+		}
+	}
+	delete(fields, "effect")
+	delete(fields, "fragShader")
+
+	for name, raw := range fields {
+		var values []float32
+		if err := json.Unmarshal(raw, &values); err != nil {
+			var value float32
+			if err := json.Unmarshal(raw, &value); err != nil {
+				return fmt.Errorf("%s %s must be a number or an array of numbers", kind, name)
+			}
+			values = []float32{value}
+		}
+		decoded.Params[name] = values
+	}
+
+	if err := decoded.Validate(); err != nil {
+		return err
+	}
+	*e = decoded
+	return nil
+}
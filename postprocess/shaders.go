@@ -0,0 +1,203 @@
+package postprocess
+
+const (
+	// SourceUniform is the expected name of the sampler holding what the previous effect drew.
+	SourceUniform = "source"
+	// SceneUniform is the expected name of the sampler holding the frame before any effect ran.
+	SceneUniform = "scene"
+	// TexelSizeUniform is the expected name of the vec2 holding the size of one pixel of the source in texture coordinates.
+	TexelSizeUniform = "texelSize"
+	// BloomUniform is the expected name of the sampler holding the blurred bright parts of the frame while bloom combines them.
+	BloomUniform = "bloom"
+	// DirectionUniform is the expected name of the vec2 holding the direction a blur pass spreads along.
+	DirectionUniform = "direction"
+)
+
+// VertexShader draws a triangle covering the screen without any vertex data.  Fragment shaders receive the texture coordinates of the frame as fragTexCoord at location 0.
+const VertexShader = `#version 410
+
+layout(location = 0) out vec2 fragTexCoord;
+
+void main() {
+	vec2 corner = vec2((gl_VertexID << 1) & 2, gl_VertexID & 2);
+	fragTexCoord = corner;
+	gl_Position = vec4(corner * 2.0 - 1.0, 0, 1);
+}
+`
+
+// FragmentShader retrieves the fragment shader of a built in single pass effect.  Bloom and custom effects have none.
+func FragmentShader(kind Kind) (string, bool) {
+	src, ok := fragmentShaders[kind]
+	return src, ok
+}
+
+var fragmentShaders = map[Kind]string{
+	ToneMap:      toneMapShader,
+	FXAA:         fxaaShader,
+	ColorGrading: colorGradingShader,
+	Vignette:     vignetteShader,
+}
+
+const toneMapShader = `#version 410
+
+layout(location = 0) in vec2 fragTexCoord;
+
+uniform sampler2D source;
+uniform float exposure;
+uniform float gamma;
+
+layout(location = 0) out vec4 outputColor;
+
+void main() {
+	vec4 color = texture(source, fragTexCoord);
+	vec3 c = color.rgb * exposure;
+	c = clamp((c * (2.51 * c + 0.03)) / (c * (2.43 * c + 0.59) + 0.14), 0.0, 1.0);
+	outputColor = vec4(pow(c, vec3(1.0 / gamma)), color.a);
+}
+`
+
+const fxaaShader = `#version 410
+
+layout(location = 0) in vec2 fragTexCoord;
+
+uniform sampler2D source;
+uniform vec2 texelSize;
+uniform float spanMax;
+uniform float reduceMul;
+uniform float reduceMin;
+
+layout(location = 0) out vec4 outputColor;
+
+float luma(vec3 c) {
+	return dot(c, vec3(0.299, 0.587, 0.114));
+}
+
+void main() {
+	float nw = luma(texture(source, fragTexCoord + vec2(-1.0, -1.0) * texelSize).rgb);
+	float ne = luma(texture(source, fragTexCoord + vec2(1.0, -1.0) * texelSize).rgb);
+	float sw = luma(texture(source, fragTexCoord + vec2(-1.0, 1.0) * texelSize).rgb);
+	float se = luma(texture(source, fragTexCoord + vec2(1.0, 1.0) * texelSize).rgb);
+	vec4 center = texture(source, fragTexCoord);
+	float m = luma(center.rgb);
+
+	float lumaMin = min(m, min(min(nw, ne), min(sw, se)));
+	float lumaMax = max(m, max(max(nw, ne), max(sw, se)));
+
+	vec2 dir = vec2(-((nw + ne) - (sw + se)), (nw + sw) - (ne + se));
+	float reduce = max((nw + ne + sw + se) * 0.25 * reduceMul, reduceMin);
+	float scale = 1.0 / (min(abs(dir.x), abs(dir.y)) + reduce);
+	dir = clamp(dir * scale, vec2(-spanMax), vec2(spanMax)) * texelSize;
+
+	vec3 a = 0.5 * (texture(source, fragTexCoord + dir * (1.0 / 3.0 - 0.5)).rgb +
+		texture(source, fragTexCoord + dir * (2.0 / 3.0 - 0.5)).rgb);
+	vec3 b = a * 0.5 + 0.25 * (texture(source, fragTexCoord - dir * 0.5).rgb +
+		texture(source, fragTexCoord + dir * 0.5).rgb);
+
+	float lb = luma(b);
+	if(lb < lumaMin || lb > lumaMax)
+		outputColor = vec4(a, center.a);
+	else
+		outputColor = vec4(b, center.a);
+}
+`
+
+const colorGradingShader = `#version 410
+
+layout(location = 0) in vec2 fragTexCoord;
+
+uniform sampler2D source;
+uniform float brightness;
+uniform float contrast;
+uniform float saturation;
+uniform vec3 tint;
+
+layout(location = 0) out vec4 outputColor;
+
+void main() {
+	vec4 color = texture(source, fragTexCoord);
+	vec3 c = color.rgb * tint + brightness;
+	c = (c - 0.5) * contrast + 0.5;
+	float grey = dot(c, vec3(0.299, 0.587, 0.114));
+	c = mix(vec3(grey), c, saturation);
+	outputColor = vec4(max(c, 0.0), color.a);
+}
+`
+
+const vignetteShader = `#version 410
+
+layout(location = 0) in vec2 fragTexCoord;
+
+uniform sampler2D source;
+uniform float radius;
+uniform float softness;
+uniform float strength;
+
+layout(location = 0) out vec4 outputColor;
+
+void main() {
+	vec4 color = texture(source, fragTexCoord);
+	float d = length(fragTexCoord - 0.5) * 1.41421356;
+	float v = smoothstep(radius, radius + softness, d);
+	outputColor = vec4(color.rgb * (1.0 - v * strength), color.a);
+}
+`
+
+// BloomExtractShader keeps the parts of the source brighter than the threshold.
+const BloomExtractShader = `#version 410
+
+layout(location = 0) in vec2 fragTexCoord;
+
+uniform sampler2D source;
+uniform float threshold;
+
+layout(location = 0) out vec4 outputColor;
+
+void main() {
+	vec3 c = texture(source, fragTexCoord).rgb;
+	float brightness = max(c.r, max(c.g, c.b));
+	float contribution = max(brightness - threshold, 0.0) / max(brightness, 0.0001);
+	outputColor = vec4(c * contribution, 1.0);
+}
+`
+
+// BlurShader blurs the source along a direction with a nine tap gaussian.
+const BlurShader = `#version 410
+
+layout(location = 0) in vec2 fragTexCoord;
+
+uniform sampler2D source;
+uniform vec2 texelSize;
+uniform vec2 direction;
+uniform float radius;
+
+layout(location = 0) out vec4 outputColor;
+
+const float weights[5] = float[](0.227027, 0.1945946, 0.1216216, 0.054054, 0.016216);
+
+void main() {
+	vec2 offset = direction * texelSize * radius;
+	vec3 c = texture(source, fragTexCoord).rgb * weights[0];
+	for(int i = 1; i < 5; i++) {
+		c += texture(source, fragTexCoord + offset * float(i)).rgb * weights[i];
+		c += texture(source, fragTexCoord - offset * float(i)).rgb * weights[i];
+	}
+	outputColor = vec4(c, 1.0);
+}
+`
+
+// BloomCombineShader adds the blurred bright parts of the frame to the source.
+const BloomCombineShader = `#version 410
+
+layout(location = 0) in vec2 fragTexCoord;
+
+uniform sampler2D source;
+uniform sampler2D bloom;
+uniform float intensity;
+
+layout(location = 0) out vec4 outputColor;
+
+void main() {
+	vec4 color = texture(source, fragTexCoord);
+	outputColor = vec4(color.rgb + texture(bloom, fragTexCoord).rgb * intensity, color.a);
+}
+`
@@ -0,0 +1,136 @@
+package resources
+
+import "os"
+
+// LitVertexShader is the name of the built in vertex shader lighting meshes with the Blinn-Phong model.  It follows the instancing convention and reads normals at location 10.
+const LitVertexShader = "lit.vert"
+
+// LitFragmentShader is the name of the built in fragment shader lighting meshes with the Blinn-Phong model.  Materials can set its shininess and specularStrength uniforms.
+const LitFragmentShader = "lit.frag"
+
+// builtinShaders holds the sources of shaders that can be loaded by name without a file in the shader directory.  A file of the same name replaces the built in shader.
+var builtinShaders = map[string]string{
+	LitVertexShader: `#version 410
+
+// Input attributes
+layout(location = 0) in vec3 vert;
+layout(location = 1) in vec2 vertTexCoord;
+layout(location = 4) in mat4 instanceModel;
+layout(location = 8) in vec4 instanceColor;
+layout(location = 9) in vec4 instanceUV;
+layout(location = 10) in vec3 vertNormal;
+
+// Uniforms
+uniform mat4 projection;
+uniform mat4 camera;
+uniform mat4 model;
+uniform vec2 uvOffset;
+uniform vec2 uvScale;
+uniform vec4 tint;
+uniform bool instanced;
+
+// Output attributes
+layout(location = 0) out vec2 fragTexCoord;
+layout(location = 1) out vec4 position;
+layout(location = 2) out vec4 fragColor;
+layout(location = 3) out vec3 fragNormal;
+layout(location = 4) out vec3 fragPosition;
+
+void main() {
+	mat4 world = model;
+	if(instanced) {
+		world = instanceModel;
+		fragTexCoord = instanceUV.xy + vertTexCoord * instanceUV.zw;
+		fragColor = instanceColor;
+	} else {
+		fragTexCoord = uvOffset + vertTexCoord * uvScale;
+		fragColor = tint;
+	}
+	vec4 worldPosition = world * vec4(vert, 1);
+	fragPosition = worldPosition.xyz;
+	fragNormal = mat3(transpose(inverse(world))) * vertNormal;
+	gl_Position = projection * camera * worldPosition;
+	position = gl_Position;
+}
+`,
+	LitFragmentShader: `#version 410
+
+const int MAX_LIGHTS = 8;
+const int DIRECTIONAL = 0;
+const int SPOT = 2;
+
+// Input attributes
+layout(location = 0) in vec2 fragTexCoord;
+layout(location = 1) in vec4 position;
+layout(location = 2) in vec4 fragColor;
+layout(location = 3) in vec3 fragNormal;
+layout(location = 4) in vec3 fragPosition;
+
+// Uniforms
+uniform sampler2D tex;
+uniform float shininess = 32.0;
+uniform float specularStrength = 0.25;
+uniform vec3 ambientLight;
+uniform vec3 cameraPosition;
+uniform int lightCount;
+uniform vec4 lightPosition[MAX_LIGHTS];
+uniform vec3 lightColor[MAX_LIGHTS];
+uniform vec3 lightDirection[MAX_LIGHTS];
+uniform vec4 lightAttenuation[MAX_LIGHTS];
+uniform vec2 lightCone[MAX_LIGHTS];
+
+// Output attributes
+layout(location = 0) out vec4 outputColor;
+
+void main() {
+	vec4 base = texture(tex, fragTexCoord) * fragColor;
+	vec3 normal = normalize(fragNormal);
+	if(!gl_FrontFacing) {
+		normal = -normal;
+	}
+	vec3 toCamera = normalize(cameraPosition - fragPosition);
+
+	vec3 diffuse = vec3(0);
+	vec3 specular = vec3(0);
+	for(int i = 0; i < lightCount && i < MAX_LIGHTS; i++) {
+		int kind = int(lightPosition[i].w + 0.5);
+		vec3 toLight = -lightDirection[i];
+		float strength = 1.0;
+		if(kind != DIRECTIONAL) {
+			toLight = lightPosition[i].xyz - fragPosition;
+			float d = length(toLight);
+			toLight /= max(d, 0.0001);
+			vec4 attenuation = lightAttenuation[i];
+			strength = 1.0 / max(attenuation.x + attenuation.y * d + attenuation.z * d * d, 0.0001);
+			// Fade out completely at the light's range.
+			if(attenuation.w > 0.0) {
+				strength *= clamp(1.0 - d / attenuation.w, 0.0, 1.0);
+			}
+			if(kind == SPOT) {
+				strength *= smoothstep(lightCone[i].y, lightCone[i].x, dot(-toLight, lightDirection[i]));
+			}
+		}
+
+		float lambert = max(dot(normal, toLight), 0.0);
+		diffuse += lightColor[i] * lambert * strength;
+		if(lambert > 0.0) {
+			vec3 halfway = normalize(toLight + toCamera);
+			specular += lightColor[i] * pow(max(dot(normal, halfway), 0.0), shininess) * specularStrength * strength;
+		}
+	}
+
+	outputColor = vec4(base.rgb * (ambientLight + diffuse) + specular, base.a);
+}
+`,
+}
+
+// loadShaderSrc loads the source of a shader from the shader directory, falling back to a built in shader of the same name.
+func loadShaderSrc(name string) (string, error) {
+	src, err := loadShaderFile(ShaderSrcDir + name)
+	if err != nil && os.IsNotExist(err) {
+		if builtin, ok := builtinShaders[name]; ok {
+			return builtin + "\x00", nil
+		}
+	}
+	return src, err
+}
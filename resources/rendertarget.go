@@ -0,0 +1,309 @@
+package resources
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// ColorFormat is the format of a color attachment of a render target.
+type ColorFormat string
+
+const (
+	// ColorRGBA8 stores 8 bits per channel.  It is the default color format.
+	ColorRGBA8 ColorFormat = "rgba8"
+	// ColorRGBA16F stores a half float per channel, which holds colors brighter than white for tone mapping and bloom.
+	ColorRGBA16F ColorFormat = "rgba16f"
+	// ColorRGBA32F stores a float per channel.
+	ColorRGBA32F ColorFormat = "rgba32f"
+)
+
+// DepthFormat is the format of the depth attachment of a render target.
+type DepthFormat string
+
+const (
+	// DepthNone leaves a render target without a depth attachment.
+	DepthNone DepthFormat = "none"
+	// Depth24 stores 24 bits of depth.  It is the default depth format.
+	Depth24 DepthFormat = "depth24"
+	// Depth32F stores a float of depth.
+	Depth32F DepthFormat = "depth32f"
+)
+
+// RenderTargetSettings describes the attachments of a render target.  Each color format adds a color attachment in order, and a render target without any has one RGBA8 attachment.  An empty depth format is Depth24.
+type RenderTargetSettings struct {
+	Width  int
+	Height int
+	Color  []ColorFormat
+	Depth  DepthFormat
+	// DepthTexture stores depth in a texture that can be sampled instead of a renderbuffer.
+	DepthTexture bool
+	// Nearest samples the color textures without smoothing, such as for pixel art.
+	Nearest bool
+}
+
+// NewRenderTargetSettings creates settings for a render target with one RGBA8 color attachment and a 24 bit depth attachment.
+func NewRenderTargetSettings(width, height int) RenderTargetSettings {
+	return RenderTargetSettings{
+		Width:  width,
+		Height: height,
+		Color:  []ColorFormat{ColorRGBA8},
+		Depth:  Depth24,
+	}
+}
+
+// RenderTarget is a framebuffer that can be drawn into instead of the window.  Its color attachments are textures, so what is drawn can be shown on meshes or processed further.
+type RenderTarget interface {
+	// Width retrieves the width of the render target in pixels.
+	Width() int
+	// Height retrieves the height of the render target in pixels.
+	Height() int
+	// FramebufferID retrieves the id of the opengl framebuffer.
+	FramebufferID() uint32
+	// ColorTexture retrieves the texture of a color attachment, or 0 if there is no such attachment.
+	ColorTexture(attachment int) uint32
+	// DepthTexture retrieves the depth texture, or 0 if depth is not stored in a texture.
+	DepthTexture() uint32
+	// Bind makes the render target the one drawn into and sets the viewport to cover it.
+	Bind()
+	// Resize changes the size of every attachment.  Texture ids stay the same.
+	Resize(width, height int)
+	// Delete frees the framebuffer and its attachments.
+	Delete()
+}
+
+type renderTarget struct {
+	settings     RenderTargetSettings
+	fbo          uint32
+	colors       []uint32
+	depth        uint32
+	depthTexture uint32
+}
+
+// NewRenderTarget creates a render target.  It must be called on the main thread.
+func NewRenderTarget(settings RenderTargetSettings) (RenderTarget, error) {
+	if settings.Width <= 0 || settings.Height <= 0 {
+		return nil, fmt.Errorf("invalid render target size %dx%d", settings.Width, settings.Height)
+	}
+	if len(settings.Color) == 0 {
+		settings.Color = []ColorFormat{ColorRGBA8}
+	}
+	if settings.Depth == "" {
+		settings.Depth = Depth24
+	}
+	for _, format := range settings.Color {
+		if _, _, ok := format.gl(); !ok {
+			return nil, fmt.Errorf("unknown color format %q", format)
+		}
+	}
+	if _, _, ok := settings.Depth.gl(); !ok && settings.Depth != DepthNone {
+		return nil, fmt.Errorf("unknown depth format %q", settings.Depth)
+	}
+
+	rt := renderTarget{
+		settings: settings,
+		colors:   make([]uint32, len(settings.Color)),
+	}
+	gl.GenFramebuffers(1, &rt.fbo)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, rt.fbo)
+
+	filter := int32(gl.LINEAR)
+	if settings.Nearest {
+		filter = gl.NEAREST
+	}
+	drawBuffers := make([]uint32, len(settings.Color))
+	for i := range settings.Color {
+		gl.GenTextures(1, &rt.colors[i])
+		gl.BindTexture(gl.TEXTURE_2D, rt.colors[i])
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, filter)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, filter)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+		drawBuffers[i] = gl.COLOR_ATTACHMENT0 + uint32(i)
+	}
+	gl.DrawBuffers(int32(len(drawBuffers)), &drawBuffers[0])
+
+	if settings.Depth != DepthNone {
+		if settings.DepthTexture {
+			gl.GenTextures(1, &rt.depthTexture)
+			gl.BindTexture(gl.TEXTURE_2D, rt.depthTexture)
+			gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
+			gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
+			gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+			gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+		} else {
+			gl.GenRenderbuffers(1, &rt.depth)
+		}
+	}
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+
+	rt.allocate()
+	status := gl.CheckFramebufferStatus(gl.FRAMEBUFFER)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	if status != gl.FRAMEBUFFER_COMPLETE {
+		rt.Delete()
+		return nil, fmt.Errorf("incomplete render target framebuffer, status 0x%x", status)
+	}
+	return &rt, nil
+}
+
+// allocate sizes the attachments and attaches them to the framebuffer, which is expected to be bound.
+func (rt *renderTarget) allocate() {
+	width, height := int32(rt.settings.Width), int32(rt.settings.Height)
+	for i, format := range rt.settings.Color {
+		internal, dataType, _ := format.gl()
+		gl.BindTexture(gl.TEXTURE_2D, rt.colors[i])
+		gl.TexImage2D(gl.TEXTURE_2D, 0, internal, width, height, 0, gl.RGBA, dataType, nil)
+		gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0+uint32(i), gl.TEXTURE_2D, rt.colors[i], 0)
+	}
+
+	internal, dataType, _ := rt.settings.Depth.gl()
+	switch {
+	case rt.depthTexture != 0:
+		gl.BindTexture(gl.TEXTURE_2D, rt.depthTexture)
+		gl.TexImage2D(gl.TEXTURE_2D, 0, internal, width, height, 0, gl.DEPTH_COMPONENT, dataType, nil)
+		gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.DEPTH_ATTACHMENT, gl.TEXTURE_2D, rt.depthTexture, 0)
+	case rt.depth != 0:
+		gl.BindRenderbuffer(gl.RENDERBUFFER, rt.depth)
+		gl.RenderbufferStorage(gl.RENDERBUFFER, uint32(internal), width, height)
+		gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.DEPTH_ATTACHMENT, gl.RENDERBUFFER, rt.depth)
+		gl.BindRenderbuffer(gl.RENDERBUFFER, 0)
+	}
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+}
+
+// Width retrieves the width of the render target in pixels.
+func (rt *renderTarget) Width() int {
+	return rt.settings.Width
+}
+
+// Height retrieves the height of the render target in pixels.
+func (rt *renderTarget) Height() int {
+	return rt.settings.Height
+}
+
+// FramebufferID retrieves the id of the opengl framebuffer.
+func (rt *renderTarget) FramebufferID() uint32 {
+	return rt.fbo
+}
+
+// ColorTexture retrieves the texture of a color attachment, or 0 if there is no such attachment.
+func (rt *renderTarget) ColorTexture(attachment int) uint32 {
+	if attachment < 0 || attachment >= len(rt.colors) {
+		return 0
+	}
+	return rt.colors[attachment]
+}
+
+// DepthTexture retrieves the depth texture, or 0 if depth is not stored in a texture.
+func (rt *renderTarget) DepthTexture() uint32 {
+	return rt.depthTexture
+}
+
+// Bind makes the render target the one drawn into and sets the viewport to cover it.  It must be called on the main thread.
+func (rt *renderTarget) Bind() {
+	gl.BindFramebuffer(gl.FRAMEBUFFER, rt.fbo)
+	gl.Viewport(0, 0, int32(rt.settings.Width), int32(rt.settings.Height))
+}
+
+// Resize changes the size of every attachment.  Texture ids stay the same so meshes showing the render target keep working.  It must be called on the main thread.
+func (rt *renderTarget) Resize(width, height int) {
+	if width <= 0 || height <= 0 || (width == rt.settings.Width && height == rt.settings.Height) {
+		return
+	}
+	rt.settings.Width, rt.settings.Height = width, height
+	gl.BindFramebuffer(gl.FRAMEBUFFER, rt.fbo)
+	rt.allocate()
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+}
+
+// Delete frees the framebuffer and its attachments.  It must be called on the main thread.
+func (rt *renderTarget) Delete() {
+	if len(rt.colors) > 0 {
+		gl.DeleteTextures(int32(len(rt.colors)), &rt.colors[0])
+	}
+	if rt.depthTexture != 0 {
+		gl.DeleteTextures(1, &rt.depthTexture)
+	}
+	if rt.depth != 0 {
+		gl.DeleteRenderbuffers(1, &rt.depth)
+	}
+	gl.DeleteFramebuffers(1, &rt.fbo)
+	rt.colors, rt.depth, rt.depthTexture, rt.fbo = nil, 0, 0, 0
+}
+
+// gl retrieves the internal format and data type of a color format.
+func (f ColorFormat) gl() (internal int32, dataType uint32, ok bool) {
+	switch f {
+	case ColorRGBA8:
+		return gl.RGBA8, gl.UNSIGNED_BYTE, true
+	case ColorRGBA16F:
+		return gl.RGBA16F, gl.HALF_FLOAT, true
+	case ColorRGBA32F:
+		return gl.RGBA32F, gl.FLOAT, true
+	}
+	return 0, 0, false
+}
+
+// gl retrieves the internal format and data type of a depth format.
+func (f DepthFormat) gl() (internal int32, dataType uint32, ok bool) {
+	switch f {
+	case Depth24:
+		return gl.DEPTH_COMPONENT24, gl.UNSIGNED_INT, true
+	case Depth32F:
+		return gl.DEPTH_COMPONENT32F, gl.FLOAT, true
+	}
+	return 0, 0, false
+}
+
+// renderTargetManager stores named render targets.
+type renderTargetManager struct {
+	targets    map[string]RenderTarget
+	textures   TextureManager
+	targetLock sync.RWMutex
+}
+
+// RenderTargetManager interface is used to interact with named render targets.
+type RenderTargetManager interface {
+	// CreateRenderTarget creates a render target and registers its first color attachment as a texture of the same name.
+	CreateRenderTarget(name string, settings RenderTargetSettings) (RenderTarget, error)
+	// GetRenderTarget returns a render target if one was created with the name.
+	GetRenderTarget(name string) (RenderTarget, bool)
+}
+
+// newRenderTargetManager creates a new RenderTargetManager whose targets are registered with the texture manager.
+func newRenderTargetManager(textures TextureManager) RenderTargetManager {
+	rm := renderTargetManager{
+		targets:  make(map[string]RenderTarget),
+		textures: textures,
+	}
+	return &rm
+}
+
+// CreateRenderTarget creates a render target and registers its first color attachment as a texture of the same name, so a mesh whose texture file is the name shows what is drawn into it.  Creating a render target that already exists resizes it instead.  It must be called on the main thread.
+func (rm *renderTargetManager) CreateRenderTarget(name string, settings RenderTargetSettings) (RenderTarget, error) {
+	if rt, exists := rm.GetRenderTarget(name); exists {
+		rt.Resize(settings.Width, settings.Height)
+		return rt, nil
+	}
+
+	rt, err := NewRenderTarget(settings)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create render target %s: %v", name, err)
+	}
+
+	rm.targetLock.Lock()
+	defer rm.targetLock.Unlock()
+	rm.targets[name] = rt
+	rm.textures.AddTexture(name, rt.ColorTexture(0))
+	return rt, nil
+}
+
+// GetRenderTarget returns a render target if one was created with the name.
+func (rm *renderTargetManager) GetRenderTarget(name string) (RenderTarget, bool) {
+	rm.targetLock.RLock()
+	defer rm.targetLock.RUnlock()
+	rt, ok := rm.targets[name]
+	return rt, ok
+}
@@ -4,13 +4,16 @@ package resources
 type Manager struct {
 	sm ShaderManager
 	tm TextureManager
+	rm RenderTargetManager
 }
 
-// NewManager creates a new Manager to handle shader and texture assets.
+// NewManager creates a new Manager to handle shader, texture and render target assets.
 func NewManager() *Manager {
+	tm := newTextureManager()
 	am := Manager{
 		sm: newShaderManager(),
-		tm: newTextureManager(),
+		tm: tm,
+		rm: newRenderTargetManager(tm),
 	}
 	return &am
 }
@@ -24,3 +27,8 @@ func (am *Manager) Shaders() ShaderManager {
 func (am *Manager) Textures() TextureManager {
 	return am.tm
 }
+
+// RenderTargets retrieves the RenderTargetManager.
+func (am *Manager) RenderTargets() RenderTargetManager {
+	return am.rm
+}
@@ -1,9 +1,10 @@
 package resources
 
 import (
-	"fmt"
+	"strings"
 
 	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
 
 	"github.com/Ariemeth/quantum-pulse/components"
 )
@@ -19,46 +20,157 @@ const (
 	ModelUniform = "model"
 	// TextureUniform is the expected name of the texture uniform in the shader.
 	TextureUniform = "tex"
+	// UVOffsetUniform is the expected name of the texture coordinate offset uniform used for sprite animation.
+	UVOffsetUniform = "uvOffset"
+	// UVScaleUniform is the expected name of the texture coordinate scale uniform used for sprite animation.
+	UVScaleUniform = "uvScale"
+	// JointsUniform is the expected name of the joint matrix array uniform in a skinning shader.
+	JointsUniform = "joints"
+	// MaxJoints is the maximum number of joint matrices that can be uploaded to a skinning shader.
+	MaxJoints = 64
+	// TintUniform is the expected name of the color uniform multiplied with the texture color.
+	TintUniform = "tint"
+	// AmbientLightUniform is the expected name of the vec3 color of the light reaching every surface in a lit shader.
+	AmbientLightUniform = "ambientLight"
+	// CameraPositionUniform is the expected name of the vec3 world position of the camera in a lit shader.
+	CameraPositionUniform = "cameraPosition"
+	// LightCountUniform is the expected name of the int holding how many lights are set in a lit shader.  Shaders declaring it are lit.
+	LightCountUniform = "lightCount"
+	// LightPositionUniform is the expected name of the vec4 array holding the world position of each light in xyz and its type in w.
+	LightPositionUniform = "lightPosition"
+	// LightColorUniform is the expected name of the vec3 array holding the color of each light multiplied by its intensity.
+	LightColorUniform = "lightColor"
+	// LightDirectionUniform is the expected name of the vec3 array holding the world direction each directional and spot light shines in.
+	LightDirectionUniform = "lightDirection"
+	// LightAttenuationUniform is the expected name of the vec4 array holding the constant, linear and quadratic attenuation of each light in xyz and its range in w.
+	LightAttenuationUniform = "lightAttenuation"
+	// LightConeUniform is the expected name of the vec2 array holding the cosines of the inner and outer half angles of each spot light's cone.
+	LightConeUniform = "lightCone"
+	// MaxLights is the maximum number of lights that can be uploaded to a lit shader for each draw.
+	MaxLights = 8
+	// LightTypeDirectional is the type of directional lights in the w of LightPositionUniform.
+	LightTypeDirectional = 0
+	// LightTypePoint is the type of point lights in the w of LightPositionUniform.
+	LightTypePoint = 1
+	// LightTypeSpot is the type of spot lights in the w of LightPositionUniform.
+	LightTypeSpot = 2
+	// InstancedUniform is the expected name of the bool uniform that is true while a shader draws instances.  Shaders declaring it read the model matrix, tint and texture coordinates from the instance attributes instead of the uniforms.
+	InstancedUniform = "instanced"
 	// VertexAttribute is the expected name of the vertex data attribute in the shader.
 	VertexAttribute = "vert"
 	// VertexTexCordAttribute is the expected name of the vertex texture coordinates attribute in the shader.
 	VertexTexCordAttribute = "vertTexCoord"
+	// VertexJointsAttribute is the expected name of the vertex joint indices attribute in a skinning shader.
+	VertexJointsAttribute = "vertJoints"
+	// VertexWeightsAttribute is the expected name of the vertex joint weights attribute in a skinning shader.
+	VertexWeightsAttribute = "vertWeights"
+	// VertexNormalAttribute is the expected name of the vertex normal attribute in a lit shader.
+	VertexNormalAttribute = "vertNormal"
+	// InstanceModelAttribute is the expected name of the mat4 model matrix attribute of each instance.  A mat4 uses four locations, 4 through 7.
+	InstanceModelAttribute = "instanceModel"
+	// InstanceColorAttribute is the expected name of the vec4 tint attribute of each instance.
+	InstanceColorAttribute = "instanceColor"
+	// InstanceUVAttribute is the expected name of the vec4 texture coordinate attribute of each instance, holding the offset in xy and the scale in zw.
+	InstanceUVAttribute = "instanceUV"
+	// InstanceSize is the number of floats describing one instance: a column major model matrix followed by the tint and the texture coordinate offset and scale.
+	InstanceSize = 24
 	// ShaderOutputColor is the expected name of the output color variable leaving the fragment shader.
 	ShaderOutputColor = "outputColor"
 )
 
 // shader holds information about a shader program
 type shader struct {
-	uniforms   map[string]int32
-	attributes map[string]uint32
-	name       string //shader name
-	program    uint32
+	uniforms    map[string]int32
+	uniformInfo map[string]UniformInfo
+	attributes  map[string]AttributeInfo
+	blocks      []UniformBlockInfo
+	vbos        map[uint32]uint32
+	instances   map[uint32]uint32
+	name        string //shader name
+	program     uint32
 }
 
 // Shader represents the behaviors needed to access a shader and its variables.
 type Shader interface {
-	// GetUniformLoc retrieves the shader location of the specified uniform.
+	// GetUniformLoc retrieves the shader location of the specified uniform, or -1 if the shader has no such uniform.
 	GetUniformLoc(name string) int32
-	// GetAttribLoc retrieves the shader location of a the specified attribute.
-	GetAttribLoc(name string) uint32
+	// GetAttribLoc retrieves the shader location of a the specified attribute, or -1 if the shader has no such attribute.
+	GetAttribLoc(name string) int32
+	// Uniforms retrieves the active uniforms of the shader sorted by name.
+	Uniforms() []UniformInfo
+	// Uniform retrieves an active uniform of the shader.
+	Uniform(name string) (UniformInfo, bool)
+	// UniformBlocks retrieves the active uniform blocks of the shader.
+	UniformBlocks() []UniformBlockInfo
+	// BindUniformBlock sets the uniform buffer binding point a uniform block reads from.
+	BindUniformBlock(name string, binding uint32) error
+	// Attributes retrieves the active vertex attributes of the shader sorted by location.
+	Attributes() []AttributeInfo
+	// SetBool sets a bool uniform.
+	SetBool(name string, v bool) error
+	// SetInt sets an int uniform.
+	SetInt(name string, v int32) error
+	// SetFloat sets a float uniform.
+	SetFloat(name string, v float32) error
+	// SetVec2 sets a vec2 uniform.
+	SetVec2(name string, v mgl32.Vec2) error
+	// SetVec3 sets a vec3 uniform.
+	SetVec3(name string, v mgl32.Vec3) error
+	// SetVec4 sets a vec4 uniform.
+	SetVec4(name string, v mgl32.Vec4) error
+	// SetMat3 sets a mat3 uniform.
+	SetMat3(name string, v mgl32.Mat3) error
+	// SetMat4 sets a mat4 uniform.
+	SetMat4(name string, v mgl32.Mat4) error
+	// SetSampler sets the texture unit a sampler uniform reads from.
+	SetSampler(name string, unit int32) error
+	// SetInts sets the first elements of an int array uniform.
+	SetInts(name string, v []int32) error
+	// SetFloats sets the first elements of a float array uniform.
+	SetFloats(name string, v []float32) error
+	// SetVec2s sets the first elements of a vec2 array uniform.
+	SetVec2s(name string, v []mgl32.Vec2) error
+	// SetVec3s sets the first elements of a vec3 array uniform.
+	SetVec3s(name string, v []mgl32.Vec3) error
+	// SetVec4s sets the first elements of a vec4 array uniform.
+	SetVec4s(name string, v []mgl32.Vec4) error
+	// SetMat4s sets the first elements of a mat4 array uniform.
+	SetMat4s(name string, v []mgl32.Mat4) error
+	// SetValues sets a uniform of any type from its values, such as 3 values for a vec3 or 8 for a vec4[2].
+	SetValues(name string, values []float32) error
+	// UniformValues retrieves every value of a uniform as the shader holds them.
+	UniformValues(name string) ([]float32, error)
 	// GetName retrieves the name of the shader program.
 	GetName() string
 	// ProgramID retrieves the program id of the shader program.
 	ProgramID() uint32
 	// CreateVAO loads the mesh data onto the gpu.
 	CreateVAO(components.Mesh) uint32
+	// UpdateVertices replaces the vertex data of a VAO previously created by CreateVAO.
+	UpdateVertices(vao uint32, verts []float32)
+	// SupportsSkinning returns true if the shader can skin meshes using joint matrices.
+	SupportsSkinning() bool
+	// SupportsInstancing returns true if the shader can draw many instances of a mesh in one draw call.
+	SupportsInstancing() bool
+	// SupportsLighting returns true if the shader lights meshes using the light uniforms.
+	SupportsLighting() bool
+	// UpdateInstances replaces the instance data of a VAO previously created by CreateVAO.
+	UpdateInstances(vao uint32, instances []float32)
 }
 
 // newShader creates a new shader program and populates the uniform and attribute layouts.
 func newShader(name string, shaderProgram uint32) Shader {
 	s := shader{
-		uniforms:   make(map[string]int32),
-		attributes: make(map[string]uint32),
-		name:       name,
-		program:    shaderProgram,
+		uniforms:    make(map[string]int32),
+		uniformInfo: make(map[string]UniformInfo),
+		attributes:  make(map[string]AttributeInfo),
+		vbos:        make(map[uint32]uint32),
+		instances:   make(map[uint32]uint32),
+		name:        name,
+		program:     shaderProgram,
 	}
 
-	s.storeLocations()
+	s.reflect()
 
 	return &s
 }
@@ -68,22 +180,29 @@ func (s *shader) ComponentType() string {
 	return ComponentTypeShader
 }
 
-// GetUniformLoc retrieves the shader location of the specified uniform.
+// GetUniformLoc retrieves the shader location of the specified uniform, or -1 if the shader has no such uniform.  Elements and members of arrays, such as lights[2].color, are looked up the first time they are used.
 func (s *shader) GetUniformLoc(name string) int32 {
 	u, ok := s.uniforms[name]
-	if !ok {
+	if ok {
+		return u
+	}
+	if !strings.Contains(name, "[") {
 		return -1
 	}
+	u = gl.GetUniformLocation(s.program, gl.Str(name+"\x00"))
+	s.uniforms[name] = u
 	return u
 }
 
-// GetAttribLoc retrieves the shader location of a the specified attribute.
-func (s *shader) GetAttribLoc(name string) uint32 {
-	a, ok := s.attributes[name]
-	if !ok {
-		return 0
+// GetAttribLoc retrieves the shader location of a the specified attribute, or -1 if the shader has no such attribute.  The attributes meshes are uploaded to keep their conventional locations when the shader does not use them.
+func (s *shader) GetAttribLoc(name string) int32 {
+	if a, ok := s.attributes[name]; ok {
+		return a.Location
+	}
+	if loc, ok := attributeLocations[name]; ok {
+		return loc
 	}
-	return a
+	return -1
 }
 
 // GetName retrieves the name of the shader program.
@@ -113,14 +232,39 @@ func (s *shader) CreateVAO(m components.Mesh) uint32 {
 	gl.BindBuffer(gl.ARRAY_BUFFER, vbo)
 	gl.BufferData(gl.ARRAY_BUFFER, len(md.Verts)*4, gl.Ptr(md.Verts), gl.STATIC_DRAW)
 
-	vertAttrib := s.GetAttribLoc(VertexAttribute)
-	gl.EnableVertexAttribArray(vertAttrib)
+	vertAttrib := s.enableAttrib(VertexAttribute)
 	gl.VertexAttribPointer(vertAttrib, 3, gl.FLOAT, false, md.VertSize*4, gl.PtrOffset(0)) // 4:number of bytes in a float32
 
-	texCoordAttrib := s.GetAttribLoc(VertexTexCordAttribute)
-	gl.EnableVertexAttribArray(texCoordAttrib)
+	texCoordAttrib := s.enableAttrib(VertexTexCordAttribute)
 	gl.VertexAttribPointer(texCoordAttrib, 2, gl.FLOAT, true, md.VertSize*4, gl.PtrOffset(3*4)) // 4:number of bytes in a float32
 
+	// Lit shaders need normals, which are generated for meshes that have none.
+	if len(md.Normals) > 0 || s.SupportsLighting() {
+		normals := md.VertexNormals()
+		var normalBuffer uint32
+		gl.GenBuffers(1, &normalBuffer)
+		gl.BindBuffer(gl.ARRAY_BUFFER, normalBuffer)
+		gl.BufferData(gl.ARRAY_BUFFER, len(normals)*3*4, gl.Ptr(normals), gl.STATIC_DRAW)
+		normalAttrib := s.enableAttrib(VertexNormalAttribute)
+		gl.VertexAttribPointer(normalAttrib, 3, gl.FLOAT, false, 0, gl.PtrOffset(0))
+	}
+
+	if md.IsSkinned() {
+		var joints uint32
+		gl.GenBuffers(1, &joints)
+		gl.BindBuffer(gl.ARRAY_BUFFER, joints)
+		gl.BufferData(gl.ARRAY_BUFFER, len(md.Joints)*4, gl.Ptr(md.Joints), gl.STATIC_DRAW)
+		jointsAttrib := s.enableAttrib(VertexJointsAttribute)
+		gl.VertexAttribIPointer(jointsAttrib, components.JointsPerVertex, gl.UNSIGNED_INT, 0, gl.PtrOffset(0))
+
+		var weights uint32
+		gl.GenBuffers(1, &weights)
+		gl.BindBuffer(gl.ARRAY_BUFFER, weights)
+		gl.BufferData(gl.ARRAY_BUFFER, len(md.Weights)*4, gl.Ptr(md.Weights), gl.STATIC_DRAW)
+		weightsAttrib := s.enableAttrib(VertexWeightsAttribute)
+		gl.VertexAttribPointer(weightsAttrib, components.JointsPerVertex, gl.FLOAT, false, 0, gl.PtrOffset(0))
+	}
+
 	if md.Indexed {
 		var indices uint32
 		gl.GenBuffers(1, &indices)
@@ -129,18 +273,74 @@ func (s *shader) CreateVAO(m components.Mesh) uint32 {
 	}
 
 	gl.BindVertexArray(0)
+	s.vbos[vao] = vbo
 	return vao
 }
 
-func (s *shader) storeLocations() {
-	program := s.ProgramID()
-	gl.UseProgram(program)
+// UpdateVertices replaces the vertex data of a VAO previously created by CreateVAO.  The new data must have the same layout as the original mesh data.
+func (s *shader) UpdateVertices(vao uint32, verts []float32) {
+	vbo, ok := s.vbos[vao]
+	if !ok || len(verts) == 0 {
+		return
+	}
+	gl.BindBuffer(gl.ARRAY_BUFFER, vbo)
+	gl.BufferData(gl.ARRAY_BUFFER, len(verts)*4, gl.Ptr(verts), gl.DYNAMIC_DRAW)
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+}
+
+// SupportsSkinning returns true if the shader can skin meshes using joint matrices.
+func (s *shader) SupportsSkinning() bool {
+	return s.GetUniformLoc(JointsUniform) >= 0
+}
 
-	s.uniforms[ProjectionUniform] = gl.GetUniformLocation(program, gl.Str(fmt.Sprintf("%s\x00", ProjectionUniform)))
-	s.uniforms[CameraUniform] = gl.GetUniformLocation(program, gl.Str(fmt.Sprintf("%s\x00", CameraUniform)))
-	s.uniforms[ModelUniform] = gl.GetUniformLocation(program, gl.Str(fmt.Sprintf("%s\x00", ModelUniform)))
-	s.uniforms[TextureUniform] = gl.GetUniformLocation(program, gl.Str(fmt.Sprintf("%s\x00", TextureUniform)))
+// SupportsInstancing returns true if the shader can draw many instances of a mesh in one draw call.
+func (s *shader) SupportsInstancing() bool {
+	return s.GetUniformLoc(InstancedUniform) >= 0
+}
+
+// SupportsLighting returns true if the shader lights meshes using the light uniforms.
+func (s *shader) SupportsLighting() bool {
+	return s.GetUniformLoc(LightCountUniform) >= 0
+}
+
+// UpdateInstances replaces the instance data of a VAO previously created by CreateVAO.  Each instance is InstanceSize floats.  The instance buffer is created the first time the VAO is used for instancing, and the VAO is left bound.
+func (s *shader) UpdateInstances(vao uint32, instances []float32) {
+	if len(instances) == 0 {
+		return
+	}
+	gl.BindVertexArray(vao)
+
+	buffer, ok := s.instances[vao]
+	if !ok {
+		gl.GenBuffers(1, &buffer)
+		gl.BindBuffer(gl.ARRAY_BUFFER, buffer)
+
+		stride := int32(InstanceSize * 4) // 4:number of bytes in a float32
+		modelAttrib := s.enableAttrib(InstanceModelAttribute)
+		for column := uint32(0); column < 4; column++ {
+			gl.EnableVertexAttribArray(modelAttrib + column)
+			gl.VertexAttribPointer(modelAttrib+column, 4, gl.FLOAT, false, stride, gl.PtrOffset(int(column)*4*4))
+			gl.VertexAttribDivisor(modelAttrib+column, 1)
+		}
+		colorAttrib := s.enableAttrib(InstanceColorAttribute)
+		gl.VertexAttribPointer(colorAttrib, 4, gl.FLOAT, false, stride, gl.PtrOffset(16*4))
+		gl.VertexAttribDivisor(colorAttrib, 1)
+		uvAttrib := s.enableAttrib(InstanceUVAttribute)
+		gl.VertexAttribPointer(uvAttrib, 4, gl.FLOAT, false, stride, gl.PtrOffset(20*4))
+		gl.VertexAttribDivisor(uvAttrib, 1)
+
+		s.instances[vao] = buffer
+	} else {
+		gl.BindBuffer(gl.ARRAY_BUFFER, buffer)
+	}
+
+	gl.BufferData(gl.ARRAY_BUFFER, len(instances)*4, gl.Ptr(instances), gl.STREAM_DRAW)
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+}
 
-	s.attributes[VertexAttribute] = 0
-	s.attributes[VertexTexCordAttribute] = 1
+// enableAttrib enables an attribute of the bound VAO and retrieves its location.
+func (s *shader) enableAttrib(name string) uint32 {
+	loc := uint32(s.GetAttribLoc(name))
+	gl.EnableVertexAttribArray(loc)
+	return loc
 }
@@ -45,16 +45,16 @@ func newShaderManager() ShaderManager {
 	return &sm
 }
 
-// LoadProgramFromFile creates a shader program from a vertex and fragment shader source files.
+// LoadProgramFromFile creates a shader program from a vertex and fragment shader source files.  Built in shaders such as lit.vert and lit.frag are used when the shader directory has no file of the same name.
 func (sm *shaderManager) LoadProgramFromFile(vertSrcFile string, fragSrcFile string, shouldBeDefault bool) (Shader, error) {
 	name := fmt.Sprintf("%s:%s", vertSrcFile, fragSrcFile)
-	simpleVert, err := loadShaderFile(fmt.Sprintf("%s%s", ShaderSrcDir, vertSrcFile))
+	simpleVert, err := loadShaderSrc(vertSrcFile)
 	if err != nil {
 		fmt.Println(err)
 		return nil, err
 	}
 
-	simpleFrag, err := loadShaderFile(fmt.Sprintf("%s%s", ShaderSrcDir, fragSrcFile))
+	simpleFrag, err := loadShaderSrc(fragSrcFile)
 	if err != nil {
 		fmt.Println(err)
 		return nil, err
@@ -0,0 +1,271 @@
+package resources
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// UniformInfo describes an active uniform of a shader program, found when the program was linked.
+type UniformInfo struct {
+	// Name is the name of the uniform.  Arrays are named without an index.
+	Name string
+	// Type is the OpenGL type of the uniform, such as gl.FLOAT_VEC3.
+	Type uint32
+	// Size is the number of elements of an array, or 1 for a uniform that is not an array.
+	Size int32
+	// Location is the location of the uniform, or -1 for a uniform in a uniform block.
+	Location int32
+	// Block is the index of the uniform block holding the uniform, or -1 for a uniform in no block.
+	Block int32
+}
+
+// TypeName retrieves the GLSL name of the uniform's type, such as vec3.
+func (u UniformInfo) TypeName() string {
+	return TypeName(u.Type)
+}
+
+// UniformBlockInfo describes an active uniform block of a shader program.
+type UniformBlockInfo struct {
+	// Name is the name of the block.
+	Name string
+	// Index is the index of the block in the program.
+	Index uint32
+	// DataSize is the number of bytes the block needs in its uniform buffer.
+	DataSize int32
+	// Binding is the uniform buffer binding point the block reads from.
+	Binding uint32
+	// Uniforms are the names of the uniforms in the block.
+	Uniforms []string
+}
+
+// AttributeInfo describes an active vertex attribute of a shader program.
+type AttributeInfo struct {
+	// Name is the name of the attribute.
+	Name string
+	// Type is the OpenGL type of the attribute, such as gl.FLOAT_VEC3.
+	Type uint32
+	// Size is the number of elements of an array, or 1 for an attribute that is not an array.
+	Size int32
+	// Location is the location of the attribute.  Matrices use one location for each column starting at it.
+	Location int32
+}
+
+// TypeName retrieves the GLSL name of the attribute's type, such as vec3.
+func (a AttributeInfo) TypeName() string {
+	return TypeName(a.Type)
+}
+
+// uniformKind is the kind of values a uniform holds, which decides how it is set.
+type uniformKind int
+
+const (
+	kindFloat uniformKind = iota
+	kindInt
+	kindUint
+	kindBool
+	kindSampler
+)
+
+// uniformType describes a GLSL type by its name, the number of values in one element and the kind of those values.
+type uniformType struct {
+	name       string
+	components int
+	kind       uniformKind
+}
+
+// uniformTypes holds the uniform types values can be set on.  Double precision uniforms are left out since there is no way to set them.
+var uniformTypes = map[uint32]uniformType{
+	gl.FLOAT:                         {"float", 1, kindFloat},
+	gl.FLOAT_VEC2:                    {"vec2", 2, kindFloat},
+	gl.FLOAT_VEC3:                    {"vec3", 3, kindFloat},
+	gl.FLOAT_VEC4:                    {"vec4", 4, kindFloat},
+	gl.FLOAT_MAT2:                    {"mat2", 4, kindFloat},
+	gl.FLOAT_MAT3:                    {"mat3", 9, kindFloat},
+	gl.FLOAT_MAT4:                    {"mat4", 16, kindFloat},
+	gl.FLOAT_MAT2x3:                  {"mat2x3", 6, kindFloat},
+	gl.FLOAT_MAT2x4:                  {"mat2x4", 8, kindFloat},
+	gl.FLOAT_MAT3x2:                  {"mat3x2", 6, kindFloat},
+	gl.FLOAT_MAT3x4:                  {"mat3x4", 12, kindFloat},
+	gl.FLOAT_MAT4x2:                  {"mat4x2", 8, kindFloat},
+	gl.FLOAT_MAT4x3:                  {"mat4x3", 12, kindFloat},
+	gl.INT:                           {"int", 1, kindInt},
+	gl.INT_VEC2:                      {"ivec2", 2, kindInt},
+	gl.INT_VEC3:                      {"ivec3", 3, kindInt},
+	gl.INT_VEC4:                      {"ivec4", 4, kindInt},
+	gl.UNSIGNED_INT:                  {"uint", 1, kindUint},
+	gl.UNSIGNED_INT_VEC2:             {"uvec2", 2, kindUint},
+	gl.UNSIGNED_INT_VEC3:             {"uvec3", 3, kindUint},
+	gl.UNSIGNED_INT_VEC4:             {"uvec4", 4, kindUint},
+	gl.BOOL:                          {"bool", 1, kindBool},
+	gl.BOOL_VEC2:                     {"bvec2", 2, kindBool},
+	gl.BOOL_VEC3:                     {"bvec3", 3, kindBool},
+	gl.BOOL_VEC4:                     {"bvec4", 4, kindBool},
+	gl.SAMPLER_1D:                    {"sampler1D", 1, kindSampler},
+	gl.SAMPLER_2D:                    {"sampler2D", 1, kindSampler},
+	gl.SAMPLER_3D:                    {"sampler3D", 1, kindSampler},
+	gl.SAMPLER_CUBE:                  {"samplerCube", 1, kindSampler},
+	gl.SAMPLER_2D_SHADOW:             {"sampler2DShadow", 1, kindSampler},
+	gl.SAMPLER_CUBE_SHADOW:           {"samplerCubeShadow", 1, kindSampler},
+	gl.SAMPLER_2D_ARRAY:              {"sampler2DArray", 1, kindSampler},
+	gl.SAMPLER_2D_ARRAY_SHADOW:       {"sampler2DArrayShadow", 1, kindSampler},
+	gl.SAMPLER_2D_MULTISAMPLE:        {"sampler2DMS", 1, kindSampler},
+	gl.SAMPLER_2D_RECT:               {"sampler2DRect", 1, kindSampler},
+	gl.SAMPLER_BUFFER:                {"samplerBuffer", 1, kindSampler},
+	gl.INT_SAMPLER_2D:                {"isampler2D", 1, kindSampler},
+	gl.INT_SAMPLER_3D:                {"isampler3D", 1, kindSampler},
+	gl.INT_SAMPLER_CUBE:              {"isamplerCube", 1, kindSampler},
+	gl.INT_SAMPLER_2D_ARRAY:          {"isampler2DArray", 1, kindSampler},
+	gl.INT_SAMPLER_BUFFER:            {"isamplerBuffer", 1, kindSampler},
+	gl.UNSIGNED_INT_SAMPLER_2D:       {"usampler2D", 1, kindSampler},
+	gl.UNSIGNED_INT_SAMPLER_3D:       {"usampler3D", 1, kindSampler},
+	gl.UNSIGNED_INT_SAMPLER_CUBE:     {"usamplerCube", 1, kindSampler},
+	gl.UNSIGNED_INT_SAMPLER_2D_ARRAY: {"usampler2DArray", 1, kindSampler},
+	gl.UNSIGNED_INT_SAMPLER_BUFFER:   {"usamplerBuffer", 1, kindSampler},
+}
+
+// TypeName retrieves the GLSL name of an OpenGL uniform or attribute type, such as vec3 for gl.FLOAT_VEC3.
+func TypeName(t uint32) string {
+	if ut, ok := uniformTypes[t]; ok {
+		return ut.name
+	}
+	switch t {
+	case gl.DOUBLE:
+		return "double"
+	case gl.DOUBLE_VEC2:
+		return "dvec2"
+	case gl.DOUBLE_VEC3:
+		return "dvec3"
+	case gl.DOUBLE_VEC4:
+		return "dvec4"
+	}
+	return "unknown"
+}
+
+// attributeLocations are the locations the vertex layout convention puts mesh data at, used for attributes a shader does not declare so mesh data is always uploaded to the same place.
+var attributeLocations = map[string]int32{
+	VertexAttribute:        0,
+	VertexTexCordAttribute: 1,
+	VertexJointsAttribute:  2,
+	VertexWeightsAttribute: 3,
+	InstanceModelAttribute: 4,
+	InstanceColorAttribute: 8,
+	InstanceUVAttribute:    9,
+	VertexNormalAttribute:  10,
+}
+
+// arrayName removes the [0] OpenGL adds to the name of an array.
+func arrayName(name string) string {
+	return strings.TrimSuffix(name, "[0]")
+}
+
+// reflect finds the active uniforms, uniform blocks and attributes of the program.
+func (s *shader) reflect() {
+	program := s.program
+
+	var count, maxLength int32
+	gl.GetProgramiv(program, gl.ACTIVE_UNIFORMS, &count)
+	gl.GetProgramiv(program, gl.ACTIVE_UNIFORM_MAX_LENGTH, &maxLength)
+	name := make([]uint8, maxLength+1)
+	for i := uint32(0); i < uint32(count); i++ {
+		var length, size int32
+		var xtype uint32
+		gl.GetActiveUniform(program, i, int32(len(name)), &length, &size, &xtype, &name[0])
+		var block int32
+		gl.GetActiveUniformsiv(program, 1, &i, gl.UNIFORM_BLOCK_INDEX, &block)
+
+		u := UniformInfo{
+			Name:     arrayName(string(name[:length])),
+			Type:     xtype,
+			Size:     size,
+			Location: -1,
+			Block:    block,
+		}
+		if block < 0 {
+			u.Location = gl.GetUniformLocation(program, gl.Str(u.Name+"\x00"))
+			s.uniforms[u.Name] = u.Location
+		}
+		s.uniformInfo[u.Name] = u
+	}
+
+	gl.GetProgramiv(program, gl.ACTIVE_UNIFORM_BLOCKS, &count)
+	gl.GetProgramiv(program, gl.ACTIVE_UNIFORM_BLOCK_MAX_NAME_LENGTH, &maxLength)
+	name = make([]uint8, maxLength+1)
+	for i := uint32(0); i < uint32(count); i++ {
+		var length, dataSize, binding int32
+		gl.GetActiveUniformBlockName(program, i, int32(len(name)), &length, &name[0])
+		gl.GetActiveUniformBlockiv(program, i, gl.UNIFORM_BLOCK_DATA_SIZE, &dataSize)
+		gl.GetActiveUniformBlockiv(program, i, gl.UNIFORM_BLOCK_BINDING, &binding)
+		block := UniformBlockInfo{
+			Name:     string(name[:length]),
+			Index:    i,
+			DataSize: dataSize,
+			Binding:  uint32(binding),
+		}
+		for _, u := range s.Uniforms() {
+			if u.Block == int32(i) {
+				block.Uniforms = append(block.Uniforms, u.Name)
+			}
+		}
+		s.blocks = append(s.blocks, block)
+	}
+
+	gl.GetProgramiv(program, gl.ACTIVE_ATTRIBUTES, &count)
+	gl.GetProgramiv(program, gl.ACTIVE_ATTRIBUTE_MAX_LENGTH, &maxLength)
+	name = make([]uint8, maxLength+1)
+	for i := uint32(0); i < uint32(count); i++ {
+		var length, size int32
+		var xtype uint32
+		gl.GetActiveAttrib(program, i, int32(len(name)), &length, &size, &xtype, &name[0])
+		a := AttributeInfo{
+			Name: arrayName(string(name[:length])),
+			Type: xtype,
+			Size: size,
+		}
+		// Built in inputs such as gl_VertexID are active but have no location.
+		if strings.HasPrefix(a.Name, "gl_") {
+			continue
+		}
+		a.Location = gl.GetAttribLocation(program, gl.Str(a.Name+"\x00"))
+		s.attributes[a.Name] = a
+	}
+}
+
+// Uniforms retrieves the active uniforms of the shader sorted by name.
+func (s *shader) Uniforms() []UniformInfo {
+	uniforms := make([]UniformInfo, 0, len(s.uniformInfo))
+	for _, u := range s.uniformInfo {
+		uniforms = append(uniforms, u)
+	}
+	sort.Slice(uniforms, func(i, j int) bool {
+		return uniforms[i].Name < uniforms[j].Name
+	})
+	return uniforms
+}
+
+// Uniform retrieves an active uniform of the shader.
+func (s *shader) Uniform(name string) (UniformInfo, bool) {
+	u, ok := s.uniformInfo[name]
+	return u, ok
+}
+
+// UniformBlocks retrieves the active uniform blocks of the shader in the order of their indices.
+func (s *shader) UniformBlocks() []UniformBlockInfo {
+	return append([]UniformBlockInfo(nil), s.blocks...)
+}
+
+// Attributes retrieves the active vertex attributes of the shader sorted by location.
+func (s *shader) Attributes() []AttributeInfo {
+	attributes := make([]AttributeInfo, 0, len(s.attributes))
+	for _, a := range s.attributes {
+		attributes = append(attributes, a)
+	}
+	sort.Slice(attributes, func(i, j int) bool {
+		if attributes[i].Location != attributes[j].Location {
+			return attributes[i].Location < attributes[j].Location
+		}
+		return attributes[i].Name < attributes[j].Name
+	})
+	return attributes
+}
@@ -0,0 +1,323 @@
+package resources
+
+import (
+	"fmt"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// The setters set uniforms with glProgramUniform, so the shader does not need to be in use, but like every OpenGL call they must be made on the main thread.
+
+// SetBool sets a bool uniform.
+func (s *shader) SetBool(name string, v bool) error {
+	value := int32(0)
+	if v {
+		value = 1
+	}
+	return s.setInts(name, gl.BOOL, 1, &value)
+}
+
+// SetInt sets an int uniform.
+func (s *shader) SetInt(name string, v int32) error {
+	return s.setInts(name, gl.INT, 1, &v)
+}
+
+// SetFloat sets a float uniform.
+func (s *shader) SetFloat(name string, v float32) error {
+	return s.setFloats(name, gl.FLOAT, 1, &v)
+}
+
+// SetVec2 sets a vec2 uniform.
+func (s *shader) SetVec2(name string, v mgl32.Vec2) error {
+	return s.setFloats(name, gl.FLOAT_VEC2, 1, &v[0])
+}
+
+// SetVec3 sets a vec3 uniform.
+func (s *shader) SetVec3(name string, v mgl32.Vec3) error {
+	return s.setFloats(name, gl.FLOAT_VEC3, 1, &v[0])
+}
+
+// SetVec4 sets a vec4 uniform.
+func (s *shader) SetVec4(name string, v mgl32.Vec4) error {
+	return s.setFloats(name, gl.FLOAT_VEC4, 1, &v[0])
+}
+
+// SetMat3 sets a mat3 uniform.
+func (s *shader) SetMat3(name string, v mgl32.Mat3) error {
+	return s.setFloats(name, gl.FLOAT_MAT3, 1, &v[0])
+}
+
+// SetMat4 sets a mat4 uniform.
+func (s *shader) SetMat4(name string, v mgl32.Mat4) error {
+	return s.setFloats(name, gl.FLOAT_MAT4, 1, &v[0])
+}
+
+// SetSampler sets the texture unit a sampler uniform reads from.
+func (s *shader) SetSampler(name string, unit int32) error {
+	u, t, err := s.lookup(name, 1)
+	if err != nil {
+		return err
+	}
+	if t.kind != kindSampler {
+		return s.typeError(u, "sampler")
+	}
+	gl.ProgramUniform1i(s.program, u.Location, unit)
+	return nil
+}
+
+// SetInts sets the first elements of an int array uniform.
+func (s *shader) SetInts(name string, v []int32) error {
+	if len(v) == 0 {
+		return s.noValues(name)
+	}
+	return s.setInts(name, gl.INT, len(v), &v[0])
+}
+
+// SetFloats sets the first elements of a float array uniform.
+func (s *shader) SetFloats(name string, v []float32) error {
+	if len(v) == 0 {
+		return s.noValues(name)
+	}
+	return s.setFloats(name, gl.FLOAT, len(v), &v[0])
+}
+
+// SetVec2s sets the first elements of a vec2 array uniform.
+func (s *shader) SetVec2s(name string, v []mgl32.Vec2) error {
+	if len(v) == 0 {
+		return s.noValues(name)
+	}
+	return s.setFloats(name, gl.FLOAT_VEC2, len(v), &v[0][0])
+}
+
+// SetVec3s sets the first elements of a vec3 array uniform.
+func (s *shader) SetVec3s(name string, v []mgl32.Vec3) error {
+	if len(v) == 0 {
+		return s.noValues(name)
+	}
+	return s.setFloats(name, gl.FLOAT_VEC3, len(v), &v[0][0])
+}
+
+// SetVec4s sets the first elements of a vec4 array uniform.
+func (s *shader) SetVec4s(name string, v []mgl32.Vec4) error {
+	if len(v) == 0 {
+		return s.noValues(name)
+	}
+	return s.setFloats(name, gl.FLOAT_VEC4, len(v), &v[0][0])
+}
+
+// SetMat4s sets the first elements of a mat4 array uniform.
+func (s *shader) SetMat4s(name string, v []mgl32.Mat4) error {
+	if len(v) == 0 {
+		return s.noValues(name)
+	}
+	return s.setFloats(name, gl.FLOAT_MAT4, len(v), &v[0][0])
+}
+
+// SetValues sets a uniform of any type from its values, such as 3 values for a vec3 or 8 for the first two elements of a vec4 array.  Values for int, bool and sampler uniforms are converted from floats.
+func (s *shader) SetValues(name string, values []float32) error {
+	if len(values) == 0 {
+		return s.noValues(name)
+	}
+	u, t, err := s.lookup(name, 1)
+	if err != nil {
+		return err
+	}
+	if len(values)%t.components != 0 {
+		return fmt.Errorf("uniform %s of shader %s is a %s, which %d values do not fill", name, s.name, t.name, len(values))
+	}
+	count := len(values) / t.components
+	if count > int(u.Size) {
+		return fmt.Errorf("uniform %s of shader %s holds %d elements, not %d", name, s.name, u.Size, count)
+	}
+	if t.kind == kindFloat {
+		s.uploadFloats(u, count, &values[0])
+		return nil
+	}
+
+	ints := make([]int32, len(values))
+	for i, v := range values {
+		ints[i] = int32(v)
+	}
+	if t.kind == kindUint {
+		uints := make([]uint32, len(values))
+		for i, v := range ints {
+			uints[i] = uint32(v)
+		}
+		s.uploadUints(u, count, &uints[0])
+		return nil
+	}
+	s.uploadInts(u, count, &ints[0])
+	return nil
+}
+
+// UniformValues retrieves every value of a uniform as the shader holds them.  Values of int, bool and sampler uniforms are converted to floats.
+func (s *shader) UniformValues(name string) ([]float32, error) {
+	u, t, err := s.lookup(name, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]float32, 0, int(u.Size)*t.components)
+	for i := int32(0); i < u.Size; i++ {
+		loc := u.Location
+		if i > 0 {
+			loc = s.GetUniformLoc(fmt.Sprintf("%s[%d]", name, i))
+		}
+		switch t.kind {
+		case kindFloat:
+			var buf [16]float32
+			if loc >= 0 {
+				gl.GetUniformfv(s.program, loc, &buf[0])
+			}
+			values = append(values, buf[:t.components]...)
+		case kindUint:
+			var buf [4]uint32
+			if loc >= 0 {
+				gl.GetUniformuiv(s.program, loc, &buf[0])
+			}
+			for _, v := range buf[:t.components] {
+				values = append(values, float32(v))
+			}
+		default:
+			var buf [4]int32
+			if loc >= 0 {
+				gl.GetUniformiv(s.program, loc, &buf[0])
+			}
+			for _, v := range buf[:t.components] {
+				values = append(values, float32(v))
+			}
+		}
+	}
+	return values, nil
+}
+
+// BindUniformBlock sets the uniform buffer binding point a uniform block reads from.
+func (s *shader) BindUniformBlock(name string, binding uint32) error {
+	for i := range s.blocks {
+		if s.blocks[i].Name == name {
+			gl.UniformBlockBinding(s.program, s.blocks[i].Index, binding)
+			s.blocks[i].Binding = binding
+			return nil
+		}
+	}
+	return fmt.Errorf("shader %s has no active uniform block %s", s.name, name)
+}
+
+// lookup retrieves an active uniform to set count elements of, returning an error if the shader has no such uniform outside of a uniform block, its type cannot be set or it has fewer elements.
+func (s *shader) lookup(name string, count int) (UniformInfo, uniformType, error) {
+	u, ok := s.uniformInfo[name]
+	if !ok {
+		return u, uniformType{}, fmt.Errorf("shader %s has no active uniform %s", s.name, name)
+	}
+	if u.Location < 0 {
+		return u, uniformType{}, fmt.Errorf("uniform %s of shader %s is in a uniform block and is set through its uniform buffer", name, s.name)
+	}
+	t, ok := uniformTypes[u.Type]
+	if !ok {
+		return u, t, fmt.Errorf("uniform %s of shader %s has the unsupported type %s", name, s.name, u.TypeName())
+	}
+	if count > int(u.Size) {
+		return u, t, fmt.Errorf("uniform %s of shader %s holds %d elements, not %d", name, s.name, u.Size, count)
+	}
+	return u, t, nil
+}
+
+// typeError retrieves the error for setting a uniform with values of the wrong type.
+func (s *shader) typeError(u UniformInfo, want string) error {
+	return fmt.Errorf("uniform %s of shader %s is a %s, not a %s", u.Name, s.name, u.TypeName(), want)
+}
+
+// noValues retrieves the error for setting a uniform without any values.
+func (s *shader) noValues(name string) error {
+	return fmt.Errorf("no values to set uniform %s of shader %s to", name, s.name)
+}
+
+// setFloats sets count elements of a uniform that must be of the float type xtype.
+func (s *shader) setFloats(name string, xtype uint32, count int, v *float32) error {
+	u, _, err := s.lookup(name, count)
+	if err != nil {
+		return err
+	}
+	if u.Type != xtype {
+		return s.typeError(u, TypeName(xtype))
+	}
+	s.uploadFloats(u, count, v)
+	return nil
+}
+
+// setInts sets count elements of a uniform that must be of the int or bool type xtype.
+func (s *shader) setInts(name string, xtype uint32, count int, v *int32) error {
+	u, _, err := s.lookup(name, count)
+	if err != nil {
+		return err
+	}
+	if u.Type != xtype {
+		return s.typeError(u, TypeName(xtype))
+	}
+	s.uploadInts(u, count, v)
+	return nil
+}
+
+// uploadFloats sets count elements of a float, vector or matrix uniform.
+func (s *shader) uploadFloats(u UniformInfo, count int, v *float32) {
+	n := int32(count)
+	switch u.Type {
+	case gl.FLOAT:
+		gl.ProgramUniform1fv(s.program, u.Location, n, v)
+	case gl.FLOAT_VEC2:
+		gl.ProgramUniform2fv(s.program, u.Location, n, v)
+	case gl.FLOAT_VEC3:
+		gl.ProgramUniform3fv(s.program, u.Location, n, v)
+	case gl.FLOAT_VEC4:
+		gl.ProgramUniform4fv(s.program, u.Location, n, v)
+	case gl.FLOAT_MAT2:
+		gl.ProgramUniformMatrix2fv(s.program, u.Location, n, false, v)
+	case gl.FLOAT_MAT3:
+		gl.ProgramUniformMatrix3fv(s.program, u.Location, n, false, v)
+	case gl.FLOAT_MAT4:
+		gl.ProgramUniformMatrix4fv(s.program, u.Location, n, false, v)
+	case gl.FLOAT_MAT2x3:
+		gl.ProgramUniformMatrix2x3fv(s.program, u.Location, n, false, v)
+	case gl.FLOAT_MAT2x4:
+		gl.ProgramUniformMatrix2x4fv(s.program, u.Location, n, false, v)
+	case gl.FLOAT_MAT3x2:
+		gl.ProgramUniformMatrix3x2fv(s.program, u.Location, n, false, v)
+	case gl.FLOAT_MAT3x4:
+		gl.ProgramUniformMatrix3x4fv(s.program, u.Location, n, false, v)
+	case gl.FLOAT_MAT4x2:
+		gl.ProgramUniformMatrix4x2fv(s.program, u.Location, n, false, v)
+	case gl.FLOAT_MAT4x3:
+		gl.ProgramUniformMatrix4x3fv(s.program, u.Location, n, false, v)
+	}
+}
+
+// uploadInts sets count elements of an int, bool or sampler uniform.
+func (s *shader) uploadInts(u UniformInfo, count int, v *int32) {
+	n := int32(count)
+	switch uniformTypes[u.Type].components {
+	case 1:
+		gl.ProgramUniform1iv(s.program, u.Location, n, v)
+	case 2:
+		gl.ProgramUniform2iv(s.program, u.Location, n, v)
+	case 3:
+		gl.ProgramUniform3iv(s.program, u.Location, n, v)
+	case 4:
+		gl.ProgramUniform4iv(s.program, u.Location, n, v)
+	}
+}
+
+// uploadUints sets count elements of a uint uniform.
+func (s *shader) uploadUints(u UniformInfo, count int, v *uint32) {
+	n := int32(count)
+	switch uniformTypes[u.Type].components {
+	case 1:
+		gl.ProgramUniform1uiv(s.program, u.Location, n, v)
+	case 2:
+		gl.ProgramUniform2uiv(s.program, u.Location, n, v)
+	case 3:
+		gl.ProgramUniform3uiv(s.program, u.Location, n, v)
+	case 4:
+		gl.ProgramUniform4uiv(s.program, u.Location, n, v)
+	}
+}
@@ -29,6 +29,8 @@ type TextureManager interface {
 	LoadTexture(filePath string, key string) (uint32, error)
 	// GetTexture returns a texture id if the texture was loaded.
 	GetTexture(key string) (texture uint32, isFound bool)
+	// AddTexture stores a texture created elsewhere, such as a render target, under a key.
+	AddTexture(key string, texture uint32)
 }
 
 // newTextureManager creates a new TextureManager
@@ -61,6 +63,13 @@ func (tm *textureManager) GetTexture(key string) (uint32, bool) {
 	return texture, status
 }
 
+// AddTexture stores a texture created elsewhere, such as a render target, under a key.  Meshes whose texture file is the key show the texture.
+func (tm *textureManager) AddTexture(key string, texture uint32) {
+	tm.textureLock.Lock()
+	defer tm.textureLock.Unlock()
+	tm.textures[key] = texture
+}
+
 func newTexture(file string) (uint32, error) {
 	imgFile, err := os.Open(fmt.Sprintf("%s%s", TextureSrcDir, file))
 	if err != nil {
@@ -0,0 +1,184 @@
+// Package scenefile describes the json format scenes are loaded from, so tools can read and write scenes the engine can load.
+package scenefile
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-gl/mathgl/mgl32"
+
+	"github.com/Ariemeth/quantum-pulse/components"
+	"github.com/Ariemeth/quantum-pulse/postprocess"
+)
+
+// Scene is the top level of a scene file.
+type Scene struct {
+	Camera Camera `json:"defaultCamera"`
+	// Cameras are additional cameras, such as for split screen or a minimap.  Cameras draw by their order and cameras with the same order draw after the default camera in the order they are listed.
+	Cameras []Camera `json:"cameras,omitempty"`
+	// CameraController holds the json of a camera controller moving the default camera, such as {"kind": "orbit"}.
+	CameraController *json.RawMessage             `json:"cameraController,omitempty"`
+	Gravity          [3]float32                   `json:"gravity"`
+	Integrator       components.IntegrationMethod `json:"integrator,omitempty"`
+	FixedStep        float32                      `json:"fixedStep,omitempty"`
+	Models           []Model                      `json:"models"`
+	Maps             []Map                        `json:"maps,omitempty"`
+	// RenderTargets are framebuffers cameras can draw into instead of the window.  They are created before the cameras and models.
+	RenderTargets []RenderTarget `json:"renderTargets,omitempty"`
+	// PostProcessing is the chain of effects applied to every frame in order, such as [{"effect": "bloom"}, {"effect": "tonemap"}].
+	PostProcessing []postprocess.Effect `json:"postProcessing,omitempty"`
+	// Lights light the meshes drawn with a lit shader, such as the built in lit.vert and lit.frag.
+	Lights []Light `json:"lights,omitempty"`
+	// AmbientLight is the color of the light reaching every surface of a lit mesh.  It defaults to a dim grey.
+	AmbientLight *[3]float32 `json:"ambientLight,omitempty"`
+}
+
+// Light is a light of a scene placed at Position.  Kind is "directional", "point" or "spot" and defaults to point, and every other setting missing from the json keeps the default of its kind.
+type Light struct {
+	components.LightData
+	Name     string     `json:"name,omitempty"`
+	Position [3]float32 `json:"position"`
+	// Components holds the json of additional components of the light's entity keyed by their registered type.
+	Components map[string]*json.RawMessage `json:"components,omitempty"`
+}
+
+// UnmarshalJSON decodes a light over the defaults of its kind.
+func (l *Light) UnmarshalJSON(data []byte) error {
+	kind := struct {
+		Kind components.LightKind `json:"kind"`
+	}{Kind: components.LightPoint}
+	if err := json.Unmarshal(data, &kind); err != nil {
+		return err
+	}
+	switch kind.Kind {
+	case components.LightDirectional, components.LightPoint, components.LightSpot:
+	default:
+		return fmt.Errorf("unknown light %q", kind.Kind)
+	}
+
+	// plain has no methods so decoding into it does not call UnmarshalJSON again.
+	type plain Light
+	p := plain{LightData: components.NewLightData(kind.Kind)}
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+	*l = Light(p)
+	return nil
+}
+
+// RenderTarget is a framebuffer cameras can draw into.  Meshes whose texture file is the name of the render target show what was drawn into it.  A width or height of 0 uses the window's size.  Color lists the format of each color attachment, which are "rgba8", "rgba16f" or "rgba32f", and Depth is "depth24", "depth32f" or "none".
+type RenderTarget struct {
+	Name   string   `json:"name"`
+	Width  int      `json:"width,omitempty"`
+	Height int      `json:"height,omitempty"`
+	Color  []string `json:"color,omitempty"`
+	Depth  string   `json:"depth,omitempty"`
+	// DepthTexture stores depth in a texture that can be sampled.
+	DepthTexture bool `json:"depthTexture,omitempty"`
+	// Nearest samples the color textures without smoothing.
+	Nearest bool `json:"nearest,omitempty"`
+}
+
+// Model is an entity of a scene built from a mesh file and optional components.  Collider and RigidBody hold the json of the component's data.
+type Model struct {
+	Name           string                       `json:"name"`
+	FileName       string                       `json:"fileName"`
+	Position       [3]float32                   `json:"position"`
+	RotAccel       [3]float32                   `json:"rotationalAcceleration"`
+	TransAccel     [3]float32                   `json:"translationalAcceleration"`
+	RotVelocity    [3]float32                   `json:"rotationalVelocity"`
+	TransVelocity  [3]float32                   `json:"translationalVelocity"`
+	VelocityFrame  components.ReferenceFrame    `json:"velocityFrame,omitempty"`
+	AccelFrame     components.ReferenceFrame    `json:"accelerationFrame,omitempty"`
+	Parent         string                       `json:"parent,omitempty"`
+	Animation      string                       `json:"animation,omitempty"`
+	Clip           string                       `json:"clip,omitempty"`
+	Skeleton       string                       `json:"skeleton,omitempty"`
+	Sprite         string                       `json:"sprite,omitempty"`
+	SpriteSequence string                       `json:"spriteSequence,omitempty"`
+	Collider       *json.RawMessage             `json:"collider,omitempty"`
+	RigidBody      *json.RawMessage             `json:"rigidBody,omitempty"`
+	Integrator     components.IntegrationMethod `json:"integrator,omitempty"`
+	Path           *Path                        `json:"path,omitempty"`
+	// Components holds the json of additional components keyed by their registered type.
+	Components map[string]*json.RawMessage `json:"components,omitempty"`
+}
+
+// Path is the route a model's path follower walks.
+type Path struct {
+	Speed        float32      `json:"speed"`
+	ArriveRadius float32      `json:"arriveRadius,omitempty"`
+	Loop         bool         `json:"loop,omitempty"`
+	Waypoints    [][3]float32 `json:"waypoints"`
+}
+
+// Map is a Tiled map whose tiles and objects are added to the scene.  TileSize is the size of one tile in world units and Mesh is the mesh of tiles without a mesh property.  Zero values use the importer's defaults.
+type Map struct {
+	FileName    string     `json:"fileName"`
+	TileSize    [2]float32 `json:"tileSize"`
+	Origin      [3]float32 `json:"origin"`
+	LayerHeight float32    `json:"layerHeight,omitempty"`
+	Mesh        string     `json:"mesh,omitempty"`
+}
+
+// Camera is a camera a scene is viewed through.  Projection is "perspective", "orthographic" or "pixel" and defaults to perspective.  FOVY is in degrees and used by perspective projections.  Size is the height an orthographic projection shows, or Bounds the left, right, bottom and top it shows.  Viewport, Clear and ClearColor default to drawing over the whole window after clearing it to grey.  Target names a render target to draw into instead of the window.
+type Camera struct {
+	Position   [3]float32                `json:"position"`
+	LookAt     [3]float32                `json:"lookat"`
+	Up         [3]float32                `json:"up"`
+	Projection components.ProjectionKind `json:"projection,omitempty"`
+	FOVY       float32                   `json:"fovy"`
+	Size       float32                   `json:"size,omitempty"`
+	Bounds     *[4]float32               `json:"bounds,omitempty"`
+	NearPlane  float32                   `json:"nearPlane"`
+	FarPlane   float32                   `json:"farPlane"`
+	Viewport   *components.Viewport      `json:"viewport,omitempty"`
+	Clear      *components.ClearFlags    `json:"clear,omitempty"`
+	ClearColor *[4]float32               `json:"clearColor,omitempty"`
+	Order      int                       `json:"order,omitempty"`
+	Target     string                    `json:"target,omitempty"`
+	Enabled    *bool                     `json:"enabled,omitempty"`
+	// Name is the id of the camera's entity.  It is only used by the cameras list.
+	Name string `json:"name,omitempty"`
+	// Controller holds the json of a camera controller moving the camera.  It is only used by the cameras list.
+	Controller *json.RawMessage `json:"controller,omitempty"`
+	// Components holds the json of additional components of the camera's entity keyed by their registered type.  A camera with a transform is placed by it.
+	Components map[string]*json.RawMessage `json:"components,omitempty"`
+}
+
+// Data converts the camera to the data its component is created from.
+func (c Camera) Data() components.CameraData {
+	cd := components.CameraData{
+		Position:   c.Position,
+		LookAt:     c.LookAt,
+		Up:         c.Up,
+		Projection: c.Projection,
+		FOVY:       c.FOVY,
+		Size:       c.Size,
+		Bounds:     c.Bounds,
+		NearPlane:  c.NearPlane,
+		FarPlane:   c.FarPlane,
+		Viewport:   c.Viewport,
+		Clear:      c.Clear,
+		Order:      c.Order,
+		Target:     c.Target,
+		Enabled:    c.Enabled,
+	}
+	if c.ClearColor != nil {
+		color := mgl32.Vec4(*c.ClearColor)
+		cd.ClearColor = &color
+	}
+	return cd
+}
+
+// Parse decodes a scene from json.
+func Parse(data []byte) (Scene, error) {
+	var s Scene
+	err := json.Unmarshal(data, &s)
+	return s, err
+}
+
+// Marshal encodes a scene as tab indented json.  The same scene always produces the same bytes.
+func Marshal(s Scene) ([]byte, error) {
+	return json.MarshalIndent(s, "", "\t")
+}
@@ -1,84 +1,224 @@
 package systems
 
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Ariemeth/quantum-pulse/components"
+	"github.com/Ariemeth/quantum-pulse/entity"
+)
+
 const (
 	// TypeAnimator is the name of the animator system.
 	TypeAnimator = "animator"
 )
 
-/*
-// Animator defines the behaviors expected of the animation system.
+// Animator defines the behaviors expected of the animation system.  Entities with a transform and an animation have their clips played, and entities with a sprite have their sprite sheet animated.
 type Animator interface {
 	System
-	// Process performs handles animating all entities that have animations.
-	Process(elapsed float64)
+	// Process advances the animations of all entities and applies the results to their transforms.
+	Process(elapsed float32)
 }
 
 type animator struct {
-	entities map[string]animatable
+	entities       map[string]animatable
+	remove         chan entity.Entity
+	add            chan entity.Entity
+	quit           chan interface{}
+	quitProcessing chan interface{}
+	runningLock    sync.Mutex
+	requirements   []string
+	interval       time.Duration
+	isRunning      bool
 }
 
 // NewAnimator creates a new Animator system.
 func NewAnimator() Animator {
 	a := animator{
-		entities: make(map[string]animatable, 0),
+		entities:       make(map[string]animatable, 0),
+		remove:         make(chan entity.Entity, 0),
+		add:            make(chan entity.Entity, 0),
+		quit:           make(chan interface{}),
+		quitProcessing: make(chan interface{}),
+		requirements:   []string{components.TypeTransform, components.TypeAnimation},
+		interval:       (1000 / 144) * time.Millisecond,
+		isRunning:      false,
 	}
+
+	go func() {
+		for {
+			select {
+			case ent := <-a.add:
+				log.Printf("Adding %s to the animator system.\n", ent.ID())
+				a.addEntity(ent)
+			case ent := <-a.remove:
+				log.Printf("Removing %s from the animator system.\n", ent.ID())
+				a.removeEntity(ent)
+			case <-a.quit:
+				return
+			}
+		}
+	}()
+
 	return &a
 }
 
-func (a *animator) Start() {
-
+// Type retrieves the type of system such as renderer, mover, etc.
+func (a *animator) Type() string {
+	return TypeAnimator
 }
 
-func (a *animator) Stop() {
+// AddEntity adds an Entity to the system.  Each system will have a component requirement that must be met before the Entity can be added.
+func (a *animator) AddEntity(e entity.Entity) {
+	a.add <- e
+}
 
+// RemoveEntity removes an Entity from the system.
+func (a *animator) RemoveEntity(e entity.Entity) {
+	a.remove <- e
 }
 
+// IsRunning is useful to check if the animator is processing entities.
 func (a *animator) IsRunning() bool {
-	return false
+	defer a.runningLock.Unlock()
+	a.runningLock.Lock()
+	return a.isRunning
 }
 
-// Type retrieves the type of system such as renderer, mover, etc.
-func (a *animator) Type() string {
-	return TypeAnimator
+// Start will begin advancing the animations of the entities that have been added.
+func (a *animator) Start() {
+	defer a.runningLock.Unlock()
+	a.runningLock.Lock()
+
+	if a.isRunning {
+		return
+	}
+
+	a.isRunning = true
+
+	go func() {
+
+		tr := time.NewTimer(a.interval)
+		previousTime := time.Now().UnixNano()
+
+		for {
+			select {
+			case <-a.quitProcessing:
+				a.isRunning = false
+				return
+			case <-tr.C:
+				current := time.Now().UnixNano()
+				// Get the elapsed time in seconds
+				elapsed := float32((current - previousTime)) / 1000000000.0
+				previousTime = current
+
+				a.Process(elapsed)
+				processingTime := time.Now().UnixNano() - current
+
+				if processingTime > 0 {
+					tr.Reset(a.interval - time.Duration(processingTime))
+				} else {
+					tr.Reset(time.Nanosecond)
+				}
+			}
+		}
+	}()
 }
 
-// AddEntity adds an Entity to the system.  Each system will have a component requirement that must be met before the Entity can be added.
-func (a *animator) AddEntity(e entity.Entity) {
-	transform, isTransform := e.Component(components.ComponentTypeTransform).(components.Transform)
+// Stop will stop the animator from animating any of its Entities.
+func (a *animator) Stop() {
+	defer a.runningLock.Unlock()
+	a.runningLock.Lock()
+	if a.isRunning {
+		a.quitProcessing <- true
+	}
+}
+
+// Terminate stops the animator and releases all resources.  Once Terminate has been called, the system cannot be reused.
+func (a *animator) Terminate() {
+	defer a.runningLock.Unlock()
+	a.runningLock.Lock()
+	if a.isRunning {
+		a.quitProcessing <- true
+	}
+	a.quit <- true
+}
+
+// Process advances the animations of all entities and applies the results to their transforms.
+func (a *animator) Process(elapsed float32) {
+	defer a.runningLock.Unlock()
+	a.runningLock.Lock()
+
+	for _, ent := range a.entities {
+		if ent.Sprite != nil {
+			ent.Sprite.Update(elapsed)
+		}
+		if ent.Animation == nil {
+			continue
+		}
 
-	if isTransform {
-		anim := animatable{
-			Transform: transform,
-			animation: make([]func(float64), 0),
+		pose, isPlaying := ent.Animation.Update(elapsed)
+		if !isPlaying {
+			continue
 		}
-		// This is just temporary for testing.
-		angle := 0.0
-		rotate := func(elapsed float64) {
-			angle += elapsed
-			anim.Transform.Set(mgl32.HomogRotate3D(float32(angle), mgl32.Vec3{0, 1, 0}))
+		applyPose(pose, ent.Transform)
+		if ent.Skeleton != nil && len(pose.Joints) > 0 {
+			ent.Skeleton.ApplyPose(pose.Joints)
 		}
-		anim.animation = append(anim.animation, rotate)
+	}
+}
+
+// addEntity adds an Entity to the system.  Each system will have a component requirement that must be met before the Entity can be added.
+func (a *animator) addEntity(e entity.Entity) {
+	transform, isTransform := e.Component(components.TypeTransform).(components.Transform)
+	animation, isAnimation := e.Component(components.TypeAnimation).(components.Animation)
+	sprite, isSprite := e.Component(components.TypeSprite).(components.Sprite)
+
+	anim := animatable{}
+	if isTransform && isAnimation {
+		anim.Animation = animation
+		anim.Transform = transform
+		// Skinned entities also have their joints animated.
+		if skeleton, isSkeleton := e.Component(components.TypeSkeleton).(components.Skeleton); isSkeleton {
+			anim.Skeleton = skeleton
+		}
+	}
+	if isSprite {
+		anim.Sprite = sprite
+	}
+
+	if anim.Animation != nil || anim.Sprite != nil {
+		defer a.runningLock.Unlock()
+		a.runningLock.Lock()
 		a.entities[e.ID()] = anim
 	}
 }
 
-// RemoveEntity removes an Entity from the system.
-func (a *animator) RemoveEntity(e entity.Entity) {
+// removeEntity removes an Entity from the system.
+func (a *animator) removeEntity(e entity.Entity) {
+	defer a.runningLock.Unlock()
+	a.runningLock.Lock()
+
 	delete(a.entities, e.ID())
 }
 
-// Process performs handles animating all entities that have animations.
-func (a *animator) Process(elapsed float64) {
-	for _, anims := range a.entities {
-		for _, anim := range anims.animation {
-			anim(elapsed)
-		}
+// applyPose applies the channels present in a pose to a transform.
+func applyPose(pose components.AnimationPose, t components.Transform) {
+	if pose.HasTranslation {
+		t.SetTranslation(pose.Translation)
+	}
+	if pose.HasRotation {
+		t.SetOrientation(pose.Rotation)
+	}
+	if pose.HasScale {
+		t.SetScale(pose.Scale)
 	}
 }
 
 type animatable struct {
-	Entity    entity.Entity
+	Animation components.Animation
 	Transform components.Transform
-	animation []func(float64)
+	Skeleton  components.Skeleton
+	Sprite    components.Sprite
 }
-*/
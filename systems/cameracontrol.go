@@ -0,0 +1,215 @@
+package systems
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Ariemeth/quantum-pulse/components"
+	"github.com/Ariemeth/quantum-pulse/entity"
+	"github.com/Ariemeth/quantum-pulse/input"
+)
+
+const (
+	// TypeCameraControl is the name of the camera control system.
+	TypeCameraControl = "cameraControl"
+)
+
+// CameraControl represents a system that moves cameras from user input.  Entities with a camera controller move their own camera if they have one and the loaded camera otherwise.
+type CameraControl interface {
+	System
+	// LoadCamera sets the camera moved by controllers whose entity has no camera.
+	LoadCamera(camera components.Camera)
+	// Process updates every enabled controller with the input since the previous update.
+	Process(elapsed float32)
+}
+
+type cameraControl struct {
+	entities       map[string]*controllable
+	camera         components.Camera
+	input          *input.State
+	previous       input.Snapshot
+	remove         chan entity.Entity
+	add            chan entity.Entity
+	quit           chan interface{}
+	quitProcessing chan interface{}
+	runningLock    sync.Mutex
+	requirements   []string
+	interval       time.Duration
+	isRunning      bool
+}
+
+// NewCameraControl creates a new CameraControl system reading the input state.
+func NewCameraControl(in *input.State) CameraControl {
+	c := cameraControl{
+		entities:       make(map[string]*controllable, 0),
+		input:          in,
+		remove:         make(chan entity.Entity, 0),
+		add:            make(chan entity.Entity, 0),
+		quit:           make(chan interface{}),
+		quitProcessing: make(chan interface{}),
+		requirements:   []string{components.TypeCameraController},
+		interval:       (1000 / 144) * time.Millisecond,
+		isRunning:      false,
+	}
+
+	go func() {
+		for {
+			select {
+			case ent := <-c.add:
+				log.Printf("Adding %s to the camera control system.\n", ent.ID())
+				c.addEntity(ent)
+			case ent := <-c.remove:
+				log.Printf("Removing %s from the camera control system.\n", ent.ID())
+				c.removeEntity(ent)
+			case <-c.quit:
+				return
+			}
+		}
+	}()
+
+	return &c
+}
+
+// Type retrieves the type of system such as renderer, mover, etc.
+func (c *cameraControl) Type() string {
+	return TypeCameraControl
+}
+
+// AddEntity adds an Entity to the system.  Each system will have a component requirement that must be met before the Entity can be added.
+func (c *cameraControl) AddEntity(e entity.Entity) {
+	c.add <- e
+}
+
+// RemoveEntity removes an Entity from the system.
+func (c *cameraControl) RemoveEntity(e entity.Entity) {
+	c.remove <- e
+}
+
+// IsRunning is useful to check if the camera control system is processing entities.
+func (c *cameraControl) IsRunning() bool {
+	defer c.runningLock.Unlock()
+	c.runningLock.Lock()
+	return c.isRunning
+}
+
+// LoadCamera sets the camera moved by controllers whose entity has no camera.
+func (c *cameraControl) LoadCamera(camera components.Camera) {
+	defer c.runningLock.Unlock()
+	c.runningLock.Lock()
+	c.camera = camera
+}
+
+// Start will begin updating the controllers that have been added.
+func (c *cameraControl) Start() {
+	defer c.runningLock.Unlock()
+	c.runningLock.Lock()
+
+	if c.isRunning {
+		return
+	}
+
+	c.isRunning = true
+
+	go func() {
+
+		tr := time.NewTimer(c.interval)
+		previousTime := time.Now().UnixNano()
+
+		for {
+			select {
+			case <-c.quitProcessing:
+				c.isRunning = false
+				return
+			case <-tr.C:
+				current := time.Now().UnixNano()
+				// Get the elapsed time in seconds
+				elapsed := float32((current - previousTime)) / 1000000000.0
+				previousTime = current
+
+				c.Process(elapsed)
+				processingTime := time.Now().UnixNano() - current
+
+				if processingTime > 0 {
+					tr.Reset(c.interval - time.Duration(processingTime))
+				} else {
+					tr.Reset(time.Nanosecond)
+				}
+			}
+		}
+	}()
+}
+
+// Stop will stop the camera control system from updating any of its controllers.
+func (c *cameraControl) Stop() {
+	defer c.runningLock.Unlock()
+	c.runningLock.Lock()
+	if c.isRunning {
+		c.quitProcessing <- true
+	}
+}
+
+// Terminate stops the camera control system and releases all resources.  Once Terminate has been called, the system cannot be reused.
+func (c *cameraControl) Terminate() {
+	defer c.runningLock.Unlock()
+	c.runningLock.Lock()
+	if c.isRunning {
+		c.quitProcessing <- true
+	}
+	c.quit <- true
+}
+
+// Process updates every enabled controller with the input since the previous update.  The input is captured once so every controller sees the same cursor and scroll movement.
+func (c *cameraControl) Process(elapsed float32) {
+	defer c.runningLock.Unlock()
+	c.runningLock.Lock()
+
+	if c.input == nil {
+		return
+	}
+	snap := c.input.Snapshot(c.previous)
+	c.previous = snap
+
+	for _, ent := range c.entities {
+		if !ent.Controller.Enabled() {
+			continue
+		}
+		cam := ent.Camera
+		if cam == nil {
+			cam = c.camera
+		}
+		if cam == nil {
+			continue
+		}
+		ent.Controller.Update(cam, snap, elapsed)
+	}
+}
+
+// addEntity adds an Entity to the system.  Each system will have a component requirement that must be met before the Entity can be added.
+func (c *cameraControl) addEntity(e entity.Entity) {
+	controller, isController := e.Component(components.TypeCameraController).(components.CameraController)
+	camera, _ := e.Component(components.TypeCamera).(components.Camera)
+
+	if isController {
+		ctrl := controllable{
+			Controller: controller,
+			Camera:     camera,
+		}
+		defer c.runningLock.Unlock()
+		c.runningLock.Lock()
+		c.entities[e.ID()] = &ctrl
+	}
+}
+
+// removeEntity removes an Entity from the system.
+func (c *cameraControl) removeEntity(e entity.Entity) {
+	defer c.runningLock.Unlock()
+	c.runningLock.Lock()
+
+	delete(c.entities, e.ID())
+}
+
+type controllable struct {
+	Controller components.CameraController
+	Camera     components.Camera
+}
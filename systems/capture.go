@@ -0,0 +1,138 @@
+package systems
+
+import (
+	"fmt"
+	"image"
+	"time"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+
+	am "github.com/Ariemeth/quantum-pulse/resources"
+)
+
+// CapturedFrame is a frame read back from the renderer.
+type CapturedFrame struct {
+	// Image holds the pixels of the frame with the top row first.
+	Image *image.RGBA
+	// Time is when the frame was drawn.
+	Time time.Time
+}
+
+// captureRequest is a request to read back a number of frames as they are drawn.
+type captureRequest struct {
+	remaining int
+	frames    chan CapturedFrame
+}
+
+// CaptureFrames reads back the next count frames drawn to the window.  The channel receives each frame and is closed after the last.  The channel holds every frame, so the frames do not need to be received while they are captured.
+func (r *renderer) CaptureFrames(count int) <-chan CapturedFrame {
+	frames := make(chan CapturedFrame, count)
+	if count <= 0 {
+		close(frames)
+		return frames
+	}
+
+	r.captureLock.Lock()
+	defer r.captureLock.Unlock()
+	r.captures = append(r.captures, &captureRequest{
+		remaining: count,
+		frames:    frames,
+	})
+	return frames
+}
+
+// captureFrame reads back the drawn frame for every capture request waiting on one.  The caller is expected to be on the main thread.
+func (r *renderer) captureFrame(width, height int) {
+	r.captureLock.Lock()
+	defer r.captureLock.Unlock()
+	if len(r.captures) == 0 || width <= 0 || height <= 0 {
+		return
+	}
+
+	frame := CapturedFrame{
+		Image: readPixels(width, height),
+		Time:  time.Now(),
+	}
+	waiting := r.captures[:0]
+	for _, req := range r.captures {
+		req.frames <- frame
+		req.remaining--
+		if req.remaining > 0 {
+			waiting = append(waiting, req)
+		} else {
+			close(req.frames)
+		}
+	}
+	r.captures = waiting
+}
+
+// RenderOffscreen draws one frame at any resolution without showing it in the window.  The frame is drawn into a render target and through the post processing chain, and cameras are sized to the resolution while it is drawn so their projections match it.  It must not be called from the main thread, such as from an input callback, since it waits for the main thread to draw the frame.
+func (r *renderer) RenderOffscreen(width, height int) (*image.RGBA, error) {
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("invalid offscreen size %dx%d", width, height)
+	}
+
+	var img *image.RGBA
+	var err error
+	r.mainFunc(func() {
+		r.runningLock.Lock()
+		defer r.runningLock.Unlock()
+
+		var target am.RenderTarget
+		target, err = am.NewRenderTarget(am.NewRenderTargetSettings(width, height))
+		if err != nil {
+			return
+		}
+		defer func() {
+			target.Delete()
+			gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+			if w, h := r.window.GetFramebufferSize(); w > 0 && h > 0 {
+				gl.Viewport(0, 0, int32(w), int32(h))
+			}
+		}()
+
+		// Cameras drawing into render targets keep the size of their render target.
+		var cameras []*cameraView
+		var sizes [][2]int
+		for _, cv := range r.activeCameras() {
+			if cv.Camera.Target() != "" {
+				continue
+			}
+			cameras = append(cameras, cv)
+			sizes = append(sizes, [2]int{cv.Camera.WindowWidth(), cv.Camera.WindowHeight()})
+			cv.Camera.Resize(width, height)
+		}
+		r.drawProcessed(target.FramebufferID(), width, height)
+		for i, cv := range cameras {
+			cv.Camera.Resize(sizes[i][0], sizes[i][1])
+		}
+
+		img = readPixels(width, height)
+	})
+	return img, err
+}
+
+// readPixels reads the pixels of the bound framebuffer with the top row first.
+func readPixels(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	gl.PixelStorei(gl.PACK_ALIGNMENT, 1)
+	gl.ReadPixels(0, 0, int32(width), int32(height), gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(&img.Pix[0]))
+	fromFramebuffer(img)
+	return img
+}
+
+// fromFramebuffer turns pixels read back from OpenGL into an image.  OpenGL rows start at the bottom so they are flipped to put the top row first.  Shaders may leave any alpha in the framebuffer while the window is shown opaque, so every pixel is made opaque.
+func fromFramebuffer(img *image.RGBA) {
+	height := img.Bounds().Dy()
+	row := make([]byte, img.Stride)
+	for top, bottom := 0, height-1; top < bottom; top, bottom = top+1, bottom-1 {
+		t := img.Pix[top*img.Stride : (top+1)*img.Stride]
+		b := img.Pix[bottom*img.Stride : (bottom+1)*img.Stride]
+		copy(row, t)
+		copy(t, b)
+		copy(b, row)
+	}
+	for i := 3; i < len(img.Pix); i += 4 {
+		img.Pix[i] = 0xff
+	}
+}
@@ -0,0 +1,40 @@
+package systems
+
+import (
+	"image"
+	"testing"
+)
+
+func TestFromFramebuffer(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int
+	}{
+		{"even rows", 3, 4},
+		{"odd rows", 2, 5},
+		{"single row", 4, 1},
+	}
+	for _, test := range tests {
+		// Each pixel of the buffer holds its framebuffer row and column with a transparent alpha.
+		img := image.NewRGBA(image.Rect(0, 0, test.width, test.height))
+		for y := 0; y < test.height; y++ {
+			for x := 0; x < test.width; x++ {
+				i := img.PixOffset(x, y)
+				img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = byte(y), byte(x), 7, byte(x+y)
+			}
+		}
+
+		fromFramebuffer(img)
+
+		for y := 0; y < test.height; y++ {
+			for x := 0; x < test.width; x++ {
+				i := img.PixOffset(x, y)
+				got := [4]byte{img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3]}
+				want := [4]byte{byte(test.height - 1 - y), byte(x), 7, 0xff}
+				if got != want {
+					t.Errorf("%s: pixel %d, %d = %v, want %v", test.name, x, y, got, want)
+				}
+			}
+		}
+	}
+}
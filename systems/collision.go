@@ -0,0 +1,466 @@
+package systems
+
+import (
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-gl/mathgl/mgl32"
+
+	"github.com/Ariemeth/quantum-pulse/components"
+	"github.com/Ariemeth/quantum-pulse/entity"
+	"github.com/Ariemeth/quantum-pulse/geometry"
+)
+
+const (
+	// TypeCollision is the name of the collision system.
+	TypeCollision = "collision"
+)
+
+// CollisionPhase describes where a collision event falls in the lifetime of a contact.
+type CollisionPhase int
+
+const (
+	// CollisionEnter is sent on the first update two colliders overlap.
+	CollisionEnter CollisionPhase = iota
+	// CollisionStay is sent on every following update the colliders still overlap.
+	CollisionStay
+	// CollisionExit is sent on the first update the colliders stop overlapping or one of them is removed.
+	CollisionExit
+)
+
+// CollisionEvent describes a contact between two entities.  The contact normal points from B towards A.  IsTrigger is true if either collider is a trigger, in which case the contact only reports the overlap.
+type CollisionEvent struct {
+	Phase     CollisionPhase
+	A         entity.Entity
+	B         entity.Entity
+	Contact   geometry.Contact
+	IsTrigger bool
+}
+
+// Collision represents a system that detects overlapping colliders and reports when contacts begin, continue and end.  Solid contacts involving a dynamic rigid body are resolved by pushing the bodies apart and exchanging momentum.
+type Collision interface {
+	System
+	// Process tests every collider against the others and delivers the resulting events.
+	Process(elapsed float32)
+	// OnCollision registers a function called for every collision event.
+	OnCollision(func(CollisionEvent))
+	// Overlap retrieves the entities whose colliders are on a layer in the mask and overlap a shape.  It is useful for selection volumes and area queries.
+	Overlap(shape geometry.Shape, mask uint32) []entity.Entity
+}
+
+type collision struct {
+	entities       map[string]collidable
+	contacts       map[collisionPair]CollisionEvent
+	handlers       []func(CollisionEvent)
+	handlerLock    sync.RWMutex
+	remove         chan entity.Entity
+	add            chan entity.Entity
+	quit           chan interface{}
+	quitProcessing chan interface{}
+	runningLock    sync.Mutex
+	requirements   []string
+	interval       time.Duration
+	isRunning      bool
+}
+
+// NewCollision creates a new Collision system.
+func NewCollision() Collision {
+	c := collision{
+		entities:       make(map[string]collidable, 0),
+		contacts:       make(map[collisionPair]CollisionEvent, 0),
+		remove:         make(chan entity.Entity, 0),
+		add:            make(chan entity.Entity, 0),
+		quit:           make(chan interface{}),
+		quitProcessing: make(chan interface{}),
+		requirements:   []string{components.TypeTransform, components.TypeCollider},
+		interval:       (1000 / 144) * time.Millisecond,
+		isRunning:      false,
+	}
+
+	go func() {
+		for {
+			select {
+			case ent := <-c.add:
+				log.Printf("Adding %s to the collision system.\n", ent.ID())
+				c.addEntity(ent)
+			case ent := <-c.remove:
+				log.Printf("Removing %s from the collision system.\n", ent.ID())
+				c.removeEntity(ent)
+			case <-c.quit:
+				return
+			}
+		}
+	}()
+
+	return &c
+}
+
+// Type retrieves the type of system such as renderer, mover, etc.
+func (c *collision) Type() string {
+	return TypeCollision
+}
+
+// AddEntity adds an Entity to the system.  Each system will have a component requirement that must be met before the Entity can be added.
+func (c *collision) AddEntity(e entity.Entity) {
+	c.add <- e
+}
+
+// RemoveEntity removes an Entity from the system.
+func (c *collision) RemoveEntity(e entity.Entity) {
+	c.remove <- e
+}
+
+// IsRunning is useful to check if the collision system is processing entities.
+func (c *collision) IsRunning() bool {
+	defer c.runningLock.Unlock()
+	c.runningLock.Lock()
+	return c.isRunning
+}
+
+// Start will begin detecting collisions between the entities that have been added.
+func (c *collision) Start() {
+	defer c.runningLock.Unlock()
+	c.runningLock.Lock()
+
+	if c.isRunning {
+		return
+	}
+
+	c.isRunning = true
+
+	go func() {
+
+		tr := time.NewTimer(c.interval)
+		previousTime := time.Now().UnixNano()
+
+		for {
+			select {
+			case <-c.quitProcessing:
+				c.isRunning = false
+				return
+			case <-tr.C:
+				current := time.Now().UnixNano()
+				// Get the elapsed time in seconds
+				elapsed := float32((current - previousTime)) / 1000000000.0
+				previousTime = current
+
+				c.Process(elapsed)
+				processingTime := time.Now().UnixNano() - current
+
+				if processingTime > 0 {
+					tr.Reset(c.interval - time.Duration(processingTime))
+				} else {
+					tr.Reset(time.Nanosecond)
+				}
+			}
+		}
+	}()
+}
+
+// Stop will stop the collision system from checking any of its Entities.
+func (c *collision) Stop() {
+	defer c.runningLock.Unlock()
+	c.runningLock.Lock()
+	if c.isRunning {
+		c.quitProcessing <- true
+	}
+}
+
+// Terminate stops the collision system and releases all resources.  Once Terminate has been called, the system cannot be reused.
+func (c *collision) Terminate() {
+	defer c.runningLock.Unlock()
+	c.runningLock.Lock()
+	if c.isRunning {
+		c.quitProcessing <- true
+	}
+	c.quit <- true
+}
+
+// OnCollision registers a function called for every collision event.  Handlers are called after the system has finished processing so they may add or remove entities.
+func (c *collision) OnCollision(handler func(CollisionEvent)) {
+	c.handlerLock.Lock()
+	defer c.handlerLock.Unlock()
+	c.handlers = append(c.handlers, handler)
+}
+
+// Process tests every collider against the others and delivers the resulting events.
+func (c *collision) Process(elapsed float32) {
+	events := c.detect()
+
+	c.handlerLock.RLock()
+	handlers := c.handlers
+	c.handlerLock.RUnlock()
+
+	for _, event := range events {
+		for _, handler := range handlers {
+			handler(event)
+		}
+	}
+}
+
+// Overlap retrieves the entities whose colliders are on a layer in the mask and overlap a shape.
+func (c *collision) Overlap(shape geometry.Shape, mask uint32) []entity.Entity {
+	defer c.runningLock.Unlock()
+	c.runningLock.Lock()
+
+	bounds := geometry.Bounds(shape)
+	var found []entity.Entity
+	for _, ent := range c.entities {
+		if ent.Collider.Layer()&mask == 0 {
+			continue
+		}
+		world := ent.Collider.WorldShape(ent.Transform)
+		if !bounds.Intersects(geometry.Bounds(world)) {
+			continue
+		}
+		if geometry.Intersects(world, shape) {
+			found = append(found, ent.Entity)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].ID() < found[j].ID() })
+	return found
+}
+
+// detect finds the overlapping pairs and compares them to the previous update to build the enter, stay and exit events.
+func (c *collision) detect() []CollisionEvent {
+	defer c.runningLock.Unlock()
+	c.runningLock.Lock()
+
+	// Broad phase: sort the world bounds along the x axis and sweep, only keeping pairs whose bounds overlap.
+	proxies := make([]collisionProxy, 0, len(c.entities))
+	for _, ent := range c.entities {
+		shape := ent.Collider.WorldShape(ent.Transform)
+		proxies = append(proxies, collisionProxy{
+			collidable: ent,
+			data:       ent.Collider.Data(),
+			shape:      shape,
+			bounds:     geometry.Bounds(shape),
+		})
+	}
+	sort.Slice(proxies, func(i, j int) bool {
+		return proxies[i].bounds.Min.X() < proxies[j].bounds.Min.X()
+	})
+
+	current := make(map[collisionPair]CollisionEvent, len(c.contacts))
+	for i := range proxies {
+		a := &proxies[i]
+		for j := i + 1; j < len(proxies); j++ {
+			b := &proxies[j]
+			if b.bounds.Min.X() > a.bounds.Max.X() {
+				break
+			}
+			if !canCollide(a.data, b.data) || !a.bounds.Intersects(b.bounds) {
+				continue
+			}
+
+			// Narrow phase: an exact test of the two shapes, ordered by id so events are stable.
+			first, second := a, b
+			if second.Entity.ID() < first.Entity.ID() {
+				first, second = second, first
+			}
+			contact, hit := geometry.Penetration(first.shape, second.shape)
+			if !hit {
+				continue
+			}
+
+			isTrigger := first.data.IsTrigger || second.data.IsTrigger
+			if !isTrigger {
+				resolveContact(first.collidable, second.collidable, contact)
+			}
+
+			pair := collisionPair{first.Entity.ID(), second.Entity.ID()}
+			phase := CollisionEnter
+			if _, ok := c.contacts[pair]; ok {
+				phase = CollisionStay
+			}
+			current[pair] = CollisionEvent{
+				Phase:     phase,
+				A:         first.Entity,
+				B:         second.Entity,
+				Contact:   contact,
+				IsTrigger: isTrigger,
+			}
+		}
+	}
+
+	events := make([]CollisionEvent, 0, len(current))
+	for pair, event := range c.contacts {
+		if _, ok := current[pair]; !ok {
+			event.Phase = CollisionExit
+			events = append(events, event)
+		}
+	}
+	for _, event := range current {
+		events = append(events, event)
+	}
+	c.contacts = current
+
+	// Exits are delivered first so a handler sees a contact end before a new one begins.
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].Phase != events[j].Phase {
+			return events[i].Phase > events[j].Phase
+		}
+		if events[i].A.ID() != events[j].A.ID() {
+			return events[i].A.ID() < events[j].A.ID()
+		}
+		return events[i].B.ID() < events[j].B.ID()
+	})
+	return events
+}
+
+// addEntity adds an Entity to the system.  Each system will have a component requirement that must be met before the Entity can be added.
+func (c *collision) addEntity(e entity.Entity) {
+	transform, isTransform := e.Component(components.TypeTransform).(components.Transform)
+	collider, isCollider := e.Component(components.TypeCollider).(components.Collider)
+
+	if isTransform && isCollider {
+		col := collidable{
+			Entity:    e,
+			Transform: transform,
+			Collider:  collider,
+		}
+		if velocity, isVelocity := e.Component(components.TypeVelocity).(components.Velocity); isVelocity {
+			col.Velocity = velocity
+		}
+		if body, isBody := e.Component(components.TypeRigidBody).(components.RigidBody); isBody {
+			col.RigidBody = body
+		}
+		defer c.runningLock.Unlock()
+		c.runningLock.Lock()
+		c.entities[e.ID()] = col
+	}
+}
+
+// removeEntity removes an Entity from the system.  Any contacts it had end on the next update.
+func (c *collision) removeEntity(e entity.Entity) {
+	defer c.runningLock.Unlock()
+	c.runningLock.Lock()
+
+	delete(c.entities, e.ID())
+}
+
+// resolveContact separates two solid colliders and applies the impulses of their collision.  Entities without a dynamic rigid body are treated as having infinite mass.  Only linear motion is resolved since contacts do not carry a contact point.
+func resolveContact(a, b collidable, contact geometry.Contact) {
+	invA, invB := inverseMass(a), inverseMass(b)
+	invTotal := invA + invB
+	if invTotal == 0 {
+		return
+	}
+	n := contact.Normal
+
+	// Push the bodies apart in proportion to their inverse mass, leaving a little overlap so resting contacts stay stable.
+	const slop, percent = 0.005, 0.8
+	if depth := contact.Depth - slop; depth > 0 {
+		correction := n.Mul(depth / invTotal * percent)
+		if invA > 0 {
+			a.Transform.Translate(correction.Mul(invA))
+		}
+		if invB > 0 {
+			b.Transform.Translate(correction.Mul(-invB))
+		}
+	}
+
+	velA, velB := linearVelocity(a), linearVelocity(b)
+	relative := velA.Sub(velB)
+	approach := relative.Dot(n)
+	if approach >= 0 {
+		return
+	}
+
+	restitution, friction := contactMaterial(a, b)
+	j := -(1 + restitution) * approach / invTotal
+	impulse := n.Mul(j)
+
+	// Friction opposes the sliding velocity and is limited by the normal impulse.
+	tangent := relative.Sub(n.Mul(approach))
+	if tangent.Len() > 1e-6 {
+		tangent = tangent.Normalize()
+		jt := -relative.Dot(tangent) / invTotal
+		if limit := j * friction; jt > limit {
+			jt = limit
+		} else if jt < -limit {
+			jt = -limit
+		}
+		impulse = impulse.Add(tangent.Mul(jt))
+	}
+
+	if invA > 0 && a.Velocity != nil {
+		setLinearVelocity(a, velA.Add(impulse.Mul(invA)))
+	}
+	if invB > 0 && b.Velocity != nil {
+		setLinearVelocity(b, velB.Sub(impulse.Mul(invB)))
+	}
+}
+
+// inverseMass retrieves the inverse mass of a collidable, which is 0 unless it is a dynamic rigid body with a velocity.
+func inverseMass(c collidable) float32 {
+	if c.RigidBody == nil || c.Velocity == nil {
+		return 0
+	}
+	return c.RigidBody.InverseMass()
+}
+
+// linearVelocity retrieves the world velocity of a collidable, which is 0 if it has no velocity or is static.  Velocities relative to a parent are treated as relative to the world.
+func linearVelocity(c collidable) mgl32.Vec3 {
+	if c.Velocity == nil || (c.RigidBody != nil && c.RigidBody.Kind() == components.BodyStatic) {
+		return mgl32.Vec3{}
+	}
+	return velocityFrame(c).Rotate(c.Velocity.Translational())
+}
+
+// setLinearVelocity replaces the world velocity of a collidable, converting it into the velocity's reference frame.
+func setLinearVelocity(c collidable, v mgl32.Vec3) {
+	c.Velocity.SetTranslational(velocityFrame(c).Conjugate().Rotate(v))
+}
+
+// velocityFrame retrieves the rotation from a collidable's velocity frame into world space.
+func velocityFrame(c collidable) mgl32.Quat {
+	if c.Velocity.Frame() == components.FrameLocal {
+		return c.Transform.Orientation()
+	}
+	return mgl32.QuatIdent()
+}
+
+// contactMaterial combines the restitution and friction of two collidables.  The bounciest restitution is used and friction is the geometric mean.  Entities without a rigid body use the defaults of a new body.
+func contactMaterial(a, b collidable) (restitution, friction float32) {
+	da, db := components.NewRigidBodyData(), components.NewRigidBodyData()
+	if a.RigidBody != nil {
+		da = a.RigidBody.Data()
+	}
+	if b.RigidBody != nil {
+		db = b.RigidBody.Data()
+	}
+	restitution = da.Restitution
+	if db.Restitution > restitution {
+		restitution = db.Restitution
+	}
+	return restitution, float32(math.Sqrt(float64(da.Friction * db.Friction)))
+}
+
+// canCollide returns true if each collider is on a layer the other collides with.
+func canCollide(a, b components.ColliderData) bool {
+	return a.Layer&b.Mask != 0 && b.Layer&a.Mask != 0
+}
+
+type collidable struct {
+	Entity    entity.Entity
+	Transform components.Transform
+	Collider  components.Collider
+	Velocity  components.Velocity
+	RigidBody components.RigidBody
+}
+
+type collisionProxy struct {
+	collidable
+	data   components.ColliderData
+	shape  geometry.Shape
+	bounds geometry.AABB
+}
+
+type collisionPair struct {
+	a string
+	b string
+}
@@ -0,0 +1,224 @@
+package systems
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+
+	"github.com/Ariemeth/quantum-pulse/components"
+	"github.com/Ariemeth/quantum-pulse/entity"
+	"github.com/Ariemeth/quantum-pulse/geometry"
+)
+
+// newCollider creates an entity with a unit sphere collider at a position.
+func newCollider(id string, position mgl32.Vec3, layer, mask uint32) (entity.Entity, components.Transform) {
+	transform := components.NewTransform()
+	transform.SetTranslation(position)
+	cd := components.NewColliderData(components.ShapeSphere)
+	cd.Radius = 1
+	cd.Layer, cd.Mask = layer, mask
+
+	e := entity.NewEntity(id)
+	e.AddComponent(transform)
+	e.AddComponent(components.NewCollider(cd))
+	return e, transform
+}
+
+// recordEvents registers a handler describing each event as its phase and pair of entities.
+func recordEvents(c Collision) *[]string {
+	events := make([]string, 0)
+	c.OnCollision(func(event CollisionEvent) {
+		events = append(events, fmt.Sprintf("%s %s %s", phaseNames[event.Phase], event.A.ID(), event.B.ID()))
+	})
+	return &events
+}
+
+var phaseNames = map[CollisionPhase]string{
+	CollisionEnter: "enter",
+	CollisionStay:  "stay",
+	CollisionExit:  "exit",
+}
+
+func equalStrings(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestCanCollide(t *testing.T) {
+	tests := []struct {
+		name          string
+		layerA, maskA uint32
+		layerB, maskB uint32
+		collide       bool
+	}{
+		{"defaults", components.DefaultCollisionLayer, components.AllCollisionLayers, components.DefaultCollisionLayer, components.AllCollisionLayers, true},
+		{"each in the other's mask", 1, 2, 2, 1, true},
+		{"only a wants b", 1, 2, 2, 4, false},
+		{"only b wants a", 1, 4, 2, 1, false},
+		{"shared layer", 3, 2, 6, 1, true},
+		{"empty mask", 1, 0, 1, components.AllCollisionLayers, false},
+	}
+	for _, test := range tests {
+		a := components.ColliderData{Layer: test.layerA, Mask: test.maskA}
+		b := components.ColliderData{Layer: test.layerB, Mask: test.maskB}
+		if got := canCollide(a, b); got != test.collide {
+			t.Errorf("%s: canCollide = %v, want %v", test.name, got, test.collide)
+		}
+		if got := canCollide(b, a); got != test.collide {
+			t.Errorf("%s: canCollide reversed = %v, want %v", test.name, got, test.collide)
+		}
+	}
+}
+
+func TestCollisionLayers(t *testing.T) {
+	c := NewCollision()
+	defer c.Terminate()
+	events := recordEvents(c)
+
+	// Every collider overlaps at the origin but only a and b collide with each other's layer.
+	a, _ := newCollider("a", mgl32.Vec3{}, 1, 2)
+	b, _ := newCollider("b", mgl32.Vec3{0.5, 0, 0}, 2, 1)
+	other, _ := newCollider("c", mgl32.Vec3{0, 0.5, 0}, 4, components.AllCollisionLayers)
+	for _, e := range []entity.Entity{a, b, other} {
+		c.(*collision).addEntity(e)
+	}
+
+	c.Process(0)
+	if want := []string{"enter a b"}; !equalStrings(*events, want) {
+		t.Errorf("events = %v, want %v", *events, want)
+	}
+
+	tests := []struct {
+		mask uint32
+		want []string
+	}{
+		{1, []string{"a"}},
+		{2 | 4, []string{"b", "c"}},
+		{8, nil},
+		{components.AllCollisionLayers, []string{"a", "b", "c"}},
+	}
+	for _, test := range tests {
+		found := c.Overlap(geometry.Sphere{Radius: 0.1}, test.mask)
+		ids := make([]string, 0, len(found))
+		for _, e := range found {
+			ids = append(ids, e.ID())
+		}
+		if !equalStrings(ids, test.want) {
+			t.Errorf("Overlap with mask %b = %v, want %v", test.mask, ids, test.want)
+		}
+	}
+}
+
+func TestCollisionEvents(t *testing.T) {
+	c := NewCollision()
+	defer c.Terminate()
+	events := recordEvents(c)
+
+	all := components.AllCollisionLayers
+	a, _ := newCollider("a", mgl32.Vec3{}, 1, all)
+	b, bt := newCollider("b", mgl32.Vec3{1.5, 0, 0}, 1, all)
+	far, _ := newCollider("c", mgl32.Vec3{10, 0, 0}, 1, all)
+	for _, e := range []entity.Entity{b, far, a} {
+		c.(*collision).addEntity(e)
+	}
+
+	tests := []struct {
+		name   string
+		change func()
+		want   []string
+	}{
+		{"first overlap", func() {}, []string{"enter a b"}},
+		{"still overlapping", func() {}, []string{"stay a b"}},
+		{"exits come before enters", func() { bt.SetTranslation(mgl32.Vec3{9, 0, 0}) }, []string{"exit a b", "enter b c"}},
+		{"the new contact stays", func() {}, []string{"stay b c"}},
+		{"removing an entity ends its contacts", func() { c.(*collision).removeEntity(far) }, []string{"exit b c"}},
+		{"no contacts", func() {}, []string{}},
+	}
+	for _, test := range tests {
+		*events = (*events)[:0]
+		test.change()
+		c.Process(0.1)
+		if !equalStrings(*events, test.want) {
+			t.Errorf("%s: events = %v, want %v", test.name, *events, test.want)
+		}
+	}
+}
+
+// near returns true if every component of two vectors is within 1e-4 of each other.
+func near(a, b mgl32.Vec3) bool {
+	for i := range a {
+		if d := a[i] - b[i]; d > 1e-4 || d < -1e-4 {
+			return false
+		}
+	}
+	return true
+}
+
+// body describes one side of a contact.  An empty kind leaves out the rigid body.
+type body struct {
+	kind        components.BodyKind
+	mass        float32
+	restitution float32
+	friction    float32
+	velocity    mgl32.Vec3
+}
+
+func (b body) collidable() collidable {
+	c := collidable{Transform: components.NewTransform(), Velocity: components.NewVelocity()}
+	c.Velocity.SetTranslational(b.velocity)
+	if b.kind != "" {
+		rbd := components.NewRigidBodyData()
+		rbd.Kind, rbd.Mass, rbd.Restitution, rbd.Friction = b.kind, b.mass, b.restitution, b.friction
+		c.RigidBody = components.NewRigidBody(rbd)
+	}
+	return c
+}
+
+func TestResolveContact(t *testing.T) {
+	// The normal points from b to a, so a moving along -X approaches b.
+	normal := mgl32.Vec3{1, 0, 0}
+	dynamic, static, kinematic := components.BodyDynamic, components.BodyStatic, components.BodyKinematic
+	tests := []struct {
+		name         string
+		a, b         body
+		depth        float32
+		wantA, wantB mgl32.Vec3
+		pushA        float32
+	}{
+		{"elastic bounce swaps equal velocities", body{dynamic, 1, 1, 0, mgl32.Vec3{-2, 0, 0}}, body{dynamic, 1, 1, 0, mgl32.Vec3{}}, 0, mgl32.Vec3{}, mgl32.Vec3{-2, 0, 0}, 0},
+		{"inelastic stop shares the momentum", body{dynamic, 1, 0, 0, mgl32.Vec3{-3, 0, 0}}, body{dynamic, 2, 0, 0, mgl32.Vec3{}}, 0, mgl32.Vec3{-1, 0, 0}, mgl32.Vec3{-1, 0, 0}, 0},
+		{"the bounciest restitution is used", body{dynamic, 1, 0, 0, mgl32.Vec3{-2, 0, 0}}, body{dynamic, 1, 1, 0, mgl32.Vec3{}}, 0, mgl32.Vec3{}, mgl32.Vec3{-2, 0, 0}, 0},
+		{"static wall reflects and is not moved", body{dynamic, 1, 1, 0, mgl32.Vec3{-2, 0, 0}}, body{static, 1, 0, 0, mgl32.Vec3{0, 3, 0}}, 0, mgl32.Vec3{2, 0, 0}, mgl32.Vec3{0, 3, 0}, 0},
+		{"static wall pushes out of the overlap", body{dynamic, 1, 0, 0, mgl32.Vec3{-2, 0, 0}}, body{static, 1, 0, 0, mgl32.Vec3{}}, 0.505, mgl32.Vec3{}, mgl32.Vec3{}, 0.4},
+		{"kinematic body pushes without being pushed", body{dynamic, 1, 0.5, 0, mgl32.Vec3{}}, body{kinematic, 1, 0, 0, mgl32.Vec3{1, 0, 0}}, 0, mgl32.Vec3{1.5, 0, 0}, mgl32.Vec3{1, 0, 0}, 0},
+		{"separating bodies are left alone", body{dynamic, 1, 1, 0.5, mgl32.Vec3{2, 1, 0}}, body{dynamic, 1, 1, 0.5, mgl32.Vec3{}}, 0, mgl32.Vec3{2, 1, 0}, mgl32.Vec3{}, 0},
+		{"without rigid bodies nothing moves", body{"", 0, 0, 0, mgl32.Vec3{-2, 0, 0}}, body{"", 0, 0, 0, mgl32.Vec3{}}, 0.5, mgl32.Vec3{-2, 0, 0}, mgl32.Vec3{}, 0},
+		{"friction is clamped by the normal impulse", body{dynamic, 1, 0, 0.5, mgl32.Vec3{-1, 4, 0}}, body{static, 1, 0, 0.5, mgl32.Vec3{}}, 0, mgl32.Vec3{0, 3.5, 0}, mgl32.Vec3{}, 0},
+		{"friction within its limit stops sliding", body{dynamic, 1, 0, 0.5, mgl32.Vec3{-4, 1, 0}}, body{static, 1, 0, 0.5, mgl32.Vec3{}}, 0, mgl32.Vec3{}, mgl32.Vec3{}, 0},
+		{"no friction keeps sliding", body{dynamic, 1, 0, 0, mgl32.Vec3{-1, 4, 0}}, body{static, 1, 0, 0.5, mgl32.Vec3{}}, 0, mgl32.Vec3{0, 4, 0}, mgl32.Vec3{}, 0},
+	}
+	for _, test := range tests {
+		a, b := test.a.collidable(), test.b.collidable()
+		resolveContact(a, b, geometry.Contact{Normal: normal, Depth: test.depth})
+		if got := a.Velocity.Translational(); !near(got, test.wantA) {
+			t.Errorf("%s: a's velocity = %v, want %v", test.name, got, test.wantA)
+		}
+		if got := b.Velocity.Translational(); !near(got, test.wantB) {
+			t.Errorf("%s: b's velocity = %v, want %v", test.name, got, test.wantB)
+		}
+		if got := a.Transform.Translation(); !near(got, mgl32.Vec3{test.pushA, 0, 0}) {
+			t.Errorf("%s: a moved to %v, want %v", test.name, got, mgl32.Vec3{test.pushA, 0, 0})
+		}
+		if got := b.Transform.Translation(); got != (mgl32.Vec3{}) {
+			t.Errorf("%s: b moved to %v, want it to stay put", test.name, got)
+		}
+	}
+}
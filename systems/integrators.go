@@ -0,0 +1,64 @@
+package systems
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+
+	"github.com/Ariemeth/quantum-pulse/components"
+)
+
+// accelerationFunc retrieves the acceleration of a body at a position and velocity.
+type accelerationFunc func(position, velocity mgl32.Vec3) mgl32.Vec3
+
+// integrate advances a position and velocity over a time step using a numerical method.  Unknown methods fall back to semi-implicit Euler.
+func integrate(method components.IntegrationMethod, p, v mgl32.Vec3, dt float32, accel accelerationFunc) (mgl32.Vec3, mgl32.Vec3) {
+	switch method {
+	case components.IntegrateEuler:
+		a := accel(p, v)
+		return p.Add(v.Mul(dt)), v.Add(a.Mul(dt))
+
+	case components.IntegrateVerlet:
+		a0 := accel(p, v)
+		next := p.Add(v.Mul(dt)).Add(a0.Mul(dt * dt / 2))
+		a1 := accel(next, v.Add(a0.Mul(dt)))
+		return next, v.Add(a0.Add(a1).Mul(dt / 2))
+
+	case components.IntegrateRK4:
+		k1v := accel(p, v)
+		k1p := v
+
+		k2v := accel(p.Add(k1p.Mul(dt/2)), v.Add(k1v.Mul(dt/2)))
+		k2p := v.Add(k1v.Mul(dt / 2))
+
+		k3v := accel(p.Add(k2p.Mul(dt/2)), v.Add(k2v.Mul(dt/2)))
+		k3p := v.Add(k2v.Mul(dt / 2))
+
+		k4v := accel(p.Add(k3p.Mul(dt)), v.Add(k3v.Mul(dt)))
+		k4p := v.Add(k3v.Mul(dt))
+
+		dp := k1p.Add(k2p.Mul(2)).Add(k3p.Mul(2)).Add(k4p).Mul(dt / 6)
+		dv := k1v.Add(k2v.Mul(2)).Add(k3v.Mul(2)).Add(k4v).Mul(dt / 6)
+		return p.Add(dp), v.Add(dv)
+
+	default:
+		next := v.Add(accel(p, v).Mul(dt))
+		return p.Add(next.Mul(dt)), next
+	}
+}
+
+// ForceField retrieves the force acting on a body at a position and velocity.  Bodies without a rigid body have a mass of 1.
+type ForceField func(position, velocity mgl32.Vec3, mass float32) mgl32.Vec3
+
+// PointGravity creates a force field pulling bodies towards a point with a strength that falls off with the square of the distance.  Strength is the gravitational parameter of the attracting body, so a body in a circular orbit at radius r moves at sqrt(strength / r).
+func PointGravity(center mgl32.Vec3, strength float32) ForceField {
+	return func(position, velocity mgl32.Vec3, mass float32) mgl32.Vec3 {
+		toCenter := center.Sub(position)
+		distSq := toCenter.Dot(toCenter)
+		if distSq < 1e-8 {
+			return mgl32.Vec3{}
+		}
+		dist := float32(math.Sqrt(float64(distSq)))
+		return toCenter.Mul(strength * mass / (distSq * dist))
+	}
+}
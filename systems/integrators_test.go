@@ -0,0 +1,82 @@
+package systems
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+
+	"github.com/Ariemeth/quantum-pulse/components"
+)
+
+// conservative is a force acting on a unit mass that conserves energy, along with that energy.
+type conservative struct {
+	name   string
+	p, v   mgl32.Vec3
+	dt     float32
+	steps  int
+	accel  accelerationFunc
+	energy func(p, v mgl32.Vec3) float32
+	// eulerGain is the least relative energy explicit Euler gains over the run.
+	eulerGain float32
+}
+
+var conservativeSystems = []conservative{
+	{"spring",
+		mgl32.Vec3{1, 0, 0}, mgl32.Vec3{}, 0.1, 1000,
+		func(p, v mgl32.Vec3) mgl32.Vec3 { return p.Mul(-1) },
+		func(p, v mgl32.Vec3) float32 { return (v.Dot(v) + p.Dot(p)) / 2 },
+		1},
+	{"circular orbit",
+		mgl32.Vec3{1, 0, 0}, mgl32.Vec3{0, 1, 0}, 0.01, 5000,
+		func(p, v mgl32.Vec3) mgl32.Vec3 { return PointGravity(mgl32.Vec3{}, 1)(p, v, 1) },
+		func(p, v mgl32.Vec3) float32 { return v.Dot(v)/2 - 1/p.Len() },
+		0.25},
+}
+
+// drift retrieves the largest change in energy relative to the starting energy and the change at the end of the run.
+func drift(method components.IntegrationMethod, s conservative) (largest, final float32) {
+	e0 := s.energy(s.p, s.v)
+	p, v := s.p, s.v
+	for i := 0; i < s.steps; i++ {
+		p, v = integrate(method, p, v, s.dt, s.accel)
+		final = (s.energy(p, v) - e0) / float32(math.Abs(float64(e0)))
+		if d := float32(math.Abs(float64(final))); d > largest {
+			largest = d
+		}
+	}
+	return largest, final
+}
+
+func TestIntegratorEnergy(t *testing.T) {
+	tests := []struct {
+		method components.IntegrationMethod
+		// bound is the largest relative energy change allowed over the run.
+		bound float32
+	}{
+		{components.IntegrateSemiImplicitEuler, 0.06},
+		{components.IntegrateVerlet, 0.01},
+		{components.IntegrateRK4, 0.001},
+	}
+	for _, s := range conservativeSystems {
+		for _, test := range tests {
+			if largest, _ := drift(test.method, s); largest > test.bound {
+				t.Errorf("%s %s: energy drifted by %v, want at most %v", s.name, test.method, largest, test.bound)
+			}
+		}
+
+		// Explicit Euler gains energy every step so it keeps growing instead of staying bounded.
+		if _, final := drift(components.IntegrateEuler, s); final < s.eulerGain {
+			t.Errorf("%s %s: energy changed by %v, want a gain of at least %v", s.name, components.IntegrateEuler, final, s.eulerGain)
+		}
+	}
+}
+
+func TestIntegrateUnknownMethod(t *testing.T) {
+	s := conservativeSystems[0]
+	p, v := integrate("leapfrog", s.p, s.v, s.dt, s.accel)
+	wantP, wantV := integrate(components.IntegrateSemiImplicitEuler, s.p, s.v, s.dt, s.accel)
+	if p != wantP || v != wantV {
+		t.Errorf("unknown method gave %v, %v, want semi-implicit Euler's %v, %v", p, v, wantP, wantV)
+	}
+}
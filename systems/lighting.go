@@ -0,0 +1,199 @@
+package systems
+
+import (
+	"math"
+	"sort"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+
+	"github.com/Ariemeth/quantum-pulse/components"
+	"github.com/Ariemeth/quantum-pulse/geometry"
+	am "github.com/Ariemeth/quantum-pulse/resources"
+)
+
+// defaultAmbientLight is the color of the light reaching every surface of a lit mesh until it is changed.
+var defaultAmbientLight = mgl32.Vec3{0.2, 0.2, 0.2}
+
+// lightSource is a light the renderer lights meshes with.  Point and spot lights shine from the translation of their transform and every light's direction is turned by it.
+type lightSource struct {
+	Light     components.Light
+	Transform components.Transform
+}
+
+// lightInfo is a light in world space ready to be uploaded to a lit shader.
+type lightInfo struct {
+	kind        float32
+	position    mgl32.Vec3
+	color       mgl32.Vec3
+	direction   mgl32.Vec3
+	attenuation mgl32.Vec4
+	cone        mgl32.Vec2
+}
+
+// info retrieves the light in world space.
+func (ls *lightSource) info() lightInfo {
+	ld := ls.Light.Data()
+	direction := ld.Direction
+	var position mgl32.Vec3
+	if ls.Transform != nil {
+		position = ls.Transform.Translation()
+		direction = ls.Transform.Data().Mul4x1(direction.Vec4(0)).Vec3()
+	}
+	if direction.Len() > 0 {
+		direction = direction.Normalize()
+	}
+
+	info := lightInfo{
+		position:    position,
+		color:       ld.Color.Mul(ld.Intensity),
+		direction:   direction,
+		attenuation: mgl32.Vec4{ld.Attenuation.Constant, ld.Attenuation.Linear, ld.Attenuation.Quadratic, ld.Range},
+	}
+	switch ld.Kind {
+	case components.LightDirectional:
+		info.kind = am.LightTypeDirectional
+	case components.LightSpot:
+		info.kind = am.LightTypeSpot
+		info.cone = spotCone(ld.InnerAngle, ld.OuterAngle)
+	default:
+		info.kind = am.LightTypePoint
+	}
+	return info
+}
+
+// reaches returns true if the light can shine on any part of the sphere.  Directional lights and lights without a range shine everywhere.
+func (info *lightInfo) reaches(bounds geometry.Sphere) bool {
+	if info.kind == am.LightTypeDirectional || info.attenuation.W() <= 0 {
+		return true
+	}
+	return info.position.Sub(bounds.Center).Len()-bounds.Radius < info.attenuation.W()
+}
+
+// spotCone retrieves the cosines of a spot light's inner and outer half angles in degrees.  The inner cosine is kept above the outer so the edge of the cone fades smoothly.
+func spotCone(inner, outer float32) mgl32.Vec2 {
+	if inner > outer {
+		inner = outer
+	}
+	cosInner := float32(math.Cos(float64(mgl32.DegToRad(inner))))
+	cosOuter := float32(math.Cos(float64(mgl32.DegToRad(outer))))
+	if cosInner-cosOuter < 0.0001 {
+		cosInner = cosOuter + 0.0001
+	}
+	return mgl32.Vec2{cosInner, cosOuter}
+}
+
+// lightSet holds the uniforms of the lights uploaded for a draw.  It is comparable so draws lit by the same lights skip uploading them again.
+type lightSet struct {
+	count       int32
+	position    [am.MaxLights * 4]float32
+	color       [am.MaxLights * 3]float32
+	direction   [am.MaxLights * 3]float32
+	attenuation [am.MaxLights * 4]float32
+	cone        [am.MaxLights * 2]float32
+}
+
+// add appends a light to the set.
+func (set *lightSet) add(info lightInfo) {
+	i := int(set.count)
+	copy(set.position[i*4:], []float32{info.position.X(), info.position.Y(), info.position.Z(), info.kind})
+	copy(set.color[i*3:], info.color[:])
+	copy(set.direction[i*3:], info.direction[:])
+	copy(set.attenuation[i*4:], info.attenuation[:])
+	copy(set.cone[i*2:], info.cone[:])
+	set.count++
+}
+
+// upload sets the light uniforms of a shader.
+func (set *lightSet) upload(shader am.Shader) {
+	gl.Uniform1i(shader.GetUniformLoc(am.LightCountUniform), set.count)
+	if set.count == 0 {
+		return
+	}
+	gl.Uniform4fv(shader.GetUniformLoc(am.LightPositionUniform), set.count, &set.position[0])
+	gl.Uniform3fv(shader.GetUniformLoc(am.LightColorUniform), set.count, &set.color[0])
+	gl.Uniform3fv(shader.GetUniformLoc(am.LightDirectionUniform), set.count, &set.direction[0])
+	gl.Uniform4fv(shader.GetUniformLoc(am.LightAttenuationUniform), set.count, &set.attenuation[0])
+	gl.Uniform2fv(shader.GetUniformLoc(am.LightConeUniform), set.count, &set.cone[0])
+}
+
+// lighting is the lights of a frame.
+type lighting struct {
+	lights  []lightInfo
+	ambient mgl32.Vec3
+	// nearby is reused to choose the lights of each draw.
+	nearby []nearbyLight
+}
+
+// nearbyLight is a light that reaches a draw along with how far away it is.
+type nearbyLight struct {
+	index    int
+	distance float32
+}
+
+// choose retrieves the lights that shine on a sphere.  Directional lights come first and then the nearest point and spot lights, up to the most a shader can hold.
+func (l *lighting) choose(bounds geometry.Sphere) lightSet {
+	set := lightSet{}
+	nearby := l.nearby[:0]
+	for i := range l.lights {
+		info := &l.lights[i]
+		if !info.reaches(bounds) {
+			continue
+		}
+		distance := float32(0)
+		if info.kind != am.LightTypeDirectional {
+			distance = info.position.Sub(bounds.Center).Len()
+		}
+		nearby = append(nearby, nearbyLight{index: i, distance: distance})
+	}
+	sort.SliceStable(nearby, func(i, j int) bool {
+		di, dj := l.lights[nearby[i].index].kind == am.LightTypeDirectional, l.lights[nearby[j].index].kind == am.LightTypeDirectional
+		if di != dj {
+			return di
+		}
+		return nearby[i].distance < nearby[j].distance
+	})
+	l.nearby = nearby
+
+	for _, n := range nearby {
+		if set.count == am.MaxLights {
+			break
+		}
+		set.add(l.lights[n.index])
+	}
+	return set
+}
+
+// gatherLights collects the enabled lights of the frame in world space.  The caller is expected to hold the running lock.
+func (r *renderer) gatherLights() *lighting {
+	l := lighting{
+		lights:  make([]lightInfo, 0, len(r.lights)),
+		ambient: r.ambientLight(),
+	}
+	// Lights are kept in the same order every frame so draws lit by the same lights match the set already uploaded.
+	ids := make([]string, 0, len(r.lights))
+	for id := range r.lights {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if ls := r.lights[id]; ls.Light.Enabled() {
+			l.lights = append(l.lights, ls.info())
+		}
+	}
+	return &l
+}
+
+// SetAmbientLight sets the color of the light reaching every surface of a lit mesh.
+func (r *renderer) SetAmbientLight(color mgl32.Vec3) {
+	r.statsLock.Lock()
+	defer r.statsLock.Unlock()
+	r.ambient = color
+}
+
+// ambientLight retrieves the color of the light reaching every surface of a lit mesh.
+func (r *renderer) ambientLight() mgl32.Vec3 {
+	r.statsLock.RLock()
+	defer r.statsLock.RUnlock()
+	return r.ambient
+}
@@ -0,0 +1,289 @@
+package systems
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"sort"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+
+	"github.com/Ariemeth/quantum-pulse/components"
+	am "github.com/Ariemeth/quantum-pulse/resources"
+)
+
+// materialState is a material resolved into what the renderer binds to draw an entity.  Entities without a material have one resolved from their mesh alone.
+type materialState struct {
+	// version is the version of the material component it was resolved from.
+	version uint64
+	// key identifies the material's settings so entities drawn the same way can be drawn as instances.  It is 0 for entities without a material.
+	key      uint64
+	pass     components.RenderPass
+	state    components.RenderState
+	texture  string
+	uniforms map[string][]float32
+	textures []samplerTexture
+}
+
+// samplerTexture is a texture bound to a sampler uniform other than the mesh's texture.
+type samplerTexture struct {
+	sampler string
+	unit    int32
+	texture uint32
+}
+
+// hasSampler returns true if the material binds a texture to the sampler.
+func (ms *materialState) hasSampler(sampler string) bool {
+	for _, t := range ms.textures {
+		if t.sampler == sampler {
+			return true
+		}
+	}
+	return false
+}
+
+// materialKey retrieves a hash of a material's settings.
+func materialKey(md components.MaterialData) uint64 {
+	data, err := json.Marshal(md)
+	if err != nil {
+		return 0
+	}
+	h := fnv.New64a()
+	h.Write(data)
+	return h.Sum64()
+}
+
+// loadMaterial loads the shader and textures an entity is drawn with from its material, falling back to its mesh for whatever the material leaves empty.  An entity whose shader changes gets a new vertex array for it.  The caller is expected to be on the main thread.
+func (r *renderer) loadMaterial(ent *renderable) error {
+	md := ent.Mesh.Data()
+	var mat components.MaterialData
+	ms := materialState{}
+	if ent.Material != nil {
+		ms.version = ent.Material.Version()
+		mat = ent.Material.Data()
+	}
+
+	vert, frag := md.VertShaderFile, md.FragShaderFile
+	if mat.VertShaderFile != "" {
+		vert = mat.VertShaderFile
+	}
+	if mat.FragShaderFile != "" {
+		frag = mat.FragShaderFile
+	}
+	shader, err := r.assets.Shaders().LoadProgramFromFile(vert, frag, false)
+	if err != nil {
+		return fmt.Errorf("unable to load shaders %s,%s", vert, frag)
+	}
+
+	ms.pass = md.Pass
+	if mat.Pass != nil {
+		ms.pass = *mat.Pass
+	}
+	ms.state = mat.RenderState
+	ms.uniforms = mat.Uniforms
+	if !mat.IsEmpty() {
+		ms.key = materialKey(mat)
+	}
+
+	// Load the textures.  Textures already loaded, such as render targets, are shared.
+	ms.texture = md.TextureFile
+	if file, ok := mat.Textures[am.TextureUniform]; ok {
+		ms.texture = file
+	}
+	if texFile := textureFile(&ms, ent.Sprite); texFile != "" {
+		texture, err := r.loadTexture(texFile)
+		if err != nil {
+			return err
+		}
+		ent.TextureID = texture
+	}
+	samplers := make([]string, 0, len(mat.Textures))
+	for sampler := range mat.Textures {
+		if sampler != am.TextureUniform {
+			samplers = append(samplers, sampler)
+		}
+	}
+	sort.Strings(samplers)
+	for i, sampler := range samplers {
+		texture, err := r.loadTexture(mat.Textures[sampler])
+		if err != nil {
+			return err
+		}
+		ms.textures = append(ms.textures, samplerTexture{sampler: sampler, unit: int32(i + 1), texture: texture})
+	}
+
+	if ent.Shader == nil || shader.ProgramID() != ent.ProgramID {
+		if ent.VAO != 0 {
+			gl.DeleteVertexArrays(1, &ent.VAO)
+		}
+		ent.Shader = shader
+		ent.ProgramID = shader.ProgramID()
+		ent.VAO = shader.CreateVAO(ent.Mesh)
+	}
+	ent.material = &ms
+	return nil
+}
+
+// loadTexture retrieves a texture, loading it the first time it is used.
+func (r *renderer) loadTexture(file string) (uint32, error) {
+	if texture, isLoaded := r.assets.Textures().GetTexture(file); isLoaded {
+		return texture, nil
+	}
+	texture, err := r.assets.Textures().LoadTexture(file, file)
+	if err != nil {
+		return 0, fmt.Errorf("unable to load texture:%s", file)
+	}
+	return texture, nil
+}
+
+// materialBindings tracks the material uniforms set on every shader program.  Uniforms keep their values between draws and frames, so uniforms a material sets are put back to their values from the shader when the next material drawn with the program does not set them.  It must only be used on the main thread.
+type materialBindings struct {
+	applied  map[uint32]*materialState
+	defaults map[uint32]map[string][]float32
+	// warned records the uniforms of each program a material could not set, which are only logged once.
+	warned map[uint32]map[string]bool
+}
+
+func newMaterialBindings() *materialBindings {
+	return &materialBindings{
+		applied:  make(map[uint32]*materialState),
+		defaults: make(map[uint32]map[string][]float32),
+		warned:   make(map[uint32]map[string]bool),
+	}
+}
+
+// apply sets the uniforms of a material on a shader unless a material with the same settings was the last applied to it.
+func (b *materialBindings) apply(shader am.Shader, ms *materialState) {
+	program := shader.ProgramID()
+	prev := b.applied[program]
+	if (prev == nil && ms.key == 0) || (prev != nil && prev.key == ms.key) {
+		return
+	}
+
+	if prev != nil {
+		for name := range prev.uniforms {
+			if _, set := ms.uniforms[name]; !set {
+				if values, ok := b.defaults[program][name]; ok {
+					shader.SetValues(name, values)
+				}
+			}
+		}
+		for _, t := range prev.textures {
+			if !ms.hasSampler(t.sampler) {
+				shader.SetSampler(t.sampler, 0)
+			}
+		}
+	}
+
+	for name, values := range ms.uniforms {
+		if _, saved := b.defaults[program][name]; !saved {
+			defaults, err := shader.UniformValues(name)
+			if err != nil {
+				b.warn(shader, name, err)
+				continue
+			}
+			if b.defaults[program] == nil {
+				b.defaults[program] = make(map[string][]float32)
+			}
+			b.defaults[program][name] = defaults
+		}
+		if err := shader.SetValues(name, values); err != nil {
+			b.warn(shader, name, err)
+		}
+	}
+	for _, t := range ms.textures {
+		if err := shader.SetSampler(t.sampler, t.unit); err != nil {
+			b.warn(shader, t.sampler, err)
+		}
+	}
+	b.applied[program] = ms
+}
+
+// warn logs why a material could not set a uniform the first time it happens for the shader.
+func (b *materialBindings) warn(shader am.Shader, name string, err error) {
+	program := shader.ProgramID()
+	if b.warned[program][name] {
+		return
+	}
+	if b.warned[program] == nil {
+		b.warned[program] = make(map[string]bool)
+	}
+	b.warned[program][name] = true
+	log.Printf("Unable to set material uniform %s: %v\n", name, err)
+}
+
+// renderState is the blending, face culling and depth testing set in OpenGL.
+type renderState struct {
+	blend      components.BlendMode
+	cull       components.CullMode
+	depthTest  bool
+	depthWrite bool
+}
+
+// passState retrieves the render state a pass draws with.  Transparent and overlay passes blend without writing depth, and overlays draw over everything.
+func passState(pass components.RenderPass) renderState {
+	switch pass {
+	case components.PassTransparent:
+		return renderState{blend: components.BlendAlpha, cull: components.CullNone, depthTest: true}
+	case components.PassOverlay:
+		return renderState{blend: components.BlendAlpha, cull: components.CullNone}
+	default:
+		return renderState{blend: components.BlendNone, cull: components.CullNone, depthTest: true, depthWrite: true}
+	}
+}
+
+// override retrieves the state with the settings a material sets replacing its own.
+func (rs renderState) override(o components.RenderState) renderState {
+	if o.Blend != "" {
+		rs.blend = o.Blend
+	}
+	if o.Cull != "" {
+		rs.cull = o.Cull
+	}
+	if o.DepthTest != nil {
+		rs.depthTest = *o.DepthTest
+	}
+	if o.DepthWrite != nil {
+		rs.depthWrite = *o.DepthWrite
+	}
+	return rs
+}
+
+// apply sets the parts of the state that differ from the current state, or all of it if the current state is unknown.
+func (rs renderState) apply(current renderState, known bool) {
+	if !known || rs.blend != current.blend {
+		switch rs.blend {
+		case components.BlendAlpha:
+			gl.Enable(gl.BLEND)
+			gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+		case components.BlendAdditive:
+			gl.Enable(gl.BLEND)
+			gl.BlendFunc(gl.SRC_ALPHA, gl.ONE)
+		default:
+			gl.Disable(gl.BLEND)
+		}
+	}
+	if !known || rs.cull != current.cull {
+		switch rs.cull {
+		case components.CullBack:
+			gl.Enable(gl.CULL_FACE)
+			gl.CullFace(gl.BACK)
+		case components.CullFront:
+			gl.Enable(gl.CULL_FACE)
+			gl.CullFace(gl.FRONT)
+		default:
+			gl.Disable(gl.CULL_FACE)
+		}
+	}
+	if !known || rs.depthTest != current.depthTest {
+		if rs.depthTest {
+			gl.Enable(gl.DEPTH_TEST)
+		} else {
+			gl.Disable(gl.DEPTH_TEST)
+		}
+	}
+	if !known || rs.depthWrite != current.depthWrite {
+		gl.DepthMask(rs.depthWrite)
+	}
+}
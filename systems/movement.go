@@ -5,6 +5,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/go-gl/mathgl/mgl32"
+
 	"github.com/Ariemeth/quantum-pulse/components"
 	"github.com/Ariemeth/quantum-pulse/entity"
 )
@@ -12,13 +14,32 @@ import (
 const (
 	// TypeMovement is the name of the movement system.
 	TypeMovement = "mover"
+
+	// maxFixedSteps is the most fixed steps a single update will simulate.
+	maxFixedSteps = 8
+	// maxFrameDepth is the longest chain of parents followed when resolving a reference frame, which also breaks cycles.
+	maxFrameDepth = 8
 )
 
-// Movement represents a system that knows how to alter an Entity's position based on its velocities.
+// Movement represents a system that knows how to alter an Entity's position based on its velocities.  Entities with a rigid body are also moved by the forces acting on them and by gravity.
 type Movement interface {
 	System
 	// Process updates entities position based on their velocities.
 	Process(elapsed float32)
+	// Gravity retrieves the acceleration applied to every dynamic rigid body.
+	Gravity() mgl32.Vec3
+	// SetGravity sets the acceleration applied to every dynamic rigid body.
+	SetGravity(mgl32.Vec3)
+	// Integrator retrieves the integration method used for entities without an Integrator component.
+	Integrator() components.IntegrationMethod
+	// SetIntegrator sets the integration method used for entities without an Integrator component.
+	SetIntegrator(components.IntegrationMethod)
+	// FixedStep retrieves the length of a simulation step in seconds.  A step of 0 advances the simulation by the elapsed time of each update.
+	FixedStep() float32
+	// SetFixedStep sets the length of a simulation step in seconds.  Elapsed time is accumulated and consumed in whole steps, which makes the simulation deterministic.
+	SetFixedStep(float32)
+	// AddForceField adds a force applied to every entity that is not a kinematic or static rigid body.
+	AddForceField(ForceField)
 }
 
 type movement struct {
@@ -31,6 +52,11 @@ type movement struct {
 	requirements   []string
 	interval       time.Duration
 	isRunning      bool
+	gravity        mgl32.Vec3
+	integrator     components.IntegrationMethod
+	fixedStep      float32
+	accumulator    float32
+	fields         []ForceField
 }
 
 // NewMovement creates a new Movement system.
@@ -42,10 +68,10 @@ func NewMovement() Movement {
 		quit:           make(chan interface{}),
 		quitProcessing: make(chan interface{}),
 		requirements: []string{components.TypeTransform,
-			components.TypeAcceleration,
 			components.TypeVelocity},
-		interval:  (1000 / 144) * time.Millisecond,
-		isRunning: false,
+		interval:   (1000 / 144) * time.Millisecond,
+		isRunning:  false,
+		integrator: components.IntegrateSemiImplicitEuler,
 	}
 
 	go func() {
@@ -143,31 +169,191 @@ func (m *movement) Terminate() {
 	m.quit <- true
 }
 
-// Process updates entities position based on their velocities.
+// Gravity retrieves the acceleration applied to every dynamic rigid body.
+func (m *movement) Gravity() mgl32.Vec3 {
+	defer m.runningLock.Unlock()
+	m.runningLock.Lock()
+	return m.gravity
+}
+
+// SetGravity sets the acceleration applied to every dynamic rigid body.
+func (m *movement) SetGravity(gravity mgl32.Vec3) {
+	defer m.runningLock.Unlock()
+	m.runningLock.Lock()
+	m.gravity = gravity
+}
+
+// Integrator retrieves the integration method used for entities without an Integrator component.
+func (m *movement) Integrator() components.IntegrationMethod {
+	defer m.runningLock.Unlock()
+	m.runningLock.Lock()
+	return m.integrator
+}
+
+// SetIntegrator sets the integration method used for entities without an Integrator component.
+func (m *movement) SetIntegrator(method components.IntegrationMethod) {
+	defer m.runningLock.Unlock()
+	m.runningLock.Lock()
+	m.integrator = method
+}
+
+// FixedStep retrieves the length of a simulation step in seconds.
+func (m *movement) FixedStep() float32 {
+	defer m.runningLock.Unlock()
+	m.runningLock.Lock()
+	return m.fixedStep
+}
+
+// SetFixedStep sets the length of a simulation step in seconds.
+func (m *movement) SetFixedStep(step float32) {
+	defer m.runningLock.Unlock()
+	m.runningLock.Lock()
+	m.fixedStep = step
+	m.accumulator = 0
+}
+
+// AddForceField adds a force applied to every entity that is not a kinematic or static rigid body.
+func (m *movement) AddForceField(field ForceField) {
+	defer m.runningLock.Unlock()
+	m.runningLock.Lock()
+	m.fields = append(m.fields, field)
+}
+
+// Process updates entities position based on their velocities.  With a fixed step the elapsed time is consumed in whole steps, and time that would take more than maxFixedSteps steps is dropped so a slow update cannot snowball.
 func (m *movement) Process(elapsed float32) {
 	defer m.runningLock.Unlock()
 	m.runningLock.Lock()
 
+	if m.fixedStep <= 0 {
+		m.step(elapsed)
+		return
+	}
+
+	m.accumulator += elapsed
+	for steps := 0; m.accumulator >= m.fixedStep; steps++ {
+		if steps == maxFixedSteps {
+			m.accumulator = 0
+			break
+		}
+		m.step(m.fixedStep)
+		m.accumulator -= m.fixedStep
+	}
+}
+
+// step advances every entity by a time step.  The caller is expected to hold the running lock.
+func (m *movement) step(dt float32) {
 	for _, ent := range m.entities {
-		// adjust the velocity based on the acceleration.
-		updateVelocityUsingAcceleration(elapsed, ent.Acceleration, ent.Velocity)
+		method := m.integrator
+		if ent.Integrator != nil {
+			method = ent.Integrator.Method()
+		}
+		m.move(dt, method, ent)
+	}
+}
 
-		// Apply velocity matrix to the transform
-		ent.Transform.Update(ent.Velocity.Translational().Mul(elapsed), ent.Velocity.Rotational().Mul(elapsed))
+// move advances a single entity by a time step.  Translation is advanced by the integration method while rotation always uses semi-implicit Euler.  Rigid bodies have forces, gravity, damping and speed limits applied, kinematic bodies only follow their velocity and acceleration and static bodies do not move.
+func (m *movement) move(dt float32, method components.IntegrationMethod, ent movable) {
+	velRot, velTrans := ent.Velocity.Rotational(), ent.Velocity.Translational()
+	// Integration happens in world space so the velocity and acceleration are brought out of their reference frames first.
+	toWorld, carried := m.frame(ent.Velocity.Frame(), ent.Velocity.Parent(), ent.Transform, 0)
+	velTrans = toWorld.Rotate(velTrans).Add(carried)
+
+	var accel, angAccel mgl32.Vec3
+	if ent.Acceleration != nil {
+		angAccel, accel = ent.Acceleration.Rotational(), ent.Acceleration.Translational()
+		accToWorld, _ := m.frame(ent.Acceleration.Frame(), ent.Acceleration.Parent(), ent.Transform, 0)
+		accel = accToWorld.Rotate(accel)
+	}
+
+	mass := float32(1)
+	fields := m.fields
+	var data components.RigidBodyData
+	if ent.RigidBody != nil {
+		data = ent.RigidBody.Data()
+		switch data.Kind {
+		case components.BodyStatic:
+			return
+		case components.BodyKinematic:
+			fields = nil
+		default:
+			body := ent.RigidBody
+			forces := body.TakeForces()
+			invMass := body.InverseMass()
+			if invMass > 0 {
+				mass = 1 / invMass
+			}
+			accel = accel.Add(m.gravity.Mul(data.GravityScale)).Add(forces.Force.Mul(invMass))
+			velTrans = velTrans.Add(forces.Impulse.Mul(invMass))
+
+			// Rotational velocity is applied about the local axes so world torques are brought into local space first.
+			toLocal := ent.Transform.Orientation().Conjugate()
+			invInertia := body.InverseInertia()
+			torque := toLocal.Rotate(forces.Torque.Mul(dt).Add(forces.AngularImpulse))
+			velRot = velRot.Add(mgl32.Vec3{torque.X() * invInertia.X(), torque.Y() * invInertia.Y(), torque.Z() * invInertia.Z()})
+		}
+	}
+
+	accelAt := func(position, velocity mgl32.Vec3) mgl32.Vec3 {
+		a := accel
+		for _, field := range fields {
+			a = a.Add(field(position, velocity, mass).Mul(1 / mass))
+		}
+		return a
+	}
+
+	position := ent.Transform.Translation()
+	nextPosition, velTrans := integrate(method, position, velTrans, dt, accelAt)
+	velRot = velRot.Add(angAccel.Mul(dt))
+
+	if ent.RigidBody != nil {
+		if data.Kind == components.BodyDynamic {
+			velTrans = velTrans.Mul(1 / (1 + dt*data.LinearDamping))
+			velRot = velRot.Mul(1 / (1 + dt*data.AngularDamping))
+		}
+		velTrans = clampLength(velTrans, data.MaxSpeed)
+		velRot = clampLength(velRot, data.MaxAngularSpeed)
+	}
+	ent.Velocity.Set(velRot, toWorld.Conjugate().Rotate(velTrans.Sub(carried)))
+
+	// Apply velocity matrix to the transform
+	ent.Transform.Update(nextPosition.Sub(position), velRot.Mul(dt))
+}
+
+// frame retrieves the rotation from a reference frame into world space and the world velocity the frame carries its entity with.  Frames relative to a parent that is missing from the system are treated as the world frame.  The caller is expected to hold the running lock.
+func (m *movement) frame(frame components.ReferenceFrame, parent string, t components.Transform, depth int) (mgl32.Quat, mgl32.Vec3) {
+	switch frame {
+	case components.FrameLocal:
+		return t.Orientation(), mgl32.Vec3{}
+	case components.FrameParent:
+		p, ok := m.entities[parent]
+		if !ok || depth >= maxFrameDepth {
+			return mgl32.QuatIdent(), mgl32.Vec3{}
+		}
+		q, carried := m.frame(p.Velocity.Frame(), p.Velocity.Parent(), p.Transform, depth+1)
+		return p.Transform.Orientation(), q.Rotate(p.Velocity.Translational()).Add(carried)
+	default:
+		return mgl32.QuatIdent(), mgl32.Vec3{}
 	}
 }
 
-// addEntity adds an Entity to the system.  Each system will have a component requirement that must be met before the Entity can be added.
+// addEntity adds an Entity to the system.  Each system will have a component requirement that must be met before the Entity can be added.  The acceleration, rigid body and integrator components are optional.
 func (m *movement) addEntity(e entity.Entity) {
 	velocity, isVelocity := e.Component(components.TypeVelocity).(components.Velocity)
-	acceleration, isAcceleration := e.Component(components.TypeAcceleration).(components.Acceleration)
 	transform, isTransform := e.Component(components.TypeTransform).(components.Transform)
 
-	if isVelocity && isAcceleration && isTransform {
+	if isVelocity && isTransform {
 		move := movable{
-			Velocity:     velocity,
-			Acceleration: acceleration,
-			Transform:    transform,
+			Velocity:  velocity,
+			Transform: transform,
+		}
+		if acceleration, isAcceleration := e.Component(components.TypeAcceleration).(components.Acceleration); isAcceleration {
+			move.Acceleration = acceleration
+		}
+		if body, isBody := e.Component(components.TypeRigidBody).(components.RigidBody); isBody {
+			move.RigidBody = body
+		}
+		if integrator, isIntegrator := e.Component(components.TypeIntegrator).(components.Integrator); isIntegrator {
+			move.Integrator = integrator
 		}
 		defer m.runningLock.Unlock()
 		m.runningLock.Lock()
@@ -183,19 +369,21 @@ func (m *movement) removeEntity(e entity.Entity) {
 	delete(m.entities, e.ID())
 }
 
-// updateVelocityUsingAcceleration updates a velocity component by adding the acceleration component to the velocity component based on how much time has passed.
-func updateVelocityUsingAcceleration(elapsed float32, a components.Acceleration, v components.Velocity) {
-	accRot, accTrans := a.Rotational(), a.Translational()
-	velRot, velTrans := v.Rotational(), v.Translational()
-
-	velRot = velRot.Add(accRot.Mul(elapsed))
-	velTrans = velTrans.Add(accTrans.Mul(elapsed))
-
-	v.Set(velRot, velTrans)
+// clampLength shortens a vector to a maximum length.  A maximum of 0 leaves the vector unchanged.
+func clampLength(v mgl32.Vec3, max float32) mgl32.Vec3 {
+	if max <= 0 {
+		return v
+	}
+	if l := v.Len(); l > max {
+		return v.Mul(max / l)
+	}
+	return v
 }
 
 type movable struct {
 	Acceleration components.Acceleration
 	Velocity     components.Velocity
 	Transform    components.Transform
+	RigidBody    components.RigidBody
+	Integrator   components.Integrator
 }
@@ -0,0 +1,219 @@
+package systems
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+
+	"github.com/Ariemeth/quantum-pulse/components"
+	"github.com/Ariemeth/quantum-pulse/entity"
+)
+
+// newMovable creates an entity with a velocity at a position.
+func newMovable(id string, position, velocity mgl32.Vec3) (entity.Entity, components.Transform) {
+	transform := components.NewTransform()
+	transform.SetTranslation(position)
+	v := components.NewVelocity()
+	v.SetTranslational(velocity)
+
+	e := entity.NewEntity(id)
+	e.AddComponent(transform)
+	e.AddComponent(v)
+	return e, transform
+}
+
+// The movement systems in these tests are never started so they are not terminated, since Terminate waits for the processing loop to stop.
+
+func TestMovementFixedStep(t *testing.T) {
+	m := NewMovement()
+	m.SetFixedStep(0.125)
+	e, transform := newMovable("a", mgl32.Vec3{}, mgl32.Vec3{1, 0, 0})
+	m.(*movement).addEntity(e)
+
+	tests := []struct {
+		name    string
+		elapsed float32
+		x       float32
+	}{
+		{"less than a step is accumulated", 0.0625, 0},
+		{"the accumulated time completes a step", 0.0625, 0.125},
+		{"whole steps are taken and the rest is kept", 0.3125, 0.375},
+		{"steps past the cap are dropped", 2, 1.375},
+		{"the dropped time is not carried over", 0.0625, 1.375},
+		{"accumulation starts again after the cap", 0.0625, 1.5},
+	}
+	for _, test := range tests {
+		m.Process(test.elapsed)
+		if x := transform.Translation().X(); x != test.x {
+			t.Errorf("%s: x = %v, want %v", test.name, x, test.x)
+		}
+	}
+}
+
+// simulate runs a fixed step simulation of a body orbiting under gravity and retrieves its position after every update.
+func simulate(method components.IntegrationMethod, elapsed []float32) []mgl32.Vec3 {
+	m := NewMovement()
+	m.SetFixedStep(1.0 / 60)
+	m.SetIntegrator(method)
+	m.SetGravity(mgl32.Vec3{0, 0, -0.1})
+	m.AddForceField(PointGravity(mgl32.Vec3{}, 2))
+
+	e, transform := newMovable("satellite", mgl32.Vec3{2, 0, 0}, mgl32.Vec3{0, 1, 0.1})
+	rbd := components.NewRigidBodyData()
+	rbd.LinearDamping = 0.01
+	e.AddComponent(components.NewRigidBody(rbd))
+	m.(*movement).addEntity(e)
+
+	positions := make([]mgl32.Vec3, 0, len(elapsed))
+	for _, dt := range elapsed {
+		m.Process(dt)
+		positions = append(positions, transform.Translation())
+	}
+	return positions
+}
+
+func TestMovementDeterministic(t *testing.T) {
+	// Uneven frame times including a stall long enough to hit the step cap.
+	elapsed := make([]float32, 0, 200)
+	for i := 0; i < 200; i++ {
+		elapsed = append(elapsed, 0.01+float32(i%7)*0.003)
+	}
+	elapsed[50] = 0.5
+
+	methods := []components.IntegrationMethod{
+		components.IntegrateEuler,
+		components.IntegrateSemiImplicitEuler,
+		components.IntegrateVerlet,
+		components.IntegrateRK4,
+	}
+	for _, method := range methods {
+		first, second := simulate(method, elapsed), simulate(method, elapsed)
+		for i := range first {
+			if first[i] != second[i] {
+				t.Errorf("%s: update %d moved to %v then %v, want identical runs", method, i, first[i], second[i])
+				break
+			}
+		}
+		if first[0] == first[len(first)-1] {
+			t.Errorf("%s: the body did not move", method)
+		}
+	}
+}
+
+func TestMovementRigidBody(t *testing.T) {
+	// A wind that pushes everything it reaches along +Y with a force of 2.
+	wind := func(position, velocity mgl32.Vec3, mass float32) mgl32.Vec3 {
+		return mgl32.Vec3{0, 2, 0}
+	}
+	tests := []struct {
+		name     string
+		body     func(*components.RigidBodyData)
+		velocity mgl32.Vec3
+		push     func(components.RigidBody)
+		wind     bool
+		steps    int
+		want     mgl32.Vec3
+		at       mgl32.Vec3
+	}{
+		{"forces add up", weightless(2), mgl32.Vec3{}, func(rb components.RigidBody) {
+			rb.AddForce(mgl32.Vec3{4, 0, 0})
+			rb.AddForce(mgl32.Vec3{4, 0, 0})
+		}, false, 1, mgl32.Vec3{2, 0, 0}, mgl32.Vec3{1, 0, 0}},
+		{"forces only last a step", weightless(2), mgl32.Vec3{}, func(rb components.RigidBody) {
+			rb.AddForce(mgl32.Vec3{8, 0, 0})
+		}, false, 2, mgl32.Vec3{2, 0, 0}, mgl32.Vec3{2, 0, 0}},
+		{"impulses add up", weightless(2), mgl32.Vec3{}, func(rb components.RigidBody) {
+			rb.AddImpulse(mgl32.Vec3{1, 0, 0})
+			rb.AddImpulse(mgl32.Vec3{0, 0, 1})
+		}, false, 1, mgl32.Vec3{0.5, 0, 0.5}, mgl32.Vec3{0.25, 0, 0.25}},
+		{"gravity is scaled", func(rbd *components.RigidBodyData) { rbd.GravityScale = 0.5 }, mgl32.Vec3{}, nil, false, 1, mgl32.Vec3{0, 0, -0.5}, mgl32.Vec3{0, 0, -0.25}},
+		{"force fields are divided by the mass", weightless(2), mgl32.Vec3{}, nil, true, 1, mgl32.Vec3{0, 0.5, 0}, mgl32.Vec3{0, 0.25, 0}},
+		{"damping slows after moving", func(rbd *components.RigidBodyData) { rbd.GravityScale, rbd.LinearDamping = 0, 2 }, mgl32.Vec3{3, 0, 0}, nil, false, 1, mgl32.Vec3{1.5, 0, 0}, mgl32.Vec3{1.5, 0, 0}},
+		{"speed is limited after moving", func(rbd *components.RigidBodyData) { rbd.GravityScale, rbd.MaxSpeed = 0, 1 }, mgl32.Vec3{3, 0, 0}, nil, false, 1, mgl32.Vec3{1, 0, 0}, mgl32.Vec3{1.5, 0, 0}},
+		{"static bodies never move", func(rbd *components.RigidBodyData) { rbd.Kind = components.BodyStatic }, mgl32.Vec3{1, 0, 0}, func(rb components.RigidBody) {
+			rb.AddImpulse(mgl32.Vec3{1, 0, 0})
+		}, true, 1, mgl32.Vec3{1, 0, 0}, mgl32.Vec3{}},
+		{"kinematic bodies ignore forces, gravity and fields", func(rbd *components.RigidBodyData) { rbd.Kind = components.BodyKinematic }, mgl32.Vec3{1, 0, 0}, func(rb components.RigidBody) {
+			rb.AddForce(mgl32.Vec3{0, 0, 8})
+			rb.AddImpulse(mgl32.Vec3{1, 0, 0})
+		}, true, 1, mgl32.Vec3{1, 0, 0}, mgl32.Vec3{0.5, 0, 0}},
+		{"without a rigid body only fields apply", nil, mgl32.Vec3{}, nil, true, 1, mgl32.Vec3{0, 1, 0}, mgl32.Vec3{0, 0.5, 0}},
+	}
+	for _, test := range tests {
+		m := NewMovement()
+		m.SetGravity(mgl32.Vec3{0, 0, -2})
+		if test.wind {
+			m.AddForceField(wind)
+		}
+		e, transform := newMovable("a", mgl32.Vec3{}, test.velocity)
+		if test.body != nil {
+			rbd := components.NewRigidBodyData()
+			test.body(&rbd)
+			rb := components.NewRigidBody(rbd)
+			if test.push != nil {
+				test.push(rb)
+			}
+			e.AddComponent(rb)
+		}
+		m.(*movement).addEntity(e)
+
+		for i := 0; i < test.steps; i++ {
+			m.Process(0.5)
+		}
+		velocity := e.Component(components.TypeVelocity).(components.Velocity)
+		if got := velocity.Translational(); !near(got, test.want) {
+			t.Errorf("%s: velocity = %v, want %v", test.name, got, test.want)
+		}
+		if got := transform.Translation(); !near(got, test.at) {
+			t.Errorf("%s: position = %v, want %v", test.name, got, test.at)
+		}
+	}
+}
+
+// weightless sets the mass of a dynamic body that gravity does not affect.
+func weightless(mass float32) func(*components.RigidBodyData) {
+	return func(rbd *components.RigidBodyData) {
+		rbd.Mass, rbd.GravityScale = mass, 0
+	}
+}
+
+func TestMovementReferenceFrames(t *testing.T) {
+	// The parent faces +Y and the child -Y, so a velocity turned by the wrong entity moves the wrong way.
+	facingY := mgl32.QuatRotate(mgl32.DegToRad(90), mgl32.Vec3{0, 0, 1})
+	facingNegativeY := mgl32.QuatRotate(mgl32.DegToRad(-90), mgl32.Vec3{0, 0, 1})
+	tests := []struct {
+		name        string
+		frame       components.ReferenceFrame
+		parent      string
+		parentFrame components.ReferenceFrame
+		at          mgl32.Vec3
+	}{
+		{"world", components.FrameWorld, "", components.FrameWorld, mgl32.Vec3{1, 0, 0}},
+		{"local moves the way it faces", components.FrameLocal, "", components.FrameWorld, mgl32.Vec3{0, -1, 0}},
+		{"parent turns and carries its child", components.FrameParent, "parent", components.FrameWorld, mgl32.Vec3{1, 1, 0}},
+		{"parent moving in its own frame", components.FrameParent, "parent", components.FrameLocal, mgl32.Vec3{0, 2, 0}},
+		{"missing parent is the world", components.FrameParent, "nobody", components.FrameWorld, mgl32.Vec3{1, 0, 0}},
+	}
+	for _, test := range tests {
+		m := NewMovement()
+		parent, _ := newMovable("parent", mgl32.Vec3{5, 5, 0}, mgl32.Vec3{1, 0, 0})
+		parent.Component(components.TypeTransform).(components.Transform).SetOrientation(facingY)
+		parent.Component(components.TypeVelocity).(components.Velocity).SetFrame(test.parentFrame)
+		m.(*movement).addEntity(parent)
+
+		child, transform := newMovable("child", mgl32.Vec3{}, mgl32.Vec3{1, 0, 0})
+		transform.SetOrientation(facingNegativeY)
+		velocity := child.Component(components.TypeVelocity).(components.Velocity)
+		velocity.SetFrame(test.frame)
+		velocity.SetParent(test.parent)
+		m.(*movement).addEntity(child)
+
+		m.Process(1)
+		if got := transform.Translation(); !near(got, test.at) {
+			t.Errorf("%s: moved to %v, want %v", test.name, got, test.at)
+		}
+		if got := velocity.Translational(); !near(got, mgl32.Vec3{1, 0, 0}) {
+			t.Errorf("%s: velocity = %v, want it kept in its frame as %v", test.name, got, mgl32.Vec3{1, 0, 0})
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package systems
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/go-gl/mathgl/mgl32"
+
+	"github.com/Ariemeth/quantum-pulse/components"
+	"github.com/Ariemeth/quantum-pulse/entity"
+)
+
+const (
+	// TypeNavigator is the name of the navigator system.
+	TypeNavigator = "navigator"
+)
+
+// Navigator represents a system that steers entities along their paths.  Entities with a transform, a velocity and a path follower have their velocity pointed at their next waypoint, and the movement system then moves them.
+type Navigator interface {
+	System
+	// Process updates the velocity of every entity following a path.
+	Process(elapsed float32)
+}
+
+type navigator struct {
+	entities       map[string]*navigable
+	remove         chan entity.Entity
+	add            chan entity.Entity
+	quit           chan interface{}
+	quitProcessing chan interface{}
+	runningLock    sync.Mutex
+	requirements   []string
+	interval       time.Duration
+	isRunning      bool
+}
+
+// NewNavigator creates a new Navigator system.
+func NewNavigator() Navigator {
+	n := navigator{
+		entities:       make(map[string]*navigable, 0),
+		remove:         make(chan entity.Entity, 0),
+		add:            make(chan entity.Entity, 0),
+		quit:           make(chan interface{}),
+		quitProcessing: make(chan interface{}),
+		requirements:   []string{components.TypeTransform, components.TypeVelocity, components.TypePathFollower},
+		interval:       (1000 / 144) * time.Millisecond,
+		isRunning:      false,
+	}
+
+	go func() {
+		for {
+			select {
+			case ent := <-n.add:
+				log.Printf("Adding %s to the navigator system.\n", ent.ID())
+				n.addEntity(ent)
+			case ent := <-n.remove:
+				log.Printf("Removing %s from the navigator system.\n", ent.ID())
+				n.removeEntity(ent)
+			case <-n.quit:
+				return
+			}
+		}
+	}()
+
+	return &n
+}
+
+// Type retrieves the type of system such as renderer, mover, etc.
+func (n *navigator) Type() string {
+	return TypeNavigator
+}
+
+// AddEntity adds an Entity to the system.  Each system will have a component requirement that must be met before the Entity can be added.
+func (n *navigator) AddEntity(e entity.Entity) {
+	n.add <- e
+}
+
+// RemoveEntity removes an Entity from the system.
+func (n *navigator) RemoveEntity(e entity.Entity) {
+	n.remove <- e
+}
+
+// IsRunning is useful to check if the navigator is processing entities.
+func (n *navigator) IsRunning() bool {
+	defer n.runningLock.Unlock()
+	n.runningLock.Lock()
+	return n.isRunning
+}
+
+// Start will begin steering the entities that have been added.
+func (n *navigator) Start() {
+	defer n.runningLock.Unlock()
+	n.runningLock.Lock()
+
+	if n.isRunning {
+		return
+	}
+
+	n.isRunning = true
+
+	go func() {
+
+		tr := time.NewTimer(n.interval)
+		previousTime := time.Now().UnixNano()
+
+		for {
+			select {
+			case <-n.quitProcessing:
+				n.isRunning = false
+				return
+			case <-tr.C:
+				current := time.Now().UnixNano()
+				// Get the elapsed time in seconds
+				elapsed := float32((current - previousTime)) / 1000000000.0
+				previousTime = current
+
+				n.Process(elapsed)
+				processingTime := time.Now().UnixNano() - current
+
+				if processingTime > 0 {
+					tr.Reset(n.interval - time.Duration(processingTime))
+				} else {
+					tr.Reset(time.Nanosecond)
+				}
+			}
+		}
+	}()
+}
+
+// Stop will stop the navigator from steering any of its Entities.
+func (n *navigator) Stop() {
+	defer n.runningLock.Unlock()
+	n.runningLock.Lock()
+	if n.isRunning {
+		n.quitProcessing <- true
+	}
+}
+
+// Terminate stops the navigator and releases all resources.  Once Terminate has been called, the system cannot be reused.
+func (n *navigator) Terminate() {
+	defer n.runningLock.Unlock()
+	n.runningLock.Lock()
+	if n.isRunning {
+		n.quitProcessing <- true
+	}
+	n.quit <- true
+}
+
+// Process updates the velocity of every entity following a path.  Waypoints within the arrive radius are skipped and the speed is limited so an entity never overshoots its waypoint in a single update.  Entities stop once their path is finished or every waypoint of a looped path is within the arrive radius.
+func (n *navigator) Process(elapsed float32) {
+	defer n.runningLock.Unlock()
+	n.runningLock.Lock()
+
+	for _, ent := range n.entities {
+		follower := ent.PathFollower
+		if follower.IsFinished() {
+			if ent.moving {
+				ent.Velocity.SetTranslational(mgl32.Vec3{})
+				ent.moving = false
+			}
+			continue
+		}
+
+		position := ent.Transform.Translation()
+		waypoints := follower.Waypoints()
+		radius := follower.ArriveRadius()
+
+		// A looped path never finishes, so at most one lap of waypoints is skipped each update.
+		toTarget := mgl32.Vec3{}
+		inRange := true
+		for skipped := 0; skipped < len(waypoints) && !follower.IsFinished(); skipped++ {
+			current := follower.Current()
+			if current >= len(waypoints) {
+				break
+			}
+			toTarget = waypoints[current].Sub(position)
+			if toTarget.Len() > radius {
+				inRange = false
+				break
+			}
+			if !follower.Advance() {
+				break
+			}
+		}
+		if inRange || follower.IsFinished() {
+			ent.Velocity.SetTranslational(mgl32.Vec3{})
+			ent.moving = false
+			continue
+		}
+
+		speed := follower.Speed()
+		distance := toTarget.Len()
+		if elapsed > 0 && distance/elapsed < speed {
+			speed = distance / elapsed
+		}
+		velocity := toTarget.Normalize().Mul(speed)
+		if ent.Velocity.Frame() == components.FrameLocal {
+			velocity = ent.Transform.Orientation().Conjugate().Rotate(velocity)
+		}
+		ent.Velocity.SetTranslational(velocity)
+		ent.moving = true
+	}
+}
+
+// addEntity adds an Entity to the system.  Each system will have a component requirement that must be met before the Entity can be added.
+func (n *navigator) addEntity(e entity.Entity) {
+	transform, isTransform := e.Component(components.TypeTransform).(components.Transform)
+	velocity, isVelocity := e.Component(components.TypeVelocity).(components.Velocity)
+	follower, isFollower := e.Component(components.TypePathFollower).(components.PathFollower)
+
+	if isTransform && isVelocity && isFollower {
+		nav := navigable{
+			Transform:    transform,
+			Velocity:     velocity,
+			PathFollower: follower,
+		}
+		defer n.runningLock.Unlock()
+		n.runningLock.Lock()
+		n.entities[e.ID()] = &nav
+	}
+}
+
+// removeEntity removes an Entity from the system.
+func (n *navigator) removeEntity(e entity.Entity) {
+	defer n.runningLock.Unlock()
+	n.runningLock.Lock()
+
+	delete(n.entities, e.ID())
+}
+
+type navigable struct {
+	Transform    components.Transform
+	Velocity     components.Velocity
+	PathFollower components.PathFollower
+	moving       bool
+}
@@ -0,0 +1,109 @@
+package systems
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-gl/mathgl/mgl32"
+
+	"github.com/Ariemeth/quantum-pulse/components"
+	"github.com/Ariemeth/quantum-pulse/entity"
+)
+
+// newNavigable creates an entity at the origin following waypoints at a speed of 2.
+func newNavigable(id string, waypoints []mgl32.Vec3, loop bool) (entity.Entity, components.Transform, components.Velocity, components.PathFollower) {
+	transform := components.NewTransform()
+	velocity := components.NewVelocity()
+	follower := components.NewPathFollower(2)
+	follower.SetArriveRadius(0.5)
+	follower.SetLoop(loop)
+	follower.SetWaypoints(waypoints)
+
+	e := entity.NewEntity(id)
+	e.AddComponent(transform)
+	e.AddComponent(velocity)
+	e.AddComponent(follower)
+	return e, transform, velocity, follower
+}
+
+// navigateWithin fails the test if Process does not return in time, which is how a path that is skipped forever shows.
+func navigateWithin(t *testing.T, n Navigator, elapsed float32) {
+	done := make(chan interface{})
+	go func() {
+		n.Process(elapsed)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Process did not return, the navigator kept skipping waypoints")
+	}
+}
+
+func TestNavigatorProcess(t *testing.T) {
+	tests := []struct {
+		name      string
+		waypoints []mgl32.Vec3
+		loop      bool
+		velocity  mgl32.Vec3
+		current   int
+		finished  bool
+	}{
+		{"heads for the first waypoint", []mgl32.Vec3{{5, 0, 0}, {5, 5, 0}}, false, mgl32.Vec3{2, 0, 0}, 0, false},
+		{"skips a reached waypoint", []mgl32.Vec3{{0.25, 0, 0}, {0, 5, 0}}, false, mgl32.Vec3{0, 2, 0}, 1, false},
+		{"finishes when every waypoint is reached", []mgl32.Vec3{{0.25, 0, 0}, {0, 0.25, 0}}, false, mgl32.Vec3{}, 2, true},
+		{"loops back to a waypoint out of range", []mgl32.Vec3{{0, -5, 0}, {0.25, 0, 0}}, true, mgl32.Vec3{0, -2, 0}, 0, false},
+		{"stops on a looped waypoint it is standing on", []mgl32.Vec3{{}}, true, mgl32.Vec3{}, 0, false},
+		{"stops when a whole loop is in range", []mgl32.Vec3{{0.25, 0, 0}, {0, 0.25, 0}, {-0.25, 0, 0}}, true, mgl32.Vec3{}, 1, false},
+	}
+	for _, test := range tests {
+		n := NewNavigator()
+		e, _, velocity, follower := newNavigable("a", test.waypoints, test.loop)
+		if test.loop {
+			// Start a looped path part way through so it has to wrap around.
+			follower.Advance()
+		}
+		n.(*navigator).addEntity(e)
+
+		navigateWithin(t, n, 0.1)
+		if got := velocity.Translational(); !got.ApproxEqual(test.velocity) {
+			t.Errorf("%s: velocity = %v, want %v", test.name, got, test.velocity)
+		}
+		if got := follower.Current(); got != test.current {
+			t.Errorf("%s: current waypoint = %d, want %d", test.name, got, test.current)
+		}
+		if got := follower.IsFinished(); got != test.finished {
+			t.Errorf("%s: IsFinished = %v, want %v", test.name, got, test.finished)
+		}
+		n.Terminate()
+	}
+}
+
+func TestNavigatorFinishedPath(t *testing.T) {
+	n := NewNavigator()
+	defer n.Terminate()
+	e, transform, velocity, follower := newNavigable("a", []mgl32.Vec3{{1, 0, 0}}, false)
+	n.(*navigator).addEntity(e)
+
+	// The speed is limited so the entity lands on the waypoint instead of passing it.
+	navigateWithin(t, n, 1)
+	if got := velocity.Translational(); !got.ApproxEqual(mgl32.Vec3{1, 0, 0}) {
+		t.Errorf("velocity towards a waypoint 1 away over 1 second = %v, want %v", got, mgl32.Vec3{1, 0, 0})
+	}
+
+	transform.SetTranslation(mgl32.Vec3{1, 0, 0})
+	navigateWithin(t, n, 1)
+	if !follower.IsFinished() {
+		t.Errorf("the path is not finished after reaching its only waypoint")
+	}
+	if got := velocity.Translational(); got != (mgl32.Vec3{}) {
+		t.Errorf("velocity after finishing = %v, want none", got)
+	}
+
+	// A finished entity is left alone so something else can move it.
+	velocity.SetTranslational(mgl32.Vec3{0, 3, 0})
+	navigateWithin(t, n, 1)
+	if got := velocity.Translational(); got != (mgl32.Vec3{0, 3, 0}) {
+		t.Errorf("velocity of a finished entity = %v, want it unchanged", got)
+	}
+}
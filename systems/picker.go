@@ -0,0 +1,203 @@
+package systems
+
+import (
+	"log"
+	"sort"
+	"sync"
+
+	"github.com/go-gl/mathgl/mgl32"
+
+	"github.com/Ariemeth/quantum-pulse/components"
+	"github.com/Ariemeth/quantum-pulse/entity"
+	"github.com/Ariemeth/quantum-pulse/geometry"
+)
+
+const (
+	// TypePicker is the name of the picker system.
+	TypePicker = "picker"
+)
+
+// PickResult describes the entity hit by a ray.
+type PickResult struct {
+	Entity   entity.Entity
+	Point    mgl32.Vec3
+	Distance float32
+}
+
+// Picker represents a system that finds the entities under a ray, such as the one under the mouse cursor.  Entities with a transform and a mesh can be picked.
+type Picker interface {
+	System
+	// Pick retrieves the nearest entity hit by a world space ray.
+	Pick(ray geometry.Ray) (PickResult, bool)
+	// PickAll retrieves every entity hit by a world space ray ordered from nearest to furthest.
+	PickAll(ray geometry.Ray) []PickResult
+}
+
+type picker struct {
+	entities     map[string]pickable
+	remove       chan entity.Entity
+	add          chan entity.Entity
+	quit         chan interface{}
+	runningLock  sync.Mutex
+	requirements []string
+	isRunning    bool
+}
+
+// NewPicker creates a new Picker system.
+func NewPicker() Picker {
+	p := picker{
+		entities:     make(map[string]pickable, 0),
+		remove:       make(chan entity.Entity, 0),
+		add:          make(chan entity.Entity, 0),
+		quit:         make(chan interface{}),
+		requirements: []string{components.TypeTransform, components.TypeMesh},
+		isRunning:    false,
+	}
+
+	go func() {
+		for {
+			select {
+			case ent := <-p.add:
+				log.Printf("Adding %s to the picker system.\n", ent.ID())
+				p.addEntity(ent)
+			case ent := <-p.remove:
+				log.Printf("Removing %s from the picker system.\n", ent.ID())
+				p.removeEntity(ent)
+			case <-p.quit:
+				return
+			}
+		}
+	}()
+
+	return &p
+}
+
+// Type retrieves the type of system such as renderer, mover, etc.
+func (p *picker) Type() string {
+	return TypePicker
+}
+
+// AddEntity adds an Entity to the system.  Each system will have a component requirement that must be met before the Entity can be added.
+func (p *picker) AddEntity(e entity.Entity) {
+	p.add <- e
+}
+
+// RemoveEntity removes an Entity from the system.
+func (p *picker) RemoveEntity(e entity.Entity) {
+	p.remove <- e
+}
+
+// IsRunning is useful to check if the picker is answering picks.
+func (p *picker) IsRunning() bool {
+	defer p.runningLock.Unlock()
+	p.runningLock.Lock()
+	return p.isRunning
+}
+
+// Start allows the picker to answer picks.  The picker does no work between picks so there is nothing to process.
+func (p *picker) Start() {
+	defer p.runningLock.Unlock()
+	p.runningLock.Lock()
+	p.isRunning = true
+}
+
+// Stop stops the picker from answering picks.
+func (p *picker) Stop() {
+	defer p.runningLock.Unlock()
+	p.runningLock.Lock()
+	p.isRunning = false
+}
+
+// Terminate stops the picker and releases all resources.  Once Terminate has been called, the system cannot be reused.
+func (p *picker) Terminate() {
+	defer p.runningLock.Unlock()
+	p.runningLock.Lock()
+	p.isRunning = false
+	p.quit <- true
+}
+
+// Pick retrieves the nearest entity hit by a world space ray.  Nothing is hit while the picker is stopped.
+func (p *picker) Pick(ray geometry.Ray) (PickResult, bool) {
+	hits := p.PickAll(ray)
+	if len(hits) == 0 {
+		return PickResult{}, false
+	}
+	return hits[0], true
+}
+
+// PickAll retrieves every entity hit by a world space ray ordered from nearest to furthest.  Each mesh's bounds are tested first and only meshes whose bounds are hit have their triangles tested.
+func (p *picker) PickAll(ray geometry.Ray) []PickResult {
+	defer p.runningLock.Unlock()
+	p.runningLock.Lock()
+
+	if !p.isRunning {
+		return nil
+	}
+
+	var hits []PickResult
+	for _, ent := range p.entities {
+		model := ent.Transform.Data()
+		if _, hit := ray.IntersectAABB(ent.Bounds.Transform(model)); !hit {
+			continue
+		}
+
+		// Test the triangles in model space so they do not need transforming.  The local ray is not normalized so distances match the world ray.
+		local := ray.Transform(model.Inv())
+		best, found := float32(0), false
+		for _, tri := range ent.Triangles {
+			if t, hit := local.IntersectTriangle(tri[0], tri[1], tri[2]); hit && (!found || t < best) {
+				best, found = t, true
+			}
+		}
+		if found {
+			hits = append(hits, PickResult{Entity: ent.Entity, Point: ray.At(best), Distance: best})
+		}
+	}
+
+	sortPicks(hits)
+	return hits
+}
+
+// addEntity adds an Entity to the system.  The mesh's bounds and triangles are cached since meshes rarely change.
+func (p *picker) addEntity(e entity.Entity) {
+	transform, isTransform := e.Component(components.TypeTransform).(components.Transform)
+	mesh, isMesh := e.Component(components.TypeMesh).(components.Mesh)
+
+	if isTransform && isMesh {
+		md := mesh.Data()
+		pick := pickable{
+			Entity:    e,
+			Transform: transform,
+			Bounds:    mesh.Bounds(),
+			Triangles: md.Triangles(),
+		}
+		defer p.runningLock.Unlock()
+		p.runningLock.Lock()
+		p.entities[e.ID()] = pick
+	}
+}
+
+// removeEntity removes an Entity from the system.
+func (p *picker) removeEntity(e entity.Entity) {
+	defer p.runningLock.Unlock()
+	p.runningLock.Lock()
+
+	delete(p.entities, e.ID())
+}
+
+// sortPicks orders hits from nearest to furthest, breaking ties by id so results are stable.
+func sortPicks(hits []PickResult) {
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Distance != hits[j].Distance {
+			return hits[i].Distance < hits[j].Distance
+		}
+		return hits[i].Entity.ID() < hits[j].Entity.ID()
+	})
+}
+
+type pickable struct {
+	Entity    entity.Entity
+	Transform components.Transform
+	Bounds    geometry.AABB
+	Triangles [][3]mgl32.Vec3
+}
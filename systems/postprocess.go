@@ -0,0 +1,291 @@
+package systems
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+
+	"github.com/Ariemeth/quantum-pulse/postprocess"
+	am "github.com/Ariemeth/quantum-pulse/resources"
+)
+
+// Post processing passes that are not an effect of their own.
+const (
+	passBloomExtract = "bloomExtract"
+	passBlur         = "blur"
+	passBloomCombine = "bloomCombine"
+)
+
+// postPass is a compiled fullscreen shader of the post processing chain.
+type postPass struct {
+	program  uint32
+	uniforms map[string]int32
+}
+
+// uniform retrieves the location of a uniform, looking it up the first time it is used since effects can have any parameters.
+func (p *postPass) uniform(name string) int32 {
+	if loc, ok := p.uniforms[name]; ok {
+		return loc
+	}
+	loc := gl.GetUniformLocation(p.program, gl.Str(name+"\x00"))
+	p.uniforms[name] = loc
+	return loc
+}
+
+// setParams sets the uniforms of an effect's parameters.
+func (p *postPass) setParams(params map[string][]float32) {
+	for name, v := range params {
+		loc := p.uniform(name)
+		if loc < 0 {
+			continue
+		}
+		switch len(v) {
+		case 1:
+			gl.Uniform1f(loc, v[0])
+		case 2:
+			gl.Uniform2f(loc, v[0], v[1])
+		case 3:
+			gl.Uniform3f(loc, v[0], v[1], v[2])
+		case 4:
+			gl.Uniform4f(loc, v[0], v[1], v[2], v[3])
+		}
+	}
+}
+
+// postChain runs a chain of post processing effects over a frame.  The frame is drawn into a half float render target so effects such as tone mapping see colors brighter than white, then every effect draws into the next render target and the last draws into the output.  It must only be used on the main thread.
+type postChain struct {
+	effects []postprocess.Effect
+	passes  map[string]*postPass
+	scene   am.RenderTarget
+	ping    am.RenderTarget
+	pong    am.RenderTarget
+	bloomA  am.RenderTarget
+	bloomB  am.RenderTarget
+	vao     uint32
+	output  uint32
+	width   int
+	height  int
+}
+
+// newPostChain compiles the shaders of a chain of effects.  Effects whose shaders cannot be compiled are logged and left out of the chain.
+func newPostChain(effects []postprocess.Effect, shaders am.ShaderManager) *postChain {
+	c := postChain{
+		passes: make(map[string]*postPass),
+	}
+	for _, effect := range effects {
+		if err := c.compile(effect, shaders); err != nil {
+			log.Printf("Unable to load the %s post processing effect: %v", effect.Kind, err)
+			continue
+		}
+		c.effects = append(c.effects, effect)
+	}
+	// Core profiles need a vertex array bound to draw, even though the fullscreen triangle has no vertex data.
+	gl.GenVertexArrays(1, &c.vao)
+	return &c
+}
+
+// compile loads the shaders an effect needs.
+func (c *postChain) compile(effect postprocess.Effect, shaders am.ShaderManager) error {
+	if err := effect.Validate(); err != nil {
+		return err
+	}
+	switch effect.Kind {
+	case postprocess.Bloom:
+		if err := c.load(passBloomExtract, postprocess.BloomExtractShader, shaders); err != nil {
+			return err
+		}
+		if err := c.load(passBlur, postprocess.BlurShader, shaders); err != nil {
+			return err
+		}
+		return c.load(passBloomCombine, postprocess.BloomCombineShader, shaders)
+	case postprocess.Custom:
+		src, err := ioutil.ReadFile(am.ShaderSrcDir + effect.FragShader)
+		if err != nil {
+			return err
+		}
+		return c.load(effect.FragShader, string(src), shaders)
+	default:
+		src, ok := postprocess.FragmentShader(effect.Kind)
+		if !ok {
+			return fmt.Errorf("no shader for %s", effect.Kind)
+		}
+		return c.load(string(effect.Kind), src, shaders)
+	}
+}
+
+// load compiles a fragment shader with the fullscreen vertex shader unless it already has been.
+func (c *postChain) load(name, fragSrc string, shaders am.ShaderManager) error {
+	if _, loaded := c.passes[name]; loaded {
+		return nil
+	}
+	shader, err := shaders.LoadProgramFromSrc(postprocess.VertexShader, fragSrc, "postprocess:"+name, false)
+	if err != nil {
+		return err
+	}
+	c.passes[name] = &postPass{
+		program:  shader.ProgramID(),
+		uniforms: make(map[string]int32),
+	}
+	return nil
+}
+
+// passName retrieves the name of the pass a single pass effect runs.
+func passName(effect postprocess.Effect) string {
+	if effect.Kind == postprocess.Custom {
+		return effect.FragShader
+	}
+	return string(effect.Kind)
+}
+
+// active returns true if the chain has any effects to run.
+func (c *postChain) active() bool {
+	return c != nil && len(c.effects) > 0
+}
+
+// begin sizes the chain's render targets to the output and binds the render target the frame is drawn into.  It retrieves the framebuffer the frame is drawn into.
+func (c *postChain) begin(width, height int) (uint32, error) {
+	hdr := am.RenderTargetSettings{Width: width, Height: height, Color: []am.ColorFormat{am.ColorRGBA16F}, Depth: am.DepthNone}
+	if err := ensureTarget(&c.ping, hdr); err != nil {
+		return 0, err
+	}
+	if err := ensureTarget(&c.pong, hdr); err != nil {
+		return 0, err
+	}
+	hdr.Depth = am.Depth24
+	if err := ensureTarget(&c.scene, hdr); err != nil {
+		return 0, err
+	}
+	c.scene.Bind()
+	return c.scene.FramebufferID(), nil
+}
+
+// ensureTarget creates a render target or resizes it to the settings.
+func ensureTarget(rt *am.RenderTarget, settings am.RenderTargetSettings) error {
+	if *rt != nil {
+		(*rt).Resize(settings.Width, settings.Height)
+		return nil
+	}
+	target, err := am.NewRenderTarget(settings)
+	if err != nil {
+		return err
+	}
+	*rt = target
+	return nil
+}
+
+// apply runs every effect over the frame drawn since begin, drawing the last into the output framebuffer.
+func (c *postChain) apply(output uint32, width, height int, stats *RenderStats) {
+	c.output, c.width, c.height = output, width, height
+
+	gl.Disable(gl.DEPTH_TEST)
+	gl.Disable(gl.BLEND)
+	gl.BindVertexArray(c.vao)
+
+	in := c.scene
+	for i, effect := range c.effects {
+		// A nil render target is the output.
+		var out am.RenderTarget
+		if i < len(c.effects)-1 {
+			out = c.ping
+			if in == c.ping {
+				out = c.pong
+			}
+		}
+
+		if effect.Kind == postprocess.Bloom {
+			c.bloom(effect, in, out, stats)
+		} else {
+			c.draw(c.passes[passName(effect)], effect.Params, in, 0, out, stats)
+		}
+		in = out
+	}
+
+	gl.BindVertexArray(0)
+	gl.UseProgram(0)
+	gl.Enable(gl.DEPTH_TEST)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, output)
+	gl.Viewport(0, 0, int32(width), int32(height))
+}
+
+// bloom extracts the bright parts of the source at half resolution, blurs them horizontally and then vertically and adds them back onto the source.
+func (c *postChain) bloom(effect postprocess.Effect, in, out am.RenderTarget, stats *RenderStats) {
+	half := am.RenderTargetSettings{
+		Width:  maxInt(c.width/2, 1),
+		Height: maxInt(c.height/2, 1),
+		Color:  []am.ColorFormat{am.ColorRGBA16F},
+		Depth:  am.DepthNone,
+	}
+	if err := ensureTarget(&c.bloomA, half); err != nil {
+		log.Printf("Unable to create the bloom render targets: %v", err)
+		c.draw(c.passes[passBloomCombine], effect.Params, in, 0, out, stats)
+		return
+	}
+	if err := ensureTarget(&c.bloomB, half); err != nil {
+		log.Printf("Unable to create the bloom render targets: %v", err)
+		c.draw(c.passes[passBloomCombine], effect.Params, in, 0, out, stats)
+		return
+	}
+
+	c.draw(c.passes[passBloomExtract], effect.Params, in, 0, c.bloomA, stats)
+	c.draw(c.passes[passBlur], withParam(effect.Params, postprocess.DirectionUniform, 1, 0), c.bloomA, 0, c.bloomB, stats)
+	c.draw(c.passes[passBlur], withParam(effect.Params, postprocess.DirectionUniform, 0, 1), c.bloomB, 0, c.bloomA, stats)
+	c.draw(c.passes[passBloomCombine], effect.Params, in, c.bloomA.ColorTexture(0), out, stats)
+}
+
+// draw runs one fullscreen pass reading the source and drawing into out, or into the output if out is nil.  The source is bound to the first texture unit, the frame before any effect to the second and the extra texture, such as the blurred bloom, to the third.
+func (c *postChain) draw(pass *postPass, params map[string][]float32, source am.RenderTarget, extra uint32, out am.RenderTarget, stats *RenderStats) {
+	if out != nil {
+		out.Bind()
+	} else {
+		gl.BindFramebuffer(gl.FRAMEBUFFER, c.output)
+		gl.Viewport(0, 0, int32(c.width), int32(c.height))
+	}
+
+	gl.UseProgram(pass.program)
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.BindTexture(gl.TEXTURE_2D, source.ColorTexture(0))
+	gl.Uniform1i(pass.uniform(postprocess.SourceUniform), 0)
+	gl.ActiveTexture(gl.TEXTURE1)
+	gl.BindTexture(gl.TEXTURE_2D, c.scene.ColorTexture(0))
+	gl.Uniform1i(pass.uniform(postprocess.SceneUniform), 1)
+	if extra != 0 {
+		gl.ActiveTexture(gl.TEXTURE2)
+		gl.BindTexture(gl.TEXTURE_2D, extra)
+		gl.Uniform1i(pass.uniform(postprocess.BloomUniform), 2)
+	}
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.Uniform2f(pass.uniform(postprocess.TexelSizeUniform), 1/float32(source.Width()), 1/float32(source.Height()))
+	pass.setParams(params)
+
+	gl.DrawArrays(gl.TRIANGLES, 0, 3)
+	stats.DrawCalls++
+}
+
+// delete frees the chain's render targets.  Shaders stay loaded in the shader manager so they can be reused.
+func (c *postChain) delete() {
+	for _, rt := range []am.RenderTarget{c.scene, c.ping, c.pong, c.bloomA, c.bloomB} {
+		if rt != nil {
+			rt.Delete()
+		}
+	}
+	gl.DeleteVertexArrays(1, &c.vao)
+}
+
+// withParam copies parameters adding one more.
+func withParam(params map[string][]float32, name string, values ...float32) map[string][]float32 {
+	copied := make(map[string][]float32, len(params)+1)
+	for n, v := range params {
+		copied[n] = v
+	}
+	copied[name] = values
+	return copied
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -1,15 +1,20 @@
 package systems
 
 import (
+	"image"
 	"log"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/glfw/v3.2/glfw"
+	"github.com/go-gl/mathgl/mgl32"
 
 	"github.com/Ariemeth/quantum-pulse/components"
 	"github.com/Ariemeth/quantum-pulse/entity"
+	"github.com/Ariemeth/quantum-pulse/geometry"
+	"github.com/Ariemeth/quantum-pulse/postprocess"
 	am "github.com/Ariemeth/quantum-pulse/resources"
 )
 
@@ -18,19 +23,79 @@ const (
 	TypeRenderer = "renderer"
 )
 
-// Renderer provides the interface needed to process the rendering of Entities.  Each time Process is called all Entities will be rendered.
+// minInstances is the fewest entities sharing a mesh that are drawn with one instanced draw call instead of one draw call each.
+const minInstances = 2
+
+// windowClearColor is the color parts of the window outside every camera's viewport are cleared to.
+var windowClearColor = mgl32.Vec4{0.5, 0.5, 0.5, 1.0}
+
+// Renderer provides the interface needed to process the rendering of Entities.  Each time Process is called all Entities will be rendered once through every enabled camera.
 type Renderer interface {
 	System
 	// Process renders all renderable entities.
 	Process()
-	// LoadCamera sets the camera to be used by the display.
+	// LoadCamera sets the camera to be used by the display when no entity has a camera.
 	LoadCamera(camera components.Camera)
+	// Cameras retrieves the enabled cameras in the order they draw.
+	Cameras() []components.Camera
+	// Stats retrieves what was drawn in the last frame.
+	Stats() RenderStats
+	// SetCulling sets whether entities outside of a camera's view are skipped.  Culling is on by default.
+	SetCulling(bool)
+	// SetInstancing sets whether entities sharing a mesh, shader and texture are drawn together in one draw call.  Instancing is on by default.
+	SetInstancing(bool)
+	// CaptureFrames reads back the next count frames drawn to the window.  The channel receives each frame and is closed after the last.
+	CaptureFrames(count int) <-chan CapturedFrame
+	// RenderOffscreen draws one frame at any resolution without showing it in the window.
+	RenderOffscreen(width, height int) (*image.RGBA, error)
+	// SetPostProcessing sets the chain of effects applied to every frame.  An empty chain draws frames straight to the window.
+	SetPostProcessing([]postprocess.Effect)
+	// SetAmbientLight sets the color of the light reaching every surface of a lit mesh.
+	SetAmbientLight(mgl32.Vec3)
+}
+
+// RenderStats counts what the renderer did in a frame.  Entities are counted once for every camera they are drawn or culled by.
+type RenderStats struct {
+	// Cameras is the number of cameras drawn through.
+	Cameras int
+	// Drawn is the number of entities drawn.
+	Drawn int
+	// Culled is the number of entities skipped because they were outside of a camera's view.
+	Culled int
+	// DrawCalls is the number of draw calls made.
+	DrawCalls int
+	// Instanced is the number of entities drawn as instances of a mesh shared with other entities.
+	Instanced int
+	// StateChanges is the number of times the shader, texture, mesh or render state changed.
+	StateChanges int
+	// ProgramChanges is the number of times a different shader was used.
+	ProgramChanges int
+	// TextureChanges is the number of times a different texture was bound.
+	TextureChanges int
+	// MeshChanges is the number of times a different mesh was bound.
+	MeshChanges int
 }
 
 type renderer struct {
-	entities       map[string]renderable
-	assets         *am.Manager
+	entities       map[string]*renderable
+	cameras        map[string]*cameraView
+	lights         map[string]*lightSource
+	ambient        mgl32.Vec3
+	cameraCount    int
 	camera         components.Camera
+	cameraLock     sync.RWMutex
+	stats          RenderStats
+	culling        bool
+	instancing     bool
+	instances      []float32
+	materials      *materialBindings
+	captures       []*captureRequest
+	post           *postChain
+	postEffects    []postprocess.Effect
+	postChanged    bool
+	captureLock    sync.Mutex
+	statsLock      sync.RWMutex
+	assets         *am.Manager
 	mainFunc       func(f func())
 	window         *glfw.Window
 	remove         chan entity.Entity
@@ -46,9 +111,15 @@ type renderer struct {
 // NewRenderer creates a new renderer system.  The renderer system handles rendering all renderable Entities to the screen.
 func NewRenderer(assetManager *am.Manager, mainFunc func(f func()), window *glfw.Window) Renderer {
 	r := renderer{
-		entities:       make(map[string]renderable),
+		entities:       make(map[string]*renderable),
+		cameras:        make(map[string]*cameraView),
+		lights:         make(map[string]*lightSource),
+		ambient:        defaultAmbientLight,
 		assets:         assetManager,
 		camera:         components.NewCamera(),
+		culling:        true,
+		instancing:     true,
+		materials:      newMaterialBindings(),
 		mainFunc:       mainFunc,
 		window:         window,
 		remove:         make(chan entity.Entity, 0),
@@ -156,53 +227,360 @@ func (r *renderer) Process() {
 		r.runningLock.Lock()
 		defer r.runningLock.Unlock()
 
-		gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+		width, height := r.window.GetFramebufferSize()
+		stats := r.drawProcessed(0, width, height)
+		r.statsLock.Lock()
+		r.stats = stats
+		r.statsLock.Unlock()
+
+		// Frames are read back before swapping since the back buffer is undefined afterwards.
+		r.captureFrame(width, height)
+
+		// Maintenance
+		r.window.SwapBuffers()
+		glfw.PollEvents()
+
+	})
+}
+
+// drawProcessed draws a frame into a framebuffer of the size passed in, running it through the post processing chain when there is one.  The caller is expected to be on the main thread and hold the running lock.
+func (r *renderer) drawProcessed(fbo uint32, width, height int) RenderStats {
+	r.updatePostChain()
+	if !r.post.active() || width <= 0 || height <= 0 {
+		gl.BindFramebuffer(gl.FRAMEBUFFER, fbo)
+		return r.drawFrame(fbo, width, height)
+	}
+
+	sceneFBO, err := r.post.begin(width, height)
+	if err != nil {
+		log.Printf("Unable to post process the frame: %v", err)
+		gl.BindFramebuffer(gl.FRAMEBUFFER, fbo)
+		return r.drawFrame(fbo, width, height)
+	}
+	stats := r.drawFrame(sceneFBO, width, height)
+	r.post.apply(fbo, width, height, &stats)
+	return stats
+}
+
+// updatePostChain replaces the post processing chain after it has been changed.  The caller is expected to be on the main thread.
+func (r *renderer) updatePostChain() {
+	r.statsLock.Lock()
+	changed, effects := r.postChanged, r.postEffects
+	r.postChanged = false
+	r.statsLock.Unlock()
+	if !changed {
+		return
+	}
+
+	if r.post != nil {
+		r.post.delete()
+		r.post = nil
+	}
+	if len(effects) > 0 {
+		r.post = newPostChain(effects, r.assets.Shaders())
+	}
+}
+
+// SetPostProcessing sets the chain of effects applied to every frame, which takes effect from the next frame.  An empty chain draws frames straight to the window.
+func (r *renderer) SetPostProcessing(effects []postprocess.Effect) {
+	r.statsLock.Lock()
+	defer r.statsLock.Unlock()
+	r.postEffects = append([]postprocess.Effect(nil), effects...)
+	r.postChanged = true
+}
+
+// drawFrame draws every enabled camera.  Cameras drawing into a render target draw first, so the window's cameras see what they drew, and the rest draw into the framebuffer passed in.  The caller is expected to be on the main thread and hold the running lock.
+func (r *renderer) drawFrame(fbo uint32, width, height int) RenderStats {
+	stats := RenderStats{}
+	culling, instancing := r.settings()
+	lights := r.gatherLights()
+
+	// Clear the whole window so areas no camera draws to do not keep old frames.
+	gl.ClearColor(windowClearColor.X(), windowClearColor.Y(), windowClearColor.Z(), windowClearColor.W())
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
 
-		for _, ent := range r.entities {
-			md := ent.Mesh.Data()
-			shader, status := r.assets.Shaders().GetShaderProgram(ent.ProgramID)
-			if !status {
-				// if the shader is not loaded there is no point in trying to render it to the screen.
+	cameras := r.activeCameras()
+	sort.SliceStable(cameras, func(i, j int) bool {
+		return cameras[i].Camera.Target() != "" && cameras[j].Camera.Target() == ""
+	})
+	bound := fbo
+	for _, cv := range cameras {
+		cv.follow()
+		cam := cv.Camera
+
+		targetWidth, targetHeight, targetFBO := width, height, fbo
+		if name := cam.Target(); name != "" {
+			rt, exists := r.assets.RenderTargets().GetRenderTarget(name)
+			if !exists {
 				continue
 			}
-			gl.UseProgram(ent.ProgramID)
-
-			gl.BindVertexArray(ent.VAO)
-			// set the camera uniform.  TODO this only needs to be done once per shader
-			projection := r.camera.Projection()
-			gl.UniformMatrix4fv(shader.GetUniformLoc(am.ProjectionUniform), 1, false, &projection[0])
-			// set the view uniform. TODO this only needs to be done once per shader
-			view := r.camera.View()
-			gl.UniformMatrix4fv(shader.GetUniformLoc(am.CameraUniform), 1, false, &view[0])
-
-			td := ent.Transform.Data()
-			gl.UniformMatrix4fv(shader.GetUniformLoc(am.ModelUniform), 1, false, &td[0])
-			gl.ActiveTexture(gl.TEXTURE0)
-			gl.Uniform1i(shader.GetUniformLoc(am.TextureUniform), 0)
-
-			texture, isLoaded := r.assets.Textures().GetTexture(md.TextureFile)
-			if isLoaded {
-				gl.BindTexture(gl.TEXTURE_2D, texture)
+			targetWidth, targetHeight, targetFBO = rt.Width(), rt.Height(), rt.FramebufferID()
+			if cam.WindowWidth() != targetWidth || cam.WindowHeight() != targetHeight {
+				cam.Resize(targetWidth, targetHeight)
+			}
+		}
+		if targetFBO != bound {
+			gl.BindFramebuffer(gl.FRAMEBUFFER, targetFBO)
+			bound = targetFBO
+		}
+		r.drawCamera(cam, targetWidth, targetHeight, culling, instancing, lights, &stats)
+	}
+	if bound != fbo {
+		gl.BindFramebuffer(gl.FRAMEBUFFER, fbo)
+	}
+	if width > 0 && height > 0 {
+		gl.Viewport(0, 0, int32(width), int32(height))
+	}
+	return stats
+}
+
+// drawCamera draws the entities a camera sees into the bound framebuffer of the size passed in.
+func (r *renderer) drawCamera(cam components.Camera, width, height int, culling, instancing bool, lights *lighting, stats *RenderStats) {
+	x, y, w, h := cam.Viewport().Pixels(width, height)
+	if width == 0 || height == 0 {
+		// The framebuffer size is unknown so draw to the whole window.
+		x, y, w, h = 0, 0, int32(cam.WindowWidth()), int32(cam.WindowHeight())
+	}
+	if w <= 0 || h <= 0 {
+		return
+	}
+	gl.Viewport(x, y, w, h)
+	clearViewport(cam, x, y, w, h)
+
+	projection := cam.Projection()
+	view := cam.View()
+	stats.Cameras++
+	queue := r.buildQueue(view, geometry.FrustumFromMatrix(projection.Mul4(view)), culling, instancing, stats)
+	queue.sort()
+
+	state := newGLState(projection, view, lights, r.materials, stats)
+	for start := 0; start < len(queue); {
+		end := queue.batch(start)
+		if end-start >= minInstances {
+			r.drawInstanced(queue[start:end], state)
+		} else {
+			for _, item := range queue[start:end] {
+				r.draw(item, state)
 			}
+		}
+		start = end
+	}
+	state.reset()
+	stats.Drawn += len(queue)
+}
 
-			if md.Indexed {
-				gl.DrawElements(gl.TRIANGLE_FAN, int32(len(md.Indices)), gl.UNSIGNED_INT, gl.PtrOffset(0))
-			} else {
-				gl.DrawArrays(gl.TRIANGLES, 0, int32(len(md.Verts))/md.VertSize)
+// buildQueue collects the entities a camera sees along with the state each needs.  Skinned entities have joints of their own so they are never instanced.
+func (r *renderer) buildQueue(view mgl32.Mat4, frustum geometry.Frustum, culling, instancing bool, stats *RenderStats) renderQueue {
+	queue := make(renderQueue, 0, len(r.entities))
+	for id, ent := range r.entities {
+		if culling && !ent.visible(frustum) {
+			stats.Culled++
+			continue
+		}
+		// Materials changed since the entity was last drawn are loaded again.
+		if ent.Material != nil && ent.Material.Version() != ent.material.version {
+			if err := r.loadMaterial(ent); err != nil {
+				log.Printf("Unable to update the material of %s: %v", id, err)
+				ent.material.version = ent.Material.Version()
 			}
+		}
+		item := drawItem{
+			id:       id,
+			ent:      ent,
+			pass:     ent.material.pass,
+			program:  ent.Shader.ProgramID(),
+			vao:      ent.VAO,
+			mesh:     ent.Mesh.Key(),
+			material: ent.material.key,
+		}
+		item.instanced = instancing && ent.Skeleton == nil && ent.Shader.SupportsInstancing()
+		if texture, isLoaded := r.assets.Textures().GetTexture(textureFile(ent.material, ent.Sprite)); isLoaded {
+			item.texture = texture
+		}
+		center := ent.Mesh.BoundingSphere().Transform(ent.Transform.Data()).Center
+		item.depth = -view.Mul4x1(center.Vec4(1)).Z()
+		queue = append(queue, item)
+	}
+	return queue
+}
 
-			gl.BindVertexArray(0)
+// draw renders an entity, changing only the state that differs from the entity drawn before it.
+func (r *renderer) draw(item drawItem, state *glState) {
+	ent := item.ent
+	md := ent.Mesh.Data()
+	shader := ent.Shader
+
+	state.usePass(item.pass, ent.material.state)
+	state.useProgram(shader)
+	state.useMaterial(ent.material)
+	state.setInstanced(false)
+	state.bindVAO(item.vao)
+	state.bindTexture(item.texture)
+
+	td := ent.Transform.Data()
+	state.setLights(ent.Mesh.BoundingSphere().Transform(td))
+	gl.UniformMatrix4fv(shader.GetUniformLoc(am.ModelUniform), 1, false, &td[0])
+	tint := ent.color()
+	gl.Uniform4f(shader.GetUniformLoc(am.TintUniform), tint.X(), tint.Y(), tint.Z(), tint.W())
+
+	if ent.Skeleton != nil {
+		joints := ent.Skeleton.JointMatrices()
+		if shader.SupportsSkinning() {
+			count := len(joints)
+			if count > am.MaxJoints {
+				count = am.MaxJoints
+			}
+			if count > 0 {
+				gl.UniformMatrix4fv(shader.GetUniformLoc(am.JointsUniform), int32(count), false, &joints[0][0])
+			}
+		} else if md.IsSkinned() {
+			// The shader cannot skin the mesh so fall back to skinning it on the cpu.
+			shader.UpdateVertices(ent.VAO, components.SkinVertices(md, joints))
 		}
+	}
 
-		// Maintenance
-		r.window.SwapBuffers()
-		glfw.PollEvents()
+	uvOffset, uvScale := ent.uv()
+	gl.Uniform2f(shader.GetUniformLoc(am.UVOffsetUniform), uvOffset.X(), uvOffset.Y())
+	gl.Uniform2f(shader.GetUniformLoc(am.UVScaleUniform), uvScale.X(), uvScale.Y())
+
+	if md.Indexed {
+		gl.DrawElements(gl.TRIANGLE_FAN, int32(len(md.Indices)), gl.UNSIGNED_INT, gl.PtrOffset(0))
+	} else {
+		gl.DrawArrays(gl.TRIANGLES, 0, int32(len(md.Verts))/md.VertSize)
+	}
+	state.stats.DrawCalls++
+}
+
+// drawInstanced renders entities sharing a mesh, shader and texture with one draw call.  The model matrix, tint and texture coordinates of every entity are uploaded to the instance buffer of the first entity's mesh, since each entity's mesh holds the same data.
+func (r *renderer) drawInstanced(items []drawItem, state *glState) {
+	first := items[0]
+	md := first.ent.Mesh.Data()
+	shader := first.ent.Shader
+
+	state.usePass(first.pass, first.ent.material.state)
+	state.useProgram(shader)
+	state.useMaterial(first.ent.material)
+	state.setInstanced(true)
+	state.bindVAO(first.vao)
+	state.bindTexture(first.texture)
+
+	instances := r.instances[:0]
+	bounds := geometry.EmptyAABB()
+	for _, item := range items {
+		td := item.ent.Transform.Data()
+		bounds = bounds.Union(item.ent.Mesh.Bounds().Transform(td))
+		tint := item.ent.color()
+		uvOffset, uvScale := item.ent.uv()
+		instances = append(instances, td[:]...)
+		instances = append(instances, tint[:]...)
+		instances = append(instances, uvOffset.X(), uvOffset.Y(), uvScale.X(), uvScale.Y())
+	}
+	r.instances = instances
+	shader.UpdateInstances(first.vao, instances)
+	// Every instance is lit by the lights reaching any of them.
+	state.setLights(geometry.Sphere{Center: bounds.Center(), Radius: bounds.Extents().Len()})
+
+	count := int32(len(items))
+	if md.Indexed {
+		gl.DrawElementsInstanced(gl.TRIANGLE_FAN, int32(len(md.Indices)), gl.UNSIGNED_INT, gl.PtrOffset(0), count)
+	} else {
+		gl.DrawArraysInstanced(gl.TRIANGLES, 0, int32(len(md.Verts))/md.VertSize, count)
+	}
+	state.stats.DrawCalls++
+	state.stats.Instanced += len(items)
+}
+
+// Stats retrieves what was drawn in the last frame.
+func (r *renderer) Stats() RenderStats {
+	r.statsLock.RLock()
+	defer r.statsLock.RUnlock()
+	return r.stats
+}
+
+// SetCulling sets whether entities outside of a camera's view are skipped.
+func (r *renderer) SetCulling(culling bool) {
+	r.statsLock.Lock()
+	defer r.statsLock.Unlock()
+	r.culling = culling
+}
+
+// SetInstancing sets whether entities sharing a mesh, shader and texture are drawn together in one draw call.
+func (r *renderer) SetInstancing(instancing bool) {
+	r.statsLock.Lock()
+	defer r.statsLock.Unlock()
+	r.instancing = instancing
+}
+
+// settings retrieves whether culling and instancing are on.
+func (r *renderer) settings() (culling, instancing bool) {
+	r.statsLock.RLock()
+	defer r.statsLock.RUnlock()
+	return r.culling, r.instancing
+}
 
+// clearViewport clears the buffers a camera asks for within its viewport only.
+func clearViewport(cam components.Camera, x, y, w, h int32) {
+	flags := cam.ClearFlags()
+	var mask uint32
+	if flags&components.ClearColorBuffer != 0 {
+		color := cam.ClearColor()
+		gl.ClearColor(color.X(), color.Y(), color.Z(), color.W())
+		mask |= gl.COLOR_BUFFER_BIT
+	}
+	if flags&components.ClearDepthBuffer != 0 {
+		mask |= gl.DEPTH_BUFFER_BIT
+	}
+	if mask == 0 {
+		return
+	}
+	gl.Enable(gl.SCISSOR_TEST)
+	gl.Scissor(x, y, w, h)
+	gl.Clear(mask)
+	gl.Disable(gl.SCISSOR_TEST)
+}
+
+// Cameras retrieves the enabled cameras in the order they draw.
+func (r *renderer) Cameras() []components.Camera {
+	views := r.activeCameras()
+	cams := make([]components.Camera, len(views))
+	for i, cv := range views {
+		cams[i] = cv.Camera
+	}
+	return cams
+}
+
+// activeCameras retrieves the enabled cameras of entities sorted by their order and then by when they were added.  The loaded camera is used when no entity has a camera.  Cameras have a lock of their own so they can be found while input callbacks run during rendering.
+func (r *renderer) activeCameras() []*cameraView {
+	r.cameraLock.RLock()
+	defer r.cameraLock.RUnlock()
+
+	if len(r.cameras) == 0 {
+		if r.camera == nil || !r.camera.Enabled() {
+			return nil
+		}
+		return []*cameraView{{Camera: r.camera}}
+	}
+
+	views := make([]*cameraView, 0, len(r.cameras))
+	for _, cv := range r.cameras {
+		if cv.Camera.Enabled() {
+			views = append(views, cv)
+		}
+	}
+	sort.Slice(views, func(i, j int) bool {
+		oi, oj := views[i].Camera.Order(), views[j].Camera.Order()
+		if oi != oj {
+			return oi < oj
+		}
+		return views[i].added < views[j].added
 	})
+	return views
 }
 
-// LoadCamera sets the camera to be used by the display.
+// LoadCamera sets the camera to be used by the display when no entity has a camera.
 func (r *renderer) LoadCamera(camera components.Camera) {
+	r.cameraLock.Lock()
+	defer r.cameraLock.Unlock()
 	r.camera = camera
 }
 
@@ -211,6 +589,22 @@ func (r *renderer) addEntity(e entity.Entity) {
 	mesh, isMesh := e.Component(components.TypeMesh).(components.Mesh)
 	transform, isTransform := e.Component(components.TypeTransform).(components.Transform)
 
+	if cam, isCamera := e.Component(components.TypeCamera).(components.Camera); isCamera {
+		r.addCamera(e, cam, transform)
+		if !isMesh {
+			return
+		}
+	}
+
+	if light, isLight := e.Component(components.TypeLight).(components.Light); isLight {
+		r.runningLock.Lock()
+		r.lights[e.ID()] = &lightSource{Light: light, Transform: transform}
+		r.runningLock.Unlock()
+		if !isMesh {
+			return
+		}
+	}
+
 	if !isMesh || !isTransform {
 		log.Printf("cannot load entity %s as a renderable", e.ID())
 		return
@@ -220,52 +614,147 @@ func (r *renderer) addEntity(e entity.Entity) {
 		Mesh:      mesh,
 		Transform: transform,
 	}
-	// Set up the shader
-	md := mesh.Data()
+	if skeleton, isSkeleton := e.Component(components.TypeSkeleton).(components.Skeleton); isSkeleton {
+		rend.Skeleton = skeleton
+	}
+	if sprite, isSprite := e.Component(components.TypeSprite).(components.Sprite); isSprite {
+		rend.Sprite = sprite
+	}
+	if tint, isTint := e.Component(components.TypeTint).(components.Tint); isTint {
+		rend.Tint = tint
+	}
+	if material, isMaterial := e.Component(components.TypeMaterial).(components.Material); isMaterial {
+		rend.Material = material
+	} else if file := mesh.Data().Material; file != "" {
+		material, err := components.LoadMaterial(file)
+		if err != nil {
+			log.Printf("Unable to load material %s: %v", file, err)
+		} else {
+			rend.Material = material
+		}
+	}
 
 	done := make(chan bool, 1)
 
+	// Set up the shader and textures
 	r.mainFunc(func() {
-		shader, err := r.assets.Shaders().LoadProgramFromFile(md.VertShaderFile, md.FragShaderFile, false)
-
-		if err != nil {
-			log.Printf("Unable to load shaders %s,%s", md.VertShaderFile, md.FragShaderFile)
+		if err := r.loadMaterial(&rend); err != nil {
+			log.Printf("Unable to load %s: %v", e.ID(), err)
 			done <- false
 			return
 		}
-
-		rend.ProgramID = shader.ProgramID()
-		rend.VAO = shader.CreateVAO(mesh)
-
-		// Load and set the texture if it exists.
-		if md.TextureFile != "" {
-			texture, err := r.assets.Textures().LoadTexture(md.TextureFile, md.TextureFile)
-			if err != nil {
-				log.Printf("Unable to load texture:%s", md.TextureFile)
-				done <- false
-				return
-			}
-
-			rend.TextureID = texture
-		}
 		done <- true
 	})
 	// Add the shader and texture ids to the mesh component.
 	if <-done {
-		r.entities[e.ID()] = rend
+		r.runningLock.Lock()
+		defer r.runningLock.Unlock()
+		r.entities[e.ID()] = &rend
+	}
+}
+
+// addCamera adds the camera of an entity.  Cameras created before the window size was known are sized to the window.
+func (r *renderer) addCamera(e entity.Entity, cam components.Camera, transform components.Transform) {
+	if cam.WindowWidth() == 0 || cam.WindowHeight() == 0 {
+		r.mainFunc(func() {
+			cam.Resize(r.window.GetSize())
+		})
+	}
+
+	cv := cameraView{
+		Camera: cam,
+	}
+	// Cameras with a controller are moved by it, everything else follows its transform.
+	if e.Component(components.TypeCameraController) == nil {
+		cv.Transform = transform
 	}
+
+	r.cameraLock.Lock()
+	defer r.cameraLock.Unlock()
+	r.cameraCount++
+	cv.added = r.cameraCount
+	r.cameras[e.ID()] = &cv
 }
 
 // removeEntity removes an Entity from the system.
 func (r *renderer) removeEntity(e entity.Entity) {
+	r.runningLock.Lock()
 	delete(r.entities, e.ID())
+	delete(r.lights, e.ID())
+	r.runningLock.Unlock()
+
+	r.cameraLock.Lock()
+	defer r.cameraLock.Unlock()
+	delete(r.cameras, e.ID())
+}
+
+// textureFile retrieves the texture to draw an entity with.  A sprite sheet naming its own texture takes priority over the texture of the material or mesh.
+func textureFile(ms *materialState, sprite components.Sprite) string {
+	if sprite != nil {
+		if file := sprite.Sheet().TextureFile; file != "" {
+			return file
+		}
+	}
+	return ms.texture
 }
 
 type renderable struct {
 	Entity    entity.Entity
 	Mesh      components.Mesh
 	Transform components.Transform
+	Skeleton  components.Skeleton
+	Sprite    components.Sprite
+	Tint      components.Tint
+	Material  components.Material
+	Shader    am.Shader
 	VAO       uint32
 	ProgramID uint32
 	TextureID uint32
+	// material is the material the entity was last loaded with.
+	material *materialState
+}
+
+// color retrieves the tint of the entity, which is white when it has none.
+func (ent *renderable) color() mgl32.Vec4 {
+	if ent.Tint == nil {
+		return mgl32.Vec4{1, 1, 1, 1}
+	}
+	return ent.Tint.Color()
+}
+
+// uv retrieves the offset and scale of the entity's texture coordinates.  Sprites only show part of the texture, everything else shows all of it.
+func (ent *renderable) uv() (offset, scale mgl32.Vec2) {
+	if ent.Sprite == nil {
+		return mgl32.Vec2{0, 0}, mgl32.Vec2{1, 1}
+	}
+	return ent.Sprite.UV()
+}
+
+// visible returns true if the entity's bounds may be inside the frustum.  The bounds are those of the mesh in its rest pose, so skinned meshes that animate beyond them are always drawn.
+func (ent *renderable) visible(frustum geometry.Frustum) bool {
+	if ent.Skeleton != nil {
+		return true
+	}
+	model := ent.Transform.Data()
+	if !frustum.IntersectsSphere(ent.Mesh.BoundingSphere().Transform(model)) {
+		return false
+	}
+	return frustum.IntersectsAABB(ent.Mesh.Bounds().Transform(model))
+}
+
+// cameraView is a camera the renderer draws through.  Cameras with a transform are placed by it, looking down the transform's forward axis.
+type cameraView struct {
+	Camera    components.Camera
+	Transform components.Transform
+	added     int
+}
+
+// follow moves the camera to its transform.
+func (cv *cameraView) follow() {
+	if cv.Transform == nil {
+		return
+	}
+	pos := cv.Transform.Translation()
+	view := cv.Transform.Orientation().Conjugate().Mat4().Mul4(mgl32.Translate3D(-pos.X(), -pos.Y(), -pos.Z()))
+	cv.Camera.SetViewMatrix(view)
 }
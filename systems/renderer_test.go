@@ -0,0 +1,57 @@
+package systems
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+
+	"github.com/Ariemeth/quantum-pulse/components"
+	"github.com/Ariemeth/quantum-pulse/geometry"
+)
+
+// newCube creates a renderable 2 unit cube centered on its position.
+func newCube(position, scale mgl32.Vec3) *renderable {
+	var verts []float32
+	for _, x := range []float32{-1, 1} {
+		for _, y := range []float32{-1, 1} {
+			for _, z := range []float32{-1, 1} {
+				verts = append(verts, x, y, z)
+			}
+		}
+	}
+	mesh := components.NewMesh()
+	mesh.Set(components.MeshData{Verts: verts, VertSize: 3})
+	transform := components.NewTransform()
+	transform.SetTranslation(position)
+	transform.SetScale(scale)
+	return &renderable{Mesh: mesh, Transform: transform}
+}
+
+func TestRenderableVisible(t *testing.T) {
+	// A camera at (0, 0, 5) looking down -Z with a 90 degree field of view sees 5 units either side of the origin.
+	view := mgl32.LookAtV(mgl32.Vec3{0, 0, 5}, mgl32.Vec3{}, mgl32.Vec3{0, 1, 0})
+	frustum := geometry.FrustumFromMatrix(mgl32.Perspective(mgl32.DegToRad(90), 1, 1, 100).Mul4(view))
+	one := mgl32.Vec3{1, 1, 1}
+
+	tests := []struct {
+		name     string
+		ent      *renderable
+		skeleton bool
+		visible  bool
+	}{
+		{"in front of the camera", newCube(mgl32.Vec3{}, one), false, true},
+		{"off to the side", newCube(mgl32.Vec3{10, 0, 0}, one), false, false},
+		{"straddling the edge", newCube(mgl32.Vec3{5.5, 0, 0}, one), false, true},
+		{"scaled up to reach the edge", newCube(mgl32.Vec3{8, 0, 0}, mgl32.Vec3{4, 1, 1}), false, true},
+		{"behind the camera", newCube(mgl32.Vec3{0, 0, 10}, one), false, false},
+		{"skinned meshes are always drawn", newCube(mgl32.Vec3{0, 0, 10}, one), true, true},
+	}
+	for _, test := range tests {
+		if test.skeleton {
+			test.ent.Skeleton = components.NewSkeleton()
+		}
+		if got := test.ent.visible(frustum); got != test.visible {
+			t.Errorf("%s: visible = %v, want %v", test.name, got, test.visible)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package systems
+
+import (
+	"sort"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+
+	"github.com/Ariemeth/quantum-pulse/components"
+	"github.com/Ariemeth/quantum-pulse/geometry"
+	am "github.com/Ariemeth/quantum-pulse/resources"
+)
+
+// drawItem is an entity waiting to be drawn along with the state it needs.
+type drawItem struct {
+	id      string
+	ent     *renderable
+	pass    components.RenderPass
+	program uint32
+	texture uint32
+	vao     uint32
+	// mesh is the key of the mesh data so entities drawing the same mesh can be found.
+	mesh uint64
+	// material is the key of the entity's material so entities drawn with the same settings can be found.
+	material uint64
+	// instanced is true if the entity can be drawn as an instance along with others sharing its state.
+	instanced bool
+	// depth is the distance from the camera, used to draw transparent meshes from back to front and opaque meshes sharing state from front to back.
+	depth float32
+}
+
+// renderQueue holds the entities one camera draws, sorted to need as few state changes as possible.
+type renderQueue []drawItem
+
+// sort orders the queue by pass, then by shader, texture, material and mesh so entities sharing state are drawn together and entities sharing a mesh can be drawn as instances.  Opaque entities sharing state are drawn from the nearest to the furthest so hidden pixels fail the depth test early.  Transparent and overlay passes blend with what is behind them, so they are drawn from the furthest to the nearest instead.
+func (q renderQueue) sort() {
+	sort.Slice(q, func(i, j int) bool {
+		a, b := q[i], q[j]
+		if a.pass != b.pass {
+			return a.pass < b.pass
+		}
+		if a.pass != components.PassOpaque && a.depth != b.depth {
+			return a.depth > b.depth
+		}
+		if a.program != b.program {
+			return a.program < b.program
+		}
+		if a.texture != b.texture {
+			return a.texture < b.texture
+		}
+		if a.material != b.material {
+			return a.material < b.material
+		}
+		if a.mesh != b.mesh {
+			return a.mesh < b.mesh
+		}
+		if a.vao != b.vao {
+			return a.vao < b.vao
+		}
+		if a.depth != b.depth {
+			return a.depth < b.depth
+		}
+		return a.id < b.id
+	})
+}
+
+// batch retrieves the end of the run of entities starting at start that can be drawn as instances of one mesh.  Entities that cannot be instanced are a run of one.
+func (q renderQueue) batch(start int) int {
+	first := q[start]
+	end := start + 1
+	if !first.instanced {
+		return end
+	}
+	for end < len(q) {
+		item := q[end]
+		if !item.instanced || item.pass != first.pass || item.program != first.program || item.texture != first.texture || item.material != first.material || item.mesh != first.mesh {
+			break
+		}
+		end++
+	}
+	return end
+}
+
+// glState tracks what is bound so binds that would change nothing are skipped.  It is reset for every camera since the projection and view change.
+type glState struct {
+	projection mgl32.Mat4
+	view       mgl32.Mat4
+	state      renderState
+	hasState   bool
+	program    uint32
+	shader     am.Shader
+	texture    uint32
+	hasTexture bool
+	vao        uint32
+	// cameraSet records the programs whose camera uniforms have been set.
+	cameraSet map[uint32]bool
+	// instanced records the value of each program's instanced uniform.
+	instanced map[uint32]bool
+	// lighting holds the lights of the frame and lightSets the lights last uploaded to each lit program.
+	lighting  *lighting
+	lightSets map[uint32]lightSet
+	// materials tracks the material uniforms of every program and units the textures bound to texture units other than the first.
+	materials *materialBindings
+	units     map[int32]uint32
+	stats     *RenderStats
+}
+
+func newGLState(projection, view mgl32.Mat4, lights *lighting, materials *materialBindings, stats *RenderStats) *glState {
+	return &glState{
+		projection: projection,
+		view:       view,
+		cameraSet:  make(map[uint32]bool),
+		instanced:  make(map[uint32]bool),
+		lighting:   lights,
+		lightSets:  make(map[uint32]lightSet),
+		materials:  materials,
+		units:      make(map[int32]uint32),
+		stats:      stats,
+	}
+}
+
+// usePass sets the blending, face culling and depth testing of a pass along with the settings of a material replacing them.
+func (s *glState) usePass(pass components.RenderPass, overrides components.RenderState) {
+	state := passState(pass).override(overrides)
+	if s.hasState && s.state == state {
+		return
+	}
+	state.apply(s.state, s.hasState)
+	s.state, s.hasState = state, true
+	s.stats.StateChanges++
+}
+
+// useMaterial sets the uniforms of a material on the current shader and binds its textures.
+func (s *glState) useMaterial(ms *materialState) {
+	if s.shader == nil {
+		return
+	}
+	s.materials.apply(s.shader, ms)
+	for _, t := range ms.textures {
+		if bound, ok := s.units[t.unit]; ok && bound == t.texture {
+			continue
+		}
+		gl.ActiveTexture(gl.TEXTURE0 + uint32(t.unit))
+		gl.BindTexture(gl.TEXTURE_2D, t.texture)
+		gl.ActiveTexture(gl.TEXTURE0)
+		s.units[t.unit] = t.texture
+		s.stats.StateChanges++
+		s.stats.TextureChanges++
+	}
+}
+
+// useProgram makes a shader current and sets its camera uniforms the first time it is used.  Instancing starts off since the uniform keeps its value from the last frame.
+func (s *glState) useProgram(shader am.Shader) {
+	program := shader.ProgramID()
+	if s.shader == nil || s.program != program {
+		gl.UseProgram(program)
+		s.program, s.shader = program, shader
+		s.stats.StateChanges++
+		s.stats.ProgramChanges++
+	}
+	if s.cameraSet[program] {
+		return
+	}
+	gl.UniformMatrix4fv(shader.GetUniformLoc(am.ProjectionUniform), 1, false, &s.projection[0])
+	gl.UniformMatrix4fv(shader.GetUniformLoc(am.CameraUniform), 1, false, &s.view[0])
+	gl.Uniform1i(shader.GetUniformLoc(am.TextureUniform), 0)
+	gl.Uniform1i(shader.GetUniformLoc(am.InstancedUniform), 0)
+	if shader.SupportsLighting() {
+		ambient := s.lighting.ambient
+		position := s.view.Inv().Col(3)
+		gl.Uniform3f(shader.GetUniformLoc(am.AmbientLightUniform), ambient.X(), ambient.Y(), ambient.Z())
+		gl.Uniform3f(shader.GetUniformLoc(am.CameraPositionUniform), position.X(), position.Y(), position.Z())
+	}
+	s.cameraSet[program] = true
+}
+
+// setLights uploads the lights shining on a sphere to the current shader if it is lit and they differ from the lights it already has.
+func (s *glState) setLights(bounds geometry.Sphere) {
+	if s.shader == nil || !s.shader.SupportsLighting() {
+		return
+	}
+	set := s.lighting.choose(bounds)
+	if uploaded, ok := s.lightSets[s.program]; ok && uploaded == set {
+		return
+	}
+	set.upload(s.shader)
+	s.lightSets[s.program] = set
+}
+
+// setInstanced sets whether the current shader reads its model matrix, tint and texture coordinates from the instance attributes.  Programs start with it off.
+func (s *glState) setInstanced(instanced bool) {
+	if s.shader == nil || s.instanced[s.program] == instanced {
+		return
+	}
+	v := int32(0)
+	if instanced {
+		v = 1
+	}
+	gl.Uniform1i(s.shader.GetUniformLoc(am.InstancedUniform), v)
+	s.instanced[s.program] = instanced
+}
+
+// bindTexture binds a texture to the first texture unit.
+func (s *glState) bindTexture(texture uint32) {
+	if s.hasTexture && s.texture == texture {
+		return
+	}
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.BindTexture(gl.TEXTURE_2D, texture)
+	s.texture, s.hasTexture = texture, true
+	s.stats.StateChanges++
+	s.stats.TextureChanges++
+}
+
+// bindVAO binds a vertex array.
+func (s *glState) bindVAO(vao uint32) {
+	if s.vao == vao {
+		return
+	}
+	gl.BindVertexArray(vao)
+	s.vao = vao
+	s.stats.StateChanges++
+	s.stats.MeshChanges++
+}
+
+// reset unbinds the vertex array and returns to the opaque pass's state for whatever draws next.
+func (s *glState) reset() {
+	if s.vao != 0 {
+		gl.BindVertexArray(0)
+	}
+	if opaque := passState(components.PassOpaque); s.hasState && s.state != opaque {
+		opaque.apply(s.state, true)
+	}
+}
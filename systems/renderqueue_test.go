@@ -0,0 +1,130 @@
+package systems
+
+import (
+	"testing"
+
+	"github.com/Ariemeth/quantum-pulse/components"
+)
+
+const (
+	opaque      = components.PassOpaque
+	transparent = components.PassTransparent
+	overlay     = components.PassOverlay
+)
+
+// queueIDs retrieves the ids of a queue's entities in order.
+func queueIDs(q renderQueue) []string {
+	ids := make([]string, len(q))
+	for i, item := range q {
+		ids[i] = item.id
+	}
+	return ids
+}
+
+func TestRenderQueueSort(t *testing.T) {
+	tests := []struct {
+		name  string
+		items []drawItem
+		want  []string
+	}{
+		{"passes draw in order", []drawItem{
+			{id: "a", pass: overlay},
+			{id: "b", pass: opaque},
+			{id: "c", pass: transparent},
+		}, []string{"b", "c", "a"}},
+		{"the pass comes before state and depth", []drawItem{
+			{id: "a", pass: transparent, program: 1, depth: 1},
+			{id: "b", pass: opaque, program: 9, depth: 50},
+		}, []string{"b", "a"}},
+		{"opaque sharing state draws front to back", []drawItem{
+			{id: "a", pass: opaque, program: 1, depth: 5},
+			{id: "b", pass: opaque, program: 1, depth: 1},
+			{id: "c", pass: opaque, program: 1, depth: 3},
+		}, []string{"b", "c", "a"}},
+		{"opaque is grouped by state before depth", []drawItem{
+			{id: "a", pass: opaque, program: 2, depth: 1},
+			{id: "b", pass: opaque, program: 1, depth: 9},
+			{id: "c", pass: opaque, program: 2, depth: 0.5},
+		}, []string{"b", "c", "a"}},
+		{"opaque state is shader, texture, material then mesh", []drawItem{
+			{id: "a", pass: opaque, program: 1, texture: 2},
+			{id: "b", pass: opaque, program: 1, texture: 1, material: 1},
+			{id: "c", pass: opaque, program: 1, texture: 1, mesh: 9},
+			{id: "d", pass: opaque, program: 1, texture: 1, mesh: 1},
+			{id: "e", pass: opaque, program: 2},
+		}, []string{"d", "c", "b", "a", "e"}},
+		{"transparent draws back to front whatever its state", []drawItem{
+			{id: "a", pass: transparent, program: 1, depth: 1},
+			{id: "b", pass: transparent, program: 2, depth: 5},
+			{id: "c", pass: transparent, program: 3, depth: 3},
+		}, []string{"b", "c", "a"}},
+		{"transparent at the same depth is grouped by state", []drawItem{
+			{id: "a", pass: transparent, program: 2, depth: 4},
+			{id: "b", pass: transparent, program: 1, depth: 4},
+		}, []string{"b", "a"}},
+		{"overlay draws back to front", []drawItem{
+			{id: "a", pass: overlay, depth: 2},
+			{id: "b", pass: overlay, depth: 8},
+		}, []string{"b", "a"}},
+		{"ties are broken by id", []drawItem{
+			{id: "b", pass: opaque},
+			{id: "a", pass: opaque},
+		}, []string{"a", "b"}},
+	}
+	for _, test := range tests {
+		q := renderQueue(test.items)
+		q.sort()
+		if got := queueIDs(q); !equalStrings(got, test.want) {
+			t.Errorf("%s: order = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+// queueBatches retrieves the ids of each run of entities batch would draw together.
+func queueBatches(q renderQueue) [][]string {
+	var runs [][]string
+	for start := 0; start < len(q); {
+		end := q.batch(start)
+		runs = append(runs, queueIDs(q[start:end]))
+		start = end
+	}
+	return runs
+}
+
+func TestRenderQueueBatch(t *testing.T) {
+	// Every item shares the state of base unless a test changes it.
+	base := drawItem{pass: opaque, program: 1, texture: 1, material: 1, mesh: 1, instanced: true}
+	with := func(id string, change func(*drawItem)) drawItem {
+		item := base
+		item.id = id
+		if change != nil {
+			change(&item)
+		}
+		return item
+	}
+	tests := []struct {
+		name  string
+		items []drawItem
+		want  [][]string
+	}{
+		{"shared state is one batch", []drawItem{with("a", nil), with("b", nil), with("c", nil)}, [][]string{{"a", "b", "c"}}},
+		{"a material change breaks the batch", []drawItem{with("a", nil), with("b", func(i *drawItem) { i.material = 2 })}, [][]string{{"a"}, {"b"}}},
+		{"a mesh change breaks the batch", []drawItem{with("a", nil), with("b", nil), with("c", func(i *drawItem) { i.mesh = 2 })}, [][]string{{"a", "b"}, {"c"}}},
+		{"a shader change breaks the batch", []drawItem{with("a", nil), with("b", func(i *drawItem) { i.program = 2 })}, [][]string{{"a"}, {"b"}}},
+		{"a texture change breaks the batch", []drawItem{with("a", nil), with("b", func(i *drawItem) { i.texture = 2 })}, [][]string{{"a"}, {"b"}}},
+		{"a pass change breaks the batch", []drawItem{with("a", nil), with("b", func(i *drawItem) { i.pass = transparent })}, [][]string{{"a"}, {"b"}}},
+		{"depth and vertex arrays do not break the batch", []drawItem{with("a", nil), with("b", func(i *drawItem) { i.depth, i.vao = 5, 7 })}, [][]string{{"a", "b"}}},
+		{"an entity that cannot be instanced ends the batch", []drawItem{with("a", nil), with("b", func(i *drawItem) { i.instanced = false }), with("c", nil)}, [][]string{{"a"}, {"b"}, {"c"}}},
+		{"an entity that cannot be instanced is drawn alone", []drawItem{with("a", func(i *drawItem) { i.instanced = false }), with("b", nil), with("c", nil)}, [][]string{{"a"}, {"b", "c"}}},
+	}
+	for _, test := range tests {
+		got := queueBatches(renderQueue(test.items))
+		same := len(got) == len(test.want)
+		for i := 0; same && i < len(got); i++ {
+			same = equalStrings(got[i], test.want[i])
+		}
+		if !same {
+			t.Errorf("%s: batches = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
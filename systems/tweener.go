@@ -0,0 +1,271 @@
+package systems
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Ariemeth/quantum-pulse/entity"
+	"github.com/Ariemeth/quantum-pulse/tween"
+)
+
+const (
+	// TypeTweener is the name of the tweener system.
+	TypeTweener = "tweener"
+)
+
+// Tweener represents a system that advances fire-and-forget tweens.
+type Tweener interface {
+	System
+	// Process advances all active tweens and drops the ones that have completed.
+	Process(elapsed float32)
+	// Play starts a tween that is not owned by any entity.
+	Play(t tween.Tween)
+	// PlayOn starts a tween owned by an entity.  The tween is cancelled if the entity is removed from the system.
+	PlayOn(e entity.Entity, t tween.Tween)
+	// Cancel stops a tween without completing it.
+	Cancel(t tween.Tween)
+	// Count retrieves the number of active tweens.
+	Count() int
+}
+
+type tweener struct {
+	tweens         []activeTween
+	pending        []activeTween
+	cancelled      []tween.Tween
+	pendingLock    sync.Mutex
+	remove         chan entity.Entity
+	quit           chan interface{}
+	quitProcessing chan interface{}
+	runningLock    sync.Mutex
+	processLock    sync.Mutex
+	interval       time.Duration
+	isRunning      bool
+}
+
+// NewTweener creates a new Tweener system.
+func NewTweener() Tweener {
+	t := tweener{
+		tweens:         make([]activeTween, 0),
+		pending:        make([]activeTween, 0),
+		remove:         make(chan entity.Entity, 0),
+		quit:           make(chan interface{}),
+		quitProcessing: make(chan interface{}),
+		interval:       (1000 / 144) * time.Millisecond,
+		isRunning:      false,
+	}
+
+	go func() {
+		for {
+			select {
+			case ent := <-t.remove:
+				log.Printf("Removing %s from the tweener system.\n", ent.ID())
+				t.removeEntity(ent)
+			case <-t.quit:
+				return
+			}
+		}
+	}()
+
+	return &t
+}
+
+// Type retrieves the type of system such as renderer, mover, etc.
+func (t *tweener) Type() string {
+	return TypeTweener
+}
+
+// AddEntity is a no-op for the tweener.  Entities take part by having tweens started on them with PlayOn.
+func (t *tweener) AddEntity(e entity.Entity) {
+}
+
+// RemoveEntity cancels all tweens owned by the Entity.
+func (t *tweener) RemoveEntity(e entity.Entity) {
+	t.remove <- e
+}
+
+// IsRunning is useful to check if the tweener is processing tweens.
+func (t *tweener) IsRunning() bool {
+	defer t.runningLock.Unlock()
+	t.runningLock.Lock()
+	return t.isRunning
+}
+
+// Start will begin advancing tweens.
+func (t *tweener) Start() {
+	defer t.runningLock.Unlock()
+	t.runningLock.Lock()
+
+	if t.isRunning {
+		return
+	}
+
+	t.isRunning = true
+
+	go func() {
+
+		tr := time.NewTimer(t.interval)
+		previousTime := time.Now().UnixNano()
+
+		for {
+			select {
+			case <-t.quitProcessing:
+				t.isRunning = false
+				return
+			case <-tr.C:
+				current := time.Now().UnixNano()
+				// Get the elapsed time in seconds
+				elapsed := float32((current - previousTime)) / 1000000000.0
+				previousTime = current
+
+				t.Process(elapsed)
+				processingTime := time.Now().UnixNano() - current
+
+				if processingTime > 0 {
+					tr.Reset(t.interval - time.Duration(processingTime))
+				} else {
+					tr.Reset(time.Nanosecond)
+				}
+			}
+		}
+	}()
+}
+
+// Stop will stop the tweener from advancing any tweens.
+func (t *tweener) Stop() {
+	defer t.runningLock.Unlock()
+	t.runningLock.Lock()
+	if t.isRunning {
+		t.quitProcessing <- true
+	}
+}
+
+// Terminate stops the tweener and releases all resources.  Once Terminate has been called, the system cannot be reused.
+func (t *tweener) Terminate() {
+	defer t.runningLock.Unlock()
+	t.runningLock.Lock()
+	if t.isRunning {
+		t.quitProcessing <- true
+	}
+	t.quit <- true
+}
+
+// Play starts a tween that is not owned by any entity.
+func (t *tweener) Play(tw tween.Tween) {
+	t.PlayOn(nil, tw)
+}
+
+// PlayOn starts a tween owned by an entity.  Tweens are queued so they may be started from within tween callbacks.
+func (t *tweener) PlayOn(e entity.Entity, tw tween.Tween) {
+	owner := ""
+	if e != nil {
+		owner = e.ID()
+	}
+
+	t.pendingLock.Lock()
+	defer t.pendingLock.Unlock()
+	t.pending = append(t.pending, activeTween{owner: owner, tween: tw})
+}
+
+// Cancel stops a tween without completing it.
+func (t *tweener) Cancel(tw tween.Tween) {
+	t.pendingLock.Lock()
+	defer t.pendingLock.Unlock()
+	t.cancelled = append(t.cancelled, tw)
+}
+
+// Count retrieves the number of active tweens.
+func (t *tweener) Count() int {
+	t.pendingLock.Lock()
+	pending := len(t.pending)
+	t.pendingLock.Unlock()
+
+	defer t.runningLock.Unlock()
+	t.runningLock.Lock()
+	return len(t.tweens) + pending
+}
+
+// Process advances all active tweens and drops the ones that have completed.  The tweens to advance are collected while the tweener is locked and advanced after it is unlocked, so tween callbacks may safely call back into the tweener.
+func (t *tweener) Process(elapsed float32) {
+	t.processLock.Lock()
+	defer t.processLock.Unlock()
+
+	t.runningLock.Lock()
+	t.pendingLock.Lock()
+	t.tweens = append(t.tweens, t.pending...)
+	t.pending = t.pending[:0]
+	cancelled := t.cancelled
+	t.cancelled = nil
+	t.pendingLock.Unlock()
+
+	active := t.tweens[:0]
+	for _, at := range t.tweens {
+		if !isCancelled(at.tween, cancelled) {
+			active = append(active, at)
+		}
+	}
+	t.tweens = active
+	updating := make([]tween.Tween, len(active))
+	for i, at := range active {
+		updating[i] = at.tween
+	}
+	t.runningLock.Unlock()
+
+	completed := make([]tween.Tween, 0)
+	for _, tw := range updating {
+		if tw.Update(elapsed) {
+			completed = append(completed, tw)
+		}
+	}
+	if len(completed) == 0 {
+		return
+	}
+
+	// Tweens removed with their entity while they were advanced are already gone.
+	t.runningLock.Lock()
+	defer t.runningLock.Unlock()
+	active = t.tweens[:0]
+	for _, at := range t.tweens {
+		if !isCancelled(at.tween, completed) {
+			active = append(active, at)
+		}
+	}
+	t.tweens = active
+}
+
+// removeEntity cancels the tweens owned by an Entity.
+func (t *tweener) removeEntity(e entity.Entity) {
+	t.pendingLock.Lock()
+	pending := t.pending[:0]
+	for _, at := range t.pending {
+		if at.owner != e.ID() {
+			pending = append(pending, at)
+		}
+	}
+	t.pending = pending
+	t.pendingLock.Unlock()
+
+	defer t.runningLock.Unlock()
+	t.runningLock.Lock()
+	active := t.tweens[:0]
+	for _, at := range t.tweens {
+		if at.owner != e.ID() {
+			active = append(active, at)
+		}
+	}
+	t.tweens = active
+}
+
+func isCancelled(tw tween.Tween, cancelled []tween.Tween) bool {
+	for _, c := range cancelled {
+		if c == tw {
+			return true
+		}
+	}
+	return false
+}
+
+type activeTween struct {
+	owner string
+	tween tween.Tween
+}
@@ -0,0 +1,99 @@
+package systems
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Ariemeth/quantum-pulse/tween"
+)
+
+// processWithin fails the test if Process does not return in time, which is how a deadlock shows.
+func processWithin(t *testing.T, tw Tweener, elapsed float32) {
+	done := make(chan interface{})
+	go func() {
+		tw.Process(elapsed)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Process did not return, tween callbacks deadlocked the tweener")
+	}
+}
+
+func TestTweenerCallbacksCallBackIn(t *testing.T) {
+	// The tweener is only terminated once the test passes since Terminate would block on a deadlocked tweener.
+	tw := NewTweener()
+
+	var value float32
+	counts := make([]int, 0)
+	target := tween.FloatTarget(
+		func() float32 { return value },
+		func(v float32) {
+			value = v
+			counts = append(counts, tw.Count())
+		})
+
+	follow := tween.NewFromTo(target, []float32{1}, []float32{0}, 1, tween.Linear)
+	first := tween.NewFromTo(target, []float32{0}, []float32{1}, 1, tween.Linear).
+		OnStart(func() { counts = append(counts, tw.Count()) }).
+		OnComplete(func() {
+			counts = append(counts, tw.Count())
+			tw.Play(follow)
+		})
+	tw.Play(first)
+
+	processWithin(t, tw, 0.5)
+	processWithin(t, tw, 0.5)
+	if value != 1 {
+		t.Errorf("value = %v, want 1", value)
+	}
+	if got := tw.Count(); got != 1 {
+		t.Errorf("Count after the first tween completed = %d, want 1 for the tween it started", got)
+	}
+
+	processWithin(t, tw, 1)
+	if value != 0 {
+		t.Errorf("value = %v, want 0", value)
+	}
+	if got := tw.Count(); got != 0 {
+		t.Errorf("Count after every tween completed = %d, want 0", got)
+	}
+	// OnStart, two updates and OnComplete of the first tween and the update of the second.
+	want := []int{1, 1, 1, 1, 1}
+	if len(counts) != len(want) {
+		t.Fatalf("callbacks saw counts %v, want %v", counts, want)
+	}
+	for i := range want {
+		if counts[i] != want[i] {
+			t.Fatalf("callbacks saw counts %v, want %v", counts, want)
+		}
+	}
+	tw.Terminate()
+}
+
+func TestTweenerCancel(t *testing.T) {
+	tw := NewTweener()
+	defer tw.Terminate()
+
+	var value float32
+	target := tween.FloatTarget(func() float32 { return value }, func(v float32) { value = v })
+	a := tween.NewFromTo(target, []float32{0}, []float32{1}, 1, tween.Linear)
+	b := tween.NewDelay(1)
+	tw.Play(a)
+	tw.Play(b)
+
+	processWithin(t, tw, 0.25)
+	tw.Cancel(a)
+	processWithin(t, tw, 0.25)
+	if value != 0.25 {
+		t.Errorf("value = %v, want 0.25 from before the tween was cancelled", value)
+	}
+	if got := tw.Count(); got != 1 {
+		t.Errorf("Count = %d, want 1", got)
+	}
+	processWithin(t, tw, 1)
+	if got := tw.Count(); got != 0 {
+		t.Errorf("Count = %d, want 0", got)
+	}
+}
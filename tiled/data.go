@@ -0,0 +1,88 @@
+package tiled
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// decodeData decodes the global tile ids of a layer or chunk stored as csv or as base64 with optional gzip or zlib compression.
+func decodeData(encoding, compression, text string) ([]uint32, error) {
+	switch encoding {
+	case "csv":
+		fields := strings.Split(text, ",")
+		gids := make([]uint32, 0, len(fields))
+		for _, f := range fields {
+			f = strings.TrimSpace(f)
+			if f == "" {
+				continue
+			}
+			gid, err := strconv.ParseUint(f, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid tile %q: %v", f, err)
+			}
+			gids = append(gids, uint32(gid))
+		}
+		return gids, nil
+	case "base64":
+		raw, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(text), ""))
+		if err != nil {
+			return nil, err
+		}
+		raw, err = decompress(compression, raw)
+		if err != nil {
+			return nil, err
+		}
+		if len(raw)%4 != 0 {
+			return nil, fmt.Errorf("tile data of %d bytes is not a whole number of tiles", len(raw))
+		}
+		gids := make([]uint32, len(raw)/4)
+		for i := range gids {
+			gids[i] = binary.LittleEndian.Uint32(raw[i*4:])
+		}
+		return gids, nil
+	default:
+		return nil, fmt.Errorf("unsupported tile data encoding %q", encoding)
+	}
+}
+
+func decompress(compression string, raw []byte) ([]byte, error) {
+	var r io.Reader
+	var err error
+	switch compression {
+	case "":
+		return raw, nil
+	case "gzip":
+		r, err = gzip.NewReader(bytes.NewReader(raw))
+	case "zlib":
+		r, err = zlib.NewReader(bytes.NewReader(raw))
+	default:
+		return nil, fmt.Errorf("unsupported tile data compression %q", compression)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(r)
+}
+
+// cells converts the global tile ids of a block of tiles into cells, skipping empty tiles.
+func cells(gids []uint32, x, y, width int) []Cell {
+	var c []Cell
+	if width <= 0 {
+		return c
+	}
+	for i, gid := range gids {
+		if gid == 0 {
+			continue
+		}
+		c = append(c, Cell{Col: x + i%width, Row: y + i/width, GID: gid})
+	}
+	return c
+}
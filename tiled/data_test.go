@@ -0,0 +1,95 @@
+package tiled
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"reflect"
+	"testing"
+)
+
+// encodeGIDs encodes global tile ids the way Tiled stores them as base64, compressing them first unless the compression is empty.
+func encodeGIDs(t *testing.T, compression string, gids []uint32) string {
+	raw := make([]byte, 4*len(gids))
+	for i, gid := range gids {
+		binary.LittleEndian.PutUint32(raw[i*4:], gid)
+	}
+
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch compression {
+	case "":
+		return base64.StdEncoding.EncodeToString(raw)
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	case "zlib":
+		w = zlib.NewWriter(&buf)
+	default:
+		t.Fatalf("unknown compression %q", compression)
+	}
+	if _, err := w.Write(raw); err != nil {
+		t.Fatalf("Unable to compress tile data: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Unable to compress tile data: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func TestDecodeData(t *testing.T) {
+	gids := []uint32{1, 0, 17, FlippedHorizontally | 3, 0xffffffff}
+	tests := []struct {
+		name        string
+		encoding    string
+		compression string
+		text        string
+		want        []uint32
+		err         bool
+	}{
+		{"csv", "csv", "", "1,0,17,2147483651,4294967295", gids, false},
+		{"csv across lines", "csv", "", "\n1,0,17,\n2147483651,4294967295\n", gids, false},
+		{"csv with a trailing comma", "csv", "", "1, 2,", []uint32{1, 2}, false},
+		{"empty csv", "csv", "", "\n", []uint32{}, false},
+		{"base64", "base64", "", encodeGIDs(t, "", gids), gids, false},
+		{"base64 across lines", "base64", "", "\n   " + encodeGIDs(t, "", gids) + "\n", gids, false},
+		{"gzip", "base64", "gzip", encodeGIDs(t, "gzip", gids), gids, false},
+		{"zlib", "base64", "zlib", encodeGIDs(t, "zlib", gids), gids, false},
+		{"csv that is not a number", "csv", "", "1,x,3", nil, true},
+		{"csv tile too large", "csv", "", "4294967296", nil, true},
+		{"invalid base64", "base64", "", "not base64!", nil, true},
+		{"partial tile", "base64", "", base64.StdEncoding.EncodeToString([]byte{1, 0, 0}), nil, true},
+		{"wrong compression", "base64", "gzip", encodeGIDs(t, "zlib", gids), nil, true},
+		{"unsupported compression", "base64", "zstd", encodeGIDs(t, "", gids), nil, true},
+		{"unsupported encoding", "xml", "", "", nil, true},
+	}
+	for _, test := range tests {
+		got, err := decodeData(test.encoding, test.compression, test.text)
+		if test.err {
+			if err == nil {
+				t.Errorf("%s: decodeData = %v, want an error", test.name, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: decodeData failed: %v", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: decodeData = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestCells(t *testing.T) {
+	got := cells([]uint32{0, 5, 6, 0, 0, 7}, -2, 4, 3)
+	want := []Cell{{-1, 4, 5}, {0, 4, 6}, {0, 5, 7}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("cells = %v, want %v", got, want)
+	}
+	if got := cells([]uint32{1, 2}, 0, 0, 0); len(got) != 0 {
+		t.Errorf("cells without a width = %v, want none", got)
+	}
+}
@@ -0,0 +1,6 @@
+// Package tiled imports maps made with the Tiled map editor.
+//
+// Maps and tilesets are read from TMX and TSX files or from their JSON variants, including orthogonal, isometric, staggered and hexagonal maps, infinite maps and every layer data encoding Tiled writes except zstd.  Entities are created for every tile and object with a transform, a mesh and a sprite selecting the tile from its tileset's texture atlas.
+//
+// Custom properties are mapped onto components through the component registry.  A property named "type.field" sets a field of the registered component of that type, for example "collider.shape" or "rigidBody.mass", and a class property named after a component sets its members the same way.  A string property named after a component, such as "mesh", is shorthand for its file.
+package tiled
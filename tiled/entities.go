@@ -0,0 +1,342 @@
+package tiled
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+
+	"github.com/Ariemeth/quantum-pulse/components"
+	"github.com/Ariemeth/quantum-pulse/entity"
+)
+
+// Options controls how a map is turned into entities.
+type Options struct {
+	// TileSize is the size in world units of one tile of the map.  The map's Y axis points down while the world's points up, so rows are placed along -Y.
+	TileSize mgl32.Vec2
+	// Origin is the world position of the map's top left corner.
+	Origin mgl32.Vec3
+	// LayerHeight is the distance along Z between a layer and the one below it.
+	LayerHeight float32
+	// Mesh is the mesh file of tiles and tile objects that do not name a mesh in their properties.
+	Mesh string
+}
+
+// DefaultOptions creates options placing tiles as the hexagon model of the hex-map example, which is 2 units across.
+func DefaultOptions() Options {
+	return Options{
+		TileSize: mgl32.Vec2{2, 2},
+		Mesh:     "hexagon.json",
+	}
+}
+
+// Entities creates an entity for every tile and object of the visible tile and object layers.  Tiles are named <layer>_<col>_<row> and objects by their name, or <layer>_<id> if they are unnamed or share a name.
+//
+// Every entity has a transform.  Tiles, and objects showing a tile, also get a mesh and a sprite selecting the tile from its tileset's texture.  Components are then created from the properties of the layer, the tileset tile and the object, each replacing those before it.
+func (m *Map) Entities(opts Options) ([]entity.Entity, error) {
+	b := builder{
+		m:      m,
+		opts:   opts,
+		meshes: make(map[string]components.MeshData),
+		sheets: make(map[*Tileset]components.SpriteSheet),
+		names:  make(map[string]bool),
+	}
+	if m.TileWidth > 0 && m.TileHeight > 0 {
+		b.scaleX = opts.TileSize.X() / float32(m.TileWidth)
+		b.scaleY = opts.TileSize.Y() / float32(m.TileHeight)
+	}
+
+	var entities []entity.Entity
+	for i, l := range m.Layers {
+		if !l.Visible {
+			continue
+		}
+		z := opts.Origin.Z() + float32(i)*opts.LayerHeight
+
+		switch l.Type {
+		case TileLayer:
+			for _, c := range l.Cells {
+				x, y := m.TileCenter(c.Col, c.Row)
+				ent, err := b.tile(fmt.Sprintf("%s_%d_%d", l.Name, c.Col, c.Row), c.GID, b.world(x+l.OffsetX, y+l.OffsetY, z), l.Properties, nil)
+				if err != nil {
+					return nil, err
+				}
+				entities = append(entities, ent)
+			}
+		case ObjectLayer:
+			for _, o := range l.Objects {
+				if !o.Visible {
+					continue
+				}
+				ent, err := b.object(l, o, z)
+				if err != nil {
+					return nil, err
+				}
+				entities = append(entities, ent)
+			}
+		}
+	}
+	return entities, nil
+}
+
+// TileCenter retrieves the center of a tile in pixels from the map's top left corner.
+func (m *Map) TileCenter(col, row int) (x, y float32) {
+	tw, th := float32(m.TileWidth), float32(m.TileHeight)
+
+	switch m.Orientation {
+	case Isometric:
+		return float32(col-row) * tw / 2, float32(col+row)*th/2 + th/2
+	case Staggered, Hexagonal:
+		side := float32(0)
+		if m.Orientation == Hexagonal {
+			side = float32(m.HexSideLength)
+		}
+		if m.StaggerAxis == "x" {
+			x = float32(col)*(tw+side)/2 + tw/2
+			y = float32(row)*th + th/2
+			if m.isStaggered(col) {
+				y += th / 2
+			}
+			return x, y
+		}
+		x = float32(col)*tw + tw/2
+		y = float32(row)*(th+side)/2 + th/2
+		if m.isStaggered(row) {
+			x += tw / 2
+		}
+		return x, y
+	default:
+		return float32(col)*tw + tw/2, float32(row)*th + th/2
+	}
+}
+
+// isStaggered returns true if the row or column along the stagger axis is shifted.
+func (m *Map) isStaggered(i int) bool {
+	odd := i&1 == 1
+	if m.StaggerIndex == "even" {
+		return !odd
+	}
+	return odd
+}
+
+// builder creates entities, sharing mesh data and sprite sheets between tiles.
+type builder struct {
+	m      *Map
+	opts   Options
+	scaleX float32
+	scaleY float32
+	meshes map[string]components.MeshData
+	sheets map[*Tileset]components.SpriteSheet
+	names  map[string]bool
+}
+
+// world converts a position in pixels to the world.
+func (b *builder) world(x, y, z float32) mgl32.Vec3 {
+	return mgl32.Vec3{b.opts.Origin.X() + x*b.scaleX, b.opts.Origin.Y() - y*b.scaleY, z}
+}
+
+// objectPixels converts an object position to pixels from the top left corner.  Objects on isometric maps are positioned along the tile axes in units of the tile height.
+func (b *builder) objectPixels(x, y float32) (float32, float32) {
+	m := b.m
+	if m.Orientation != Isometric || m.TileHeight == 0 {
+		return x, y
+	}
+	tx, ty := x/float32(m.TileHeight), y/float32(m.TileHeight)
+	return (tx - ty) * float32(m.TileWidth) / 2, (tx + ty) * float32(m.TileHeight) / 2
+}
+
+func (b *builder) object(l *Layer, o *Object, z float32) (entity.Entity, error) {
+	name := o.Name
+	if name == "" || b.names[name] {
+		name = fmt.Sprintf("%s_%d", l.Name, o.ID)
+	}
+
+	// Rectangles and ellipses are placed by their top left corner and tiles by their bottom left corner, both rotating about that corner.
+	angle := float64(o.Rotation) * math.Pi / 180
+	cos, sin := float32(math.Cos(angle)), float32(math.Sin(angle))
+	cx, cy := o.Width/2, o.Height/2
+	if o.GID != 0 {
+		cy = -cy
+	}
+	if o.Point || len(o.Polygon) > 0 {
+		cx, cy = 0, 0
+	}
+	px, py := b.objectPixels(o.X, o.Y)
+	px += cos*cx - sin*cy + l.OffsetX
+	py += sin*cx + cos*cy + l.OffsetY
+	pos := b.world(px, py, z)
+
+	props := l.Properties
+	ent, err := b.tile(name, o.GID, pos, props, o)
+	if err != nil {
+		return nil, err
+	}
+	if o.Rotation != 0 {
+		t := ent.Component(components.TypeTransform).(components.Transform)
+		rot := t.Rotation()
+		t.SetRotation(mgl32.Vec3{rot.X(), rot.Y(), rot.Z() - float32(angle)})
+	}
+	return ent, nil
+}
+
+// tile creates an entity at a position, showing a tile if the global tile id is not 0.  The object is nil for tiles on tile layers.
+func (b *builder) tile(name string, gid uint32, pos mgl32.Vec3, props Properties, o *Object) (entity.Entity, error) {
+	b.names[name] = true
+	ent := entity.NewEntity(name)
+
+	t := components.NewTransform()
+	t.SetTranslation(pos)
+	ent.AddComponent(t)
+
+	ts, id, isTile := b.m.Tileset(gid)
+	if isTile {
+		rotation, scaleX := flip(gid, b.m.Orientation == Hexagonal)
+		t.SetRotation(mgl32.Vec3{0, 0, rotation})
+		t.SetScale(mgl32.Vec3{scaleX, 1, 1})
+
+		props = props.Merge(ts.Properties)
+		if tile, ok := ts.Tile(id); ok {
+			props = props.Merge(tile.Properties)
+		}
+	}
+	if o != nil {
+		props = props.Merge(o.Properties)
+	}
+
+	comps, err := props.Components()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	for _, c := range comps {
+		switch c.Type() {
+		case components.TypeTransform:
+			// The transform from the properties is relative to where the tile or object was placed.
+			offset := c.(components.Transform)
+			t.SetTranslation(pos.Add(offset.Translation()))
+			t.SetOrientation(t.Orientation().Mul(offset.Orientation()))
+			t.SetScale(mgl32.Vec3{t.Scale().X() * offset.Scale().X(), offset.Scale().Y(), offset.Scale().Z()})
+			continue
+		case components.TypePathFollower:
+			// Paths without waypoints follow the points of their polygon or polyline.
+			follower := c.(components.PathFollower)
+			if o != nil && len(follower.Waypoints()) == 0 && len(o.Polygon) > 0 {
+				follower.SetWaypoints(b.polygon(o, pos.Z()))
+			}
+		}
+		ent.AddComponent(c)
+	}
+
+	if !isTile {
+		return ent, nil
+	}
+
+	if ent.Component(components.TypeMesh) == nil && b.opts.Mesh != "" {
+		mesh, err := b.mesh(b.opts.Mesh)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+		ent.AddComponent(mesh)
+	}
+	if ent.Component(components.TypeSprite) == nil {
+		sprite := components.NewSprite()
+		sprite.Set(b.sheet(ts, id))
+		if ts.Image.Source != "" {
+			sprite.SetFrame(id)
+		}
+		ent.AddComponent(sprite)
+	}
+
+	return ent, nil
+}
+
+// mesh creates a mesh component, reading each mesh file only once.
+func (b *builder) mesh(fileName string) (components.Mesh, error) {
+	md, ok := b.meshes[fileName]
+	if !ok {
+		loader := components.NewMesh()
+		if err := loader.Load(fileName); err != nil {
+			return nil, err
+		}
+		md = loader.Data()
+		b.meshes[fileName] = md
+	}
+	mesh := components.NewMesh()
+	mesh.Set(md)
+	return mesh, nil
+}
+
+// sheet retrieves the sprite sheet of a tileset with a frame for every tile.  Tiles of image collections are sheets of their own image.
+func (b *builder) sheet(ts *Tileset, id int) components.SpriteSheet {
+	if ts.Image.Source == "" {
+		ss := components.SpriteSheet{}
+		if tile, ok := ts.Tile(id); ok {
+			ss.TextureFile = tile.Image.Source
+			ss.Width = tile.Image.Width
+			ss.Height = tile.Image.Height
+		}
+		return ss
+	}
+
+	if ss, ok := b.sheets[ts]; ok {
+		return ss
+	}
+	ss := components.SpriteSheet{
+		TextureFile: ts.Image.Source,
+		Width:       ts.Image.Width,
+		Height:      ts.Image.Height,
+		Frames:      make([]components.SpriteRect, ts.TileCount),
+	}
+	for i := range ss.Frames {
+		x, y, w, h, _ := ts.TileRect(i)
+		ss.Frames[i] = components.SpriteRect{X: float32(x), Y: float32(y), Width: float32(w), Height: float32(h)}
+	}
+	b.sheets[ts] = ss
+	return ss
+}
+
+// polygon retrieves the points of an object's polygon or polyline in the world.
+func (b *builder) polygon(o *Object, z float32) []mgl32.Vec3 {
+	angle := float64(o.Rotation) * math.Pi / 180
+	cos, sin := float32(math.Cos(angle)), float32(math.Sin(angle))
+	points := make([]mgl32.Vec3, len(o.Polygon))
+	for i, p := range o.Polygon {
+		x, y := b.objectPixels(o.X+cos*p[0]-sin*p[1], o.Y+sin*p[0]+cos*p[1])
+		points[i] = b.world(x, y, z)
+	}
+	return points
+}
+
+// flip converts the flip flags of a global tile id into a rotation about Z and a scale along X.  Tiled flips diagonally first, then horizontally and then vertically, which always amounts to a rotation with or without a mirror.  Hexagonal maps have no diagonal flip and use that flag to rotate 60 degrees instead.
+func flip(gid uint32, hexagonal bool) (rotation, scaleX float32) {
+	// The columns of a 2x2 matrix in world space, where Y points up.
+	a, b, c, d := float32(1), float32(0), float32(0), float32(1)
+	if hexagonal {
+		// Tiled rotates clockwise on screen, which is clockwise looking down the world's Z axis.
+		degrees := 0.0
+		if gid&FlippedDiagonally != 0 {
+			degrees -= 60
+		}
+		if gid&RotatedHexagonal120 != 0 {
+			degrees -= 120
+		}
+		cos, sin := float32(math.Cos(degrees*math.Pi/180)), float32(math.Sin(degrees*math.Pi/180))
+		a, b, c, d = cos, sin, -sin, cos
+	} else if gid&FlippedDiagonally != 0 {
+		// Swapping the screen's x and y maps world (x, y) to (-y, -x).
+		a, b, c, d = -b, -a, -d, -c
+	}
+	if gid&FlippedHorizontally != 0 {
+		a, c = -a, -c
+	}
+	if gid&FlippedVertically != 0 {
+		b, d = -b, -d
+	}
+
+	scaleX = 1
+	if a*d-b*c < 0 {
+		// Mirror along X first so what remains is a rotation.
+		a, b = -a, -b
+		scaleX = -1
+	}
+	return float32(math.Atan2(float64(b), float64(a))), scaleX
+}
@@ -0,0 +1,89 @@
+package tiled
+
+import (
+	"math"
+	"testing"
+)
+
+// sameAngle returns true if two angles in degrees point the same way.
+func sameAngle(a, b float64) bool {
+	d := math.Mod(a-b, 360)
+	if d < 0 {
+		d += 360
+	}
+	return d < 1e-3 || 360-d < 1e-3
+}
+
+func TestFlip(t *testing.T) {
+	const (
+		h = FlippedHorizontally
+		v = FlippedVertically
+		d = FlippedDiagonally
+		r = RotatedHexagonal120
+	)
+	// Rotations are counter clockwise in the world, so Tiled's clockwise turns are negative.
+	tests := []struct {
+		name      string
+		flags     uint32
+		hexagonal bool
+		rotation  float64
+		scaleX    float32
+	}{
+		{"none", 0, false, 0, 1},
+		{"horizontal", h, false, 0, -1},
+		{"vertical", v, false, 180, -1},
+		{"horizontal and vertical", h | v, false, 180, 1},
+		{"diagonal", d, false, 90, -1},
+		{"diagonal and horizontal turn clockwise", d | h, false, -90, 1},
+		{"diagonal and vertical turn counter clockwise", d | v, false, 90, 1},
+		{"all three", d | h | v, false, -90, -1},
+		{"120 degrees is only for hexagonal maps", r, false, 0, 1},
+		{"hexagonal none", 0, true, 0, 1},
+		{"hexagonal horizontal", h, true, 0, -1},
+		{"hexagonal vertical", v, true, 180, -1},
+		{"hexagonal horizontal and vertical", h | v, true, 180, 1},
+		{"hexagonal 60 degrees", d, true, -60, 1},
+		{"hexagonal 120 degrees", r, true, -120, 1},
+		{"hexagonal 180 degrees", d | r, true, 180, 1},
+		{"hexagonal 60 degrees and horizontal", d | h, true, 60, -1},
+		{"hexagonal 120 degrees and vertical", r | v, true, -60, -1},
+		{"hexagonal 180 degrees and horizontal", d | r | h, true, 180, -1},
+		{"hexagonal everything", d | r | h | v, true, 0, 1},
+	}
+	for _, test := range tests {
+		rotation, scaleX := flip(test.flags|7, test.hexagonal)
+		if degrees := float64(rotation) * 180 / math.Pi; !sameAngle(degrees, test.rotation) || scaleX != test.scaleX {
+			t.Errorf("%s: flip = %v degrees and a scale of %v, want %v degrees and a scale of %v", test.name, degrees, scaleX, test.rotation, test.scaleX)
+		}
+	}
+}
+
+func TestTileCenter(t *testing.T) {
+	tests := []struct {
+		name     string
+		m        Map
+		col, row int
+		x, y     float32
+	}{
+		{"orthogonal origin", Map{Orientation: Orthogonal, TileWidth: 32, TileHeight: 16}, 0, 0, 16, 8},
+		{"orthogonal", Map{Orientation: Orthogonal, TileWidth: 32, TileHeight: 16}, 2, 3, 80, 56},
+		{"isometric down the column", Map{Orientation: Isometric, TileWidth: 64, TileHeight: 32}, 1, 0, 32, 32},
+		{"isometric down the row", Map{Orientation: Isometric, TileWidth: 64, TileHeight: 32}, 0, 1, -32, 32},
+		{"staggered odd rows", Map{Orientation: Staggered, TileWidth: 64, TileHeight: 32, StaggerAxis: "y", StaggerIndex: "odd"}, 0, 0, 32, 16},
+		{"staggered odd rows shifted", Map{Orientation: Staggered, TileWidth: 64, TileHeight: 32, StaggerAxis: "y", StaggerIndex: "odd"}, 1, 1, 128, 32},
+		{"staggered even rows shifted", Map{Orientation: Staggered, TileWidth: 64, TileHeight: 32, StaggerAxis: "y", StaggerIndex: "even"}, 0, 0, 64, 16},
+		{"staggered even rows", Map{Orientation: Staggered, TileWidth: 64, TileHeight: 32, StaggerAxis: "y", StaggerIndex: "even"}, 1, 1, 96, 32},
+		{"staggered odd columns", Map{Orientation: Staggered, TileWidth: 64, TileHeight: 32, StaggerAxis: "x", StaggerIndex: "odd"}, 2, 0, 96, 16},
+		{"staggered odd columns shifted", Map{Orientation: Staggered, TileWidth: 64, TileHeight: 32, StaggerAxis: "x", StaggerIndex: "odd"}, 1, 0, 64, 32},
+		{"hexagonal odd rows", Map{Orientation: Hexagonal, TileWidth: 28, TileHeight: 32, HexSideLength: 16, StaggerAxis: "y", StaggerIndex: "odd"}, 0, 2, 14, 64},
+		{"hexagonal odd rows shifted", Map{Orientation: Hexagonal, TileWidth: 28, TileHeight: 32, HexSideLength: 16, StaggerAxis: "y", StaggerIndex: "odd"}, 0, 1, 28, 40},
+		{"hexagonal even columns shifted", Map{Orientation: Hexagonal, TileWidth: 32, TileHeight: 28, HexSideLength: 16, StaggerAxis: "x", StaggerIndex: "even"}, 0, 0, 16, 28},
+		{"hexagonal even columns", Map{Orientation: Hexagonal, TileWidth: 32, TileHeight: 28, HexSideLength: 16, StaggerAxis: "x", StaggerIndex: "even"}, 1, 0, 40, 14},
+		{"hexagonal even columns further down", Map{Orientation: Hexagonal, TileWidth: 32, TileHeight: 28, HexSideLength: 16, StaggerAxis: "x", StaggerIndex: "even"}, 2, 1, 64, 56},
+	}
+	for _, test := range tests {
+		if x, y := test.m.TileCenter(test.col, test.row); x != test.x || y != test.y {
+			t.Errorf("%s: TileCenter(%d, %d) = %v, %v, want %v, %v", test.name, test.col, test.row, x, y, test.x, test.y)
+		}
+	}
+}
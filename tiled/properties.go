@@ -0,0 +1,121 @@
+package tiled
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Ariemeth/quantum-pulse/components"
+)
+
+// Property is a custom property set in Tiled.  Members of class properties are flattened into properties named "class.member".
+type Property struct {
+	Name  string
+	Type  string
+	Value string
+}
+
+// Properties is a list of custom properties.
+type Properties []Property
+
+// Get retrieves the value of a property.
+func (p Properties) Get(name string) (string, bool) {
+	for _, prop := range p {
+		if prop.Name == name {
+			return prop.Value, true
+		}
+	}
+	return "", false
+}
+
+// Merge creates a list holding both lists of properties, where the properties passed in replace those with the same name.
+func (p Properties) Merge(overrides Properties) Properties {
+	merged := make(Properties, 0, len(p)+len(overrides))
+	for _, prop := range p {
+		if _, ok := overrides.Get(prop.Name); !ok {
+			merged = append(merged, prop)
+		}
+	}
+	return append(merged, overrides...)
+}
+
+// Components creates a component for every registered component type the properties refer to.  Properties that do not start with the name of a registered component are left alone.  Components are returned in alphabetical order of their type.
+func (p Properties) Components() ([]components.Component, error) {
+	data := make(map[string]map[string]interface{})
+	for _, prop := range p {
+		componentType, field := prop.Name, "file"
+		if i := strings.Index(prop.Name, "."); i >= 0 {
+			componentType, field = prop.Name[:i], prop.Name[i+1:]
+		} else if prop.Type != "" && prop.Type != "string" && prop.Type != "file" {
+			continue
+		}
+		if field == "" || !components.IsRegistered(componentType) {
+			continue
+		}
+		if data[componentType] == nil {
+			data[componentType] = make(map[string]interface{})
+		}
+		setField(data[componentType], strings.Split(field, "."), prop.value())
+	}
+
+	types := make([]string, 0, len(data))
+	for t := range data {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	comps := make([]components.Component, 0, len(types))
+	for _, t := range types {
+		raw, err := json.Marshal(data[t])
+		if err != nil {
+			return nil, err
+		}
+		c, err := components.CreateComponent(t, raw)
+		if err != nil {
+			return nil, err
+		}
+		comps = append(comps, c)
+	}
+	return comps, nil
+}
+
+// value converts the property to the value it has in a component's json.  Strings holding a json array or object, such as "[1, 0, 0]" for a vector, are used as json.
+func (prop Property) value() interface{} {
+	switch prop.Type {
+	case "int", "object":
+		if v, err := strconv.ParseInt(prop.Value, 10, 64); err == nil {
+			return v
+		}
+	case "float":
+		if v, err := strconv.ParseFloat(prop.Value, 64); err == nil {
+			return v
+		}
+	case "bool":
+		if v, err := strconv.ParseBool(prop.Value); err == nil {
+			return v
+		}
+	}
+
+	trimmed := strings.TrimSpace(prop.Value)
+	if strings.HasPrefix(trimmed, "[") || strings.HasPrefix(trimmed, "{") {
+		var v interface{}
+		if err := json.Unmarshal([]byte(trimmed), &v); err == nil {
+			return json.RawMessage(trimmed)
+		}
+	}
+	return prop.Value
+}
+
+// setField sets a value in nested json objects, creating the objects along the path as needed.
+func setField(object map[string]interface{}, path []string, value interface{}) {
+	for _, key := range path[:len(path)-1] {
+		child, ok := object[key].(map[string]interface{})
+		if !ok {
+			child = make(map[string]interface{})
+			object[key] = child
+		}
+		object = child
+	}
+	object[path[len(path)-1]] = value
+}
@@ -0,0 +1,223 @@
+package tiled
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// MapSrcDir is the expected location of maps.
+	MapSrcDir = "assets/maps/"
+	// textureSrcDir is where the resources package loads textures from, so tileset images are named relative to it.
+	textureSrcDir = "assets/textures/"
+)
+
+// Map orientations.
+const (
+	Orthogonal = "orthogonal"
+	Isometric  = "isometric"
+	Staggered  = "staggered"
+	Hexagonal  = "hexagonal"
+)
+
+// Flags stored in the high bits of a global tile id.
+const (
+	FlippedHorizontally uint32 = 0x80000000
+	FlippedVertically   uint32 = 0x40000000
+	FlippedDiagonally   uint32 = 0x20000000
+	RotatedHexagonal120 uint32 = 0x10000000
+	flagMask                   = FlippedHorizontally | FlippedVertically | FlippedDiagonally | RotatedHexagonal120
+)
+
+// Map is a map made in Tiled.
+type Map struct {
+	Orientation   string
+	Width         int
+	Height        int
+	TileWidth     int
+	TileHeight    int
+	HexSideLength int
+	// StaggerAxis is "x" or "y" and only used by staggered and hexagonal maps.
+	StaggerAxis string
+	// StaggerIndex is "odd" or "even" and only used by staggered and hexagonal maps.
+	StaggerIndex string
+	Infinite     bool
+	Properties   Properties
+	Tilesets     []*Tileset
+	Layers       []*Layer
+}
+
+// Tileset is a set of tiles cut from one image or made of one image per tile.  Tile ids within a tileset start at 0 and FirstGID is the global id of the first tile.
+type Tileset struct {
+	FirstGID   uint32
+	Name       string
+	TileWidth  int
+	TileHeight int
+	Spacing    int
+	Margin     int
+	TileCount  int
+	Columns    int
+	Image      Image
+	Properties Properties
+	Tiles      []*TilesetTile
+}
+
+// TilesetTile holds what a tileset stores about a single tile.  Only tiles with properties or their own image are listed.
+type TilesetTile struct {
+	ID         int
+	Class      string
+	Image      Image
+	Properties Properties
+}
+
+// Image is a texture used by a tileset.  Source is relative to the texture directory.
+type Image struct {
+	Source string
+	Width  int
+	Height int
+}
+
+// LayerType is the kind of a layer.
+type LayerType string
+
+// Layer types.  Group layers are flattened into the layers they contain.
+const (
+	TileLayer   LayerType = "tilelayer"
+	ObjectLayer LayerType = "objectgroup"
+	ImageLayer  LayerType = "imagelayer"
+)
+
+// Layer is a layer of tiles or objects.  Layers inside groups are named by their path such as "group/layer".
+type Layer struct {
+	Name       string
+	Type       LayerType
+	Visible    bool
+	OffsetX    float32
+	OffsetY    float32
+	Properties Properties
+	// Cells holds the non empty tiles of a tile layer.
+	Cells []Cell
+	// Objects holds the objects of an object layer.
+	Objects []*Object
+}
+
+// Cell is a tile placed on a tile layer.
+type Cell struct {
+	Col int
+	Row int
+	GID uint32
+}
+
+// Object is a shape, point or tile placed freely on an object layer.  Positions are in pixels.  Tile objects are placed by their bottom left corner and other objects by their top left corner.  Rotation is in degrees clockwise.
+type Object struct {
+	ID       int
+	Name     string
+	Class    string
+	X        float32
+	Y        float32
+	Width    float32
+	Height   float32
+	Rotation float32
+	GID      uint32
+	Visible  bool
+	Ellipse  bool
+	Point    bool
+	// Polygon holds the points of a polygon or polyline relative to the object's position.
+	Polygon    [][2]float32
+	Polyline   bool
+	Properties Properties
+}
+
+// Load loads a map from a TMX file or from a JSON file with a .json or .tmj extension.  The file name is relative to MapSrcDir.
+func Load(fileName string) (*Map, error) {
+	fullFileName := fileName
+	if !strings.Contains(fileName, MapSrcDir) {
+		fullFileName = fmt.Sprintf("%s%s", MapSrcDir, fileName)
+	}
+
+	data, err := ioutil.ReadFile(fullFileName)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := path.Dir(filepath.ToSlash(fullFileName))
+	if isJSON(fullFileName) {
+		return ParseJSON(data, dir)
+	}
+	return ParseTMX(data, dir)
+}
+
+// TileID retrieves the global tile id without its flip flags.
+func TileID(gid uint32) uint32 {
+	return gid &^ flagMask
+}
+
+// Tileset retrieves the tileset a global tile id belongs to and the tile's id within it.
+func (m *Map) Tileset(gid uint32) (*Tileset, int, bool) {
+	id := TileID(gid)
+	if id == 0 {
+		return nil, 0, false
+	}
+	var found *Tileset
+	for _, ts := range m.Tilesets {
+		if ts.FirstGID <= id && (found == nil || ts.FirstGID > found.FirstGID) {
+			found = ts
+		}
+	}
+	if found == nil {
+		return nil, 0, false
+	}
+	return found, int(id - found.FirstGID), true
+}
+
+// Tile retrieves what the tileset stores about one of its tiles.
+func (ts *Tileset) Tile(id int) (*TilesetTile, bool) {
+	for _, t := range ts.Tiles {
+		if t.ID == id {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// TileRect retrieves the area of a tile in the tileset image in pixels.  The boolean is false for tiles that have their own image.
+func (ts *Tileset) TileRect(id int) (x, y, w, h int, ok bool) {
+	if ts.Image.Source == "" || ts.Columns <= 0 {
+		return 0, 0, 0, 0, false
+	}
+	col, row := id%ts.Columns, id/ts.Columns
+	x = ts.Margin + col*(ts.TileWidth+ts.Spacing)
+	y = ts.Margin + row*(ts.TileHeight+ts.Spacing)
+	return x, y, ts.TileWidth, ts.TileHeight, true
+}
+
+// finish fills in what can be derived once a tileset has been read.
+func (ts *Tileset) finish() {
+	if ts.Columns <= 0 && ts.Image.Width > 0 && ts.TileWidth > 0 {
+		ts.Columns = (ts.Image.Width - 2*ts.Margin + ts.Spacing) / (ts.TileWidth + ts.Spacing)
+	}
+	if ts.TileCount <= 0 && ts.Columns > 0 && ts.Image.Height > 0 && ts.TileHeight > 0 {
+		rows := (ts.Image.Height - 2*ts.Margin + ts.Spacing) / (ts.TileHeight + ts.Spacing)
+		ts.TileCount = rows * ts.Columns
+	}
+}
+
+// texturePath converts an image path relative to the file referencing it into a path relative to the texture directory.  Images outside of the texture directory are named by their file name.
+func texturePath(dir, source string) string {
+	if source == "" {
+		return ""
+	}
+	full := path.Clean(path.Join(dir, filepath.ToSlash(source)))
+	if i := strings.LastIndex(full, textureSrcDir); i >= 0 {
+		return full[i+len(textureSrcDir):]
+	}
+	return path.Base(full)
+}
+
+func isJSON(fileName string) bool {
+	ext := strings.ToLower(path.Ext(fileName))
+	return ext == ".json" || ext == ".tmj" || ext == ".tsj"
+}
@@ -0,0 +1,388 @@
+package tiled
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+type jsonMap struct {
+	Orientation   string         `json:"orientation"`
+	Width         int            `json:"width"`
+	Height        int            `json:"height"`
+	TileWidth     int            `json:"tilewidth"`
+	TileHeight    int            `json:"tileheight"`
+	HexSideLength int            `json:"hexsidelength"`
+	StaggerAxis   string         `json:"staggeraxis"`
+	StaggerIndex  string         `json:"staggerindex"`
+	Infinite      bool           `json:"infinite"`
+	Properties    jsonProperties `json:"properties"`
+	Tilesets      []jsonTileset  `json:"tilesets"`
+	Layers        []jsonLayer    `json:"layers"`
+}
+
+type jsonTileset struct {
+	FirstGID    uint32         `json:"firstgid"`
+	Source      string         `json:"source"`
+	Name        string         `json:"name"`
+	TileWidth   int            `json:"tilewidth"`
+	TileHeight  int            `json:"tileheight"`
+	Spacing     int            `json:"spacing"`
+	Margin      int            `json:"margin"`
+	TileCount   int            `json:"tilecount"`
+	Columns     int            `json:"columns"`
+	Image       string         `json:"image"`
+	ImageWidth  int            `json:"imagewidth"`
+	ImageHeight int            `json:"imageheight"`
+	Properties  jsonProperties `json:"properties"`
+	Tiles       []jsonTile     `json:"tiles"`
+}
+
+type jsonTile struct {
+	ID          int            `json:"id"`
+	Type        string         `json:"type"`
+	Class       string         `json:"class"`
+	Image       string         `json:"image"`
+	ImageWidth  int            `json:"imagewidth"`
+	ImageHeight int            `json:"imageheight"`
+	Properties  jsonProperties `json:"properties"`
+}
+
+type jsonLayer struct {
+	Type        string          `json:"type"`
+	Name        string          `json:"name"`
+	Visible     *bool           `json:"visible"`
+	OffsetX     float32         `json:"offsetx"`
+	OffsetY     float32         `json:"offsety"`
+	Width       int             `json:"width"`
+	Properties  jsonProperties  `json:"properties"`
+	Encoding    string          `json:"encoding"`
+	Compression string          `json:"compression"`
+	Data        json.RawMessage `json:"data"`
+	Chunks      []jsonChunk     `json:"chunks"`
+	Objects     []jsonObject    `json:"objects"`
+	Layers      []jsonLayer     `json:"layers"`
+}
+
+type jsonChunk struct {
+	X      int             `json:"x"`
+	Y      int             `json:"y"`
+	Width  int             `json:"width"`
+	Height int             `json:"height"`
+	Data   json.RawMessage `json:"data"`
+}
+
+type jsonObject struct {
+	ID         int            `json:"id"`
+	Name       string         `json:"name"`
+	Type       string         `json:"type"`
+	Class      string         `json:"class"`
+	X          float32        `json:"x"`
+	Y          float32        `json:"y"`
+	Width      float32        `json:"width"`
+	Height     float32        `json:"height"`
+	Rotation   float32        `json:"rotation"`
+	GID        uint32         `json:"gid"`
+	Visible    *bool          `json:"visible"`
+	Ellipse    bool           `json:"ellipse"`
+	Point      bool           `json:"point"`
+	Polygon    []jsonPoint    `json:"polygon"`
+	Polyline   []jsonPoint    `json:"polyline"`
+	Properties jsonProperties `json:"properties"`
+}
+
+type jsonPoint struct {
+	X float32 `json:"x"`
+	Y float32 `json:"y"`
+}
+
+type jsonProperties []jsonProperty
+
+type jsonProperty struct {
+	Name  string          `json:"name"`
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+// ParseJSON parses a map in Tiled's JSON format.  Dir is the directory of the map file, which the paths of external tilesets and images are relative to.
+func ParseJSON(data []byte, dir string) (*Map, error) {
+	jm := jsonMap{}
+	if err := json.Unmarshal(data, &jm); err != nil {
+		return nil, err
+	}
+
+	m := Map{
+		Orientation:   jm.Orientation,
+		Width:         jm.Width,
+		Height:        jm.Height,
+		TileWidth:     jm.TileWidth,
+		TileHeight:    jm.TileHeight,
+		HexSideLength: jm.HexSideLength,
+		StaggerAxis:   jm.StaggerAxis,
+		StaggerIndex:  jm.StaggerIndex,
+		Infinite:      jm.Infinite,
+	}
+
+	props, err := jm.Properties.convert("")
+	if err != nil {
+		return nil, err
+	}
+	m.Properties = props
+
+	for _, jt := range jm.Tilesets {
+		var ts *Tileset
+		if jt.Source != "" {
+			ts, err = loadTileset(dir, jt.Source)
+		} else {
+			ts, err = jt.convert(dir)
+		}
+		if err != nil {
+			return nil, err
+		}
+		ts.FirstGID = jt.FirstGID
+		m.Tilesets = append(m.Tilesets, ts)
+	}
+
+	for _, jl := range jm.Layers {
+		layers, err := jl.convert("", true)
+		if err != nil {
+			return nil, err
+		}
+		m.Layers = append(m.Layers, layers...)
+	}
+
+	return &m, nil
+}
+
+// ParseTilesetJSON parses a tileset in Tiled's JSON format.  Dir is the directory of the tileset file, which the paths of images are relative to.
+func ParseTilesetJSON(data []byte, dir string) (*Tileset, error) {
+	jt := jsonTileset{}
+	if err := json.Unmarshal(data, &jt); err != nil {
+		return nil, err
+	}
+	return jt.convert(dir)
+}
+
+func (jt jsonTileset) convert(dir string) (*Tileset, error) {
+	props, err := jt.Properties.convert("")
+	if err != nil {
+		return nil, err
+	}
+	ts := Tileset{
+		Name:       jt.Name,
+		TileWidth:  jt.TileWidth,
+		TileHeight: jt.TileHeight,
+		Spacing:    jt.Spacing,
+		Margin:     jt.Margin,
+		TileCount:  jt.TileCount,
+		Columns:    jt.Columns,
+		Image:      Image{Source: texturePath(dir, jt.Image), Width: jt.ImageWidth, Height: jt.ImageHeight},
+		Properties: props,
+	}
+	for _, tile := range jt.Tiles {
+		props, err := tile.Properties.convert("")
+		if err != nil {
+			return nil, err
+		}
+		class := tile.Class
+		if class == "" {
+			class = tile.Type
+		}
+		ts.Tiles = append(ts.Tiles, &TilesetTile{
+			ID:         tile.ID,
+			Class:      class,
+			Image:      Image{Source: texturePath(dir, tile.Image), Width: tile.ImageWidth, Height: tile.ImageHeight},
+			Properties: props,
+		})
+	}
+	ts.finish()
+	return &ts, nil
+}
+
+// convert converts a layer and flattens groups into the layers they contain.  Layers in hidden groups are hidden.
+func (jl jsonLayer) convert(prefix string, visible bool) ([]*Layer, error) {
+	name := prefix + jl.Name
+	visible = visible && (jl.Visible == nil || *jl.Visible)
+	props, err := jl.Properties.convert("")
+	if err != nil {
+		return nil, err
+	}
+
+	switch LayerType(jl.Type) {
+	case "group":
+		var layers []*Layer
+		for _, child := range jl.Layers {
+			children, err := child.convert(name+"/", visible)
+			if err != nil {
+				return nil, err
+			}
+			for _, c := range children {
+				c.OffsetX += jl.OffsetX
+				c.OffsetY += jl.OffsetY
+				c.Properties = props.Merge(c.Properties)
+			}
+			layers = append(layers, children...)
+		}
+		return layers, nil
+	case TileLayer, ObjectLayer, ImageLayer:
+	default:
+		return nil, nil
+	}
+
+	l := Layer{
+		Name:       name,
+		Type:       LayerType(jl.Type),
+		Visible:    visible,
+		OffsetX:    jl.OffsetX,
+		OffsetY:    jl.OffsetY,
+		Properties: props,
+	}
+
+	switch l.Type {
+	case TileLayer:
+		if len(jl.Chunks) > 0 {
+			for _, chunk := range jl.Chunks {
+				gids, err := jsonGIDs(jl.Encoding, jl.Compression, chunk.Data)
+				if err != nil {
+					return nil, fmt.Errorf("layer %s: %v", name, err)
+				}
+				l.Cells = append(l.Cells, cells(gids, chunk.X, chunk.Y, chunk.Width)...)
+			}
+		} else {
+			gids, err := jsonGIDs(jl.Encoding, jl.Compression, jl.Data)
+			if err != nil {
+				return nil, fmt.Errorf("layer %s: %v", name, err)
+			}
+			l.Cells = cells(gids, 0, 0, jl.Width)
+		}
+	case ObjectLayer:
+		for _, jo := range jl.Objects {
+			o, err := jo.convert()
+			if err != nil {
+				return nil, fmt.Errorf("layer %s: %v", name, err)
+			}
+			l.Objects = append(l.Objects, o)
+		}
+	}
+
+	return []*Layer{&l}, nil
+}
+
+// jsonGIDs retrieves the global tile ids of a layer or chunk, which are either an array or a base64 string.
+func jsonGIDs(encoding, compression string, data json.RawMessage) ([]uint32, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	if encoding == "base64" {
+		var text string
+		if err := json.Unmarshal(data, &text); err != nil {
+			return nil, err
+		}
+		return decodeData(encoding, compression, text)
+	}
+	var gids []uint32
+	err := json.Unmarshal(data, &gids)
+	return gids, err
+}
+
+func (jo jsonObject) convert() (*Object, error) {
+	props, err := jo.Properties.convert("")
+	if err != nil {
+		return nil, err
+	}
+	class := jo.Class
+	if class == "" {
+		class = jo.Type
+	}
+	o := Object{
+		ID:         jo.ID,
+		Name:       jo.Name,
+		Class:      class,
+		X:          jo.X,
+		Y:          jo.Y,
+		Width:      jo.Width,
+		Height:     jo.Height,
+		Rotation:   jo.Rotation,
+		GID:        jo.GID,
+		Visible:    jo.Visible == nil || *jo.Visible,
+		Ellipse:    jo.Ellipse,
+		Point:      jo.Point,
+		Properties: props,
+	}
+
+	points := jo.Polygon
+	if jo.Polyline != nil {
+		points = jo.Polyline
+		o.Polyline = true
+	}
+	for _, p := range points {
+		o.Polygon = append(o.Polygon, [2]float32{p.X, p.Y})
+	}
+	return &o, nil
+}
+
+// convert converts properties, flattening the members of class properties into properties named after the class property.
+func (jp jsonProperties) convert(prefix string) (Properties, error) {
+	var props Properties
+	for _, p := range jp {
+		members, err := jsonValue(prefix+p.Name, p.Type, p.Value)
+		if err != nil {
+			return nil, err
+		}
+		props = append(props, members...)
+	}
+	return props, nil
+}
+
+// jsonValue converts a property value.  Class values are objects whose members have no declared type, so their type is taken from the json.
+func jsonValue(name, typ string, value json.RawMessage) (Properties, error) {
+	if len(value) == 0 {
+		return Properties{{Name: name, Type: typ}}, nil
+	}
+
+	switch value[0] {
+	case '{':
+		members := make(map[string]json.RawMessage)
+		if err := json.Unmarshal(value, &members); err != nil {
+			return nil, fmt.Errorf("property %s: %v", name, err)
+		}
+		var props Properties
+		for _, member := range sortedKeys(members) {
+			p, err := jsonValue(name+"."+member, "", members[member])
+			if err != nil {
+				return nil, err
+			}
+			props = append(props, p...)
+		}
+		return props, nil
+	case '"':
+		var s string
+		if err := json.Unmarshal(value, &s); err != nil {
+			return nil, fmt.Errorf("property %s: %v", name, err)
+		}
+		if typ == "" {
+			typ = "string"
+		}
+		return Properties{{Name: name, Type: typ, Value: s}}, nil
+	case 't', 'f':
+		return Properties{{Name: name, Type: "bool", Value: string(value)}}, nil
+	default:
+		if typ == "" {
+			typ = "float"
+			if _, err := strconv.ParseInt(string(value), 10, 64); err == nil {
+				typ = "int"
+			}
+		}
+		return Properties{{Name: name, Type: typ, Value: string(value)}}, nil
+	}
+}
+
+func sortedKeys(m map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,65 @@
+package tiled
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+const testTMJ = `{
+ "orientation": "orthogonal", "width": 2, "height": 2, "tilewidth": 32, "tileheight": 32, "infinite": false,
+ "properties": [{"name": "music", "type": "string", "value": "theme.ogg"}],
+ "tilesets": [{
+  "firstgid": 1, "name": "tiles", "tilewidth": 32, "tileheight": 32, "tilecount": 2, "columns": 2,
+  "image": "../textures/tiles.png", "imagewidth": 64, "imageheight": 32,
+  "tiles": [{"id": 1, "class": "water", "properties": [{"name": "speed", "type": "float", "value": 0.5}]}]
+ }],
+ "layers": [
+  {"type": "tilelayer", "name": "ground", "width": 2, "height": 2, "visible": true, "data": [1, 2, 0, 2147483649]},
+  {"type": "group", "name": "things", "offsetx": 4, "visible": false,
+   "properties": [{"name": "team", "type": "string", "value": "red"}],
+   "layers": [{"type": "objectgroup", "name": "units", "offsety": 2, "visible": true, "objects": [
+    {"id": 1, "name": "path", "x": 10, "y": 20, "visible": true,
+     "polyline": [{"x": 0, "y": 0}, {"x": 8, "y": 0}, {"x": 8, "y": 8}],
+     "properties": [{"name": "spawn", "type": "class", "propertytype": "Spawn", "value": {"count": 3}}]},
+    {"id": 2, "gid": 2, "x": 32, "y": 64, "width": 32, "height": 32, "visible": true}
+   ]}]}
+ ]
+}`
+
+func TestParseJSON(t *testing.T) {
+	m, err := ParseJSON([]byte(testTMJ), "assets/maps")
+	if err != nil {
+		t.Fatalf("ParseJSON failed: %v", err)
+	}
+	compareMaps(t, "TMJ", m, testMap())
+}
+
+func TestParseJSONData(t *testing.T) {
+	gids := []uint32{0, 3, FlippedVertically | 2, 0}
+	tests := []struct {
+		name  string
+		layer string
+		want  []Cell
+	}{
+		{"array", `"data": [0, 3, 1073741826, 0]`, []Cell{{1, 0, 3}, {0, 1, FlippedVertically | 2}}},
+		{"base64", fmt.Sprintf(`"encoding": "base64", "data": %q`, encodeGIDs(t, "", gids)), []Cell{{1, 0, 3}, {0, 1, FlippedVertically | 2}}},
+		{"gzip", fmt.Sprintf(`"encoding": "base64", "compression": "gzip", "data": %q`, encodeGIDs(t, "gzip", gids)), []Cell{{1, 0, 3}, {0, 1, FlippedVertically | 2}}},
+		{"zlib chunks", fmt.Sprintf(`"encoding": "base64", "compression": "zlib", "chunks": [{"x": -2, "y": 0, "width": 2, "height": 2, "data": %q}, {"x": 0, "y": 0, "width": 2, "height": 2, "data": %q}]`, encodeGIDs(t, "zlib", gids), encodeGIDs(t, "zlib", []uint32{5, 0, 0, 0})), []Cell{{-1, 0, 3}, {-2, 1, FlippedVertically | 2}, {0, 0, 5}}},
+	}
+	for _, test := range tests {
+		tmj := fmt.Sprintf(`{"orientation": "orthogonal", "width": 2, "height": 2, "tilewidth": 32, "tileheight": 32, "layers": [{"type": "tilelayer", "name": "ground", "width": 2, "height": 2, %s}]}`, test.layer)
+		m, err := ParseJSON([]byte(tmj), "")
+		if err != nil {
+			t.Errorf("%s: ParseJSON failed: %v", test.name, err)
+			continue
+		}
+		if len(m.Layers) != 1 || !reflect.DeepEqual(m.Layers[0].Cells, test.want) {
+			t.Errorf("%s: layers = %s, want one layer with cells %v", test.name, describe(m.Layers), test.want)
+		}
+	}
+
+	if _, err := ParseJSON([]byte(`{"layers": [{"type": "tilelayer", "name": "broken", "width": 1, "encoding": "base64", "data": "not base64!"}]}`), ""); err == nil {
+		t.Errorf("ParseJSON of a layer with invalid data did not fail")
+	}
+}
@@ -0,0 +1,375 @@
+package tiled
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strconv"
+	"strings"
+)
+
+type xmlMap struct {
+	Orientation   string        `xml:"orientation,attr"`
+	Width         int           `xml:"width,attr"`
+	Height        int           `xml:"height,attr"`
+	TileWidth     int           `xml:"tilewidth,attr"`
+	TileHeight    int           `xml:"tileheight,attr"`
+	HexSideLength int           `xml:"hexsidelength,attr"`
+	StaggerAxis   string        `xml:"staggeraxis,attr"`
+	StaggerIndex  string        `xml:"staggerindex,attr"`
+	Infinite      int           `xml:"infinite,attr"`
+	Properties    xmlProperties `xml:"properties"`
+	Tilesets      []xmlTileset  `xml:"tileset"`
+	// Layers catches every other element so layers keep the order they are drawn in.
+	Layers []xmlLayer `xml:",any"`
+}
+
+type xmlTileset struct {
+	FirstGID   uint32        `xml:"firstgid,attr"`
+	Source     string        `xml:"source,attr"`
+	Name       string        `xml:"name,attr"`
+	TileWidth  int           `xml:"tilewidth,attr"`
+	TileHeight int           `xml:"tileheight,attr"`
+	Spacing    int           `xml:"spacing,attr"`
+	Margin     int           `xml:"margin,attr"`
+	TileCount  int           `xml:"tilecount,attr"`
+	Columns    int           `xml:"columns,attr"`
+	Image      xmlImage      `xml:"image"`
+	Properties xmlProperties `xml:"properties"`
+	Tiles      []xmlTile     `xml:"tile"`
+}
+
+type xmlTile struct {
+	ID         int           `xml:"id,attr"`
+	Type       string        `xml:"type,attr"`
+	Class      string        `xml:"class,attr"`
+	Image      xmlImage      `xml:"image"`
+	Properties xmlProperties `xml:"properties"`
+}
+
+type xmlImage struct {
+	Source string `xml:"source,attr"`
+	Width  int    `xml:"width,attr"`
+	Height int    `xml:"height,attr"`
+}
+
+type xmlLayer struct {
+	XMLName    xml.Name
+	Name       string        `xml:"name,attr"`
+	Visible    string        `xml:"visible,attr"`
+	OffsetX    float32       `xml:"offsetx,attr"`
+	OffsetY    float32       `xml:"offsety,attr"`
+	Width      int           `xml:"width,attr"`
+	Properties xmlProperties `xml:"properties"`
+	Data       xmlData       `xml:"data"`
+	Objects    []xmlObject   `xml:"object"`
+	Image      xmlImage      `xml:"image"`
+	Layers     []xmlLayer    `xml:",any"`
+}
+
+type xmlData struct {
+	Encoding    string     `xml:"encoding,attr"`
+	Compression string     `xml:"compression,attr"`
+	Text        string     `xml:",chardata"`
+	Tiles       []xmlGID   `xml:"tile"`
+	Chunks      []xmlChunk `xml:"chunk"`
+}
+
+type xmlGID struct {
+	GID uint32 `xml:"gid,attr"`
+}
+
+type xmlChunk struct {
+	X      int      `xml:"x,attr"`
+	Y      int      `xml:"y,attr"`
+	Width  int      `xml:"width,attr"`
+	Height int      `xml:"height,attr"`
+	Text   string   `xml:",chardata"`
+	Tiles  []xmlGID `xml:"tile"`
+}
+
+type xmlObject struct {
+	ID         int           `xml:"id,attr"`
+	Name       string        `xml:"name,attr"`
+	Type       string        `xml:"type,attr"`
+	Class      string        `xml:"class,attr"`
+	X          float32       `xml:"x,attr"`
+	Y          float32       `xml:"y,attr"`
+	Width      float32       `xml:"width,attr"`
+	Height     float32       `xml:"height,attr"`
+	Rotation   float32       `xml:"rotation,attr"`
+	GID        uint32        `xml:"gid,attr"`
+	Visible    string        `xml:"visible,attr"`
+	Ellipse    *struct{}     `xml:"ellipse"`
+	Point      *struct{}     `xml:"point"`
+	Polygon    *xmlPoints    `xml:"polygon"`
+	Polyline   *xmlPoints    `xml:"polyline"`
+	Properties xmlProperties `xml:"properties"`
+}
+
+type xmlPoints struct {
+	Points string `xml:"points,attr"`
+}
+
+type xmlProperties struct {
+	Properties []xmlProperty `xml:"property"`
+}
+
+type xmlProperty struct {
+	Name       string        `xml:"name,attr"`
+	Type       string        `xml:"type,attr"`
+	Value      *string       `xml:"value,attr"`
+	Text       string        `xml:",chardata"`
+	Properties xmlProperties `xml:"properties"`
+}
+
+// ParseTMX parses a map in Tiled's XML format.  Dir is the directory of the map file, which the paths of external tilesets and images are relative to.
+func ParseTMX(data []byte, dir string) (*Map, error) {
+	xm := xmlMap{}
+	if err := xml.Unmarshal(data, &xm); err != nil {
+		return nil, err
+	}
+
+	m := Map{
+		Orientation:   xm.Orientation,
+		Width:         xm.Width,
+		Height:        xm.Height,
+		TileWidth:     xm.TileWidth,
+		TileHeight:    xm.TileHeight,
+		HexSideLength: xm.HexSideLength,
+		StaggerAxis:   xm.StaggerAxis,
+		StaggerIndex:  xm.StaggerIndex,
+		Infinite:      xm.Infinite != 0,
+		Properties:    xm.Properties.convert(""),
+	}
+
+	for _, xt := range xm.Tilesets {
+		var ts *Tileset
+		var err error
+		if xt.Source != "" {
+			ts, err = loadTileset(dir, xt.Source)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			ts = xt.convert(dir)
+		}
+		ts.FirstGID = xt.FirstGID
+		m.Tilesets = append(m.Tilesets, ts)
+	}
+
+	for _, xl := range xm.Layers {
+		layers, err := xl.convert("", true)
+		if err != nil {
+			return nil, err
+		}
+		m.Layers = append(m.Layers, layers...)
+	}
+
+	return &m, nil
+}
+
+// ParseTSX parses a tileset in Tiled's XML format.  Dir is the directory of the tileset file, which the paths of images are relative to.
+func ParseTSX(data []byte, dir string) (*Tileset, error) {
+	xt := xmlTileset{}
+	if err := xml.Unmarshal(data, &xt); err != nil {
+		return nil, err
+	}
+	return xt.convert(dir), nil
+}
+
+// loadTileset loads an external tileset in either format.
+func loadTileset(dir, source string) (*Tileset, error) {
+	fileName := path.Join(dir, source)
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	if isJSON(fileName) {
+		return ParseTilesetJSON(data, path.Dir(fileName))
+	}
+	return ParseTSX(data, path.Dir(fileName))
+}
+
+func (xt xmlTileset) convert(dir string) *Tileset {
+	ts := Tileset{
+		Name:       xt.Name,
+		TileWidth:  xt.TileWidth,
+		TileHeight: xt.TileHeight,
+		Spacing:    xt.Spacing,
+		Margin:     xt.Margin,
+		TileCount:  xt.TileCount,
+		Columns:    xt.Columns,
+		Image:      xt.Image.convert(dir),
+		Properties: xt.Properties.convert(""),
+	}
+	for _, tile := range xt.Tiles {
+		class := tile.Class
+		if class == "" {
+			class = tile.Type
+		}
+		ts.Tiles = append(ts.Tiles, &TilesetTile{
+			ID:         tile.ID,
+			Class:      class,
+			Image:      tile.Image.convert(dir),
+			Properties: tile.Properties.convert(""),
+		})
+	}
+	ts.finish()
+	return &ts
+}
+
+func (xi xmlImage) convert(dir string) Image {
+	return Image{
+		Source: texturePath(dir, xi.Source),
+		Width:  xi.Width,
+		Height: xi.Height,
+	}
+}
+
+// convert converts a layer and flattens groups into the layers they contain.  Layers in hidden groups are hidden.
+func (xl xmlLayer) convert(prefix string, visible bool) ([]*Layer, error) {
+	name := prefix + xl.Name
+	visible = visible && xl.Visible != "0"
+
+	switch xl.XMLName.Local {
+	case "group":
+		var layers []*Layer
+		for _, child := range xl.Layers {
+			children, err := child.convert(name+"/", visible)
+			if err != nil {
+				return nil, err
+			}
+			for _, c := range children {
+				c.OffsetX += xl.OffsetX
+				c.OffsetY += xl.OffsetY
+				c.Properties = xl.Properties.convert("").Merge(c.Properties)
+			}
+			layers = append(layers, children...)
+		}
+		return layers, nil
+	case "layer", "objectgroup", "imagelayer":
+	default:
+		return nil, nil
+	}
+
+	l := Layer{
+		Name:       name,
+		Type:       LayerType(xl.XMLName.Local),
+		Visible:    visible,
+		OffsetX:    xl.OffsetX,
+		OffsetY:    xl.OffsetY,
+		Properties: xl.Properties.convert(""),
+	}
+	if l.Type == "layer" {
+		l.Type = TileLayer
+	}
+
+	switch l.Type {
+	case TileLayer:
+		if len(xl.Data.Chunks) > 0 {
+			for _, chunk := range xl.Data.Chunks {
+				gids, err := xmlGIDs(xl.Data.Encoding, xl.Data.Compression, chunk.Text, chunk.Tiles)
+				if err != nil {
+					return nil, fmt.Errorf("layer %s: %v", name, err)
+				}
+				l.Cells = append(l.Cells, cells(gids, chunk.X, chunk.Y, chunk.Width)...)
+			}
+		} else {
+			gids, err := xmlGIDs(xl.Data.Encoding, xl.Data.Compression, xl.Data.Text, xl.Data.Tiles)
+			if err != nil {
+				return nil, fmt.Errorf("layer %s: %v", name, err)
+			}
+			l.Cells = cells(gids, 0, 0, xl.Width)
+		}
+	case ObjectLayer:
+		for _, xo := range xl.Objects {
+			o, err := xo.convert()
+			if err != nil {
+				return nil, fmt.Errorf("layer %s: %v", name, err)
+			}
+			l.Objects = append(l.Objects, o)
+		}
+	}
+
+	return []*Layer{&l}, nil
+}
+
+// xmlGIDs retrieves the global tile ids of a layer or chunk, which are either encoded text or a tile element per tile.
+func xmlGIDs(encoding, compression, text string, tiles []xmlGID) ([]uint32, error) {
+	if encoding != "" {
+		return decodeData(encoding, compression, text)
+	}
+	gids := make([]uint32, len(tiles))
+	for i, t := range tiles {
+		gids[i] = t.GID
+	}
+	return gids, nil
+}
+
+func (xo xmlObject) convert() (*Object, error) {
+	class := xo.Class
+	if class == "" {
+		class = xo.Type
+	}
+	o := Object{
+		ID:         xo.ID,
+		Name:       xo.Name,
+		Class:      class,
+		X:          xo.X,
+		Y:          xo.Y,
+		Width:      xo.Width,
+		Height:     xo.Height,
+		Rotation:   xo.Rotation,
+		GID:        xo.GID,
+		Visible:    xo.Visible != "0",
+		Ellipse:    xo.Ellipse != nil,
+		Point:      xo.Point != nil,
+		Properties: xo.Properties.convert(""),
+	}
+
+	points := xo.Polygon
+	if xo.Polyline != nil {
+		points = xo.Polyline
+		o.Polyline = true
+	}
+	if points != nil {
+		for _, pair := range strings.Fields(points.Points) {
+			xy := strings.Split(pair, ",")
+			if len(xy) != 2 {
+				return nil, fmt.Errorf("object %d has an invalid point %q", xo.ID, pair)
+			}
+			x, errX := strconv.ParseFloat(xy[0], 32)
+			y, errY := strconv.ParseFloat(xy[1], 32)
+			if errX != nil || errY != nil {
+				return nil, fmt.Errorf("object %d has an invalid point %q", xo.ID, pair)
+			}
+			o.Polygon = append(o.Polygon, [2]float32{float32(x), float32(y)})
+		}
+	}
+
+	return &o, nil
+}
+
+// convert converts properties, flattening the members of class properties into properties named after the class property.
+func (xp xmlProperties) convert(prefix string) Properties {
+	var props Properties
+	for _, p := range xp.Properties {
+		name := prefix + p.Name
+		if p.Type == "class" {
+			props = append(props, p.Properties.convert(name+".")...)
+			continue
+		}
+		value := p.Text
+		if p.Value != nil {
+			value = *p.Value
+		}
+		typ := p.Type
+		if typ == "" {
+			typ = "string"
+		}
+		props = append(props, Property{Name: name, Type: typ, Value: value})
+	}
+	return props
+}
@@ -0,0 +1,180 @@
+package tiled
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+const testTMX = `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="2" height="2" tilewidth="32" tileheight="32" infinite="0">
+ <properties>
+  <property name="music" value="theme.ogg"/>
+ </properties>
+ <tileset firstgid="1" name="tiles" tilewidth="32" tileheight="32" tilecount="2" columns="2">
+  <image source="../textures/tiles.png" width="64" height="32"/>
+  <tile id="1" type="water">
+   <properties>
+    <property name="speed" type="float" value="0.5"/>
+   </properties>
+  </tile>
+ </tileset>
+ <layer id="1" name="ground" width="2" height="2">
+  <data encoding="csv">
+1,2,
+0,2147483649
+</data>
+ </layer>
+ <group id="2" name="things" offsetx="4" visible="0">
+  <properties>
+   <property name="team" value="red"/>
+  </properties>
+  <objectgroup id="3" name="units" offsety="2">
+   <object id="1" name="path" x="10" y="20">
+    <properties>
+     <property name="spawn" type="class">
+      <properties>
+       <property name="count" type="int" value="3"/>
+      </properties>
+     </property>
+    </properties>
+    <polyline points="0,0 8,0 8,8"/>
+   </object>
+   <object id="2" gid="2" x="32" y="64" width="32" height="32"/>
+  </objectgroup>
+ </group>
+</map>
+`
+
+// testMap is the map both testTMX and testTMJ describe.
+func testMap() *Map {
+	return &Map{
+		Orientation: Orthogonal,
+		Width:       2,
+		Height:      2,
+		TileWidth:   32,
+		TileHeight:  32,
+		Properties:  Properties{{Name: "music", Type: "string", Value: "theme.ogg"}},
+		Tilesets: []*Tileset{{
+			FirstGID:   1,
+			Name:       "tiles",
+			TileWidth:  32,
+			TileHeight: 32,
+			TileCount:  2,
+			Columns:    2,
+			Image:      Image{Source: "tiles.png", Width: 64, Height: 32},
+			Tiles: []*TilesetTile{{
+				ID:         1,
+				Class:      "water",
+				Properties: Properties{{Name: "speed", Type: "float", Value: "0.5"}},
+			}},
+		}},
+		Layers: []*Layer{
+			{
+				Name:    "ground",
+				Type:    TileLayer,
+				Visible: true,
+				Cells:   []Cell{{0, 0, 1}, {1, 0, 2}, {1, 1, FlippedHorizontally | 1}},
+			},
+			{
+				Name:       "things/units",
+				Type:       ObjectLayer,
+				OffsetX:    4,
+				OffsetY:    2,
+				Properties: Properties{{Name: "team", Type: "string", Value: "red"}},
+				Objects: []*Object{
+					{
+						ID:         1,
+						Name:       "path",
+						X:          10,
+						Y:          20,
+						Visible:    true,
+						Polygon:    [][2]float32{{0, 0}, {8, 0}, {8, 8}},
+						Polyline:   true,
+						Properties: Properties{{Name: "spawn.count", Type: "int", Value: "3"}},
+					},
+					{ID: 2, GID: 2, X: 32, Y: 64, Width: 32, Height: 32, Visible: true},
+				},
+			},
+		},
+	}
+}
+
+// compareMaps reports every part of a parsed map that differs from what was expected.
+func compareMaps(t *testing.T, name string, got, want *Map) {
+	header := func(m *Map) Map {
+		h := *m
+		h.Tilesets, h.Layers = nil, nil
+		return h
+	}
+	if g, w := header(got), header(want); !reflect.DeepEqual(g, w) {
+		t.Errorf("%s: map = %+v, want %+v", name, g, w)
+	}
+
+	if len(got.Tilesets) != len(want.Tilesets) {
+		t.Errorf("%s: %d tilesets, want %d", name, len(got.Tilesets), len(want.Tilesets))
+	}
+	for i := 0; i < len(got.Tilesets) && i < len(want.Tilesets); i++ {
+		if !reflect.DeepEqual(got.Tilesets[i], want.Tilesets[i]) {
+			t.Errorf("%s: tileset %d = %+v with tiles %s, want %+v with tiles %s", name, i, *got.Tilesets[i], describe(got.Tilesets[i].Tiles), *want.Tilesets[i], describe(want.Tilesets[i].Tiles))
+		}
+	}
+
+	if len(got.Layers) != len(want.Layers) {
+		t.Errorf("%s: %d layers, want %d", name, len(got.Layers), len(want.Layers))
+	}
+	for i := 0; i < len(got.Layers) && i < len(want.Layers); i++ {
+		if !reflect.DeepEqual(got.Layers[i], want.Layers[i]) {
+			t.Errorf("%s: layer %d = %+v with objects %s, want %+v with objects %s", name, i, *got.Layers[i], describe(got.Layers[i].Objects), *want.Layers[i], describe(want.Layers[i].Objects))
+		}
+	}
+}
+
+// describe prints what a slice of pointers points to.
+func describe(items interface{}) string {
+	v := reflect.ValueOf(items)
+	s := "["
+	for i := 0; i < v.Len(); i++ {
+		if i > 0 {
+			s += " "
+		}
+		s += fmt.Sprintf("%+v", v.Index(i).Elem().Interface())
+	}
+	return s + "]"
+}
+
+func TestParseTMX(t *testing.T) {
+	m, err := ParseTMX([]byte(testTMX), "assets/maps")
+	if err != nil {
+		t.Fatalf("ParseTMX failed: %v", err)
+	}
+	compareMaps(t, "TMX", m, testMap())
+}
+
+func TestParseTMXData(t *testing.T) {
+	gids := []uint32{0, 3, FlippedVertically | 2, 0}
+	tests := []struct {
+		name string
+		data string
+		want []Cell
+	}{
+		{"tile elements", `<data><tile/><tile gid="3"/><tile gid="1073741826"/><tile/></data>`, []Cell{{1, 0, 3}, {0, 1, FlippedVertically | 2}}},
+		{"gzip", fmt.Sprintf(`<data encoding="base64" compression="gzip">%s</data>`, encodeGIDs(t, "gzip", gids)), []Cell{{1, 0, 3}, {0, 1, FlippedVertically | 2}}},
+		{"zlib chunks", fmt.Sprintf(`<data encoding="base64" compression="zlib"><chunk x="-2" y="0" width="2" height="2">%s</chunk><chunk x="0" y="0" width="2" height="2">%s</chunk></data>`, encodeGIDs(t, "zlib", gids), encodeGIDs(t, "zlib", []uint32{5, 0, 0, 0})), []Cell{{-1, 0, 3}, {-2, 1, FlippedVertically | 2}, {0, 0, 5}}},
+	}
+	for _, test := range tests {
+		tmx := fmt.Sprintf(`<map orientation="orthogonal" width="2" height="2" tilewidth="32" tileheight="32"><layer name="ground" width="2" height="2">%s</layer></map>`, test.data)
+		m, err := ParseTMX([]byte(tmx), "")
+		if err != nil {
+			t.Errorf("%s: ParseTMX failed: %v", test.name, err)
+			continue
+		}
+		if len(m.Layers) != 1 || !reflect.DeepEqual(m.Layers[0].Cells, test.want) {
+			t.Errorf("%s: layers = %s, want one layer with cells %v", test.name, describe(m.Layers), test.want)
+		}
+	}
+
+	if _, err := ParseTMX([]byte(`<map><layer name="broken" width="1"><data encoding="csv">x</data></layer></map>`), ""); err == nil {
+		t.Errorf("ParseTMX of a layer with invalid data did not fail")
+	}
+}
@@ -0,0 +1,2 @@
+// Package tween provides easing curves and tweens used to animate values over a short period of time.
+package tween
@@ -0,0 +1,287 @@
+package tween
+
+import "math"
+
+// EasingFunc maps the linear progress of a tween, from 0 to 1, onto an eased progress.
+type EasingFunc func(t float32) float32
+
+const (
+	backOvershoot = 1.70158
+	backInOut     = backOvershoot * 1.525
+	elasticPeriod = (2 * math.Pi) / 3
+	elasticInOut  = (2 * math.Pi) / 4.5
+)
+
+// Linear does not apply any easing.
+func Linear(t float32) float32 {
+	return t
+}
+
+// EaseInQuad accelerates from zero velocity using a quadratic curve.
+func EaseInQuad(t float32) float32 {
+	return t * t
+}
+
+// EaseOutQuad decelerates to zero velocity using a quadratic curve.
+func EaseOutQuad(t float32) float32 {
+	return 1 - (1-t)*(1-t)
+}
+
+// EaseInOutQuad accelerates until halfway then decelerates using a quadratic curve.
+func EaseInOutQuad(t float32) float32 {
+	if t < 0.5 {
+		return 2 * t * t
+	}
+	return 1 - pow(-2*t+2, 2)/2
+}
+
+// EaseInCubic accelerates from zero velocity using a cubic curve.
+func EaseInCubic(t float32) float32 {
+	return t * t * t
+}
+
+// EaseOutCubic decelerates to zero velocity using a cubic curve.
+func EaseOutCubic(t float32) float32 {
+	return 1 - pow(1-t, 3)
+}
+
+// EaseInOutCubic accelerates until halfway then decelerates using a cubic curve.
+func EaseInOutCubic(t float32) float32 {
+	if t < 0.5 {
+		return 4 * t * t * t
+	}
+	return 1 - pow(-2*t+2, 3)/2
+}
+
+// EaseInQuart accelerates from zero velocity using a quartic curve.
+func EaseInQuart(t float32) float32 {
+	return t * t * t * t
+}
+
+// EaseOutQuart decelerates to zero velocity using a quartic curve.
+func EaseOutQuart(t float32) float32 {
+	return 1 - pow(1-t, 4)
+}
+
+// EaseInOutQuart accelerates until halfway then decelerates using a quartic curve.
+func EaseInOutQuart(t float32) float32 {
+	if t < 0.5 {
+		return 8 * t * t * t * t
+	}
+	return 1 - pow(-2*t+2, 4)/2
+}
+
+// EaseInQuint accelerates from zero velocity using a quintic curve.
+func EaseInQuint(t float32) float32 {
+	return t * t * t * t * t
+}
+
+// EaseOutQuint decelerates to zero velocity using a quintic curve.
+func EaseOutQuint(t float32) float32 {
+	return 1 - pow(1-t, 5)
+}
+
+// EaseInOutQuint accelerates until halfway then decelerates using a quintic curve.
+func EaseInOutQuint(t float32) float32 {
+	if t < 0.5 {
+		return 16 * t * t * t * t * t
+	}
+	return 1 - pow(-2*t+2, 5)/2
+}
+
+// EaseInSine accelerates from zero velocity using a sine curve.
+func EaseInSine(t float32) float32 {
+	return 1 - float32(math.Cos(float64(t)*math.Pi/2))
+}
+
+// EaseOutSine decelerates to zero velocity using a sine curve.
+func EaseOutSine(t float32) float32 {
+	return float32(math.Sin(float64(t) * math.Pi / 2))
+}
+
+// EaseInOutSine accelerates until halfway then decelerates using a sine curve.
+func EaseInOutSine(t float32) float32 {
+	return -(float32(math.Cos(math.Pi*float64(t))) - 1) / 2
+}
+
+// EaseInExpo accelerates from zero velocity using an exponential curve.
+func EaseInExpo(t float32) float32 {
+	if t <= 0 {
+		return 0
+	}
+	return pow(2, 10*t-10)
+}
+
+// EaseOutExpo decelerates to zero velocity using an exponential curve.
+func EaseOutExpo(t float32) float32 {
+	if t >= 1 {
+		return 1
+	}
+	return 1 - pow(2, -10*t)
+}
+
+// EaseInOutExpo accelerates until halfway then decelerates using an exponential curve.
+func EaseInOutExpo(t float32) float32 {
+	switch {
+	case t <= 0:
+		return 0
+	case t >= 1:
+		return 1
+	case t < 0.5:
+		return pow(2, 20*t-10) / 2
+	default:
+		return (2 - pow(2, -20*t+10)) / 2
+	}
+}
+
+// EaseInCirc accelerates from zero velocity using a circular curve.
+func EaseInCirc(t float32) float32 {
+	return 1 - sqrt(1-t*t)
+}
+
+// EaseOutCirc decelerates to zero velocity using a circular curve.
+func EaseOutCirc(t float32) float32 {
+	return sqrt(1 - (t-1)*(t-1))
+}
+
+// EaseInOutCirc accelerates until halfway then decelerates using a circular curve.
+func EaseInOutCirc(t float32) float32 {
+	if t < 0.5 {
+		return (1 - sqrt(1-4*t*t)) / 2
+	}
+	return (sqrt(1-pow(-2*t+2, 2)) + 1) / 2
+}
+
+// EaseInBack pulls back slightly before accelerating towards the end value.
+func EaseInBack(t float32) float32 {
+	return (backOvershoot+1)*t*t*t - backOvershoot*t*t
+}
+
+// EaseOutBack overshoots the end value slightly before settling on it.
+func EaseOutBack(t float32) float32 {
+	return 1 + (backOvershoot+1)*pow(t-1, 3) + backOvershoot*pow(t-1, 2)
+}
+
+// EaseInOutBack pulls back at the start and overshoots at the end.
+func EaseInOutBack(t float32) float32 {
+	if t < 0.5 {
+		return (pow(2*t, 2) * ((backInOut+1)*2*t - backInOut)) / 2
+	}
+	return (pow(2*t-2, 2)*((backInOut+1)*(t*2-2)+backInOut) + 2) / 2
+}
+
+// EaseInElastic oscillates with increasing amplitude before snapping to the end value.
+func EaseInElastic(t float32) float32 {
+	if t <= 0 || t >= 1 {
+		return clampUnit(t)
+	}
+	return -pow(2, 10*t-10) * float32(math.Sin((float64(t)*10-10.75)*elasticPeriod))
+}
+
+// EaseOutElastic overshoots and oscillates with decreasing amplitude around the end value.
+func EaseOutElastic(t float32) float32 {
+	if t <= 0 || t >= 1 {
+		return clampUnit(t)
+	}
+	return pow(2, -10*t)*float32(math.Sin((float64(t)*10-0.75)*elasticPeriod)) + 1
+}
+
+// EaseInOutElastic oscillates at both the start and end of the tween.
+func EaseInOutElastic(t float32) float32 {
+	if t <= 0 || t >= 1 {
+		return clampUnit(t)
+	}
+	s := float32(math.Sin((20*float64(t) - 11.125) * elasticInOut))
+	if t < 0.5 {
+		return -(pow(2, 20*t-10) * s) / 2
+	}
+	return (pow(2, -20*t+10)*s)/2 + 1
+}
+
+// EaseInBounce bounces with increasing height before reaching the end value.
+func EaseInBounce(t float32) float32 {
+	return 1 - EaseOutBounce(1-t)
+}
+
+// EaseOutBounce bounces with decreasing height as it settles on the end value.
+func EaseOutBounce(t float32) float32 {
+	const n1 = 7.5625
+	const d1 = 2.75
+
+	switch {
+	case t < 1/d1:
+		return n1 * t * t
+	case t < 2/d1:
+		t -= 1.5 / d1
+		return n1*t*t + 0.75
+	case t < 2.5/d1:
+		t -= 2.25 / d1
+		return n1*t*t + 0.9375
+	default:
+		t -= 2.625 / d1
+		return n1*t*t + 0.984375
+	}
+}
+
+// EaseInOutBounce bounces at both the start and end of the tween.
+func EaseInOutBounce(t float32) float32 {
+	if t < 0.5 {
+		return (1 - EaseOutBounce(1-2*t)) / 2
+	}
+	return (1 + EaseOutBounce(2*t-1)) / 2
+}
+
+var easings = map[string]EasingFunc{
+	"linear":           Linear,
+	"easeInQuad":       EaseInQuad,
+	"easeOutQuad":      EaseOutQuad,
+	"easeInOutQuad":    EaseInOutQuad,
+	"easeInCubic":      EaseInCubic,
+	"easeOutCubic":     EaseOutCubic,
+	"easeInOutCubic":   EaseInOutCubic,
+	"easeInQuart":      EaseInQuart,
+	"easeOutQuart":     EaseOutQuart,
+	"easeInOutQuart":   EaseInOutQuart,
+	"easeInQuint":      EaseInQuint,
+	"easeOutQuint":     EaseOutQuint,
+	"easeInOutQuint":   EaseInOutQuint,
+	"easeInSine":       EaseInSine,
+	"easeOutSine":      EaseOutSine,
+	"easeInOutSine":    EaseInOutSine,
+	"easeInExpo":       EaseInExpo,
+	"easeOutExpo":      EaseOutExpo,
+	"easeInOutExpo":    EaseInOutExpo,
+	"easeInCirc":       EaseInCirc,
+	"easeOutCirc":      EaseOutCirc,
+	"easeInOutCirc":    EaseInOutCirc,
+	"easeInBack":       EaseInBack,
+	"easeOutBack":      EaseOutBack,
+	"easeInOutBack":    EaseInOutBack,
+	"easeInElastic":    EaseInElastic,
+	"easeOutElastic":   EaseOutElastic,
+	"easeInOutElastic": EaseInOutElastic,
+	"easeInBounce":     EaseInBounce,
+	"easeOutBounce":    EaseOutBounce,
+	"easeInOutBounce":  EaseInOutBounce,
+}
+
+// Easing retrieves an easing function by its name, such as "easeOutBack".
+func Easing(name string) (EasingFunc, bool) {
+	e, ok := easings[name]
+	return e, ok
+}
+
+func pow(x, y float32) float32 {
+	return float32(math.Pow(float64(x), float64(y)))
+}
+
+func sqrt(x float32) float32 {
+	return float32(math.Sqrt(float64(x)))
+}
+
+func clampUnit(t float32) float32 {
+	if t <= 0 {
+		return 0
+	}
+	return 1
+}
@@ -0,0 +1,31 @@
+package tween
+
+import "testing"
+
+func TestEasingEndpoints(t *testing.T) {
+	for name, easing := range easings {
+		if got := easing(0); got < -1e-5 || got > 1e-5 {
+			t.Errorf("%s(0) = %v, want 0", name, got)
+		}
+		if got := easing(1); got < 1-1e-5 || got > 1+1e-5 {
+			t.Errorf("%s(1) = %v, want 1", name, got)
+		}
+	}
+}
+
+func TestEasing(t *testing.T) {
+	tests := []struct {
+		name  string
+		found bool
+	}{
+		{"linear", true},
+		{"easeOutBack", true},
+		{"easeInOutBounce", true},
+		{"easeSideways", false},
+	}
+	for _, test := range tests {
+		if _, found := Easing(test.name); found != test.found {
+			t.Errorf("Easing(%q) found = %v, want %v", test.name, found, test.found)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package tween
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+
+	"github.com/Ariemeth/quantum-pulse/components"
+)
+
+// FloatTarget creates a target from a getter and setter of a single float value.
+func FloatTarget(get func() float32, set func(float32)) Target {
+	return Target{
+		Get: func() []float32 { return []float32{get()} },
+		Set: func(v []float32) { set(v[0]) },
+	}
+}
+
+// SetterTarget creates a target for a value that can only be set.  Tweens using this target must supply the starting value with NewFromTo.
+func SetterTarget(set func(float32)) Target {
+	return Target{
+		Get: func() []float32 { return []float32{0} },
+		Set: func(v []float32) { set(v[0]) },
+	}
+}
+
+// TranslationTarget creates a target animating the translation of a transform.
+func TranslationTarget(t components.Transform) Target {
+	return vec3Target(t.Translation, t.SetTranslation)
+}
+
+// RotationTarget creates a target animating the euler rotation of a transform in radians.
+func RotationTarget(t components.Transform) Target {
+	return vec3Target(t.Rotation, t.SetRotation)
+}
+
+// ScaleTarget creates a target animating the scale of a transform.
+func ScaleTarget(t components.Transform) Target {
+	return vec3Target(t.Scale, t.SetScale)
+}
+
+// CameraPositionTarget creates a target animating the position of a camera while keeping the point it looks at.
+func CameraPositionTarget(c components.Camera) Target {
+	return vec3Target(c.PositionVec3, func(v mgl32.Vec3) {
+		c.SetView(v, c.LookAtVec3(), c.UpVec3())
+	})
+}
+
+// CameraLookAtTarget creates a target animating the point a camera is looking at.
+func CameraLookAtTarget(c components.Camera) Target {
+	return vec3Target(c.LookAtVec3, func(v mgl32.Vec3) {
+		c.SetView(c.PositionVec3(), v, c.UpVec3())
+	})
+}
+
+// CameraFOVTarget creates a target animating the vertical field of view of a camera in degrees.
+func CameraFOVTarget(c components.Camera) Target {
+	return FloatTarget(
+		func() float32 { return mgl32.RadToDeg(c.FOVy()) },
+		func(fov float32) {
+			c.SetProjection(fov, c.NearPlane(), c.FarPlane(), c.WindowWidth(), c.WindowHeight())
+		})
+}
+
+func vec3Target(get func() mgl32.Vec3, set func(mgl32.Vec3)) Target {
+	return Target{
+		Get: func() []float32 {
+			v := get()
+			return []float32{v[0], v[1], v[2]}
+		},
+		Set: func(v []float32) {
+			set(mgl32.Vec3{valueAt(v, 0), valueAt(v, 1), valueAt(v, 2)})
+		},
+	}
+}
@@ -0,0 +1,353 @@
+package tween
+
+// Tween represents a value animation that is advanced over time.  Tweens are not safe for concurrent use and are expected to be advanced by a single system such as systems.Tweener.
+type Tween interface {
+	// Update advances the tween by the elapsed seconds and returns true once it has completed.
+	Update(elapsed float32) bool
+	// IsComplete returns true once the tween and all of its repeats have finished.
+	IsComplete() bool
+	// Reset returns the tween to its initial state so it can be played again.
+	Reset()
+	// SetDelay sets the number of seconds to wait before the tween starts.
+	SetDelay(seconds float32) Tween
+	// SetRepeat sets how many times the tween is repeated after it first completes.  A negative count repeats forever.
+	SetRepeat(count int) Tween
+	// SetYoyo causes every repeat to play in the opposite direction of the previous one.
+	SetYoyo(yoyo bool) Tween
+	// OnStart registers a function called when the tween starts after its delay.
+	OnStart(func()) Tween
+	// OnComplete registers a function called when the tween and all of its repeats have finished.
+	OnComplete(func()) Tween
+
+	// step advances the tween and returns the time left over after it completes.
+	step(elapsed float32, reversed bool) (float32, bool)
+	// rewind returns the tween to the start of its playback in the given direction.
+	rewind(reversed bool)
+}
+
+// body is the part of a tween that changes values.  Delays, repeats and callbacks are handled by the tween wrapping it.
+type body interface {
+	start()
+	advance(elapsed float32, reversed bool) (float32, bool)
+	rewind(reversed bool)
+}
+
+type tween struct {
+	body       body
+	delay      float32
+	delayLeft  float32
+	repeat     int
+	played     int
+	yoyo       bool
+	backward   bool
+	started    bool
+	done       bool
+	onStart    []func()
+	onComplete []func()
+}
+
+// Target describes how a tween reads and writes the values it animates.
+type Target struct {
+	Get func() []float32
+	Set func([]float32)
+}
+
+// New creates a tween that animates the target from its value when the tween starts to the values in to.
+func New(target Target, to []float32, duration float32, easing EasingFunc) Tween {
+	return wrap(&valueTween{target: target, to: to, duration: duration, easing: easing})
+}
+
+// NewBy creates a tween that animates the target by the amounts in delta relative to its value when the tween starts.
+func NewBy(target Target, delta []float32, duration float32, easing EasingFunc) Tween {
+	return wrap(&valueTween{target: target, delta: delta, relative: true, duration: duration, easing: easing})
+}
+
+// NewFromTo creates a tween that animates the target between two fixed values.
+func NewFromTo(target Target, from, to []float32, duration float32, easing EasingFunc) Tween {
+	return wrap(&valueTween{target: target, fixedFrom: from, to: to, duration: duration, easing: easing})
+}
+
+// NewDelay creates a tween that does nothing for a number of seconds.  It is useful to space out tweens in a sequence.
+func NewDelay(seconds float32) Tween {
+	return wrap(&valueTween{duration: seconds})
+}
+
+// NewCallback creates a tween that calls a function and completes immediately.
+func NewCallback(f func()) Tween {
+	return wrap(&callbackTween{call: f})
+}
+
+// NewSequence creates a tween that plays each of the tweens one after the other.
+func NewSequence(tweens ...Tween) Tween {
+	return wrap(&sequence{children: tweens})
+}
+
+// NewParallel creates a tween that plays all of the tweens at the same time and completes when the last one finishes.
+func NewParallel(tweens ...Tween) Tween {
+	return wrap(&parallel{children: tweens})
+}
+
+func wrap(b body) *tween {
+	return &tween{body: b}
+}
+
+// Update advances the tween by the elapsed seconds and returns true once it has completed.
+func (t *tween) Update(elapsed float32) bool {
+	_, done := t.step(elapsed, false)
+	return done
+}
+
+// IsComplete returns true once the tween and all of its repeats have finished.
+func (t *tween) IsComplete() bool {
+	return t.done
+}
+
+// Reset returns the tween to its initial state so it can be played again.
+func (t *tween) Reset() {
+	t.started = false
+	t.rewind(false)
+}
+
+// SetDelay sets the number of seconds to wait before the tween starts.
+func (t *tween) SetDelay(seconds float32) Tween {
+	t.delay, t.delayLeft = seconds, seconds
+	return t
+}
+
+// SetRepeat sets how many times the tween is repeated after it first completes.  A negative count repeats forever.
+func (t *tween) SetRepeat(count int) Tween {
+	t.repeat = count
+	return t
+}
+
+// SetYoyo causes every repeat to play in the opposite direction of the previous one.
+func (t *tween) SetYoyo(yoyo bool) Tween {
+	t.yoyo = yoyo
+	return t
+}
+
+// OnStart registers a function called when the tween starts after its delay.
+func (t *tween) OnStart(f func()) Tween {
+	t.onStart = append(t.onStart, f)
+	return t
+}
+
+// OnComplete registers a function called when the tween and all of its repeats have finished.
+func (t *tween) OnComplete(f func()) Tween {
+	t.onComplete = append(t.onComplete, f)
+	return t
+}
+
+func (t *tween) step(elapsed float32, reversed bool) (float32, bool) {
+	if t.done {
+		return elapsed, true
+	}
+
+	if t.delayLeft > 0 {
+		if elapsed < t.delayLeft {
+			t.delayLeft -= elapsed
+			return 0, false
+		}
+		elapsed -= t.delayLeft
+		t.delayLeft = 0
+	}
+
+	if !t.started {
+		t.started = true
+		t.body.start()
+		for _, f := range t.onStart {
+			f()
+		}
+	}
+
+	for {
+		left, finished := t.body.advance(elapsed, reversed != t.backward)
+		if !finished {
+			return 0, false
+		}
+
+		if t.repeat >= 0 && t.played >= t.repeat {
+			t.done = true
+			for _, f := range t.onComplete {
+				f()
+			}
+			return left, true
+		}
+
+		t.played++
+		if t.yoyo {
+			t.backward = !t.backward
+		}
+		t.body.rewind(reversed != t.backward)
+
+		// Stop when there is no time left or the body cannot consume any, which would otherwise loop forever.
+		if left <= 0 || left >= elapsed {
+			return 0, false
+		}
+		elapsed = left
+	}
+}
+
+func (t *tween) rewind(reversed bool) {
+	t.delayLeft = t.delay
+	t.played = 0
+	t.backward = false
+	t.done = false
+	t.body.rewind(reversed)
+}
+
+type valueTween struct {
+	target    Target
+	fixedFrom []float32
+	from      []float32
+	to        []float32
+	delta     []float32
+	relative  bool
+	duration  float32
+	time      float32
+	easing    EasingFunc
+	values    []float32
+}
+
+func (v *valueTween) start() {
+	if v.target.Get == nil || v.target.Set == nil {
+		return
+	}
+
+	if v.fixedFrom != nil {
+		v.from = append([]float32(nil), v.fixedFrom...)
+	} else {
+		v.from = append([]float32(nil), v.target.Get()...)
+	}
+
+	if v.relative {
+		v.to = make([]float32, len(v.from))
+		for i := range v.from {
+			v.to[i] = v.from[i] + valueAt(v.delta, i)
+		}
+	}
+	v.values = make([]float32, len(v.from))
+}
+
+func (v *valueTween) advance(elapsed float32, reversed bool) (float32, bool) {
+	v.time += elapsed
+	left := float32(0)
+	if v.time >= v.duration {
+		left = v.time - v.duration
+		v.time = v.duration
+	}
+
+	progress := float32(1)
+	if v.duration > 0 {
+		progress = v.time / v.duration
+	}
+	if reversed {
+		progress = 1 - progress
+	}
+	v.apply(progress)
+
+	return left, v.time >= v.duration
+}
+
+func (v *valueTween) rewind(reversed bool) {
+	v.time = 0
+}
+
+// apply sets the target to the values at the given progress.
+func (v *valueTween) apply(progress float32) {
+	if v.values == nil {
+		return
+	}
+	if v.easing != nil {
+		progress = v.easing(progress)
+	}
+	for i := range v.values {
+		from := v.from[i]
+		v.values[i] = from + (valueAt(v.to, i)-from)*progress
+	}
+	v.target.Set(v.values)
+}
+
+type callbackTween struct {
+	call func()
+}
+
+func (c *callbackTween) start() {}
+
+func (c *callbackTween) advance(elapsed float32, reversed bool) (float32, bool) {
+	if c.call != nil {
+		c.call()
+	}
+	return elapsed, true
+}
+
+func (c *callbackTween) rewind(reversed bool) {}
+
+type sequence struct {
+	children []Tween
+	index    int
+}
+
+func (s *sequence) start() {}
+
+func (s *sequence) advance(elapsed float32, reversed bool) (float32, bool) {
+	for s.index < len(s.children) {
+		child := s.children[s.index]
+		if reversed {
+			child = s.children[len(s.children)-1-s.index]
+		}
+
+		left, done := child.step(elapsed, reversed)
+		if !done {
+			return 0, false
+		}
+		s.index++
+		elapsed = left
+	}
+	return elapsed, true
+}
+
+func (s *sequence) rewind(reversed bool) {
+	s.index = 0
+	for _, child := range s.children {
+		child.rewind(reversed)
+	}
+}
+
+type parallel struct {
+	children []Tween
+}
+
+func (p *parallel) start() {}
+
+func (p *parallel) advance(elapsed float32, reversed bool) (float32, bool) {
+	left := elapsed
+	done := true
+	for _, child := range p.children {
+		childLeft, childDone := child.step(elapsed, reversed)
+		if !childDone {
+			done = false
+		}
+		if childLeft < left {
+			left = childLeft
+		}
+	}
+	if !done {
+		return 0, false
+	}
+	return left, true
+}
+
+func (p *parallel) rewind(reversed bool) {
+	for _, child := range p.children {
+		child.rewind(reversed)
+	}
+}
+
+// valueAt safely retrieves a value from a slice.
+func valueAt(v []float32, i int) float32 {
+	if i < len(v) {
+		return v[i]
+	}
+	return 0
+}
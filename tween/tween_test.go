@@ -0,0 +1,163 @@
+package tween
+
+import "testing"
+
+// recorder is a target holding a single value.
+type recorder struct {
+	value float32
+}
+
+func (r *recorder) target() Target {
+	return FloatTarget(func() float32 { return r.value }, func(v float32) { r.value = v })
+}
+
+func TestTweenRepeat(t *testing.T) {
+	tests := []struct {
+		name   string
+		repeat int
+		yoyo   bool
+		steps  []float32
+		values []float32
+		// doneAt is the step the tween completes on, or -1 if it never does.
+		doneAt int
+	}{
+		{"once", 0, false, []float32{0.5, 0.5}, []float32{0.5, 1}, 1},
+		{"repeat", 2, false, []float32{1, 0.5, 0.5, 1}, []float32{1, 0.5, 1, 1}, 3},
+		{"yoyo", 2, true, []float32{1, 0.5, 0.5, 1}, []float32{1, 0.5, 0, 1}, 3},
+		{"yoyo carries time into the next repeat", 1, true, []float32{1.5, 0.5}, []float32{0.5, 0}, 1},
+		{"yoyo forever", -1, true, []float32{1, 1, 1, 1, 0.25}, []float32{1, 0, 1, 0, 0.25}, -1},
+	}
+	for _, test := range tests {
+		r := &recorder{}
+		starts, completions := 0, 0
+		tw := NewFromTo(r.target(), []float32{0}, []float32{1}, 1, Linear).
+			SetRepeat(test.repeat).
+			SetYoyo(test.yoyo).
+			OnStart(func() { starts++ }).
+			OnComplete(func() { completions++ })
+
+		for i, step := range test.steps {
+			done := tw.Update(step)
+			if r.value != test.values[i] {
+				t.Errorf("%s: value after step %d = %v, want %v", test.name, i, r.value, test.values[i])
+			}
+			if done != (i == test.doneAt) {
+				t.Errorf("%s: done after step %d = %v", test.name, i, done)
+			}
+		}
+		wantCompletions := 0
+		if test.doneAt >= 0 {
+			wantCompletions = 1
+		}
+		if starts != 1 || completions != wantCompletions {
+			t.Errorf("%s: started %d and completed %d times, want 1 and %d", test.name, starts, completions, wantCompletions)
+		}
+	}
+}
+
+func TestTweenDelay(t *testing.T) {
+	r := &recorder{value: 5}
+	started := false
+	tw := New(r.target(), []float32{10}, 1, Linear).SetDelay(1).OnStart(func() { started = true })
+
+	tw.Update(0.5)
+	if started || r.value != 5 {
+		t.Errorf("during the delay started = %v and value = %v, want false and 5", started, r.value)
+	}
+	tw.Update(1)
+	if !started || r.value != 7.5 {
+		t.Errorf("after the delay started = %v and value = %v, want true and 7.5", started, r.value)
+	}
+
+	tw.Reset()
+	tw.Update(0.5)
+	if r.value != 7.5 {
+		t.Errorf("after reset the delay did not hold the value, got %v", r.value)
+	}
+}
+
+func TestSequence(t *testing.T) {
+	a, b := &recorder{}, &recorder{}
+	calls := 0
+	completions := 0
+	seq := NewSequence(
+		NewFromTo(a.target(), []float32{0}, []float32{1}, 1, Linear),
+		NewCallback(func() { calls++ }),
+		NewFromTo(b.target(), []float32{0}, []float32{2}, 1, Linear),
+	).OnComplete(func() { completions++ })
+
+	tests := []struct {
+		step  float32
+		a, b  float32
+		calls int
+		done  bool
+	}{
+		{0.5, 0.5, 0, 0, false},
+		{1, 1, 1, 1, false},
+		{0.25, 1, 1.5, 1, false},
+		{0.25, 1, 2, 1, true},
+		{1, 1, 2, 1, true},
+	}
+	for i, test := range tests {
+		done := seq.Update(test.step)
+		if a.value != test.a || b.value != test.b || calls != test.calls || done != test.done {
+			t.Errorf("step %d: a = %v, b = %v, calls = %d, done = %v, want %v, %v, %d, %v", i, a.value, b.value, calls, done, test.a, test.b, test.calls, test.done)
+		}
+	}
+	if completions != 1 {
+		t.Errorf("sequence completed %d times, want 1", completions)
+	}
+
+	// A step long enough for every child completes the sequence at once.
+	seq.Reset()
+	if !seq.Update(5) || a.value != 1 || b.value != 2 || calls != 2 {
+		t.Errorf("a long step left a = %v, b = %v, calls = %d", a.value, b.value, calls)
+	}
+}
+
+func TestSequenceYoyo(t *testing.T) {
+	a, b := &recorder{}, &recorder{}
+	seq := NewSequence(
+		NewFromTo(a.target(), []float32{0}, []float32{1}, 1, Linear),
+		NewFromTo(b.target(), []float32{0}, []float32{1}, 1, Linear),
+	).SetRepeat(1).SetYoyo(true)
+
+	seq.Update(2)
+	// Played backwards the last child runs first.
+	seq.Update(0.5)
+	if a.value != 1 || b.value != 0.5 {
+		t.Errorf("a = %v, b = %v, want 1 and 0.5", a.value, b.value)
+	}
+	if !seq.Update(1.5) || a.value != 0 || b.value != 0 {
+		t.Errorf("a = %v, b = %v, want the sequence to end back at 0", a.value, b.value)
+	}
+}
+
+func TestParallel(t *testing.T) {
+	a, b := &recorder{}, &recorder{}
+	completions := 0
+	par := NewParallel(
+		NewFromTo(a.target(), []float32{0}, []float32{1}, 1, Linear),
+		NewFromTo(b.target(), []float32{0}, []float32{1}, 2, Linear),
+	).OnComplete(func() { completions++ })
+
+	tests := []struct {
+		step float32
+		a, b float32
+		done bool
+	}{
+		{0.5, 0.5, 0.25, false},
+		{0.5, 1, 0.5, false},
+		{0.5, 1, 0.75, false},
+		{0.5, 1, 1, true},
+	}
+	for i, test := range tests {
+		done := par.Update(test.step)
+		if a.value != test.a || b.value != test.b || done != test.done {
+			t.Errorf("step %d: a = %v, b = %v, done = %v, want %v, %v, %v", i, a.value, b.value, done, test.a, test.b, test.done)
+		}
+	}
+	if completions != 1 {
+		t.Errorf("parallel completed %d times, want 1", completions)
+	}
+}